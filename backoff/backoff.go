@@ -2,49 +2,263 @@ package backoff
 
 import (
 	"context"
-	"fmt"
 	"github.com/cenkalti/backoff/v5"
 	"time"
 )
 
-type BackoffWrapper struct {
+// BackoffWrapper 指数バックオフで処理をリトライする
+// T はリトライする処理の戻り値の型。
+type BackoffWrapper[T any] struct {
 	ctx       context.Context
-	operation backoff.Operation[any]
-	options   []backoff.RetryOption
+	operation backoff.Operation[T]
+	// operationContext SetDoOperationContext で設定した処理。operation より優先する
+	operationContext func(ctx context.Context) (T, error)
+	settings         settings
 }
 
-func NewBackoff(ctx context.Context, initialInterval time.Duration, randomizationFactor float64, multiplier float64, maxTries uint) *BackoffWrapper {
-	exponentialBackOff := backoff.NewExponentialBackOff()
+// settings Option で変更する設定
+type settings struct {
+	// exponential リトライ間隔の算出に使用する指数バックオフ
+	exponential *backoff.ExponentialBackOff
+	// maxTries nil 以外の場合、最大の実行回数（0 は無制限）
+	maxTries *uint
+	notify   backoff.Notify
+	// retryIf nil 以外の場合、false を返したエラーはリトライしない
+	retryIf func(error) bool
+	// attemptTimeout 0 より大きい場合、1回の処理ごとの制限時間
+	attemptTimeout time.Duration
+	// maxInterval 0 より大きい場合、リトライ間隔の上限
+	maxInterval time.Duration
+	// strategy nil 以外の場合、指数バックオフの代わりに使用する
+	strategy Strategy
+	// maxElapsedTime nil 以外の場合、リトライを続ける経過時間の上限（0 は無制限）
+	maxElapsedTime *time.Duration
+	// observer nil 以外の場合、リトライの状況を通知する
+	observer Observer
+	// errorDelay nil 以外の場合、エラーから求めた間隔を優先する
+	errorDelay func(error) (time.Duration, bool)
+	// budget nil 以外の場合、リトライの前にトークンを消費する
+	budget *RetryBudget
+}
+
+// Option BackoffWrapper の設定
+type Option func(*settings)
+
+// WithInitialInterval 最初のリトライまでの間隔を d にする
+func WithInitialInterval(d time.Duration) Option {
+	return func(s *settings) {
+		s.exponential.InitialInterval = d
+	}
+}
+
+// WithRandomizationFactor リトライ間隔のゆらぎの割合を f にする（0 でゆらぎなし）
+func WithRandomizationFactor(f float64) Option {
+	return func(s *settings) {
+		s.exponential.RandomizationFactor = f
+	}
+}
+
+// WithMultiplier リトライごとに間隔を m 倍にする
+func WithMultiplier(m float64) Option {
+	return func(s *settings) {
+		s.exponential.Multiplier = m
+	}
+}
+
+// WithMaxTries 最初の実行を含めて最大 n 回実行する（0 は無制限）
+func WithMaxTries(n uint) Option {
+	return func(s *settings) {
+		s.maxTries = &n
+	}
+}
+
+// WithNotify リトライの前に、失敗した処理のエラーと次のリトライまでの間隔を受け取る
+func WithNotify(n backoff.Notify) Option {
+	return func(s *settings) {
+		s.notify = n
+	}
+}
+
+// WithRetryIf リトライするエラーを判定する関数を指定する
+// f が false を返したエラーは Permanent で包んだ場合と同様にリトライしない。
+func WithRetryIf(f func(error) bool) Option {
+	return func(s *settings) {
+		s.retryIf = f
+	}
+}
+
+// WithAttemptTimeout 1回の処理ごとに d の制限時間を設ける
+// 処理が止まってしまった場合もリトライの予算を使い切らずに次の試行へ進める。
+// SetDoOperationContext で設定した処理に渡す context の期限として適用される。
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(s *settings) {
+		s.attemptTimeout = d
+	}
+}
+
+// WithMaxInterval リトライ間隔の上限を d にする
+// 指数的に伸びる間隔が d に達した後は、d（にゆらぎを加えた間隔）でリトライを続ける。
+func WithMaxInterval(d time.Duration) Option {
+	return func(s *settings) {
+		s.exponential.MaxInterval = d
+		s.maxInterval = d
+	}
+}
+
+// WithMaxElapsedTime 最初の実行から d を過ぎた場合、回数が残っていてもリトライをやめる
+// d が 0 の場合は経過時間で打ち切らない。指定しない場合は15分で打ち切る。
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(s *settings) {
+		s.maxElapsedTime = &d
+	}
+}
 
-	// リトライの初期間隔
-	exponentialBackOff.InitialInterval = initialInterval * time.Second
-	// リトライ間隔を決めるランダム値
-	exponentialBackOff.RandomizationFactor = randomizationFactor
-	// リトライ間隔を決める乗数
-	exponentialBackOff.Multiplier = multiplier
+// Permanent はリトライしないエラーとして err を包む
+// 処理が Permanent で包んだエラーを返すと、残りの回数に関わらずリトライをやめて err を返す。
+// 入力値の検証エラーなど、リトライしても結果が変わらないエラーに使用する。
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return backoff.Permanent(err)
+}
+
+// NewBackoffWithOptions Option を指定して生成する
+// 指定しない項目は初期間隔 500ms、乗数 1.5、ゆらぎ 0.5、間隔の上限 60s、回数は無制限、経過時間の上限 15分となる。
+func NewBackoffWithOptions[T any](ctx context.Context, opts ...Option) *BackoffWrapper[T] {
+	return &BackoffWrapper[T]{
+		ctx:      ctx,
+		settings: newSettings(opts...),
+	}
+}
 
-	// v5の場合、設定された最大回数の-1回まで実行される。それ以前の場合、同じ回数分実行される。
-	options := []backoff.RetryOption{backoff.WithBackOff(exponentialBackOff), backoff.WithMaxTries(maxTries)}
+// newSettings は初期値に opts を適用した設定を返す
+func newSettings(opts ...Option) settings {
+	s := settings{exponential: backoff.NewExponentialBackOff()}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
 
-	return &BackoffWrapper{
-		ctx:     ctx,
-		options: options,
+// newStrategy は設定からリトライ間隔の算出方法を組み立てる
+// lastErr は直前に失敗した処理のエラーを返す。
+func (s settings) newStrategy(lastErr func() error) Strategy {
+	var strategy Strategy = s.exponential
+	if s.strategy != nil {
+		strategy = s.strategy
+		if s.maxInterval > 0 {
+			strategy = capped{Strategy: strategy, max: s.maxInterval}
+		}
+	}
+	if s.errorDelay != nil {
+		strategy = errorDelayed{Strategy: strategy, delay: s.errorDelay, lastErr: lastErr}
+	}
+	if s.budget != nil {
+		strategy = budgeted{Strategy: strategy, budget: s.budget}
+	}
+	return strategy
+}
+
+// Deprecated: initialInterval が秒に換算されるなど誤りやすいため、NewBackoffWithOptions を使用すること。
+func NewBackoff[T any](ctx context.Context, initialInterval time.Duration, randomizationFactor float64, multiplier float64, maxTries uint) *BackoffWrapper[T] {
+	return NewBackoffWithOptions[T](ctx,
+		// リトライの初期間隔
+		WithInitialInterval(initialInterval*time.Second),
+		// リトライ間隔を決めるランダム値
+		WithRandomizationFactor(randomizationFactor),
+		// リトライ間隔を決める乗数
+		WithMultiplier(multiplier),
+		// v5の場合、設定された最大回数の-1回まで実行される。それ以前の場合、同じ回数分実行される。
+		WithMaxTries(maxTries),
+	)
+}
+
+// NewBackoffWithInterval 初期間隔と最大間隔を time.Duration のまま指定して生成する
+// NewBackoff と異なり initialInterval を秒に換算しないため、ミリ秒単位のポーリングにも使用できる。
+// maxTries が 0 の場合は回数を制限しない（ctx の終了まで再試行する）。
+func NewBackoffWithInterval[T any](ctx context.Context, initialInterval, maxInterval time.Duration, randomizationFactor float64, multiplier float64, maxTries uint) *BackoffWrapper[T] {
+	opts := []Option{
+		WithInitialInterval(initialInterval),
+		WithMaxInterval(maxInterval),
+		WithRandomizationFactor(randomizationFactor),
+		WithMultiplier(multiplier),
+		WithMaxTries(maxTries),
+	}
+	if maxTries == 0 {
+		// 経過時間でも打ち切らない
+		opts = append(opts, WithMaxElapsedTime(0))
 	}
+	return NewBackoffWithOptions[T](ctx, opts...)
 }
 
-func (b *BackoffWrapper) SetDoOperation(o backoff.Operation[any]) {
+func (b *BackoffWrapper[T]) SetDoOperation(o backoff.Operation[T]) {
 	b.operation = o
 }
 
-func (b *BackoffWrapper) SetNotify(n backoff.Notify) {
-	b.options = append(b.options, backoff.WithNotify(n))
+// SetDoOperationContext は context を受け取る処理を設定する
+// 渡される context は NewBackoff の ctx から派生し、WithAttemptTimeout の制限時間が設定される。
+func (b *BackoffWrapper[T]) SetDoOperationContext(o func(ctx context.Context) (T, error)) {
+	b.operationContext = o
+}
+
+// Apply は Option を適用する
+func (b *BackoffWrapper[T]) Apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(&b.settings)
+	}
+}
+
+func (b *BackoffWrapper[T]) SetNotify(n backoff.Notify) {
+	b.Apply(WithNotify(n))
+}
+
+// SetRetryIf はリトライするエラーを判定する関数を設定する
+// f が false を返したエラーは Permanent で包んだ場合と同様にリトライしない。
+func (b *BackoffWrapper[T]) SetRetryIf(f func(error) bool) {
+	b.Apply(WithRetryIf(f))
+}
+
+// Run は設定された処理をリトライしながら実行し、最終的なエラーを返す
+func (b *BackoffWrapper[T]) Run() error {
+	_, err := b.Exec()
+	return err
+}
+
+// Exec は設定された処理をリトライしながら実行し、成功した処理の戻り値を返す
+// リトライしても成功しなかった場合は最後のエラー（ctx が終了した場合は ctx のエラー）を返す。
+func (b *BackoffWrapper[T]) Exec() (T, error) {
+	r := b.Retrier()
+	for {
+		v, err := b.attempt()
+		if !r.Wait(err) {
+			return v, r.Err()
+		}
+	}
+}
+
+// Retrier は BackoffWrapper の設定でリトライの状態を管理する Retrier を返す
+// 処理を SetDoOperation で渡さずに、手書きのループからリトライ間隔と回数の判定だけを利用する場合に使用する。
+func (b *BackoffWrapper[T]) Retrier() *Retrier {
+	return newRetrier(b.ctx, b.settings)
 }
 
-func (b *BackoffWrapper) Exec() {
-	_, err := backoff.Retry(b.ctx, b.operation, b.options...)
-	if err != nil {
-		fmt.Println("処理失敗")
+// attempt は1回分の処理を実行する
+func (b *BackoffWrapper[T]) attempt() (T, error) {
+	var (
+		v   T
+		err error
+	)
+	if b.operationContext != nil {
+		ctx := b.ctx
+		if b.settings.attemptTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, b.settings.attemptTimeout)
+			defer cancel()
+		}
+		v, err = b.operationContext(ctx)
 	} else {
-		fmt.Println("処理成功")
+		v, err = b.operation()
 	}
+	return v, err
 }
@@ -21,7 +21,7 @@ func TestBackoffWrapper_Success(t *testing.T) {
 		return "ok", nil
 	}
 
-	bw := NewBackoff(ctx, 0, 0, 1, 5)
+	bw := NewBackoff[any](ctx, 0, 0, 1, 5)
 	bw.SetDoOperation(op)
 
 	called := int32(0)
@@ -53,7 +53,7 @@ func TestBackoffWrapper_Failure(t *testing.T) {
 		return nil, errors.New("常にエラー")
 	}
 
-	bw := NewBackoff(ctx, 0, 0, 1, 3)
+	bw := NewBackoff[any](ctx, 0, 0, 1, 3)
 	bw.SetDoOperation(op)
 
 	var lastErr error
@@ -76,3 +76,313 @@ func TestBackoffWrapper_Failure(t *testing.T) {
 		t.Errorf("Notifyで渡されたエラーが想定外です。got=%v", lastErr)
 	}
 }
+
+// 間隔を time.Duration のまま指定するパターンのテスト
+func TestNewBackoffWithInterval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	counter := int32(0)
+
+	bw := NewBackoffWithInterval[any](ctx, 10*time.Millisecond, 20*time.Millisecond, 0, 2, 0)
+	bw.SetDoOperation(func() (any, error) {
+		atomic.AddInt32(&counter, 1)
+		return nil, errors.New("一時エラー")
+	})
+
+	start := time.Now()
+	err := bw.Run()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ctxの終了まで再試行されていません。err=%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("間隔が秒に換算されています。elapsed=%v", elapsed)
+	}
+	if counter < 5 {
+		t.Errorf("リトライ回数が想定外です。got=%d", counter)
+	}
+}
+
+// 処理の戻り値と最終的なエラーを受け取るテスト
+func TestBackoffWrapper_Exec(t *testing.T) {
+	errFinal := errors.New("最終エラー")
+
+	tests := []struct {
+		name    string
+		results []error
+		want    int
+		wantErr error
+	}{
+		{
+			name:    "正常値: リトライ後に成功",
+			results: []error{errors.New("一時エラー"), nil},
+			want:    2,
+		},
+		{
+			name:    "異常値: 最後のエラーを返す",
+			results: []error{errors.New("一時エラー"), errFinal},
+			wantErr: errFinal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := 0
+			bw := NewBackoffWithInterval[int](context.Background(), time.Millisecond, time.Millisecond, 0, 1, uint(len(tt.results)))
+			bw.SetDoOperation(func() (int, error) {
+				err := tt.results[counter]
+				counter++
+				return counter, err
+			})
+
+			got, err := bw.Exec()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("戻り値が想定外です。got=%d, want=%d", got, tt.want)
+			}
+		})
+	}
+}
+
+// リトライしないエラーのテスト
+func TestBackoffWrapper_Permanent(t *testing.T) {
+	errInvalid := errors.New("検証エラー")
+
+	tests := []struct {
+		name    string
+		err     error
+		retryIf func(error) bool
+		want    int32
+	}{
+		{
+			name: "正常値: Permanent で包んだエラー",
+			err:  Permanent(errInvalid),
+			want: 1,
+		},
+		{
+			name:    "正常値: RetryIf が false",
+			err:     errInvalid,
+			retryIf: func(err error) bool { return !errors.Is(err, errInvalid) },
+			want:    1,
+		},
+		{
+			name:    "正常値: RetryIf が true",
+			err:     errInvalid,
+			retryIf: func(error) bool { return true },
+			want:    5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := int32(0)
+			bw := NewBackoffWithInterval[any](context.Background(), time.Millisecond, time.Millisecond, 0, 1, 5)
+			bw.SetDoOperation(func() (any, error) {
+				atomic.AddInt32(&counter, 1)
+				return nil, tt.err
+			})
+			if tt.retryIf != nil {
+				bw.SetRetryIf(tt.retryIf)
+			}
+
+			_, err := bw.Exec()
+			if !errors.Is(err, errInvalid) {
+				t.Errorf("エラーが想定外です。got=%v", err)
+			}
+			if counter != tt.want {
+				t.Errorf("実行回数が想定外です。got=%d, want=%d", counter, tt.want)
+			}
+		})
+	}
+
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) は nil を返す必要があります")
+	}
+}
+
+// 1回ごとの制限時間のテスト
+func TestBackoffWrapper_AttemptTimeout(t *testing.T) {
+	counter := int32(0)
+	bw := NewBackoffWithInterval[string](context.Background(), time.Millisecond, time.Millisecond, 0, 1, 5)
+	bw.Apply(WithAttemptTimeout(20 * time.Millisecond))
+	bw.SetDoOperationContext(func(ctx context.Context) (string, error) {
+		// 1回目は止まってしまい、制限時間で打ち切られる
+		if atomic.AddInt32(&counter, 1) == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			return "", errors.New("制限時間が設定されていません")
+		}
+		return "ok", nil
+	})
+
+	start := time.Now()
+	got, err := bw.Exec()
+	if err != nil || got != "ok" {
+		t.Errorf("結果が想定外です。got=%q, err=%v", got, err)
+	}
+	if counter != 2 {
+		t.Errorf("実行回数が想定外です。got=%d, want=2", counter)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("制限時間で打ち切られていません。elapsed=%v", elapsed)
+	}
+}
+
+// リトライ間隔と経過時間の上限のテスト
+func TestBackoffWrapper_MaxIntervalAndElapsedTime(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		wantMaxDelay time.Duration
+		wantMaxTries int32
+	}{
+		{
+			name:         "正常値: 間隔の上限",
+			opts:         []Option{WithMaxInterval(20 * time.Millisecond), WithMaxElapsedTime(150 * time.Millisecond)},
+			wantMaxDelay: 20 * time.Millisecond,
+		},
+		{
+			name:         "正常値: 経過時間で打ち切る",
+			opts:         []Option{WithMaxElapsedTime(50 * time.Millisecond)},
+			wantMaxDelay: time.Second,
+			wantMaxTries: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := int32(0)
+			var maxDelay time.Duration
+			bw := NewBackoffWithInterval[any](context.Background(), 10*time.Millisecond, time.Second, 0, 2, 100)
+			bw.Apply(tt.opts...)
+			bw.SetDoOperation(func() (any, error) {
+				atomic.AddInt32(&counter, 1)
+				return nil, errors.New("常にエラー")
+			})
+			bw.SetNotify(func(err error, d time.Duration) {
+				maxDelay = max(maxDelay, d)
+			})
+
+			start := time.Now()
+			if _, err := bw.Exec(); err == nil {
+				t.Error("エラーが返されていません")
+			}
+			if maxDelay > tt.wantMaxDelay {
+				t.Errorf("間隔が上限を超えています。got=%v, want<=%v", maxDelay, tt.wantMaxDelay)
+			}
+			if tt.wantMaxTries > 0 && counter > tt.wantMaxTries {
+				t.Errorf("実行回数が想定外です。got=%d, want<=%d", counter, tt.wantMaxTries)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("経過時間で打ち切られていません。elapsed=%v", elapsed)
+			}
+		})
+	}
+}
+
+// Option を指定して生成するテスト
+func TestNewBackoffWithOptions(t *testing.T) {
+	counter := int32(0)
+	var delays []time.Duration
+
+	bw := NewBackoffWithOptions[string](context.Background(),
+		WithInitialInterval(time.Millisecond),
+		WithMultiplier(2),
+		WithRandomizationFactor(0),
+		WithMaxTries(4),
+		WithNotify(func(err error, d time.Duration) {
+			delays = append(delays, d)
+		}),
+	)
+	bw.SetDoOperation(func() (string, error) {
+		if atomic.AddInt32(&counter, 1) < 4 {
+			return "", errors.New("一時エラー")
+		}
+		return "ok", nil
+	})
+
+	got, err := bw.Exec()
+	if err != nil || got != "ok" {
+		t.Errorf("結果が想定外です。got=%q, err=%v", got, err)
+	}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("リトライ回数が想定外です。got=%v, want=%v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("間隔が想定外です。got=%v, want=%v", delays, want)
+		}
+	}
+}
+
+// リトライの状況を受け取るテスト
+func TestBackoffWrapper_Observer(t *testing.T) {
+	metrics := &Metrics{}
+	var retries []Attempt
+
+	for _, fail := range []int32{2, 10} {
+		counter := int32(0)
+		bw := NewBackoffWithOptions[string](context.Background(),
+			WithInitialInterval(time.Millisecond),
+			WithRandomizationFactor(0),
+			WithMultiplier(1),
+			WithMaxTries(4),
+			WithObserver(metrics),
+			WithNotify(func(err error, d time.Duration) {
+				retries = append(retries, Attempt{Err: err, Delay: d})
+			}),
+		)
+		bw.SetDoOperation(func() (string, error) {
+			if atomic.AddInt32(&counter, 1) <= fail {
+				return "", errors.New("一時エラー")
+			}
+			return "ok", nil
+		})
+		bw.Exec()
+	}
+
+	got := metrics.Snapshot()
+	want := MetricsSnapshot{Executions: 2, Failures: 1, Attempts: 7, Retries: 5}
+	got.Elapsed = 0
+	if got != want {
+		t.Errorf("集計値が想定外です。got=%+v, want=%+v", got, want)
+	}
+	if len(retries) != 5 {
+		t.Errorf("Notifyの呼ばれた回数が想定外です。got=%d, want=5", len(retries))
+	}
+}
+
+// Observer に渡される情報のテスト
+func TestBackoffWrapper_ObserverAttempt(t *testing.T) {
+	rec := &recordObserver{}
+	bw := NewBackoffWithInterval[any](context.Background(), time.Millisecond, time.Millisecond, 0, 1, 3)
+	bw.Apply(WithObserver(rec))
+	bw.SetDoOperation(func() (any, error) {
+		return nil, errors.New("常にエラー")
+	})
+	bw.Exec()
+
+	if len(rec.attempts) != 2 {
+		t.Fatalf("OnRetry の呼ばれた回数が想定外です。got=%d, want=2", len(rec.attempts))
+	}
+	for i, a := range rec.attempts {
+		if a.Number != uint(i+1) || a.Delay != time.Millisecond || a.Err == nil || a.Elapsed <= 0 {
+			t.Errorf("Attempt が想定外です。got=%+v", a)
+		}
+	}
+	if rec.outcome.Attempts != 3 || rec.outcome.Err == nil {
+		t.Errorf("Outcome が想定外です。got=%+v", rec.outcome)
+	}
+}
+
+type recordObserver struct {
+	attempts []Attempt
+	outcome  Outcome
+}
+
+func (r *recordObserver) OnRetry(a Attempt)  { r.attempts = append(r.attempts, a) }
+func (r *recordObserver) OnFinish(o Outcome) { r.outcome = o }
@@ -0,0 +1,79 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+// RetryBudget 複数の BackoffWrapper で共有するリトライの予算
+// リトライごとにトークンを1つ消費し、処理が成功するたびに successRatio 分のトークンを補充するトークンバケット。
+// 依存先の障害で失敗が続くとトークンが尽き、リトライで負荷を増幅させずに失敗を返すようになる。
+type RetryBudget struct {
+	mu           sync.Mutex
+	tokens       float64
+	maxTokens    float64
+	successRatio float64
+}
+
+// NewRetryBudget は最大 maxTokens 回分のリトライの予算を生成する
+// successRatio は成功1回あたりに補充するトークン数で、0.1 の場合は成功10回につきリトライ1回分を補充する。
+// 生成直後は maxTokens 分のトークンを持つ。
+func NewRetryBudget(maxTokens uint, successRatio float64) *RetryBudget {
+	return &RetryBudget{
+		tokens:       float64(maxTokens),
+		maxTokens:    float64(maxTokens),
+		successRatio: successRatio,
+	}
+}
+
+// TryWithdraw はトークンが残っている場合に1つ消費して true を返す
+func (r *RetryBudget) TryWithdraw() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// Deposit は成功1回分のトークンを補充する（最大 maxTokens まで）
+func (r *RetryBudget) Deposit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens = min(r.tokens+r.successRatio, r.maxTokens)
+}
+
+// Available は残っているトークン数を返す
+func (r *RetryBudget) Available() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.tokens
+}
+
+// WithRetryBudget リトライの前に budget からトークンを消費し、尽きている場合はリトライせずに最後のエラーを返す
+// 処理が成功した場合は budget にトークンを補充する。
+func WithRetryBudget(budget *RetryBudget) Option {
+	return func(s *settings) {
+		s.budget = budget
+	}
+}
+
+// budgeted リトライの予算が尽きた場合にリトライをやめる
+type budgeted struct {
+	Strategy
+	budget *RetryBudget
+}
+
+func (b budgeted) NextBackOff() time.Duration {
+	next := b.Strategy.NextBackOff()
+	if next == backoff.Stop || !b.budget.TryWithdraw() {
+		return backoff.Stop
+	}
+	return next
+}
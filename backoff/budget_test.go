@@ -0,0 +1,76 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(2, 0.5)
+
+	for i := 0; i < 2; i++ {
+		if !budget.TryWithdraw() {
+			t.Fatalf("%d回目のトークンを消費できません", i+1)
+		}
+	}
+	if budget.TryWithdraw() {
+		t.Error("トークンが尽きていません")
+	}
+
+	budget.Deposit()
+	if budget.TryWithdraw() {
+		t.Error("成功1回で1トークン分が補充されています")
+	}
+	budget.Deposit()
+	budget.Deposit()
+	if !budget.TryWithdraw() {
+		t.Error("成功2回で1トークン分が補充されていません")
+	}
+
+	for i := 0; i < 10; i++ {
+		budget.Deposit()
+	}
+	if got := budget.Available(); got != 2 {
+		t.Errorf("トークン数が上限を超えています。got=%v", got)
+	}
+}
+
+// 予算を共有する BackoffWrapper のテスト
+func TestBackoffWrapper_RetryBudget(t *testing.T) {
+	errFail := errors.New("常にエラー")
+	budget := NewRetryBudget(3, 1)
+
+	run := func(fail bool) int32 {
+		counter := int32(0)
+		bw := NewBackoffWithInterval[any](context.Background(), time.Millisecond, time.Millisecond, 0, 1, 5)
+		bw.Apply(WithRetryBudget(budget))
+		bw.SetDoOperation(func() (any, error) {
+			atomic.AddInt32(&counter, 1)
+			if fail {
+				return nil, errFail
+			}
+			return nil, nil
+		})
+		if _, err := bw.Exec(); fail && !errors.Is(err, errFail) {
+			t.Errorf("エラーが想定外です。got=%v", err)
+		}
+		return counter
+	}
+
+	// 予算の3回分だけリトライする
+	if got := run(true); got != 4 {
+		t.Errorf("実行回数が想定外です。got=%d, want=4", got)
+	}
+	// 予算が尽きたためリトライしない
+	if got := run(true); got != 1 {
+		t.Errorf("実行回数が想定外です。got=%d, want=1", got)
+	}
+	// 成功で補充された1回分だけリトライする
+	run(false)
+	if got := run(true); got != 2 {
+		t.Errorf("実行回数が想定外です。got=%d, want=2", got)
+	}
+}
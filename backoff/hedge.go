@@ -0,0 +1,69 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// hedgeResult 1回分の実行結果
+type hedgeResult[T any] struct {
+	v   T
+	err error
+}
+
+// Hedge は op を実行し、hedgeDelay 以内に結果が返らない場合は同じ処理を追加で実行する
+// 追加の実行は最大 maxHedges 回で、最初に成功した結果を返し、残りの実行の ctx はキャンセルする。
+// 実行中の処理が失敗した場合は hedgeDelay を待たずに次の実行を開始する。すべて失敗した場合は最後のエラーを返す。
+// 同じ処理を重複して実行するため、Redis や MySQL の読み取りなど冪等な処理のテールレイテンシ改善にのみ使用すること。
+func Hedge[T any](ctx context.Context, op func(ctx context.Context) (T, error), hedgeDelay time.Duration, maxHedges uint) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	// 返却時に実行中の処理をキャンセルする
+	defer cancel()
+
+	// 送信でゴルーチンが止まらないよう、実行しうる回数分のバッファを持つ
+	results := make(chan hedgeResult[T], maxHedges+1)
+	launch := func() {
+		go func() {
+			v, err := op(ctx)
+			results <- hedgeResult[T]{v: v, err: err}
+		}()
+	}
+
+	launch()
+	launched, pending := uint(1), 1
+
+	timer := time.NewTimer(hedgeDelay)
+	defer timer.Stop()
+
+	var (
+		zero    T
+		lastErr error
+	)
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				return r.v, nil
+			}
+			lastErr = r.err
+			if launched <= maxHedges {
+				launch()
+				launched++
+				pending++
+				timer.Reset(hedgeDelay)
+			} else if pending == 0 {
+				return zero, lastErr
+			}
+		case <-timer.C:
+			if launched <= maxHedges {
+				launch()
+				launched++
+				pending++
+				timer.Reset(hedgeDelay)
+			}
+		case <-ctx.Done():
+			return zero, context.Cause(ctx)
+		}
+	}
+}
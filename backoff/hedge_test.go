@@ -0,0 +1,96 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedge(t *testing.T) {
+	errFail := errors.New("常にエラー")
+
+	tests := []struct {
+		name      string
+		maxHedges uint
+		// op は n 回目（1から）の実行を行う
+		op          func(ctx context.Context, n int32) (int32, error)
+		want        int32
+		wantErr     error
+		wantLaunch  int32
+		maxDuration time.Duration
+	}{
+		{
+			name:      "正常値: 最初の実行が間に合う",
+			maxHedges: 2,
+			op: func(ctx context.Context, n int32) (int32, error) {
+				return n, nil
+			},
+			want:        1,
+			wantLaunch:  1,
+			maxDuration: 100 * time.Millisecond,
+		},
+		{
+			name:      "正常値: 遅い実行を追い越す",
+			maxHedges: 2,
+			op: func(ctx context.Context, n int32) (int32, error) {
+				if n == 1 {
+					<-ctx.Done()
+					return 0, ctx.Err()
+				}
+				return n, nil
+			},
+			want:        2,
+			wantLaunch:  2,
+			maxDuration: 200 * time.Millisecond,
+		},
+		{
+			name:      "異常値: すべて失敗",
+			maxHedges: 2,
+			op: func(ctx context.Context, n int32) (int32, error) {
+				return 0, errFail
+			},
+			wantErr:     errFail,
+			wantLaunch:  3,
+			maxDuration: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := int32(0)
+			start := time.Now()
+			got, err := Hedge(context.Background(), func(ctx context.Context) (int32, error) {
+				return tt.op(ctx, atomic.AddInt32(&counter, 1))
+			}, 20*time.Millisecond, tt.maxHedges)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("戻り値が想定外です。got=%d, want=%d", got, tt.want)
+			}
+			if n := atomic.LoadInt32(&counter); n != tt.wantLaunch {
+				t.Errorf("実行回数が想定外です。got=%d, want=%d", n, tt.wantLaunch)
+			}
+			if elapsed := time.Since(start); elapsed > tt.maxDuration {
+				t.Errorf("時間がかかりすぎています。elapsed=%v", elapsed)
+			}
+		})
+	}
+}
+
+// ctx が終了した場合のテスト
+func TestHedge_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err := Hedge(ctx, func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, 10*time.Millisecond, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("エラーが想定外です。got=%v", err)
+	}
+}
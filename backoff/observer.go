@@ -0,0 +1,91 @@
+package backoff
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Attempt 失敗した1回分の実行の情報
+type Attempt struct {
+	// Number 失敗した実行の回数（最初の実行は 1）
+	Number uint
+	// Delay 次のリトライまでの間隔
+	Delay time.Duration
+	// Err 失敗した実行のエラー
+	Err error
+	// Elapsed Exec の開始からの経過時間
+	Elapsed time.Duration
+}
+
+// Outcome Exec 1回分の結果
+type Outcome struct {
+	// Attempts 実行した回数
+	Attempts uint
+	// Elapsed Exec の開始から終了までの時間
+	Elapsed time.Duration
+	// Err 最終的なエラー（成功した場合は nil）
+	Err error
+}
+
+// Observer リトライの状況を受け取る
+// OnRetry はリトライの前に、OnFinish は Exec の終了時に呼ばれる。
+// 同じ Observer を複数の BackoffWrapper で共有する場合、同時に呼ばれることがある。
+type Observer interface {
+	OnRetry(a Attempt)
+	OnFinish(o Outcome)
+}
+
+// WithObserver リトライの状況を observer に通知する
+func WithObserver(observer Observer) Option {
+	return func(s *settings) {
+		s.observer = observer
+	}
+}
+
+// Metrics リトライの状況を集計する Observer
+// 複数の BackoffWrapper で共有し、Snapshot でリトライの多発を監視できる。
+type Metrics struct {
+	executions atomic.Uint64
+	failures   atomic.Uint64
+	attempts   atomic.Uint64
+	retries    atomic.Uint64
+	elapsed    atomic.Int64
+}
+
+// MetricsSnapshot Metrics のある時点の集計値
+type MetricsSnapshot struct {
+	// Executions 終了した Exec の回数
+	Executions uint64
+	// Failures 最終的に失敗した Exec の回数
+	Failures uint64
+	// Attempts 処理を実行した回数の合計
+	Attempts uint64
+	// Retries リトライした回数の合計
+	Retries uint64
+	// Elapsed 終了した Exec の所要時間の合計
+	Elapsed time.Duration
+}
+
+func (m *Metrics) OnRetry(Attempt) {
+	m.retries.Add(1)
+}
+
+func (m *Metrics) OnFinish(o Outcome) {
+	m.executions.Add(1)
+	if o.Err != nil {
+		m.failures.Add(1)
+	}
+	m.attempts.Add(uint64(o.Attempts))
+	m.elapsed.Add(int64(o.Elapsed))
+}
+
+// Snapshot は現在の集計値を返す
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Executions: m.executions.Load(),
+		Failures:   m.failures.Load(),
+		Attempts:   m.attempts.Load(),
+		Retries:    m.retries.Load(),
+		Elapsed:    time.Duration(m.elapsed.Load()),
+	}
+}
@@ -0,0 +1,144 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+// Retrier 1回分のリトライの状態
+// 処理をクロージャにまとめずに、既存のループからリトライ間隔と回数の判定だけを利用できる。
+//
+//	r := backoff.NewRetrier(ctx, backoff.WithMaxElapsedTime(time.Minute))
+//	for {
+//		conn, err = dial()
+//		if !r.Wait(err) {
+//			break
+//		}
+//	}
+//	return conn, r.Err()
+//
+// Option の意味は BackoffWrapper と同じ。1つの Retrier を複数のゴルーチンから使用しないこと。
+type Retrier struct {
+	ctx      context.Context
+	settings settings
+	strategy Strategy
+	start    time.Time
+	attempts uint
+	err      error
+	done     bool
+}
+
+// NewRetrier は Option を指定して Retrier を生成する
+// 指定しない項目は NewBackoffWithOptions と同じ初期値となる。
+func NewRetrier(ctx context.Context, opts ...Option) *Retrier {
+	return newRetrier(ctx, newSettings(opts...))
+}
+
+func newRetrier(ctx context.Context, s settings) *Retrier {
+	r := &Retrier{ctx: ctx, settings: s, start: time.Now()}
+	r.strategy = s.newStrategy(func() error { return r.err })
+	r.strategy.Reset()
+	return r
+}
+
+// NextDelay は直前の実行のエラー err から、次のリトライまでの間隔を返す
+// リトライしない場合（err が nil の場合を含む）は false を返し、以降の呼び出しも false を返す。
+// 待機は行わないため、呼び出し側で間隔を待ってから次の実行を行うこと。
+func (r *Retrier) NextDelay(err error) (time.Duration, bool) {
+	if r.done {
+		return 0, false
+	}
+	r.attempts++
+	r.err = err
+
+	if err == nil {
+		if r.settings.budget != nil {
+			r.settings.budget.Deposit()
+		}
+		r.finish(nil)
+		return 0, false
+	}
+
+	var permanent *backoff.PermanentError
+	if errors.As(err, &permanent) {
+		r.finish(permanent.Unwrap())
+		return 0, false
+	}
+	if r.settings.retryIf != nil && !r.settings.retryIf(err) {
+		r.finish(err)
+		return 0, false
+	}
+	if n := r.settings.maxTries; n != nil && *n > 0 && r.attempts >= *n {
+		r.finish(err)
+		return 0, false
+	}
+	if cerr := context.Cause(r.ctx); cerr != nil {
+		r.finish(cerr)
+		return 0, false
+	}
+
+	next := r.strategy.NextBackOff()
+	if next == backoff.Stop {
+		r.finish(err)
+		return 0, false
+	}
+
+	maxElapsed := backoff.DefaultMaxElapsedTime
+	if r.settings.maxElapsedTime != nil {
+		maxElapsed = *r.settings.maxElapsedTime
+	}
+	if maxElapsed > 0 && time.Since(r.start)+next > maxElapsed {
+		r.finish(err)
+		return 0, false
+	}
+
+	if r.settings.notify != nil {
+		r.settings.notify(err, next)
+	}
+	if r.settings.observer != nil {
+		r.settings.observer.OnRetry(Attempt{Number: r.attempts, Delay: next, Err: err, Elapsed: time.Since(r.start)})
+	}
+	return next, true
+}
+
+// Wait は NextDelay で求めた間隔だけ待機し、リトライする場合に true を返す
+// ctx が終了した場合は待機を中断して false を返す。
+func (r *Retrier) Wait(err error) bool {
+	next, ok := r.NextDelay(err)
+	if !ok {
+		return false
+	}
+
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.ctx.Done():
+		r.finish(context.Cause(r.ctx))
+		return false
+	}
+}
+
+// Attempts はこれまでに NextDelay に渡した実行の回数を返す
+func (r *Retrier) Attempts() uint {
+	return r.attempts
+}
+
+// Err は最終的なエラーを返す
+// 成功した場合は nil、Permanent で包んだエラーは包む前のエラー、ctx が終了した場合は ctx のエラーを返す。
+func (r *Retrier) Err() error {
+	return r.err
+}
+
+// finish はリトライを終了する
+func (r *Retrier) finish(err error) {
+	r.done = true
+	r.err = err
+	if r.settings.observer != nil {
+		r.settings.observer.OnFinish(Outcome{Attempts: r.attempts, Elapsed: time.Since(r.start), Err: err})
+	}
+}
@@ -0,0 +1,95 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// 手書きのループからリトライの判定を利用するテスト
+func TestRetrier(t *testing.T) {
+	errFail := errors.New("一時エラー")
+	errInvalid := errors.New("検証エラー")
+
+	tests := []struct {
+		name         string
+		results      []error
+		wantAttempts uint
+		wantErr      error
+	}{
+		{
+			name:         "正常値: リトライ後に成功",
+			results:      []error{errFail, errFail, nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "異常値: 回数の上限",
+			results:      []error{errFail, errFail, errFail, errFail},
+			wantAttempts: 3,
+			wantErr:      errFail,
+		},
+		{
+			name:         "異常値: Permanent",
+			results:      []error{errFail, Permanent(errInvalid)},
+			wantAttempts: 2,
+			wantErr:      errInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			r := NewRetrier(context.Background(),
+				WithInitialInterval(time.Millisecond),
+				WithRandomizationFactor(0),
+				WithMultiplier(2),
+				WithMaxTries(3),
+			)
+
+			var err error
+			for i := 0; ; i++ {
+				err = tt.results[i]
+				d, ok := r.NextDelay(err)
+				if !ok {
+					break
+				}
+				delays = append(delays, d)
+				time.Sleep(d)
+			}
+
+			if !errors.Is(r.Err(), tt.wantErr) || (tt.wantErr == nil && r.Err() != nil) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v", r.Err(), tt.wantErr)
+			}
+			if r.Attempts() != tt.wantAttempts {
+				t.Errorf("実行回数が想定外です。got=%d, want=%d", r.Attempts(), tt.wantAttempts)
+			}
+			for i, d := range delays {
+				if want := time.Millisecond << i; d != want {
+					t.Errorf("間隔が想定外です。got=%v, want=%v", d, want)
+				}
+			}
+			if _, ok := r.NextDelay(errFail); ok {
+				t.Error("終了後にリトライしています")
+			}
+		})
+	}
+}
+
+// 待機中に ctx が終了した場合のテスト
+func TestRetrier_WaitCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	r := NewRetrier(ctx, WithInitialInterval(time.Second))
+	start := time.Now()
+	if r.Wait(errors.New("一時エラー")) {
+		t.Error("ctx の終了後にリトライしています")
+	}
+	if !errors.Is(r.Err(), context.DeadlineExceeded) {
+		t.Errorf("エラーが想定外です。got=%v", r.Err())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("待機が中断されていません。elapsed=%v", elapsed)
+	}
+}
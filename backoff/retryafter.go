@@ -0,0 +1,83 @@
+package backoff
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+// RetryAfterer 次のリトライまでの間隔を指定するエラー
+// HTTP 429 や 503 の Retry-After ヘッダーなど、サーバーが指定した待ち時間をエラーに持たせる場合に実装する。
+type RetryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// retryAfterError 待ち時間を付加したエラー
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.after
+}
+
+// RetryAfter は次のリトライまでの間隔として d を指定したエラーとして err を包む
+// WithRetryAfter を指定した BackoffWrapper は、間隔の算出方法に関わらず d 後にリトライする。
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, after: d}
+}
+
+// DelayFromRetryAfter は err が RetryAfterer を含む場合、その待ち時間を返す
+func DelayFromRetryAfter(err error) (time.Duration, bool) {
+	var r RetryAfterer
+	if !errors.As(err, &r) {
+		return 0, false
+	}
+	return r.RetryAfter(), true
+}
+
+// WithRetryAfter エラーが RetryAfterer を含む場合、その待ち時間でリトライする
+// レート制限のある外部 API を呼ぶ場合に、サーバーの指定した間隔を守るために使用する。
+func WithRetryAfter() Option {
+	return WithErrorDelay(DelayFromRetryAfter)
+}
+
+// WithErrorDelay 失敗した処理のエラーからリトライ間隔を求める
+// f が true を返した場合は、その間隔を算出方法による間隔の代わりに使用する。
+// 間隔の上限（WithMaxInterval）は適用しないが、経過時間の上限（WithMaxElapsedTime）は適用する。
+func WithErrorDelay(f func(error) (time.Duration, bool)) Option {
+	return func(s *settings) {
+		s.errorDelay = f
+	}
+}
+
+// errorDelayed エラーから求めた間隔を優先する
+type errorDelayed struct {
+	Strategy
+	delay   func(error) (time.Duration, bool)
+	lastErr func() error
+}
+
+func (e errorDelayed) NextBackOff() time.Duration {
+	next := e.Strategy.NextBackOff()
+	if next == backoff.Stop {
+		return next
+	}
+	if d, ok := e.delay(e.lastErr()); ok && d >= 0 {
+		return d
+	}
+	return next
+}
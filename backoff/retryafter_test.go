@@ -0,0 +1,77 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// サーバーが指定した間隔でリトライするテスト
+func TestBackoffWrapper_RetryAfter(t *testing.T) {
+	errLimited := errors.New("429 Too Many Requests")
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  error
+		want time.Duration
+	}{
+		{
+			name: "正常値: RetryAfter の間隔",
+			opts: []Option{WithRetryAfter()},
+			err:  RetryAfter(errLimited, 30*time.Millisecond),
+			want: 30 * time.Millisecond,
+		},
+		{
+			name: "正常値: 指定しない場合は算出方法の間隔",
+			err:  RetryAfter(errLimited, 30*time.Millisecond),
+			want: time.Millisecond,
+		},
+		{
+			name: "正常値: RetryAfterer を含まないエラー",
+			opts: []Option{WithRetryAfter()},
+			err:  errLimited,
+			want: time.Millisecond,
+		},
+		{
+			name: "正常値: エラーから求める",
+			opts: []Option{WithErrorDelay(func(err error) (time.Duration, bool) {
+				return 5 * time.Millisecond, errors.Is(err, errLimited)
+			})},
+			err:  errLimited,
+			want: 5 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delays []time.Duration
+			bw := NewBackoffWithInterval[any](context.Background(), time.Millisecond, time.Millisecond, 0, 1, 3)
+			bw.Apply(tt.opts...)
+			bw.SetDoOperation(func() (any, error) {
+				return nil, tt.err
+			})
+			bw.SetNotify(func(err error, d time.Duration) {
+				delays = append(delays, d)
+			})
+
+			_, err := bw.Exec()
+			if !errors.Is(err, errLimited) {
+				t.Errorf("エラーが想定外です。got=%v", err)
+			}
+			if len(delays) != 2 {
+				t.Fatalf("リトライ回数が想定外です。got=%v", delays)
+			}
+			for _, d := range delays {
+				if d != tt.want {
+					t.Errorf("間隔が想定外です。got=%v, want=%v", d, tt.want)
+				}
+			}
+		})
+	}
+
+	if RetryAfter(nil, time.Second) != nil {
+		t.Error("RetryAfter(nil) は nil を返す必要があります")
+	}
+}
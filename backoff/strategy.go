@@ -0,0 +1,109 @@
+package backoff
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+)
+
+// Strategy リトライ間隔の算出方法
+// NextBackOff は次のリトライまでの間隔を返し、backoff.Stop を返すとリトライをやめる。
+// Reset は Exec の開始時に呼ばれる。
+type Strategy = backoff.BackOff
+
+// WithStrategy リトライ間隔の算出方法を指数バックオフから strategy に置き換える
+// 1つの Strategy は状態を持つため、同時に実行する BackoffWrapper 間で共有しないこと。
+func WithStrategy(strategy Strategy) Option {
+	return func(s *settings) {
+		s.strategy = strategy
+	}
+}
+
+// NewConstant 常に interval の間隔でリトライする
+// 一定間隔で状態を確認するポーリングに使用する。
+func NewConstant(interval time.Duration) Strategy {
+	return backoff.NewConstantBackOff(interval)
+}
+
+// linear 間隔を一定量ずつ伸ばす
+type linear struct {
+	initial time.Duration
+	step    time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+// NewLinear initial から始めて、リトライごとに step ずつ間隔を伸ばす
+// max が 0 より大きい場合は max を上限とする。バッチ処理など、緩やかに間隔を広げたい場合に使用する。
+func NewLinear(initial, step, max time.Duration) Strategy {
+	l := &linear{initial: initial, step: step, max: max}
+	l.Reset()
+	return l
+}
+
+func (l *linear) NextBackOff() time.Duration {
+	next := l.current
+	l.current += l.step
+	if l.max > 0 && next > l.max {
+		return l.max
+	}
+	return next
+}
+
+func (l *linear) Reset() {
+	l.current = l.initial
+}
+
+// decorrelatedJitter AWS の Decorrelated Jitter
+type decorrelatedJitter struct {
+	mu    sync.Mutex
+	base  time.Duration
+	max   time.Duration
+	sleep time.Duration
+}
+
+// NewDecorrelatedJitter base から前回の間隔の3倍までの乱数を間隔とする（max を上限とする）
+// 多数のクライアントが同時に失敗した場合も、リトライの時刻が分散して一斉に再接続しない。
+func NewDecorrelatedJitter(base, max time.Duration) Strategy {
+	d := &decorrelatedJitter{base: base, max: max}
+	d.Reset()
+	return d
+}
+
+func (d *decorrelatedJitter) NextBackOff() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	upper := d.sleep * 3
+	next := d.base
+	if upper > d.base {
+		next += rand.N(upper - d.base)
+	}
+	if d.max > 0 && next > d.max {
+		next = d.max
+	}
+	d.sleep = next
+	return next
+}
+
+func (d *decorrelatedJitter) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sleep = d.base
+}
+
+// capped リトライ間隔を max で打ち切る
+type capped struct {
+	Strategy
+	max time.Duration
+}
+
+func (c capped) NextBackOff() time.Duration {
+	next := c.Strategy.NextBackOff()
+	if next != backoff.Stop && next > c.max {
+		return c.max
+	}
+	return next
+}
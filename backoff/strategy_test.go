@@ -0,0 +1,90 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		want     []time.Duration
+	}{
+		{
+			name:     "正常値: 一定間隔",
+			strategy: NewConstant(10 * time.Millisecond),
+			want:     []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+		},
+		{
+			name:     "正常値: 線形に伸びる",
+			strategy: NewLinear(10*time.Millisecond, 5*time.Millisecond, 0),
+			want:     []time.Duration{10 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:     "正常値: 線形の上限",
+			strategy: NewLinear(10*time.Millisecond, 10*time.Millisecond, 25*time.Millisecond),
+			want:     []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond},
+		},
+		{
+			name:     "正常値: 上限で打ち切る",
+			strategy: capped{Strategy: NewLinear(10*time.Millisecond, 10*time.Millisecond, 0), max: 15 * time.Millisecond},
+			want:     []time.Duration{10 * time.Millisecond, 15 * time.Millisecond, 15 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				// Reset 後は最初の間隔に戻る
+				tt.strategy.Reset()
+				for j, want := range tt.want {
+					if got := tt.strategy.NextBackOff(); got != want {
+						t.Errorf("%d回目の間隔が想定外です。got=%v, want=%v", j+1, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	base, limit := 10*time.Millisecond, 100*time.Millisecond
+	s := NewDecorrelatedJitter(base, limit)
+
+	prev := base
+	for i := 0; i < 1000; i++ {
+		got := s.NextBackOff()
+		if got < base || got > limit || got > max(prev*3, base) {
+			t.Fatalf("間隔が範囲外です。got=%v, prev=%v", got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestBackoffWrapper_WithStrategy(t *testing.T) {
+	var delays []time.Duration
+	bw := NewBackoffWithInterval[any](context.Background(), time.Second, time.Second, 0, 2, 4)
+	bw.Apply(WithStrategy(NewLinear(time.Millisecond, time.Millisecond, 0)), WithMaxInterval(2*time.Millisecond))
+	bw.SetDoOperation(func() (any, error) {
+		return nil, errors.New("常にエラー")
+	})
+	bw.SetNotify(func(err error, d time.Duration) {
+		delays = append(delays, d)
+	})
+
+	if _, err := bw.Exec(); err == nil {
+		t.Error("エラーが返されていません")
+	}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("リトライ回数が想定外です。got=%v, want=%v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("間隔が想定外です。got=%v, want=%v", delays, want)
+		}
+	}
+}
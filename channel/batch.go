@@ -0,0 +1,66 @@
+package channel
+
+import (
+	"context"
+	"time"
+)
+
+// Batch は入力チャネルの値を収集し、maxSize 個に達するか、バッチの最初の値を受け取ってから maxWait が経過した時点でスライスとして送ります。
+// 入力チャネルが閉じられた場合は収集途中の値を送ってから出力チャネルを閉じます。ctx がキャンセルされた場合は収集途中の値を破棄して閉じます。
+// 空のバッチは送りません。
+func Batch[T any](ctx context.Context, in <-chan T, maxSize int, maxWait time.Duration) <-chan []T {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	batchStream := make(chan []T)
+	go func() {
+		defer close(batchStream)
+
+		var batch []T
+		// nil のチャネルは受信できないので、バッチが空の間はタイムアウトしない
+		var timeout <-chan time.Time
+
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case batchStream <- batch:
+			case <-ctx.Done():
+				return false
+			}
+			batch = nil
+			timeout = nil
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if len(batch) == 0 {
+					timeout = time.After(maxWait)
+				}
+				batch = append(batch, v)
+
+				// 最大バッチサイズに到達した場合
+				if len(batch) >= maxSize && !flush() {
+					return
+				}
+			// タイムアウトの場合、バッチが満杯になるのを待たない
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return batchStream
+}
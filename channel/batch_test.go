@@ -0,0 +1,91 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBatch_size は、maxSize に達した時点でバッチが送られ、入力 close 時に残りが送られることを検証します。
+func TestBatch_size(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	out := Batch[int](ctx, in, 2, time.Hour)
+
+	go func() {
+		defer close(in)
+		for i := 1; i <= 5; i++ {
+			in <- i
+		}
+	}()
+
+	var got [][]int
+	deadline := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case b, ok := <-out:
+			if !ok {
+				done = true
+				break
+			}
+			got = append(got, b)
+		case <-deadline:
+			t.Fatalf("timeout: got=%v", got)
+		}
+	}
+
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("want %v, got %v", want, got)
+			}
+		}
+	}
+}
+
+// TestBatch_timeout は、maxSize に満たなくても maxWait 経過でバッチが送られることを検証します。
+func TestBatch_timeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	out := Batch[int](ctx, in, 10, 50*time.Millisecond)
+
+	in <- 1
+	in <- 2
+
+	select {
+	case b := <-out:
+		if len(b) != 2 || b[0] != 1 || b[1] != 2 {
+			t.Fatalf("want [1 2], got %v", b)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected batch to flush after maxWait")
+	}
+
+	// 空のままでは送られない
+	select {
+	case b := <-out:
+		t.Fatalf("unexpected batch: %v", b)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("expected out to be closed after ctx cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close after ctx cancel")
+	}
+}
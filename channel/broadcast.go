@@ -0,0 +1,172 @@
+package channel
+
+import (
+	"context"
+	"sync"
+)
+
+// SlowConsumerPolicy は購読者のバッファが埋まっている場合の振る舞い
+type SlowConsumerPolicy int
+
+const (
+	// Block は購読者が受信するまで配信を待つ。遅い購読者がいると全体が待たされる
+	Block SlowConsumerPolicy = iota
+	// DropNewest は購読者のバッファが埋まっている場合、新しい値を破棄する
+	DropNewest
+	// DropOldest は購読者のバッファが埋まっている場合、最も古い値を破棄して新しい値を入れる
+	DropOldest
+)
+
+// Broadcaster は入力チャネルの値を、Subscribe したすべての購読者に複製して配信します。
+// 入力チャネルが閉じられるか ctx がキャンセルされると、すべての購読者のチャネルを閉じます。
+type Broadcaster[T any] struct {
+	mu     sync.Mutex
+	subs   map[<-chan T]*subscriber[T]
+	buffer int
+	policy SlowConsumerPolicy
+	closed bool
+}
+
+// subscriber は購読者ごとのチャネル
+// mu は配信とチャネルの close が同時に起きないようにするためのもの。
+type subscriber[T any] struct {
+	mu     sync.Mutex
+	c      chan T
+	done   chan struct{}
+	once   sync.Once
+	closed bool
+}
+
+// NewBroadcaster は in の値を購読者へ配信する Broadcaster を生成し、配信を開始します。
+// buffer は購読者ごとのチャネルのバッファ数です。DropNewest と DropOldest はバッファが無いと値を保持できないため、1 未満の場合は 1 にします。
+func NewBroadcaster[T any](ctx context.Context, in <-chan T, buffer int, policy SlowConsumerPolicy) *Broadcaster[T] {
+	if buffer < 0 {
+		buffer = 0
+	}
+	if buffer < 1 && (policy == DropNewest || policy == DropOldest) {
+		buffer = 1
+	}
+
+	b := &Broadcaster[T]{
+		subs:   make(map[<-chan T]*subscriber[T]),
+		buffer: buffer,
+		policy: policy,
+	}
+
+	go func() {
+		defer b.closeAll()
+		for v := range OrDone(ctx, in) {
+			b.publish(ctx, v)
+		}
+	}()
+
+	return b
+}
+
+// Subscribe は新しい購読者を登録し、値を受け取るチャネルを返します。
+// Broadcaster が既に終了している場合は閉じられたチャネルを返します。
+func (b *Broadcaster[T]) Subscribe() <-chan T {
+	s := &subscriber[T]{c: make(chan T, b.buffer), done: make(chan struct{})}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.close()
+		return s.c
+	}
+	b.subs[s.c] = s
+	return s.c
+}
+
+// Unsubscribe は購読を解除し、購読者のチャネルを閉じます。
+func (b *Broadcaster[T]) Unsubscribe(c <-chan T) {
+	b.mu.Lock()
+	s, ok := b.subs[c]
+	delete(b.subs, c)
+	b.mu.Unlock()
+
+	if ok {
+		s.close()
+	}
+}
+
+// Len は現在の購読者数を返します。
+func (b *Broadcaster[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// publish は現在の購読者すべてに値を配信します。
+func (b *Broadcaster[T]) publish(ctx context.Context, v T) {
+	b.mu.Lock()
+	subs := make([]*subscriber[T], 0, len(b.subs))
+	for _, s := range b.subs {
+		subs = append(subs, s)
+	}
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.send(ctx, v, b.policy)
+	}
+}
+
+// closeAll はすべての購読者のチャネルを閉じ、以降の Subscribe を受け付けなくします。
+func (b *Broadcaster[T]) closeAll() {
+	b.mu.Lock()
+	b.closed = true
+	subs := b.subs
+	b.subs = make(map[<-chan T]*subscriber[T])
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+}
+
+// send はポリシーに従って購読者へ値を送ります。
+func (s *subscriber[T]) send(ctx context.Context, v T, policy SlowConsumerPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	switch policy {
+	case DropNewest:
+		select {
+		case s.c <- v:
+		default:
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.c <- v:
+				return
+			default:
+			}
+			// 空きが無ければ最も古い値を捨ててから再度送る
+			select {
+			case <-s.c:
+			default:
+			}
+		}
+	default:
+		select {
+		case s.c <- v:
+		case <-s.done:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// close は購読者のチャネルを閉じます。配信中で Block している場合は done で解除してから閉じます。
+func (s *subscriber[T]) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.c)
+	})
+}
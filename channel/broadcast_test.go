@@ -0,0 +1,151 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestBroadcaster は、すべての購読者に値が複製され、入力の close 後に購読者のチャネルが閉じられることを検証します。
+func TestBroadcaster(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	b := NewBroadcaster[int](ctx, in, 4, Block)
+
+	sub1 := b.Subscribe()
+	sub2 := b.Subscribe()
+	if b.Len() != 2 {
+		t.Fatalf("want 2 subscribers, got %d", b.Len())
+	}
+
+	go func() {
+		defer close(in)
+		in <- 1
+		in <- 2
+		in <- 3
+	}()
+
+	for name, sub := range map[string]<-chan int{"sub1": sub1, "sub2": sub2} {
+		got := collect(t, sub, 2*time.Second)
+		if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+			t.Fatalf("%s: want [1 2 3], got %v", name, got)
+		}
+	}
+
+	// 終了後の購読は閉じられたチャネルを返す
+	if got := collect(t, b.Subscribe(), 2*time.Second); len(got) != 0 {
+		t.Fatalf("want no values after close, got %v", got)
+	}
+}
+
+// TestBroadcaster_Unsubscribe は、購読解除でチャネルが閉じられ、Block 中の配信も解除されることを検証します。
+func TestBroadcaster_Unsubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	b := NewBroadcaster[int](ctx, in, 0, Block)
+
+	slow := b.Subscribe()
+	fast := b.Subscribe()
+
+	// slow は読まないので、Block ポリシーでは配信が詰まる
+	in <- 1
+	time.Sleep(50 * time.Millisecond)
+
+	// 購読解除で詰まりが解消され、他の購読者に配信される
+	b.Unsubscribe(slow)
+	select {
+	case <-waitClosed(slow):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected slow to close after unsubscribe")
+	}
+
+	select {
+	case v := <-fast:
+		if v != 1 {
+			t.Fatalf("want 1, got %d", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected fast to receive after slow unsubscribed")
+	}
+
+	if b.Len() != 1 {
+		t.Fatalf("want 1 subscriber, got %d", b.Len())
+	}
+}
+
+// TestBroadcaster_policy は、バッファが埋まった購読者に対する破棄ポリシーを検証します。
+func TestBroadcaster_policy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SlowConsumerPolicy
+		want   []int
+	}{
+		{name: "DropNewest", policy: DropNewest, want: []int{1, 2}},
+		{name: "DropOldest", policy: DropOldest, want: []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			in := make(chan int)
+			b := NewBroadcaster[int](ctx, in, 2, tt.policy)
+			sub := b.Subscribe()
+
+			for i := 1; i <= 5; i++ {
+				in <- i
+			}
+			// 最後の値の配信が終わるのを待ってから読む
+			time.Sleep(50 * time.Millisecond)
+			close(in)
+
+			got := collect(t, sub, 2*time.Second)
+			if len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBroadcaster_policyZeroBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SlowConsumerPolicy
+		want   int
+	}{
+		{name: "DropNewest", policy: DropNewest, want: 1},
+		{name: "DropOldest", policy: DropOldest, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			in := make(chan int)
+			b := NewBroadcaster[int](ctx, in, 0, tt.policy)
+			sub := b.Subscribe()
+
+			// 受信者がいなくても配信が止まらない
+			for i := 1; i <= 3; i++ {
+				select {
+				case in <- i:
+				case <-time.After(time.Second):
+					t.Fatal("配信が止まりました。")
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			close(in)
+
+			got := collect(t, sub, 2*time.Second)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Fatalf("want [%v], got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package channel
 
 import (
 	"context"
+	"reflect"
 )
 
 // Or 複数のチャンネルを1つに結合し、最初の入力チャンネルが閉じられた際に結果のチャンネルを閉じます。
 // 値を扱わず、どれかのシグナルに通知が来たらチャネルをCloseするので any ではなくメモリコストが0の struct{} を使用している。
+// どの入力も閉じられない限り内部のゴルーチンは終了しないため、キャンセルしたい場合は OrContext を使用してください。
 func Or(channels ...<-chan struct{}) <-chan struct{} {
 	switch len(channels) {
 	case 0:
@@ -16,23 +18,43 @@ func Or(channels ...<-chan struct{}) <-chan struct{} {
 		return channels[0]
 	}
 
+	return or(nil, channels)
+}
+
+// OrContext は Or と同様に複数のチャネルを1つに結合し、いずれかの入力が閉じられるか ctx が終了した時点で結果のチャネルを閉じます。
+// ctx の終了で内部のゴルーチンも終了するため、入力が閉じられないままでもリークしません。
+func OrContext(ctx context.Context, channels ...<-chan struct{}) <-chan struct{} {
+	return or(ctx.Done(), channels)
+}
+
+// ContextsToChans は各 ctx の Done チャネルを返します。Or や OrContext に渡すために使用します。
+func ContextsToChans(ctxs ...context.Context) []<-chan struct{} {
+	chans := make([]<-chan struct{}, len(ctxs))
+	for i, ctx := range ctxs {
+		chans[i] = ctx.Done()
+	}
+	return chans
+}
+
+// or は channels と done のいずれかが閉じられた時点で閉じるチャネルを返します。
+// 再帰的にゴルーチンを起動せず、1つのゴルーチンで reflect.Select により待ち受けます。
+func or(done <-chan struct{}, channels []<-chan struct{}) <-chan struct{} {
+	cases := make([]reflect.SelectCase, 0, len(channels)+1)
+	for _, c := range channels {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c)})
+	}
+	if done != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)})
+	}
+	if len(cases) == 0 {
+		// 待つ対象が無い場合は閉じられることのない nil を返し、ゴルーチンを起動しない
+		return nil
+	}
+
 	orDone := make(chan struct{})
 	go func() {
 		defer close(orDone)
-
-		switch len(channels) {
-		case 2:
-			select {
-			case <-channels[0]:
-			case <-channels[1]:
-			}
-		default:
-			select {
-			case <-channels[1]:
-			case <-channels[2]:
-			case <-Or(append(channels[3:], orDone)...):
-			}
-		}
+		reflect.Select(cases)
 	}()
 
 	return orDone
@@ -103,6 +125,58 @@ func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
 	return out1, out2
 }
 
+// TeeN は入力チャネルを n 個の出力チャネルに分割し、各項目をすべての出力に複製し、コンテキストキャンセルを遵守します。
+// Tee と同様に各出力はバッファを1つ持ちますが、遅い出力があると全体がその速度に引きずられます。
+// n が 0 以下の場合は入力を読まずに nil を返します。
+func TeeN[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		return nil
+	}
+
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T, 1)
+		result[i] = outs[i]
+	}
+
+	go func() {
+		defer func() {
+			for _, o := range outs {
+				close(o)
+			}
+		}()
+
+		// 送信済みの出力を nil にして select の対象から外すため、作業用にコピーを持つ
+		pending := make([]chan T, n)
+		for v := range OrDone(ctx, in) {
+			// v が nil の interface でも送れるように、ポインタ経由で Value を作る
+			val := reflect.ValueOf(&v).Elem()
+			copy(pending, outs)
+			for remain := n; remain > 0; remain-- {
+				// Tee と同様に、受信可能になった出力から順に送る
+				cases := make([]reflect.SelectCase, 0, n+1)
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+				idx := make([]int, 0, n)
+				for i, o := range pending {
+					if o == nil {
+						continue
+					}
+					cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(o), Send: val})
+					idx = append(idx, i)
+				}
+				chosen, _, _ := reflect.Select(cases)
+				if chosen == 0 {
+					return
+				}
+				pending[idx[chosen-1]] = nil
+			}
+		}
+	}()
+
+	return result
+}
+
 // Bridge はコンテキストキャンセルを尊重しつつ、複数のチャネルストリームからの値を単一の出力チャネルに多重化します。
 func Bridge[T any](ctx context.Context, chanStream <-chan <-chan T) <-chan T {
 	valStream := make(chan T)
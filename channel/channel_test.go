@@ -35,6 +35,61 @@ func Test_Or(t *testing.T) {
 	}
 }
 
+// Test_Or_first は、4つ以上の入力でも先頭のチャネルが閉じられた時点で結合されたチャネルが閉じることを検証します。
+func Test_Or_first(t *testing.T) {
+	chans := make([]chan struct{}, 4)
+	ins := make([]<-chan struct{}, len(chans))
+	for i := range chans {
+		chans[i] = make(chan struct{})
+		ins[i] = chans[i]
+	}
+
+	done := Or(ins...)
+	close(chans[0])
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for done to close after closing the first input")
+	}
+}
+
+// Test_OrContext は、入力が閉じられなくても ctx の終了で結合されたチャネルが閉じることを検証します。
+func Test_OrContext(t *testing.T) {
+	a := make(chan struct{})
+	b := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := OrContext(ctx, a, b)
+
+	select {
+	case <-done:
+		t.Fatal("done should not be closed yet")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for done to close after cancel")
+	}
+}
+
+// Test_ContextsToChans は、いずれかの ctx がキャンセルされると Or の結果が閉じることを検証します。
+func Test_ContextsToChans(t *testing.T) {
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.Background())
+
+	done := Or(ContextsToChans(ctx1, ctx2)...)
+	cancel2()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for done to close after cancel")
+	}
+}
+
 // Test_OrDone は、値が適切に転送され、コンテキストのキャンセルが正しく処理されることを確認するために OrDone 関数をテストします。
 func Test_OrDone(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -179,3 +234,56 @@ func TestTee_minimumCoverage(t *testing.T) {
 	waitClosed(out1, "out1")
 	waitClosed(out2, "out2")
 }
+
+// TestTeeN は、TeeN がすべての出力に同じ列を複製し、入力の close 後にすべての出力を閉じることを検証します。
+func TestTeeN(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan any)
+	outs := TeeN[any](ctx, in, 3)
+	if len(outs) != 3 {
+		t.Fatalf("want 3 outputs, got %d", len(outs))
+	}
+
+	go func() {
+		defer close(in)
+		in <- 1
+		in <- nil
+		in <- "three"
+	}()
+
+	results := make([][]any, len(outs))
+	done := make(chan int)
+	for i, o := range outs {
+		go func(i int, o <-chan any) {
+			for v := range o {
+				results[i] = append(results[i], v)
+			}
+			done <- i
+		}(i, o)
+	}
+
+	for range outs {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout: expected all outputs to close after input closes")
+		}
+	}
+
+	for i, got := range results {
+		if len(got) != 3 || got[0] != 1 || got[1] != nil || got[2] != "three" {
+			t.Fatalf("out%d: want [1 <nil> three], got %v", i, got)
+		}
+	}
+}
+
+// TestTeeN_nonPositive は、n が 0 以下の場合に panic せず nil を返すことを検証します。
+func TestTeeN_nonPositive(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if outs := TeeN[int](context.Background(), make(chan int), n); outs != nil {
+			t.Fatalf("n=%d: want nil, got %v", n, outs)
+		}
+	}
+}
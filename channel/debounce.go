@@ -0,0 +1,128 @@
+package channel
+
+import (
+	"context"
+	"time"
+)
+
+// Debounce は入力チャネルの値が quiet の間途切れた時点で、最後に受け取った値だけを送ります。
+// 入力チャネルが閉じられた場合は保留中の値を送ってから出力チャネルを閉じます。
+func Debounce[T any](ctx context.Context, in <-chan T, quiet time.Duration) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+
+		var pending T
+		var timer *time.Timer
+		// nil のチャネルは受信できないので、保留中の値が無い間は発火しない
+		var fire <-chan time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if fire != nil {
+						timer.Stop()
+						sendOrDone(ctx, valStream, pending)
+					}
+					return
+				}
+				pending = v
+				// 値が来るたびに待ち時間をリセットする
+				if timer == nil {
+					timer = time.NewTimer(quiet)
+				} else {
+					timer.Reset(quiet)
+				}
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				if !sendOrDone(ctx, valStream, pending) {
+					return
+				}
+			}
+		}
+	}()
+	return valStream
+}
+
+// Throttle は interval あたり最大1つの値だけを送ります。送信後 interval の間に受け取った値は破棄します。
+func Throttle[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	return throttle(ctx, in, interval, false)
+}
+
+// ThrottleLatest は interval あたり最大1つの値だけを送ります。送信後 interval の間に受け取った値は最後のものだけを保持し、
+// interval の経過後に送ります。
+func ThrottleLatest[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	return throttle(ctx, in, interval, true)
+}
+
+// throttle は Throttle / ThrottleLatest の共通実装
+func throttle[T any](ctx context.Context, in <-chan T, interval time.Duration, latest bool) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+
+		var pending T
+		hasPending := false
+		// 送信直後のみ有効。nil の間は待ち時間が無いので即時に送る
+		var cooldown <-chan time.Time
+
+		send := func(v T) bool {
+			if !sendOrDone(ctx, valStream, v) {
+				return false
+			}
+			cooldown = time.After(interval)
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					// 保留中の値は待ち時間が明けてから送る
+					if hasPending {
+						select {
+						case <-cooldown:
+							sendOrDone(ctx, valStream, pending)
+						case <-ctx.Done():
+						}
+					}
+					return
+				}
+				if cooldown == nil {
+					if !send(v) {
+						return
+					}
+					continue
+				}
+				if latest {
+					pending, hasPending = v, true
+				}
+			case <-cooldown:
+				cooldown = nil
+				if hasPending {
+					hasPending = false
+					if !send(pending) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return valStream
+}
+
+// sendOrDone は ctx のキャンセルを尊重しつつ値を送ります。送れた場合に true を返します。
+func sendOrDone[T any](ctx context.Context, c chan<- T, v T) bool {
+	select {
+	case c <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
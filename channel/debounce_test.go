@@ -0,0 +1,98 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// collect は出力チャネルが閉じられるまで値を集めます。
+func collect[T any](t *testing.T, c <-chan T, timeout time.Duration) []T {
+	t.Helper()
+	var got []T
+	deadline := time.After(timeout)
+	for {
+		select {
+		case v, ok := <-c:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-deadline:
+			t.Fatalf("timeout: got=%v", got)
+		}
+	}
+}
+
+// TestDebounce は、連続した値のうち静止期間の直前の値だけが送られることを検証します。
+func TestDebounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	out := Debounce[int](ctx, in, 50*time.Millisecond)
+
+	go func() {
+		defer close(in)
+		in <- 1
+		in <- 2
+		in <- 3
+		time.Sleep(150 * time.Millisecond)
+		in <- 4
+		in <- 5
+	}()
+
+	// 入力 close 時に保留中の値が送られる
+	got := collect(t, out, 2*time.Second)
+	if len(got) != 2 || got[0] != 3 || got[1] != 5 {
+		t.Fatalf("want [3 5], got %v", got)
+	}
+}
+
+// TestThrottle は、interval 中の値が破棄されることを検証します。
+func TestThrottle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	out := Throttle[int](ctx, in, 100*time.Millisecond)
+
+	go func() {
+		defer close(in)
+		in <- 1
+		in <- 2
+		in <- 3
+		time.Sleep(200 * time.Millisecond)
+		in <- 4
+	}()
+
+	got := collect(t, out, 2*time.Second)
+	if len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Fatalf("want [1 4], got %v", got)
+	}
+}
+
+// TestThrottleLatest は、interval 中の最後の値が interval の経過後に送られることを検証します。
+func TestThrottleLatest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	out := ThrottleLatest[int](ctx, in, 100*time.Millisecond)
+
+	start := time.Now()
+	go func() {
+		defer close(in)
+		in <- 1
+		in <- 2
+		in <- 3
+	}()
+
+	got := collect(t, out, 2*time.Second)
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("want [1 3], got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("latest value sent before interval: %v", elapsed)
+	}
+}
@@ -0,0 +1,37 @@
+package channel
+
+import (
+	"context"
+	"sync"
+)
+
+// FanIn は複数の入力チャネルの値を1つの出力チャネルに多重化します。
+// すべての入力チャネルが閉じられるか、ctx がキャンセルされると出力チャネルを閉じます。値の順序は入力チャネル間で保証されません。
+func FanIn[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	var wg sync.WaitGroup
+	valStream := make(chan T)
+
+	multiplex := func(c <-chan T) {
+		defer wg.Done()
+		for v := range OrDone(ctx, c) {
+			select {
+			case valStream <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	wg.Add(len(chans))
+	for _, c := range chans {
+		go multiplex(c)
+	}
+
+	// すべての入力の転送が終わったら閉じる
+	go func() {
+		wg.Wait()
+		close(valStream)
+	}()
+
+	return valStream
+}
@@ -0,0 +1,87 @@
+package channel
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestFanIn は、すべての入力チャネルの値が 1 つの出力に集約され、全入力の close 後に出力が閉じられることを検証します。
+func TestFanIn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gen := func(vals ...int) <-chan int {
+		c := make(chan int)
+		go func() {
+			defer close(c)
+			for _, v := range vals {
+				c <- v
+			}
+		}()
+		return c
+	}
+
+	out := FanIn[int](ctx, gen(1, 2), gen(3), gen(4, 5, 6))
+
+	var got []int
+	deadline := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case v, ok := <-out:
+			if !ok {
+				done = true
+				break
+			}
+			got = append(got, v)
+		case <-deadline:
+			t.Fatalf("timeout: got=%v", got)
+		}
+	}
+
+	sort.Ints(got)
+	want := []int{1, 2, 3, 4, 5, 6}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+// TestFanIn_cancel は、入力が閉じられなくても ctx のキャンセルで出力が閉じられることを検証します。
+func TestFanIn_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := make(chan int)
+	b := make(chan int)
+	out := FanIn[int](ctx, a, b)
+
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("expected out to be closed after ctx cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close after ctx cancel")
+	}
+}
+
+// TestFanIn_noInput は、入力チャネルが無い場合に出力がすぐに閉じられることを検証します。
+func TestFanIn_noInput(t *testing.T) {
+	out := FanIn[int](context.Background())
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Fatal("expected out to be closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close")
+	}
+}
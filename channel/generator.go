@@ -0,0 +1,60 @@
+package channel
+
+import "context"
+
+// Repeat は values を先頭から順に、ctx がキャンセルされるまで繰り返し送ります。
+// values が空の場合は何も送らずに閉じます。
+func Repeat[T any](ctx context.Context, values ...T) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+		if len(values) == 0 {
+			return
+		}
+		for {
+			for _, v := range values {
+				if !sendOrDone(ctx, valStream, v) {
+					return
+				}
+			}
+		}
+	}()
+	return valStream
+}
+
+// RepeatFn は fn を繰り返し呼び出し、その結果を ctx がキャンセルされるまで送ります。
+func RepeatFn[T any](ctx context.Context, fn func() T) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+		for {
+			if !sendOrDone(ctx, valStream, fn()) {
+				return
+			}
+		}
+	}()
+	return valStream
+}
+
+// Take は入力チャネルから先頭の n 個だけを送り、出力チャネルを閉じます。
+// n 個に達する前に入力チャネルが閉じられるか ctx がキャンセルされた場合も閉じます。
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+		for i := 0; i < n; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				if !sendOrDone(ctx, valStream, v) {
+					return
+				}
+			}
+		}
+	}()
+	return valStream
+}
@@ -0,0 +1,71 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRepeatTake は、Repeat の値が繰り返され、Take で先頭 n 個だけ取り出せることを検証します。
+func TestRepeatTake(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := collect(t, Take(ctx, Repeat(ctx, 1, 2, 3), 7), 2*time.Second)
+	want := []int{1, 2, 3, 1, 2, 3, 1}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+// TestRepeatFn は、RepeatFn が fn の結果を順に送ることを検証します。
+func TestRepeatFn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := 0
+	got := collect(t, Take(ctx, RepeatFn(ctx, func() int { n++; return n }), 3), 2*time.Second)
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("want [1 2 3], got %v", got)
+	}
+}
+
+// TestTake_shortInput は、n 個に満たないまま入力が閉じられた場合に出力が閉じられることを検証します。
+func TestTake_shortInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	close(in)
+
+	got := collect(t, Take(ctx, in, 5), 2*time.Second)
+	if len(got) != 2 {
+		t.Fatalf("want 2 values, got %v", got)
+	}
+
+	// 値が無い場合の Repeat はすぐに閉じる
+	if got := collect(t, Repeat[int](ctx), 2*time.Second); len(got) != 0 {
+		t.Fatalf("want no values, got %v", got)
+	}
+}
+
+// TestRepeat_cancel は、ctx のキャンセルで Repeat の出力が閉じられることを検証します。
+func TestRepeat_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Repeat(ctx, "a")
+	<-out
+	cancel()
+
+	select {
+	case <-waitClosed(out):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close after ctx cancel")
+	}
+}
@@ -0,0 +1,36 @@
+package channel
+
+import "context"
+
+// Map は入力チャネルの各値に fn を適用した結果を送るステージです。
+// 入力チャネルが閉じられるか ctx がキャンセルされると出力チャネルを閉じます。
+func Map[T any, R any](ctx context.Context, in <-chan T, fn func(T) R) <-chan R {
+	valStream := make(chan R)
+	go func() {
+		defer close(valStream)
+		for v := range OrDone(ctx, in) {
+			if !sendOrDone(ctx, valStream, fn(v)) {
+				return
+			}
+		}
+	}()
+	return valStream
+}
+
+// Filter は pred が true を返した値だけを送るステージです。
+// 入力チャネルが閉じられるか ctx がキャンセルされると出力チャネルを閉じます。
+func Filter[T any](ctx context.Context, in <-chan T, pred func(T) bool) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+		for v := range OrDone(ctx, in) {
+			if !pred(v) {
+				continue
+			}
+			if !sendOrDone(ctx, valStream, v) {
+				return
+			}
+		}
+	}()
+	return valStream
+}
@@ -0,0 +1,54 @@
+package channel
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestMapFilter は、Map と Filter を組み合わせたパイプラインが順序を保って値を変換することを検証します。
+func TestMapFilter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 6; i++ {
+			in <- i
+		}
+	}()
+
+	even := Filter(ctx, in, func(v int) bool { return v%2 == 0 })
+	out := Map(ctx, even, func(v int) string { return strconv.Itoa(v * 10) })
+
+	got := collect(t, out, 2*time.Second)
+	want := []string{"20", "40", "60"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+// TestMap_cancel は、出力を読まなくても ctx のキャンセルで出力が閉じられることを検証します。
+func TestMap_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan int, 1)
+	in <- 1
+	out := Map(ctx, in, func(v int) int { return v })
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-waitClosed(out):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close after ctx cancel")
+	}
+}
@@ -0,0 +1,80 @@
+package channel
+
+import (
+	"context"
+	"sync"
+)
+
+// Pool は workers 個のゴルーチンで入力チャネルの値を fn で並行処理し、結果とエラーをそれぞれのチャネルに送ります。
+// 入力チャネルが閉じられるか ctx がキャンセルされ、すべてのワーカーが終了すると結果のチャネルを閉じます。
+// エラーはワーカーを止めないよう内部で溜めてから送り、すべて送り終えた時点でエラーのチャネルを閉じます。
+// そのため結果のチャネルだけを読み切っても詰まりません。エラーのチャネルを読まない場合は、終了後に ctx をキャンセルしてください。
+// 結果の順序は入力の順序と一致しません。
+func Pool[T any, R any](ctx context.Context, in <-chan T, workers int, fn func(context.Context, T) (R, error)) (<-chan R, <-chan error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make(chan R)
+	errIn := make(chan error)
+	errs := make(chan error)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for v := range OrDone(ctx, in) {
+				r, err := fn(ctx, v)
+				if err != nil {
+					if !sendOrDone(ctx, errIn, err) {
+						return
+					}
+					continue
+				}
+				if !sendOrDone(ctx, results, r) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		defer close(errIn)
+		wg.Wait()
+	}()
+
+	go forwardErrors(ctx, errIn, errs)
+
+	return results, errs
+}
+
+// forwardErrors は in のエラーを溜めながら out に送り、in が閉じられ溜めたエラーを送り終えると out を閉じます。
+// in からは常に受信できるため、送り手は out の読み手を待たずに済みます。
+func forwardErrors(ctx context.Context, in <-chan error, out chan<- error) {
+	defer close(out)
+
+	var pending []error
+	for in != nil || len(pending) > 0 {
+		// 溜めたエラーが無い間は送信の case を nil チャネルにして無効にする
+		var send chan<- error
+		var next error
+		if len(pending) > 0 {
+			send, next = out, pending[0]
+		}
+
+		select {
+		case err, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			pending = append(pending, err)
+		case send <- next:
+			pending = pending[1:]
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPool は、すべての入力が処理され、成功は結果チャネルに、失敗はエラーチャネルに振り分けられることを検証します。
+func TestPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+	}()
+
+	var running, maxRunning int32
+	errOdd := errors.New("odd")
+	results, errs := Pool(ctx, in, 3, func(ctx context.Context, v int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if v%2 == 1 {
+			return 0, errOdd
+		}
+		return v * 10, nil
+	})
+
+	sum, errCount := 0, 0
+	deadline := time.After(5 * time.Second)
+	for results != nil || errs != nil {
+		select {
+		case r, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			sum += r
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			if !errors.Is(err, errOdd) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			errCount++
+		case <-deadline:
+			t.Fatal("timeout waiting for pool to finish")
+		}
+	}
+
+	if sum != 300 {
+		t.Fatalf("sum: want 300, got %d", sum)
+	}
+	if errCount != 5 {
+		t.Fatalf("errCount: want 5, got %d", errCount)
+	}
+	if maxRunning > 3 {
+		t.Fatalf("concurrency exceeded: %d", maxRunning)
+	}
+}
+
+// TestPool_cancel は、結果を読まなくても ctx のキャンセルでワーカーが終了し、出力チャネルが閉じられることを検証します。
+func TestPool_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+
+	results, errs := Pool(ctx, in, 2, func(ctx context.Context, v int) (int, error) {
+		return v, nil
+	})
+
+	// 結果を読まずに詰まらせてからキャンセルする
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected results to close after ctx cancel")
+	case <-waitClosed(results):
+	}
+	select {
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected errs to close after ctx cancel")
+	case <-waitClosed(errs):
+	}
+}
+
+// waitClosed はチャネルを読み捨て、閉じられたら通知します。
+func waitClosed[T any](ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+	return done
+}
+
+// TestPool_resultsOnly は、エラーのチャネルを読まずに結果のチャネルだけを読み切ってもワーカーが詰まらないことを検証します。
+func TestPool_resultsOnly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 1; i <= 10; i++ {
+			in <- i
+		}
+	}()
+
+	errFail := errors.New("fail")
+	results, errs := Pool(ctx, in, 2, func(ctx context.Context, v int) (int, error) {
+		if v%3 == 0 {
+			return 0, errFail
+		}
+		return v, nil
+	})
+
+	done := make(chan int)
+	go func() {
+		n := 0
+		for range results {
+			n++
+		}
+		done <- n
+	}()
+
+	select {
+	case n := <-done:
+		if n != 7 {
+			t.Fatalf("results: want 7, got %d", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: workers blocked on unread errors")
+	}
+
+	// 溜めたエラーは後から読める
+	errCount := 0
+	for err := range errs {
+		if !errors.Is(err, errFail) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		errCount++
+	}
+	if errCount != 3 {
+		t.Fatalf("errCount: want 3, got %d", errCount)
+	}
+}
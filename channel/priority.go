@@ -0,0 +1,54 @@
+package channel
+
+import "context"
+
+// PriorityMerge は high と low の2つのチャネルを1つに結合します。high に値がある間は常に high を優先して送り、
+// high が空のときだけ low の値を送ります。両方の入力が閉じられるか ctx がキャンセルされると出力チャネルを閉じます。
+func PriorityMerge[T any](ctx context.Context, high <-chan T, low <-chan T) <-chan T {
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+
+		// 閉じられた入力は nil にして select の対象から外す
+		for high != nil || low != nil {
+			var v T
+			var ok bool
+
+			// まず high だけを待たずに確認する
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-high:
+				if !ok {
+					high = nil
+					continue
+				}
+				if !sendOrDone(ctx, valStream, v) {
+					return
+				}
+				continue
+			default:
+			}
+
+			// high が空の場合は両方を待つ
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-high:
+				if !ok {
+					high = nil
+					continue
+				}
+			case v, ok = <-low:
+				if !ok {
+					low = nil
+					continue
+				}
+			}
+			if !sendOrDone(ctx, valStream, v) {
+				return
+			}
+		}
+	}()
+	return valStream
+}
@@ -0,0 +1,64 @@
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPriorityMerge は、両方に値がある場合は high が先に送られ、両方の close 後に出力が閉じられることを検証します。
+func TestPriorityMerge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	high := make(chan string, 3)
+	low := make(chan string, 3)
+	low <- "l1"
+	low <- "l2"
+	high <- "h1"
+	high <- "h2"
+	close(high)
+	close(low)
+
+	got := collect(t, PriorityMerge[string](ctx, high, low), 2*time.Second)
+	want := []string{"h1", "h2", "l1", "l2"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+// TestPriorityMerge_preempt は、low の処理中に来た high が、残っている low より先に送られることを検証します。
+func TestPriorityMerge_preempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	high := make(chan int, 1)
+	low := make(chan int, 3)
+	low <- 1
+	low <- 2
+	low <- 3
+
+	out := PriorityMerge[int](ctx, high, low)
+	if v := <-out; v != 1 {
+		t.Fatalf("want 1, got %d", v)
+	}
+
+	high <- 100
+	// マージ側が次の low を送信待ちにしている可能性があるので、100 は遅くとも2つ目までに来る
+	first, second := <-out, <-out
+	if first != 100 && second != 100 {
+		t.Fatalf("want 100 to preempt low values, got %d, %d", first, second)
+	}
+
+	cancel()
+	select {
+	case <-waitClosed(out):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected out to close after ctx cancel")
+	}
+}
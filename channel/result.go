@@ -0,0 +1,63 @@
+package channel
+
+import "context"
+
+// Result はパイプラインの各段で値とエラーをまとめて受け渡すための型
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// Unwrap は値とエラーを返します。
+func (r Result[T]) Unwrap() (T, error) {
+	return r.Value, r.Err
+}
+
+// Split は Result のチャネルを値とエラーのチャネルに分割します。Err が nil でない Result はエラーのチャネルへ、それ以外は値のチャネルへ送ります。
+// 入力チャネルが閉じられるか ctx がキャンセルされると両方の出力チャネルを閉じます。呼び出し側は両方のチャネルを読み切るか ctx をキャンセルしてください。
+func Split[T any](ctx context.Context, in <-chan Result[T]) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error)
+
+	go func() {
+		defer close(values)
+		defer close(errs)
+		for r := range OrDone(ctx, in) {
+			if r.Err != nil {
+				if !sendOrDone(ctx, errs, r.Err) {
+					return
+				}
+				continue
+			}
+			if !sendOrDone(ctx, values, r.Value) {
+				return
+			}
+		}
+	}()
+
+	return values, errs
+}
+
+// FirstError は複数のパイプラインのエラーチャネルを待ち受け、最初に受け取った nil 以外のエラーを返します。
+// すべてのチャネルがエラー無しで閉じられた場合は nil を、ctx が終了した場合は ctx.Err() を返します。
+// エラーを受け取った時点で待ち受けをやめるため、残りのパイプラインは呼び出し側で ctx をキャンセルして停止してください。
+func FirstError(ctx context.Context, errs ...<-chan error) error {
+	// 戻った後に FanIn のゴルーチンが残らないよう、内部用の ctx で止める
+	fanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errStream := FanIn(fanCtx, errs...)
+	for {
+		select {
+		case err, ok := <-errStream:
+			if !ok {
+				return ctx.Err()
+			}
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
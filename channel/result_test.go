@@ -0,0 +1,106 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSplit は、Result が値とエラーのチャネルに振り分けられることを検証します。
+func TestSplit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errFailed := errors.New("failed")
+	in := make(chan Result[int], 3)
+	in <- Result[int]{Value: 1}
+	in <- Result[int]{Err: errFailed}
+	in <- Result[int]{Value: 2}
+	close(in)
+
+	values, errs := Split[int](ctx, in)
+
+	var gotValues []int
+	var gotErrs []error
+	deadline := time.After(2 * time.Second)
+	for values != nil || errs != nil {
+		select {
+		case v, ok := <-values:
+			if !ok {
+				values = nil
+				continue
+			}
+			gotValues = append(gotValues, v)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			gotErrs = append(gotErrs, err)
+		case <-deadline:
+			t.Fatalf("timeout: values=%v errs=%v", gotValues, gotErrs)
+		}
+	}
+
+	if len(gotValues) != 2 || gotValues[0] != 1 || gotValues[1] != 2 {
+		t.Fatalf("want [1 2], got %v", gotValues)
+	}
+	if len(gotErrs) != 1 || !errors.Is(gotErrs[0], errFailed) {
+		t.Fatalf("want [%v], got %v", errFailed, gotErrs)
+	}
+}
+
+// TestFirstError は、最初のエラー、全チャネルの正常終了、ctx の終了それぞれの戻り値を検証します。
+func TestFirstError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		setup   func(cancel context.CancelFunc) []<-chan error
+		wantErr error
+	}{
+		{
+			name: "最初のエラーを返す",
+			setup: func(context.CancelFunc) []<-chan error {
+				a := make(chan error, 2)
+				a <- nil
+				a <- errFailed
+				// 閉じられないチャネルがあってもエラーを受け取った時点で戻る
+				return []<-chan error{a, make(chan error)}
+			},
+			wantErr: errFailed,
+		},
+		{
+			name: "すべて閉じられた場合は nil",
+			setup: func(context.CancelFunc) []<-chan error {
+				a := make(chan error)
+				b := make(chan error)
+				close(a)
+				close(b)
+				return []<-chan error{a, b}
+			},
+			wantErr: nil,
+		},
+		{
+			name: "ctx の終了",
+			setup: func(cancel context.CancelFunc) []<-chan error {
+				cancel()
+				return []<-chan error{make(chan error)}
+			},
+			wantErr: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			got := FirstError(ctx, tt.setup(cancel)...)
+			if !errors.Is(got, tt.wantErr) {
+				t.Fatalf("want %v, got %v", tt.wantErr, got)
+			}
+		})
+	}
+}
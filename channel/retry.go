@@ -0,0 +1,43 @@
+package channel
+
+import (
+	"context"
+
+	"valley-pkg/backoff"
+)
+
+// DeadLetter はリトライしても処理できなかった値と最後のエラー
+type DeadLetter[T any] struct {
+	Value T
+	Err   error
+}
+
+// ConsumeWithRetry は入力チャネルの値を順に handler で処理し、失敗した場合は policy が返す BackoffWrapper でリトライします。
+// リトライしても失敗した値は DeadLetter として出力チャネルに送り、次の値の処理に進みます。
+// policy は値ごとに呼ばれるため、毎回新しい BackoffWrapper を返してください。
+// 入力チャネルが閉じられるか ctx がキャンセルされると出力チャネルを閉じます。呼び出し側は出力チャネルを読み切るか ctx をキャンセルしてください。
+func ConsumeWithRetry[T any](ctx context.Context, in <-chan T, handler func(context.Context, T) error, policy func(context.Context) *backoff.BackoffWrapper[any]) <-chan DeadLetter[T] {
+	deadLetters := make(chan DeadLetter[T])
+	go func() {
+		defer close(deadLetters)
+		for v := range OrDone(ctx, in) {
+			b := policy(ctx)
+			b.SetDoOperation(func() (any, error) {
+				return nil, handler(ctx, v)
+			})
+
+			err := b.Run()
+			if err == nil {
+				continue
+			}
+			// キャンセルによる中断は処理失敗として扱わない
+			if ctx.Err() != nil {
+				return
+			}
+			if !sendOrDone(ctx, deadLetters, DeadLetter[T]{Value: v, Err: err}) {
+				return
+			}
+		}
+	}()
+	return deadLetters
+}
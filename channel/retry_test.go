@@ -0,0 +1,70 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"valley-pkg/backoff"
+)
+
+// TestConsumeWithRetry は、一時的な失敗はリトライで処理され、失敗し続けた値だけが DeadLetter として送られることを検証します。
+func TestConsumeWithRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errFailed := errors.New("failed")
+	attempts := map[int]int{}
+	handler := func(_ context.Context, v int) error {
+		attempts[v]++
+		switch {
+		// 1 は2回目で成功する
+		case v == 1 && attempts[v] < 2:
+			return errFailed
+		// 2 は常に失敗する
+		case v == 2:
+			return errFailed
+		}
+		return nil
+	}
+	policy := func(ctx context.Context) *backoff.BackoffWrapper[any] {
+		return backoff.NewBackoff[any](ctx, 0, 0, 1, 4)
+	}
+
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+
+	got := collect(t, ConsumeWithRetry[int](ctx, in, handler, policy), 2*time.Second)
+	if len(got) != 1 {
+		t.Fatalf("want 1 dead letter, got %v", got)
+	}
+	if got[0].Value != 2 || !errors.Is(got[0].Err, errFailed) {
+		t.Fatalf("unexpected dead letter: %+v", got[0])
+	}
+	if attempts[1] != 2 || attempts[3] != 1 {
+		t.Fatalf("unexpected attempts: %v", attempts)
+	}
+	if attempts[2] < 2 {
+		t.Fatalf("want value 2 to be retried, got %d attempts", attempts[2])
+	}
+}
+
+// TestConsumeWithRetry_cancel は、ctx のキャンセルで出力チャネルが閉じられることを検証します。
+func TestConsumeWithRetry_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := func(ctx context.Context) *backoff.BackoffWrapper[any] {
+		return backoff.NewBackoff[any](ctx, 0, 0, 1, 4)
+	}
+	out := ConsumeWithRetry[int](ctx, make(chan int), func(context.Context, int) error { return nil }, policy)
+	cancel()
+
+	select {
+	case <-waitClosed(out):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected output to be closed after cancel")
+	}
+}
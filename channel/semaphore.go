@@ -0,0 +1,150 @@
+package channel
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Semaphore は重み付きセマフォです。合計 size までの重みを同時に取得できます。
+// 待機者は取得要求の順に解放されるため、大きな重みの要求が小さな要求に追い越され続けることはありません。
+type Semaphore struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List
+}
+
+// semaphoreWaiter は取得待ちの要求
+type semaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore は合計 size までの重みを扱えるセマフォを生成します。
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{size: size}
+}
+
+// Acquire は重み n を取得します。取得できるまでブロックし、ctx が終了した場合は何も取得せずに ctx.Err() を返します。
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	// 取得できる見込みが無いので ctx の終了まで待つ
+	if n > s.size {
+		s.mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ready := make(chan struct{})
+	elem := s.waiters.PushBack(semaphoreWaiter{n: n, ready: ready})
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-ready:
+			// キャンセルと同時に取得できていた場合は返却する
+			s.cur -= n
+			s.notifyWaiters()
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// 先頭の待機者が抜けた場合、後続が取得できるようになっている可能性がある
+			if isFront && s.size > s.cur {
+				s.notifyWaiters()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire は待たずに重み n の取得を試みます。取得できた場合に true を返します。
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release は重み n を返却します。取得した以上を返却した場合は panic します。
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more than held")
+	}
+	s.notifyWaiters()
+}
+
+// notifyWaiters は先頭から取得可能な待機者を順に解放します。呼び出し側で mu を保持している必要があります。
+func (s *Semaphore) notifyWaiters() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(semaphoreWaiter)
+		if s.size-s.cur < w.n {
+			// 順番を守るため、先頭が取得できない場合は後続も解放しない
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
+
+// RateLimit は入力チャネルの値を1秒あたり perSecond 個以下のペースで送ります。
+// 入力チャネルが閉じられるか ctx がキャンセルされると出力チャネルを閉じます。perSecond が 0 以下の場合は制限しません。
+func RateLimit[T any](ctx context.Context, in <-chan T, perSecond float64) <-chan T {
+	if perSecond <= 0 {
+		return OrDone(ctx, in)
+	}
+	interval := time.Duration(float64(time.Second) / perSecond)
+
+	valStream := make(chan T)
+	go func() {
+		defer close(valStream)
+
+		next := time.Now()
+		for v := range OrDone(ctx, in) {
+			// 前回の送信から interval 経過するまで待つ
+			if wait := time.Until(next); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+			if !sendOrDone(ctx, valStream, v) {
+				return
+			}
+
+			// 入力が途切れていた間の分をまとめて放出しないよう、現在時刻を基準にする
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			next = next.Add(interval)
+		}
+	}()
+	return valStream
+}
@@ -0,0 +1,90 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestSemaphore は、重みの合計が size を超えないことと、Release で待機者が解放されることを検証します。
+func TestSemaphore(t *testing.T) {
+	ctx := context.Background()
+	s := NewSemaphore(3)
+
+	if err := s.Acquire(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.TryAcquire(2) {
+		t.Fatal("expected TryAcquire to fail when weight exceeds remaining")
+	}
+	if !s.TryAcquire(1) {
+		t.Fatal("expected TryAcquire to succeed")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		if err := s.Acquire(ctx, 3); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		close(acquired)
+	}()
+
+	s.Release(1)
+	select {
+	case <-acquired:
+		t.Fatal("acquired before enough weight was released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	s.Release(2)
+	select {
+	case <-acquired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected waiter to acquire after release")
+	}
+	s.Release(3)
+}
+
+// TestSemaphore_cancel は、ctx の終了で待機が解除され、重みが取得されないことを検証します。
+func TestSemaphore_cancel(t *testing.T) {
+	s := NewSemaphore(1)
+	if !s.TryAcquire(1) {
+		t.Fatal("expected TryAcquire to succeed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+
+	s.Release(1)
+	if !s.TryAcquire(1) {
+		t.Fatal("expected canceled waiter not to hold weight")
+	}
+}
+
+// TestRateLimit は、値が perSecond のペースを超えずに送られることを検証します。
+func TestRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		in <- i
+	}
+	close(in)
+
+	start := time.Now()
+	got := collect(t, RateLimit[int](ctx, in, 50), 5*time.Second)
+	elapsed := time.Since(start)
+
+	if len(got) != 5 {
+		t.Fatalf("want 5 values, got %v", got)
+	}
+	// 最初の値は即時なので、残り4つ分の間隔がかかる
+	if elapsed < 80*time.Millisecond {
+		t.Fatalf("rate limit not applied: %v", elapsed)
+	}
+}
@@ -0,0 +1,62 @@
+package channel
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrTimeout は送受信が指定時間内に完了しなかった場合のエラー
+var ErrTimeout = errors.New("channel operation timeout")
+
+// ErrClosed は受信中のチャネルが閉じられた場合のエラー
+var ErrClosed = errors.New("channel closed")
+
+// Recv は timeout を上限にチャネルから値を1つ受信します。
+// タイムアウトした場合は ErrTimeout、チャネルが閉じられた場合は ErrClosed、ctx が終了した場合は ctx.Err() を返します。
+// timeout が 0 以下の場合はタイムアウトしません。
+func Recv[T any](ctx context.Context, c <-chan T, timeout time.Duration) (T, error) {
+	var zero T
+	timer, stop := newTimeout(timeout)
+	defer stop()
+
+	select {
+	case v, ok := <-c:
+		if !ok {
+			return zero, ErrClosed
+		}
+		return v, nil
+	case <-timer:
+		return zero, ErrTimeout
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Send は timeout を上限にチャネルへ値を1つ送信します。
+// タイムアウトした場合は ErrTimeout、ctx が終了した場合は ctx.Err() を返します。
+// timeout が 0 以下の場合はタイムアウトしません。
+func Send[T any](ctx context.Context, c chan<- T, v T, timeout time.Duration) error {
+	timer, stop := newTimeout(timeout)
+	defer stop()
+
+	select {
+	case c <- v:
+		return nil
+	case <-timer:
+		return ErrTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newTimeout は timeout 後に発火するチャネルと、タイマーを解放する関数を返します。
+// time.After と違い、先に送受信が完了した場合にタイマーをすぐ解放できます。
+func newTimeout(timeout time.Duration) (<-chan time.Time, func()) {
+	if timeout <= 0 {
+		return nil, func() {}
+	}
+	t := time.NewTimer(timeout)
+	return t.C, func() { t.Stop() }
+}
@@ -0,0 +1,56 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRecv は、受信成功・タイムアウト・close・ctx 終了のそれぞれで期待する結果が返ることを検証します。
+func TestRecv(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan int, 1)
+	c <- 7
+	v, err := Recv(ctx, c, time.Second)
+	if err != nil || v != 7 {
+		t.Fatalf("want 7, got %d, %v", v, err)
+	}
+
+	if _, err := Recv(ctx, c, 20*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("want ErrTimeout, got %v", err)
+	}
+
+	close(c)
+	if _, err := Recv(ctx, c, time.Second); !errors.Is(err, ErrClosed) {
+		t.Fatalf("want ErrClosed, got %v", err)
+	}
+
+	canceled, cancel2 := context.WithCancel(context.Background())
+	cancel2()
+	if _, err := Recv(canceled, make(chan int), 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
+
+// TestSend は、送信成功・タイムアウト・ctx 終了のそれぞれで期待する結果が返ることを検証します。
+func TestSend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := make(chan int, 1)
+	if err := Send(ctx, c, 1, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Send(ctx, c, 2, 20*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("want ErrTimeout, got %v", err)
+	}
+
+	deadline, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if err := Send(deadline, c, 3, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+}
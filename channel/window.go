@@ -0,0 +1,50 @@
+package channel
+
+import "context"
+
+// Window は入力チャネルの直近 size 個の値をスライスとして送ります。最初のウィンドウは size 個そろった時点で送り、以降は step 個受け取るごとに送ります。
+// step が size より小さい場合、隣り合うウィンドウは値を共有します。送るスライスは毎回新しく確保するため、受信側で保持しても上書きされません。
+// size 個に満たない状態で入力チャネルが閉じられた場合は何も送らずに出力チャネルを閉じます。
+func Window[T any](ctx context.Context, in <-chan T, size, step int) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	if step < 1 {
+		step = 1
+	}
+
+	windowStream := make(chan []T)
+	go func() {
+		defer close(windowStream)
+
+		// 直近 size 個を保持するリングバッファ
+		ring := make([]T, size)
+		count, pending := 0, 0
+		for v := range OrDone(ctx, in) {
+			ring[count%size] = v
+			count++
+			if count < size {
+				continue
+			}
+
+			// 最初のウィンドウが埋まった時点で送り、以降は step 個ごとに送る
+			if count > size {
+				pending++
+				if pending < step {
+					continue
+				}
+			}
+			pending = 0
+
+			window := make([]T, size)
+			start := count % size
+			n := copy(window, ring[start:])
+			copy(window[n:], ring[:start])
+			if !sendOrDone(ctx, windowStream, window) {
+				return
+			}
+		}
+	}()
+
+	return windowStream
+}
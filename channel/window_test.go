@@ -0,0 +1,79 @@
+package channel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestWindow は、size と step の組み合わせごとに期待どおりのウィンドウが送られることを検証します。
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		step int
+		in   []int
+		want [][]int
+	}{
+		{
+			name: "1つずつずらす",
+			size: 3,
+			step: 1,
+			in:   []int{1, 2, 3, 4, 5},
+			want: [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}},
+		},
+		{
+			name: "2つずつずらす",
+			size: 3,
+			step: 2,
+			in:   []int{1, 2, 3, 4, 5, 6},
+			want: [][]int{{1, 2, 3}, {3, 4, 5}},
+		},
+		{
+			name: "重ならない",
+			size: 2,
+			step: 2,
+			in:   []int{1, 2, 3, 4, 5},
+			want: [][]int{{1, 2}, {3, 4}},
+		},
+		{
+			name: "size に満たない",
+			size: 4,
+			step: 1,
+			in:   []int{1, 2, 3},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			in := make(chan int, len(tt.in))
+			for _, v := range tt.in {
+				in <- v
+			}
+			close(in)
+
+			got := collect(t, Window[int](ctx, in, tt.size, tt.step), 2*time.Second)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestWindow_cancel は、ctx のキャンセルで出力チャネルが閉じられることを検証します。
+func TestWindow_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Window[int](ctx, make(chan int), 2, 1)
+	cancel()
+
+	select {
+	case <-waitClosed(out):
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout: expected output to be closed after cancel")
+	}
+}
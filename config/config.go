@@ -2,9 +2,14 @@ package env
 
 import (
 	"github.com/cockroachdb/errors"
+	"github.com/spf13/afero"
 	"github.com/spf13/viper"
+	"io/fs"
 	"log"
+	"os"
+	"path"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 )
@@ -14,49 +19,183 @@ const (
 	configDir = "configs"
 )
 
+// ErrConfigNotFound は WithFS で指定したファイルシステムに設定ファイルが無い場合のエラー
+var ErrConfigNotFound = errors.New("config file not found")
+
 // Read は環境変数とYAMLファイルから新規のコンフィグを取得
+// 設定ディレクトリは環境変数 APP_CONFIG_DIR、無い場合は呼び出し元のパッケージのパスから求める。
+// 取得に失敗した場合はプロセスを終了する。呼び出し側でエラーを扱う場合は ReadE を使用すること。
 func Read(config any) {
-	appEnv, err := GetAppEnv()
-	if err != nil {
-		log.Fatalf("get appEnv error: %s \n", err)
-		return
-	}
-	if err := read(config, appEnv, getConfigDirPath(2)); err != nil {
-		log.Fatalf("get config error: %s \n", err)
-		return
+	if err := readWithAppEnv(config, resolveDirPath(options{}, getConfigDirPath(2)), newOptions(nil)); err != nil {
+		log.Fatalf("%s \n", err)
 	}
 }
 
 // ReadWithConfigDirPath は環境変数と指定の設定ディレクトリ名とYAMLファイルから新規のコンフィグを取得
+// 取得に失敗した場合はプロセスを終了する。呼び出し側でエラーを扱う場合は ReadWithConfigDirPathE を使用すること。
 func ReadWithConfigDirPath(config any, cfgDirPath string) {
-	appEnv, err := GetAppEnv()
+	if err := readWithAppEnv(config, cfgDirPath, newOptions(nil)); err != nil {
+		log.Fatalf("%s \n", err)
+	}
+}
+
+// ReadE は Read と同様にコンフィグを取得し、失敗した場合はエラーを返す
+// 設定ファイルが無い場合に初期値で続行するなど、呼び出し側で扱いを決める場合に使用する。
+func ReadE(config any) error {
+	return readWithAppEnv(config, resolveDirPath(options{}, getConfigDirPath(2)), newOptions(nil))
+}
+
+// ReadWithConfigDirPathE は ReadWithConfigDirPath と同様にコンフィグを取得し、失敗した場合はエラーを返す
+func ReadWithConfigDirPathE(config any, cfgDirPath string) error {
+	return readWithAppEnv(config, cfgDirPath, newOptions(nil))
+}
+
+// MustRead は Read と同様にコンフィグを取得し、失敗した場合は panic する
+func MustRead(config any) {
+	if err := readWithAppEnv(config, resolveDirPath(options{}, getConfigDirPath(2)), newOptions(nil)); err != nil {
+		panic(err)
+	}
+}
 
+// MustReadWithConfigDirPath は ReadWithConfigDirPath と同様にコンフィグを取得し、失敗した場合は panic する
+func MustReadWithConfigDirPath(config any, cfgDirPath string) {
+	if err := readWithAppEnv(config, cfgDirPath, newOptions(nil)); err != nil {
+		panic(err)
+	}
+}
+
+// ReadWithOptions は Option で指定した複数の設定元を重ねてコンフィグを取得する
+// 優先順位は高い順に、コマンドライン引数（WithFlags）、環境変数（WithEnvPrefix）、環境ごとの設定ファイル（APP_ENV）、
+// 共通の設定ファイル（WithBaseFile）、初期値（WithDefaults）となる。
+// 設定ディレクトリは WithConfigDirPath、環境変数 APP_CONFIG_DIR、呼び出し元のパッケージのパスの順に決める。
+func ReadWithOptions(config any, opts ...Option) error {
+	o := newOptions(opts)
+	o.dirPath = resolveDirPath(o, getConfigDirPath(2))
+	return readWithAppEnv(config, o.dirPath, o)
+}
+
+// Load は ReadWithOptions と同様にコンフィグを読み込み、T の値として返す
+// WithSection を指定すると、1つの設定ファイルの一部だけを独立した型として読み込める。
+//
+//	redisCfg, err := env.Load[RedisConfig](env.WithSection("redis"))
+func Load[T any](opts ...Option) (T, error) {
+	o := newOptions(opts)
+	o.dirPath = resolveDirPath(o, getConfigDirPath(2))
+
+	var cfg T
+	if err := readWithAppEnv(&cfg, o.dirPath, o); err != nil {
+		var zero T
+		return zero, err
+	}
+	return cfg, nil
+}
+
+// readWithAppEnv は環境変数 APP_ENV の設定ファイルを読み込む
+func readWithAppEnv(config any, cfgDirPath string, o options) error {
+	appEnv, err := GetAppEnv()
 	if err != nil {
-		log.Fatalf("get appEnv error: %s \n", err)
-		return
+		return errors.Errorf("get appEnv error: %w", err)
 	}
-	if err := read(config, appEnv, cfgDirPath); err != nil {
-		log.Fatalf("get config error: %s \n", err)
-		return
+	if err := read(config, appEnv, cfgDirPath, o); err != nil {
+		return errors.Errorf("get config error: %w", err)
 	}
+	return nil
 }
 
 // read はconfigの読み込みを実施
-func read(cfg any, cfgName string, cfgDirPath string) error {
+func read(cfg any, cfgName string, cfgDirPath string, o options) error {
+	v, err := newViper(cfgName, cfgDirPath, o)
+	if err != nil {
+		return err
+	}
+	if err := bindEnvs(v, reflect.TypeOf(cfg), o.section); err != nil {
+		return errors.Errorf("bind env error: %w", err)
+	}
+	if err := expandValues(v); err != nil {
+		return errors.Errorf("expand cfg error: %w", err)
+	}
+	if o.section != "" {
+		if err := v.UnmarshalKey(o.section, cfg); err != nil {
+			return errors.Errorf("parse cfg section %q error: %w", o.section, err)
+		}
+	} else if err := v.Unmarshal(cfg); err != nil {
+		return errors.Errorf("parse cfg error: %w", err)
+	}
+	return validate(cfg)
+}
+
+// newViper は設定元を優先順位の低い順に重ねた viper を返す
+func newViper(cfgName string, cfgDirPath string, o options) (*viper.Viper, error) {
 	v := viper.New()
+	for key, value := range o.defaults {
+		v.SetDefault(key, value)
+	}
+	v.SetEnvPrefix(o.envPrefix)
+	v.SetEnvKeyReplacer(o.envKeyReplacer)
 	v.AutomaticEnv()
 
-	v.SetConfigName(cfgName)
+	if o.baseName == "" {
+		if _, err := readConfigFile(v, cfgName, cfgDirPath, o, false); err != nil {
+			return nil, errors.Errorf("read cfg error: %w", err)
+		}
+	} else {
+		if _, err := readConfigFile(v, o.baseName, cfgDirPath, o, false); err != nil {
+			return nil, errors.Errorf("read base cfg error: %w", err)
+		}
+		// 環境ごとの設定ファイルは任意
+		if _, err := readConfigFile(v, cfgName, cfgDirPath, o, true); err != nil && !isConfigNotFound(err) {
+			return nil, errors.Errorf("merge cfg error: %w", err)
+		}
+	}
+
+	if o.flags != nil {
+		if err := v.BindPFlags(o.flags); err != nil {
+			return nil, errors.Errorf("bind flags error: %w", err)
+		}
+	}
+	return v, nil
+}
+
+// setConfigFile は v が読み込む設定ファイルを cfgDirPath の name（拡張子なし）にする
+// WithFS を指定した場合は、そのファイルシステムから .yaml、.yml の順に探す。
+func setConfigFile(v *viper.Viper, name string, cfgDirPath string, o options) error {
 	v.SetConfigType("yaml")
-	v.AddConfigPath(cfgDirPath)
+	if o.fsys == nil {
+		v.SetConfigName(name)
+		v.AddConfigPath(cfgDirPath)
+		return nil
+	}
 
-	if err := v.ReadInConfig(); err != nil {
-		return errors.Errorf("read cfg error: %w", err)
+	v.SetFs(afero.FromIOFS{FS: o.fsys})
+	for _, ext := range []string{"yaml", "yml"} {
+		file := path.Join(filepath.ToSlash(cfgDirPath), name+"."+ext)
+		if _, err := fs.Stat(o.fsys, file); err == nil {
+			v.SetConfigFile(file)
+			return nil
+		}
 	}
-	if err := v.Unmarshal(cfg); err != nil {
-		return errors.Errorf("parse cfg error: %w", err)
+	return errors.Wrapf(ErrConfigNotFound, "%s in %s", name, cfgDirPath)
+}
+
+// isConfigNotFound は設定ファイルが無いことによるエラーかを返す
+func isConfigNotFound(err error) bool {
+	var notFound viper.ConfigFileNotFoundError
+	return errors.As(err, &notFound) || errors.Is(err, ErrConfigNotFound)
+}
+
+// resolveDirPath は設定ディレクトリを WithConfigDirPath、環境変数 APP_CONFIG_DIR の順に決める
+// どちらも無い場合、WithFS を指定していればそのルート、指定していなければ callerDir を返す。
+func resolveDirPath(o options, callerDir string) string {
+	if o.dirPath != "" {
+		return o.dirPath
 	}
-	return nil
+	if dir := os.Getenv(DirKey); dir != "" {
+		return dir
+	}
+	if o.fsys != nil {
+		return "."
+	}
+	return callerDir
 }
 
 // getConfigDirPath configディレクトリの取得(readでのみ使用)
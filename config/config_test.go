@@ -0,0 +1,227 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/pflag"
+)
+
+type readConfig struct {
+	Name string `mapstructure:"name"`
+	Port int    `mapstructure:"port"`
+}
+
+func TestReadWithConfigDirPathE(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    readConfig
+		wantErr bool
+	}{
+		{
+			name: "正常値",
+			body: "name: valley\nport: 8080\n",
+			want: readConfig{Name: "valley", Port: 8080},
+		},
+		{
+			name:    "異常値: 不正なYAML",
+			body:    "name: [valley\n",
+			wantErr: true,
+		},
+		{
+			name:    "異常値: 型が異なる",
+			body:    "port: abc\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), tt.body)
+
+			var got readConfig
+			err := ReadWithConfigDirPathE(&got, dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラーが想定外です。err=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("コンフィグが想定外です。got=%+v, want=%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustReadWithConfigDirPath(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("設定ファイルが無い場合に panic していません")
+		}
+	}()
+	var cfg readConfig
+	MustReadWithConfigDirPath(&cfg, t.TempDir())
+}
+
+type layeredConfig struct {
+	Name    string `mapstructure:"name"`
+	Port    int    `mapstructure:"port"`
+	Timeout int    `mapstructure:"timeout"`
+	Level   string `mapstructure:"level"`
+	Debug   bool   `mapstructure:"debug"`
+}
+
+func TestReadWithOptions(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	t.Setenv("LEVEL", "warn")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), "name: base\nport: 80\nlevel: info\ndebug: false\n")
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "port: 8080\ndebug: false\n")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Bool("debug", false, "")
+	flags.String("name", "", "")
+	if err := flags.Parse([]string{"--debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got layeredConfig
+	err := ReadWithOptions(&got,
+		WithConfigDirPath(dir),
+		WithDefaults(map[string]any{"timeout": 5, "port": 1}),
+		WithBaseFile("base"),
+		WithFlags(flags),
+	)
+	if err != nil {
+		t.Fatalf("ReadWithOptions error: %v", err)
+	}
+
+	want := layeredConfig{
+		Name:    "base", // 共通の設定ファイル（引数は未指定）
+		Port:    8080,   // 環境ごとの設定ファイル
+		Timeout: 5,      // 初期値
+		Level:   "warn", // 環境変数
+		Debug:   true,   // コマンドライン引数
+	}
+	if got != want {
+		t.Errorf("コンフィグが想定外です。got=%+v, want=%+v", got, want)
+	}
+}
+
+func TestReadWithOptions_BaseOnly(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), "name: base\n")
+
+	var got layeredConfig
+	if err := ReadWithOptions(&got, WithConfigDirPath(dir), WithBaseFile("base")); err != nil {
+		t.Fatalf("環境ごとの設定ファイルが無い場合にエラーになっています: %v", err)
+	}
+	if got.Name != "base" {
+		t.Errorf("コンフィグが想定外です。got=%+v", got)
+	}
+
+	if err := ReadWithOptions(&got, WithConfigDirPath(dir)); err == nil {
+		t.Error("共通の設定ファイルを指定しない場合はエラーになる必要があります")
+	}
+}
+
+type redisSection struct {
+	Host string `mapstructure:"host" validate:"required"`
+	Port int    `mapstructure:"port"`
+}
+
+type mysqlSection struct {
+	User string `mapstructure:"user"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	t.Setenv("REDIS_PORT", "6380")
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "name: valley\nport: 80\nredis:\n  host: localhost\n  port: 6379\nmysql:\n  user: root\n")
+
+	all, err := Load[readConfig](WithConfigDirPath(dir))
+	if err != nil || all != (readConfig{Name: "valley", Port: 80}) {
+		t.Errorf("Load の結果が想定外です。got=%+v, err=%v", all, err)
+	}
+
+	redisCfg, err := Load[redisSection](WithConfigDirPath(dir), WithSection("redis"))
+	if err != nil || redisCfg != (redisSection{Host: "localhost", Port: 6380}) {
+		t.Errorf("redis の結果が想定外です。got=%+v, err=%v", redisCfg, err)
+	}
+
+	mysqlCfg, err := Load[*mysqlSection](WithConfigDirPath(dir), WithSection("mysql"))
+	if err != nil || mysqlCfg == nil || mysqlCfg.User != "root" {
+		t.Errorf("mysql の結果が想定外です。got=%+v, err=%v", mysqlCfg, err)
+	}
+
+	if _, err := Load[redisSection](WithConfigDirPath(dir), WithSection("mysql")); !errors.Is(err, ErrValidation) {
+		t.Errorf("検証エラーになっていません。err=%v", err)
+	}
+}
+
+func TestLoad_DirPath(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+
+	envDir := t.TempDir()
+	writeFile(t, filepath.Join(envDir, DefaultEnv+".yaml"), "name: env\n")
+	optDir := t.TempDir()
+	writeFile(t, filepath.Join(optDir, DefaultEnv+".yaml"), "name: option\n")
+
+	fsys := fstest.MapFS{
+		"configs/base.yml":                {Data: []byte("name: base\nport: 80\n")},
+		"configs/" + DefaultEnv + ".yaml": {Data: []byte("name: embedded\n")},
+	}
+
+	tests := []struct {
+		name    string
+		envDir  string
+		opts    []Option
+		want    readConfig
+		wantErr error
+	}{
+		{
+			name:   "正常値: 環境変数のディレクトリ",
+			envDir: envDir,
+			want:   readConfig{Name: "env"},
+		},
+		{
+			name:   "正常値: 指定したディレクトリを優先",
+			envDir: envDir,
+			opts:   []Option{WithConfigDirPath(optDir)},
+			want:   readConfig{Name: "option"},
+		},
+		{
+			name: "正常値: fs.FS",
+			opts: []Option{WithFS(fsys), WithConfigDirPath("configs"), WithBaseFile("base")},
+			want: readConfig{Name: "embedded", Port: 80},
+		},
+		{
+			name:    "異常値: fs.FS にファイルが無い",
+			opts:    []Option{WithFS(fsys)},
+			wantErr: ErrConfigNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DirKey, tt.envDir)
+
+			got, err := Load[readConfig](tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("コンフィグが想定外です。got=%+v, want=%+v", got, tt.want)
+			}
+		})
+	}
+}
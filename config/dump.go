@@ -0,0 +1,177 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/viper"
+)
+
+// maskedValue は秘匿するキーの値の代わりに出力する文字列
+const maskedValue = "******"
+
+// secretKeywords はキーの末尾に含まれる場合に値を秘匿する語
+var secretKeywords = []string{"password", "passwd", "secret", "token", "credential", "private_key", "api_key", "apikey"}
+
+// Dump は読み込み済みの config の値を、キーごとに値の取得元を付けて出力する
+// 取得元は ReadWithOptions と同じ opts から、コマンドライン引数、環境変数、設定ファイル、初期値の順に判定する。
+// パスワードなどの秘匿する値は `secret:"true"` タグ、もしくはキー名（password, token など）で判定して伏せ字にする。
+// URL に含まれるパスワードも伏せ字にする。起動時のログ出力や問い合わせの調査に使用する。
+//
+//	redis.host: "localhost" # file:tst001.yaml
+//	redis.password: "******" # env:OM_REDIS_PASSWORD
+func Dump(config any, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	o.dirPath = resolveDirPath(o, getConfigDirPath(2))
+	appEnv, err := GetAppEnv()
+	if err != nil {
+		return nil, errors.Errorf("get appEnv error: %w", err)
+	}
+
+	s := &sourceResolver{opts: o}
+	if s.envFile, s.envFileName, err = readSingleFile(o.dirPath, appEnv, o); err != nil {
+		return nil, err
+	}
+	if o.baseName != "" {
+		if s.baseFile, s.baseFileName, err = readSingleFile(o.dirPath, o.baseName, o); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s=%s, dir=%s\n", Key, appEnv, o.dirPath)
+	rv := reflect.ValueOf(config)
+	for _, f := range configFields(rv.Type(), o.section) {
+		value, err := formatValue(fieldValue(rv, f.index), isSecret(f))
+		if err != nil {
+			return nil, errors.Errorf("format %s error: %w", f.key, err)
+		}
+		fmt.Fprintf(&buf, "%s: %s # %s\n", f.key, value, s.source(f))
+	}
+	return buf.Bytes(), nil
+}
+
+// sourceResolver はキーの値の取得元を判定する
+type sourceResolver struct {
+	opts         options
+	envFile      *viper.Viper
+	envFileName  string
+	baseFile     *viper.Viper
+	baseFileName string
+}
+
+// source は優先順位の高い順に、値を設定している取得元を返す
+func (s *sourceResolver) source(f configField) string {
+	if s.opts.flags != nil {
+		if flag := s.opts.flags.Lookup(f.key); flag != nil && flag.Changed {
+			return "flag:--" + flag.Name
+		}
+	}
+	if name := envName(f, s.opts); name != "" {
+		if _, ok := os.LookupEnv(name); ok {
+			return "env:" + name
+		}
+	}
+	if s.envFile != nil && s.envFile.IsSet(f.key) {
+		return "file:" + s.envFileName
+	}
+	if s.baseFile != nil && s.baseFile.IsSet(f.key) {
+		return "file:" + s.baseFileName
+	}
+	if _, ok := s.opts.defaults[f.key]; ok {
+		return "default"
+	}
+	return "unset"
+}
+
+// readSingleFile は1つの設定ファイルを読み込み、ファイル名と共に返す。ファイルが無い場合は nil を返す
+func readSingleFile(cfgDirPath, name string, o options) (*viper.Viper, string, error) {
+	v := viper.New()
+	file, err := readConfigFile(v, name, cfgDirPath, o, false)
+	if err != nil {
+		if isConfigNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", errors.Errorf("read cfg error: %w", err)
+	}
+	return v, filepath.Base(file), nil
+}
+
+// fieldValue は index の位置のフィールドの値を返す。途中のポインタが nil の場合は無効な値を返す
+func fieldValue(rv reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(i)
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// isSecret は値を秘匿するフィールドかを返す
+func isSecret(f configField) bool {
+	if secret, err := strconv.ParseBool(f.field.Tag.Get("secret")); err == nil {
+		return secret
+	}
+	last := f.key[strings.LastIndex(f.key, ".")+1:]
+	for _, keyword := range secretKeywords {
+		if strings.Contains(last, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue は値を YAML として読める1行の文字列にする
+func formatValue(rv reflect.Value, secret bool) (string, error) {
+	if !rv.IsValid() {
+		return "null", nil
+	}
+	if secret {
+		if rv.IsZero() {
+			return `""`, nil
+		}
+		return strconv.Quote(maskedValue), nil
+	}
+
+	if s, ok := rv.Interface().(fmt.Stringer); ok {
+		return strconv.Quote(s.String()), nil
+	}
+	if rv.Kind() == reflect.String {
+		return strconv.Quote(maskURLPassword(rv.String())), nil
+	}
+	b, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// maskURLPassword は URL に含まれるパスワードを伏せ字（xxxxx）にする
+func maskURLPassword(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	if _, ok := u.User.Password(); !ok {
+		return s
+	}
+	return u.Redacted()
+}
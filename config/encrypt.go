@@ -0,0 +1,128 @@
+package env
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/viper"
+
+	"valley-pkg/crypter"
+)
+
+// encryptedExt は暗号化した設定ファイルの拡張子
+// 環境ごとの設定ファイルが tst001 の場合、tst001.yaml.enc を暗号化した設定ファイルとして扱う。
+const encryptedExt = ".yaml.enc"
+
+// WithDecrypter 暗号化した設定ファイル（<name>.yaml.enc）を c で復号して読み込む
+// 同じ名前の暗号化していない設定ファイルより優先し、復号した内容はファイルに書き出さずにメモリ上で扱う。
+func WithDecrypter(c crypter.Crypter) Option {
+	return WithDecrypterFunc(func() (crypter.Crypter, error) {
+		return c, nil
+	})
+}
+
+// WithDecrypterFunc 暗号化した設定ファイルを f が返す Crypter で復号して読み込む
+// f は暗号化した設定ファイルを読み込む場合のみ呼ばれるため、KMS から鍵を取得する場合などに使用する。
+func WithDecrypterFunc(f func() (crypter.Crypter, error)) Option {
+	return func(o *options) {
+		o.decrypter = f
+	}
+}
+
+// WithAesKeyFromEnv 暗号化した設定ファイルを、環境変数 keyEnv と ivEnv の AES の鍵と IV で復号して読み込む
+func WithAesKeyFromEnv(keyEnv, ivEnv string) Option {
+	return WithDecrypterFunc(func() (crypter.Crypter, error) {
+		return crypter.NewAes(os.Getenv(keyEnv), os.Getenv(ivEnv))
+	})
+}
+
+// EncryptConfig は設定ファイルの内容 plain を c で暗号化し、<name>.yaml.enc として保存できる Base64 の文字列にする
+// デプロイ用のリポジトリに暗号化した設定ファイルをコミットするためのツールから使用する。
+func EncryptConfig(c crypter.Crypter, plain []byte) ([]byte, error) {
+	cipherText, err := c.EnCrypt(plain)
+	if err != nil {
+		return nil, errors.Errorf("encrypt cfg error: %w", err)
+	}
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(cipherText)), base64.StdEncoding.EncodedLen(len(cipherText))+1)
+	base64.StdEncoding.Encode(encoded, cipherText)
+	return append(encoded, '\n'), nil
+}
+
+// decryptConfig は EncryptConfig で暗号化した内容を c で復号する
+func decryptConfig(c crypter.Crypter, data []byte) ([]byte, error) {
+	cipherText, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, errors.Errorf("decode encrypted cfg error: %w", err)
+	}
+	plain, err := c.DeCrypt(cipherText)
+	if err != nil {
+		return nil, errors.Errorf("decrypt cfg error: %w", err)
+	}
+	return plain, nil
+}
+
+// encryptedFilePath は暗号化した設定ファイルのパスを返す。WithDecrypter を指定していない場合や、ファイルが無い場合は false を返す
+func encryptedFilePath(name string, cfgDirPath string, o options) (string, bool) {
+	if o.decrypter == nil {
+		return "", false
+	}
+	if o.fsys != nil {
+		file := path.Join(filepath.ToSlash(cfgDirPath), name+encryptedExt)
+		_, err := fs.Stat(o.fsys, file)
+		return file, err == nil
+	}
+	file := filepath.Join(cfgDirPath, name+encryptedExt)
+	_, err := os.Stat(file)
+	return file, err == nil
+}
+
+// readConfigFile は cfgDirPath の設定ファイル name（拡張子なし）を v に読み込み、読み込んだファイルのパスを返す
+// merge が true の場合は、読み込み済みの設定に上書きでマージする。
+func readConfigFile(v *viper.Viper, name string, cfgDirPath string, o options, merge bool) (string, error) {
+	if file, ok := encryptedFilePath(name, cfgDirPath, o); ok {
+		var (
+			data []byte
+			err  error
+		)
+		if o.fsys != nil {
+			data, err = fs.ReadFile(o.fsys, file)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return "", errors.Errorf("read encrypted cfg error: %w", err)
+		}
+		c, err := o.decrypter()
+		if err != nil {
+			return "", errors.Errorf("get decrypter error: %w", err)
+		}
+		plain, err := decryptConfig(c, data)
+		if err != nil {
+			return "", err
+		}
+
+		v.SetConfigType("yaml")
+		if merge {
+			err = v.MergeConfig(bytes.NewReader(plain))
+		} else {
+			err = v.ReadConfig(bytes.NewReader(plain))
+		}
+		return file, err
+	}
+
+	if err := setConfigFile(v, name, cfgDirPath, o); err != nil {
+		return "", err
+	}
+	var err error
+	if merge {
+		err = v.MergeInConfig()
+	} else {
+		err = v.ReadInConfig()
+	}
+	return v.ConfigFileUsed(), err
+}
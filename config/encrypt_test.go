@@ -0,0 +1,93 @@
+package env
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"valley-pkg/crypter"
+)
+
+const (
+	testAesKey = "0123456789abcdef0123456789abcdef"
+	testAesIv  = "abcdef0123456789"
+)
+
+func TestLoad_Encrypted(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	t.Setenv("TEST_CONFIG_KEY", testAesKey)
+	t.Setenv("TEST_CONFIG_IV", testAesIv)
+
+	c, err := crypter.NewAes(testAesKey, testAesIv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := EncryptConfig(c, []byte("name: secret\n"))
+	if err != nil {
+		t.Fatalf("EncryptConfig error: %v", err)
+	}
+	if strings.Contains(string(encrypted), "secret") {
+		t.Fatal("暗号化されていません")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), "name: base\nport: 80\n")
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "name: plain\n")
+	writeFile(t, filepath.Join(dir, DefaultEnv+encryptedExt), string(encrypted))
+
+	wrongKey, _ := crypter.NewAes(strings.Repeat("x", 32), testAesIv)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    readConfig
+		wantErr bool
+	}{
+		{
+			name: "正常値: 暗号化したファイルを優先",
+			opts: []Option{WithConfigDirPath(dir), WithBaseFile("base"), WithDecrypter(c)},
+			want: readConfig{Name: "secret", Port: 80},
+		},
+		{
+			name: "正常値: 環境変数の鍵",
+			opts: []Option{WithConfigDirPath(dir), WithAesKeyFromEnv("TEST_CONFIG_KEY", "TEST_CONFIG_IV")},
+			want: readConfig{Name: "secret"},
+		},
+		{
+			name: "正常値: fs.FS",
+			opts: []Option{
+				WithFS(fstest.MapFS{DefaultEnv + encryptedExt: {Data: encrypted}}),
+				WithDecrypter(c),
+			},
+			want: readConfig{Name: "secret"},
+		},
+		{
+			name: "正常値: 復号しない場合は暗号化していないファイル",
+			opts: []Option{WithConfigDirPath(dir)},
+			want: readConfig{Name: "plain"},
+		},
+		{
+			name:    "異常値: 鍵が異なる",
+			opts:    []Option{WithConfigDirPath(dir), WithDecrypter(wrongKey)},
+			wantErr: true,
+		},
+		{
+			name:    "異常値: 鍵の環境変数が無い",
+			opts:    []Option{WithConfigDirPath(dir), WithAesKeyFromEnv("UNDEFINED_VALLEY_KEY", "UNDEFINED_VALLEY_IV")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load[readConfig](tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラーが想定外です。err=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("コンフィグが想定外です。got=%+v, want=%+v", got, tt.want)
+			}
+		})
+	}
+}
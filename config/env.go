@@ -1,10 +1,20 @@
 package env
 
-import "os"
+import (
+	"encoding"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 const (
 	Key        = "APP_ENV"
 	DefaultEnv = "tst001"
+	// DirKey 設定ディレクトリを指定する環境変数
+	DirKey = "APP_CONFIG_DIR"
 )
 
 // GetAppEnv 環境変数取得
@@ -15,3 +25,112 @@ func GetAppEnv() (string, error) {
 	}
 	return env, nil
 }
+
+// bindEnvs は構造体 t のフィールドに対応するキーを環境変数に紐付ける
+// AutomaticEnv だけでは設定ファイルに無いキーを Unmarshal で読み取れないため、全てのキーを明示的に紐付ける。
+// キーは mapstructure タグ（無い場合はフィールド名）を "." で連結したもので、環境変数名は接頭辞と変換の設定から求める。
+// `env:"REDIS_URL"` タグを指定したフィールドは、接頭辞に関わらずその名前の環境変数に紐付ける。
+// section が空以外の場合は section 以下のキーとして紐付ける。
+func bindEnvs(v *viper.Viper, t reflect.Type, section string) error {
+	for _, f := range configFields(t, section) {
+		input := []string{f.key}
+		if envName := f.field.Tag.Get("env"); envName != "" {
+			input = append(input, envName)
+		}
+		if err := v.BindEnv(input...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envName はフィールド f に対応する環境変数名を返す
+func envName(f configField, o options) string {
+	if name := f.field.Tag.Get("env"); name != "" {
+		return name
+	}
+	name := strings.ToUpper(f.key)
+	if o.envPrefix != "" {
+		name = strings.ToUpper(o.envPrefix) + "_" + name
+	}
+	if o.envKeyReplacer != nil {
+		name = o.envKeyReplacer.Replace(name)
+	}
+	return name
+}
+
+// configField コンフィグの値を持つフィールド
+type configField struct {
+	// key "redis.host" のようなキー
+	key   string
+	field reflect.StructField
+	// index 最上位の構造体からのフィールドの位置（reflect.Value.FieldByIndex に渡す）
+	index []int
+}
+
+// configFields は構造体 t の値を持つフィールドをキーの順に返す
+// section が空以外の場合は section 以下のキーとする。
+func configFields(t reflect.Type, section string) []configField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return appendConfigFields(nil, t, strings.ToLower(section), nil)
+}
+
+// appendConfigFields は prefix 以下のフィールドを再帰的に追加する
+func appendConfigFields(fields []configField, t reflect.Type, prefix string, index []int) []configField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		// squash は親のキーに展開する
+		if opts == "squash" || (f.Anonymous && name == "") {
+			if ft.Kind() == reflect.Struct {
+				fields = appendConfigFields(fields, ft, prefix, fieldIndex)
+			}
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		key := strings.ToLower(name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if isNestedStruct(ft) {
+			fields = appendConfigFields(fields, ft, key, fieldIndex)
+			continue
+		}
+		fields = append(fields, configField{key: key, field: f, index: fieldIndex})
+	}
+	return fields
+}
+
+// isNestedStruct はキーを持つ構造体として展開する型かを返す
+// time.Time など文字列から変換する構造体は1つの値として扱う。
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem())
+}
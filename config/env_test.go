@@ -0,0 +1,60 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type envConfig struct {
+	Name  string `mapstructure:"name"`
+	Redis struct {
+		Write struct {
+			Host string `mapstructure:"host"`
+			Port int    `mapstructure:"port"`
+		} `mapstructure:"write"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		URL     string        `mapstructure:"url" env:"REDIS_URL"`
+	} `mapstructure:"redis"`
+	Common `mapstructure:",squash"`
+}
+
+type Common struct {
+	LogLevel string `mapstructure:"log_level"`
+}
+
+func TestReadWithOptions_Env(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	t.Setenv("OM_REDIS_WRITE_HOST", "redis-write")
+	t.Setenv("OM_REDIS_WRITE_PORT", "6380")
+	t.Setenv("OM_REDIS_TIMEOUT", "3s")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("OM_LOG_LEVEL", "debug")
+	// 接頭辞の無い環境変数は使用しない
+	t.Setenv("NAME", "no-prefix")
+
+	dir := t.TempDir()
+	// 設定ファイルに無いキーも環境変数から読み取る
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "name: valley\nredis:\n  write:\n    port: 6379\n")
+
+	var got envConfig
+	if err := ReadWithOptions(&got, WithConfigDirPath(dir), WithEnvPrefix("OM")); err != nil {
+		t.Fatalf("ReadWithOptions error: %v", err)
+	}
+
+	if got.Name != "valley" {
+		t.Errorf("name が想定外です。got=%q", got.Name)
+	}
+	if got.Redis.Write.Host != "redis-write" || got.Redis.Write.Port != 6380 {
+		t.Errorf("redis.write が想定外です。got=%+v", got.Redis.Write)
+	}
+	if got.Redis.Timeout != 3*time.Second {
+		t.Errorf("redis.timeout が想定外です。got=%v", got.Redis.Timeout)
+	}
+	if got.Redis.URL != "redis://localhost:6379" {
+		t.Errorf("redis.url が想定外です。got=%q", got.Redis.URL)
+	}
+	if got.LogLevel != "debug" {
+		t.Errorf("log_level が想定外です。got=%q", got.LogLevel)
+	}
+}
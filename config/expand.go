@@ -0,0 +1,114 @@
+package env
+
+import (
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/viper"
+)
+
+// ErrUndefinedVariable は ${...} で参照したキーと環境変数がどちらも存在しない場合のエラー
+var ErrUndefinedVariable = errors.New("undefined config variable")
+
+// ErrCircularReference は ${...} の参照が循環している場合のエラー
+var ErrCircularReference = errors.New("circular config reference")
+
+// expandValues は文字列の値に含まれる ${name} を展開する
+// name は同じコンフィグのキー（"redis.host" など）を優先し、存在しない場合は環境変数を参照する。
+// "$${" と記述した箇所は展開せずに "${" として扱う。
+//
+//	redis:
+//	  host: localhost
+//	  port: 6379
+//	  url: redis://${redis.host}:${redis.port}/${REDIS_DB}
+func expandValues(v *viper.Viper) error {
+	e := &expander{v: v, resolved: map[string]string{}, resolving: map[string]bool{}}
+	for _, key := range v.AllKeys() {
+		if _, ok := v.Get(key).(string); !ok {
+			continue
+		}
+		value, err := e.resolve(key)
+		if err != nil {
+			return err
+		}
+		v.Set(key, value)
+	}
+	return nil
+}
+
+// expander は参照を解決した値を保持する
+type expander struct {
+	v         *viper.Viper
+	resolved  map[string]string
+	resolving map[string]bool
+}
+
+// resolve はキー key の値を展開する
+func (e *expander) resolve(key string) (string, error) {
+	if value, ok := e.resolved[key]; ok {
+		return value, nil
+	}
+	if e.resolving[key] {
+		return "", errors.Wrapf(ErrCircularReference, "key %q", key)
+	}
+	e.resolving[key] = true
+	defer delete(e.resolving, key)
+
+	value, err := e.expand(e.v.GetString(key))
+	if err != nil {
+		return "", errors.Wrapf(err, "key %q", key)
+	}
+	e.resolved[key] = value
+	return value, nil
+}
+
+// expand は s に含まれる ${name} を展開する
+func (e *expander) expand(s string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "$")
+		if i < 0 || i == len(s)-1 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		rest := s[i+1:]
+
+		switch {
+		case strings.HasPrefix(rest, "${"):
+			// エスケープ
+			b.WriteString("${")
+			s = rest[2:]
+		case rest[0] == '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", errors.Errorf("unclosed variable reference: %q", s[i:])
+			}
+			value, err := e.lookup(rest[1:end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			s = rest[end+1:]
+		default:
+			b.WriteByte('$')
+			s = rest
+		}
+	}
+}
+
+// lookup は name をコンフィグのキー、環境変数の順に探す
+func (e *expander) lookup(name string) (string, error) {
+	key := strings.ToLower(name)
+	if e.v.IsSet(key) {
+		if _, ok := e.v.Get(key).(string); ok {
+			return e.resolve(key)
+		}
+		return e.v.GetString(key), nil
+	}
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return "", errors.Wrapf(ErrUndefinedVariable, "${%s}", name)
+}
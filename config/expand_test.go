@@ -0,0 +1,74 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+type expandConfig struct {
+	Redis struct {
+		Host string `mapstructure:"host"`
+		Port int    `mapstructure:"port"`
+		URL  string `mapstructure:"url"`
+	} `mapstructure:"redis"`
+	DSN      string `mapstructure:"dsn"`
+	Template string `mapstructure:"template"`
+}
+
+func TestReadWithConfigDirPathE_Expand(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	t.Setenv("REDIS_DB", "2")
+
+	tests := []struct {
+		name    string
+		body    string
+		want    expandConfig
+		wantErr error
+	}{
+		{
+			name: "正常値: キーと環境変数の参照",
+			body: "redis:\n  host: localhost\n  port: 6379\n  url: redis://${redis.host}:${redis.port}/${REDIS_DB}\ndsn: ${redis.url}?timeout=1s\n",
+			want: func() expandConfig {
+				var c expandConfig
+				c.Redis.Host = "localhost"
+				c.Redis.Port = 6379
+				c.Redis.URL = "redis://localhost:6379/2"
+				c.DSN = "redis://localhost:6379/2?timeout=1s"
+				return c
+			}(),
+		},
+		{
+			name: "正常値: エスケープ",
+			body: "template: \"$${name} costs $5\"\n",
+			want: expandConfig{Template: "${name} costs $5"},
+		},
+		{
+			name:    "異常値: 存在しない参照",
+			body:    "dsn: ${UNDEFINED_VALLEY_VAR}\n",
+			wantErr: ErrUndefinedVariable,
+		},
+		{
+			name:    "異常値: 循環参照",
+			body:    "dsn: ${template}\ntemplate: ${dsn}\n",
+			wantErr: ErrCircularReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), tt.body)
+
+			var got expandConfig
+			err := ReadWithConfigDirPathE(&got, dir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("コンフィグが想定外です。got=%+v, want=%+v", got, tt.want)
+			}
+		})
+	}
+}
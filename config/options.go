@@ -0,0 +1,140 @@
+package env
+
+import (
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"valley-pkg/crypter"
+)
+
+// Option コンフィグの読み込みの設定
+type Option func(*options)
+
+// options Option で変更する設定
+type options struct {
+	// dirPath 空以外の場合、設定ファイルを探すディレクトリ
+	dirPath string
+	// fsys nil 以外の場合、設定ファイルを読み込むファイルシステム
+	fsys fs.FS
+	// defaults キーごとの初期値
+	defaults map[string]any
+	// baseName 空以外の場合、環境ごとの設定ファイルより先に読み込む共通の設定ファイル名
+	baseName string
+	// flags nil 以外の場合、環境変数より優先するコマンドライン引数
+	flags *pflag.FlagSet
+	// envPrefix 空以外の場合、環境変数名の接頭辞
+	envPrefix string
+	// envKeyReplacer nil 以外の場合、キーから環境変数名への変換
+	envKeyReplacer *strings.Replacer
+	// decrypter nil 以外の場合、暗号化した設定ファイルを復号する Crypter を返す
+	decrypter func() (crypter.Crypter, error)
+	// section 空以外の場合、コンフィグとして読み込むキー
+	section string
+	// debounce ファイルの変更から再読み込みまでの待ち時間
+	debounce time.Duration
+	// onError 再読み込みに失敗した場合に呼ばれる
+	onError func(error)
+}
+
+// newOptions は初期値に opts を適用した設定を返す
+func newOptions(opts []Option) options {
+	o := options{
+		envKeyReplacer: strings.NewReplacer(".", "_"),
+		debounce:       defaultDebounce,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithConfigDirPath 設定ファイルを dirPath から探す
+// 環境変数 APP_CONFIG_DIR より優先する。
+func WithConfigDirPath(dirPath string) Option {
+	return func(o *options) {
+		o.dirPath = dirPath
+	}
+}
+
+// WithFS 設定ファイルを OS のファイルシステムではなく fsys から読み込む
+// embed.FS で設定ファイルをバイナリに同梱する場合に使用する。ディレクトリは fsys のルートからのパスで指定する。
+//
+//	//go:embed configs
+//	var configs embed.FS
+//
+//	cfg, err := env.Load[Config](env.WithFS(configs), env.WithConfigDirPath("configs"))
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+// WithDefaults キーごとの初期値を指定する
+// キーは "redis.timeout" のように "." で区切って階層を指定する。
+func WithDefaults(defaults map[string]any) Option {
+	return func(o *options) {
+		if o.defaults == nil {
+			o.defaults = make(map[string]any, len(defaults))
+		}
+		for k, v := range defaults {
+			o.defaults[k] = v
+		}
+	}
+}
+
+// WithBaseFile 環境ごとの設定ファイルより先に、全環境で共通の設定ファイル name（拡張子なし）を読み込む
+// 環境ごとの設定ファイルは共通の設定ファイルに上書きでマージされ、差分だけを記述すれば良い。
+// 共通の設定ファイルを指定した場合、環境ごとの設定ファイルが無くてもエラーにしない。
+func WithBaseFile(name string) Option {
+	return func(o *options) {
+		o.baseName = name
+	}
+}
+
+// WithFlags コマンドライン引数 flags の値で上書きする
+// 値を指定された引数のみ上書きし、フラグ名をキーとして扱う（"redis.host" のように指定する）。
+func WithFlags(flags *pflag.FlagSet) Option {
+	return func(o *options) {
+		o.flags = flags
+	}
+}
+
+// WithEnvPrefix 環境変数名に prefix を付ける
+// prefix が "OM" の場合、キー "redis.write.host" は環境変数 OM_REDIS_WRITE_HOST に対応する。
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithEnvKeyReplacer キーから環境変数名への変換を r にする（指定しない場合は "." を "_" に置き換える）
+func WithEnvKeyReplacer(r *strings.Replacer) Option {
+	return func(o *options) {
+		o.envKeyReplacer = r
+	}
+}
+
+// WithSection 設定ファイル全体ではなく、キー section 以下をコンフィグとして読み込む
+// section が "redis" の場合、"redis.host" がコンフィグの "host" になる。
+func WithSection(section string) Option {
+	return func(o *options) {
+		o.section = section
+	}
+}
+
+// WithDebounce ファイルの変更から d の間、次の変更が無い場合に再読み込みする
+func WithDebounce(d time.Duration) Option {
+	return func(o *options) {
+		o.debounce = d
+	}
+}
+
+// WithErrorHandler 再読み込みに失敗した場合に f を呼ぶ（指定しない場合はログに出力する）
+func WithErrorHandler(f func(error)) Option {
+	return func(o *options) {
+		o.onError = f
+	}
+}
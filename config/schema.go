@@ -0,0 +1,100 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Schema コンフィグの全ての設定項目の一覧
+type Schema struct {
+	Fields []FieldSchema `json:"fields"`
+}
+
+// FieldSchema 1つの設定項目の情報
+type FieldSchema struct {
+	// Key "redis.host" のようなキー
+	Key string `json:"key"`
+	// Type Go の型名
+	Type string `json:"type"`
+	// Default 初期値（無い場合は nil）。秘匿する項目は伏せ字にする
+	Default any `json:"default,omitempty"`
+	// Env 対応する環境変数名
+	Env string `json:"env"`
+	// Validate `validate` タグの検証ルール
+	Validate string `json:"validate,omitempty"`
+	// Secret 秘匿する項目か
+	Secret bool `json:"secret,omitempty"`
+	// Description `desc` タグの説明
+	Description string `json:"description,omitempty"`
+}
+
+// NewSchema は config の型から設定項目の一覧を作成する
+// 初期値は WithDefaults の値、無い場合は config のフィールドのゼロ値以外の値とする。
+// 環境変数名は WithEnvPrefix などの opts から求める。運用担当者向けの一覧やツールの入力に使用する。
+func NewSchema(config any, opts ...Option) Schema {
+	o := newOptions(opts)
+	rv := reflect.ValueOf(config)
+
+	fields := configFields(rv.Type(), o.section)
+	schema := Schema{Fields: make([]FieldSchema, 0, len(fields))}
+	for _, f := range fields {
+		field := FieldSchema{
+			Key:         f.key,
+			Type:        f.field.Type.String(),
+			Env:         envName(f, o),
+			Validate:    f.field.Tag.Get("validate"),
+			Secret:      isSecret(f),
+			Description: f.field.Tag.Get("desc"),
+		}
+		if def, ok := o.defaults[f.key]; ok {
+			field.Default = def
+		} else if v := fieldValue(rv, f.index); v.IsValid() && !v.IsZero() {
+			field.Default = v.Interface()
+		}
+		if field.Secret && field.Default != nil {
+			field.Default = maskedValue
+		}
+		if d, ok := field.Default.(fmt.Stringer); ok {
+			field.Default = d.String()
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+	return schema
+}
+
+// JSON は設定項目の一覧を JSON にする
+func (s Schema) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Markdown は設定項目の一覧を Markdown の表にする
+func (s Schema) Markdown() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("| Key | Type | Default | Env | Validation | Description |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, f := range s.Fields {
+		def := ""
+		if f.Default != nil {
+			def = fmt.Sprintf("`%v`", f.Default)
+		}
+		validate := ""
+		if f.Validate != "" {
+			validate = "`" + f.Validate + "`"
+		}
+		desc := f.Description
+		if f.Secret {
+			desc = strings.TrimSpace("(secret) " + desc)
+		}
+		fmt.Fprintf(&buf, "| `%s` | `%s` | %s | `%s` | %s | %s |\n",
+			f.Key, f.Type, escapeCell(def), f.Env, escapeCell(validate), escapeCell(desc))
+	}
+	return buf.Bytes()
+}
+
+// escapeCell は表のセルを区切らないように "|" をエスケープする
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
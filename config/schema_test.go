@@ -0,0 +1,64 @@
+package env
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaConfig struct {
+	Redis struct {
+		Host     string        `mapstructure:"host" validate:"required" desc:"Redis のホスト"`
+		Timeout  time.Duration `mapstructure:"timeout" validate:"gt=0"`
+		Password string        `mapstructure:"password"`
+	} `mapstructure:"redis"`
+	Mode string `mapstructure:"mode" validate:"oneof=a|b" env:"APP_MODE"`
+}
+
+func TestNewSchema(t *testing.T) {
+	var cfg schemaConfig
+	cfg.Redis.Timeout = 3 * time.Second
+	cfg.Redis.Password = "p@ss"
+
+	schema := NewSchema(&cfg, WithEnvPrefix("OM"), WithDefaults(map[string]any{"redis.host": "localhost"}))
+
+	want := []FieldSchema{
+		{Key: "redis.host", Type: "string", Default: "localhost", Env: "OM_REDIS_HOST", Validate: "required", Description: "Redis のホスト"},
+		{Key: "redis.timeout", Type: "time.Duration", Default: "3s", Env: "OM_REDIS_TIMEOUT", Validate: "gt=0"},
+		{Key: "redis.password", Type: "string", Default: maskedValue, Env: "OM_REDIS_PASSWORD", Secret: true},
+		{Key: "mode", Type: "string", Env: "APP_MODE", Validate: "oneof=a|b"},
+	}
+	if len(schema.Fields) != len(want) {
+		t.Fatalf("項目数が想定外です。got=%+v", schema.Fields)
+	}
+	for i := range want {
+		if schema.Fields[i] != want[i] {
+			t.Errorf("項目が想定外です。got=%+v, want=%+v", schema.Fields[i], want[i])
+		}
+	}
+
+	b, err := schema.JSON()
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	var decoded Schema
+	if err := json.Unmarshal(b, &decoded); err != nil || len(decoded.Fields) != len(want) {
+		t.Errorf("JSON が想定外です。err=%v, json=%s", err, b)
+	}
+
+	md := string(schema.Markdown())
+	t.Log("\n" + md)
+	for _, row := range []string{
+		"| `redis.host` | `string` | `localhost` | `OM_REDIS_HOST` | `required` | Redis のホスト |",
+		"| `mode` | `string` |  | `APP_MODE` | `oneof=a\\|b` |  |",
+		"| `redis.password` | `string` | `******` | `OM_REDIS_PASSWORD` |  | (secret) |",
+	} {
+		if !strings.Contains(md, row+"\n") {
+			t.Errorf("%q が含まれていません", row)
+		}
+	}
+	if strings.Contains(md, "p@ss") || strings.Contains(string(b), "p@ss") {
+		t.Error("秘匿する値が出力されています")
+	}
+}
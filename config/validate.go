@@ -0,0 +1,124 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrValidation は読み込んだコンフィグの検証に失敗した場合のエラー
+var ErrValidation = errors.New("config validation error")
+
+// Validator 読み込んだコンフィグを検証する
+// `validate` タグで表現できない項目間の整合性などを検証する場合に実装する。
+type Validator interface {
+	Validate() error
+}
+
+var (
+	structValidatorOnce sync.Once
+	structValidator     *validator.Validate
+)
+
+// getStructValidator は `validate` タグを検証するバリデーターを返す
+// エラーのフィールド名には mapstructure タグ（YAML のキー）を使用する。
+func getStructValidator() *validator.Validate {
+	structValidatorOnce.Do(func() {
+		structValidator = validator.New(validator.WithRequiredStructEnabled())
+		structValidator.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+			switch name {
+			case "-":
+				return ""
+			case "":
+				return f.Name
+			}
+			return name
+		})
+	})
+	return structValidator
+}
+
+// validate は読み込んだコンフィグを `validate` タグと Validator の順に検証する
+// 必須項目の欠落や範囲外の値を起動時に検出し、どのキーが不正かをエラーに含める。
+// エラーは ErrValidation でラップし、元のエラー（validator.ValidationErrors など）も errors.As で取り出せる。
+func validate(cfg any) error {
+	cfg = indirect(cfg)
+	if isStruct(cfg) {
+		if err := getStructValidator().Struct(cfg); err != nil {
+			var verrs validator.ValidationErrors
+			if errors.As(err, &verrs) {
+				return fmt.Errorf("%w: %s: %w", ErrValidation, describe(verrs, secretKeys(cfg)), err)
+			}
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+	if v, ok := cfg.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+	return nil
+}
+
+// describe は検証エラーを "redis.timeout: required" のようなキーごとの説明にする
+// 値は Dump と同じ判定で秘匿するキーの場合は伏せ字にし、起動時のエラーやログにパスワードなどを出さない。
+func describe(verrs validator.ValidationErrors, secrets map[string]bool) string {
+	msgs := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		// 先頭の構造体名を除いたキー
+		_, key, _ := strings.Cut(fe.Namespace(), ".")
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		secret, ok := secrets[strings.ToLower(key)]
+		if !ok {
+			secret = isSecret(configField{key: strings.ToLower(key)})
+		}
+		value := fe.Value()
+		if secret {
+			value = maskedValue
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s (got %v)", key, rule, value))
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// secretKeys はキーごとに値を秘匿するかを返す
+func secretKeys(cfg any) map[string]bool {
+	fields := configFields(reflect.TypeOf(cfg), "")
+	secrets := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		secrets[f.key] = isSecret(f)
+	}
+	return secrets
+}
+
+// indirect は Load[*T] のような多重のポインタを1つのポインタにする
+func indirect(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Pointer && !rv.Elem().IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return v
+	}
+	return rv.Interface()
+}
+
+// isStruct は構造体、もしくは nil ではない構造体のポインタかを返す
+func isStruct(v any) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-playground/validator/v10"
+)
+
+var errPoolSize = errors.New("max_idle must not exceed max_active")
+
+type validatedConfig struct {
+	Env   string `mapstructure:"env" validate:"oneof=dev stg prd"`
+	Redis struct {
+		Host    string `mapstructure:"host" validate:"required"`
+		Timeout int    `mapstructure:"timeout" validate:"gt=0"`
+	} `mapstructure:"redis"`
+	MaxIdle   int `mapstructure:"max_idle"`
+	MaxActive int `mapstructure:"max_active"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.MaxIdle > c.MaxActive {
+		return errPoolSize
+	}
+	return nil
+}
+
+func TestReadWithConfigDirPathE_Validate(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+		wantMsg []string
+	}{
+		{
+			name: "正常値",
+			body: "env: dev\nredis:\n  host: localhost\n  timeout: 3\nmax_idle: 1\nmax_active: 2\n",
+		},
+		{
+			name:    "異常値: validate タグの違反",
+			body:    "env: local\nredis:\n  timeout: 0\n",
+			wantErr: ErrValidation,
+			wantMsg: []string{"env: oneof=dev stg prd", "redis.host: required", "redis.timeout: gt=0"},
+		},
+		{
+			name:    "異常値: Validator の違反",
+			body:    "env: dev\nredis:\n  host: localhost\n  timeout: 3\nmax_idle: 3\nmax_active: 2\n",
+			wantErr: errPoolSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), tt.body)
+
+			var cfg validatedConfig
+			err := ReadWithConfigDirPathE(&cfg, dir)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("エラーが想定外です。err=%v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			for _, msg := range tt.wantMsg {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("エラーに %q が含まれていません。err=%v", msg, err)
+				}
+			}
+			if tt.wantMsg != nil {
+				var verrs validator.ValidationErrors
+				if !errors.As(err, &verrs) {
+					t.Error("validator.ValidationErrors を取り出せません")
+				}
+			}
+		})
+	}
+}
+
+func TestReadWithConfigDirPathE_ValidateSecret(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+
+	type secretConfig struct {
+		Password string `mapstructure:"password" validate:"min=8"`
+		Pin      string `mapstructure:"pin" secret:"true" validate:"len=4"`
+		Name     string `mapstructure:"name" validate:"min=3"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "password: hunter2\npin: 12345\nname: ab\n")
+
+	var cfg secretConfig
+	err := ReadWithConfigDirPathE(&cfg, dir)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("エラーが想定外です。got=%v, want=%v", err, ErrValidation)
+	}
+	for _, secret := range []string{"hunter2", "12345"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("エラーに秘匿する値 %q が含まれています。err=%v", secret, err)
+		}
+	}
+	for _, msg := range []string{"password: min=8 (got " + maskedValue + ")", "pin: len=4 (got " + maskedValue + ")", "name: min=3 (got ab)"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("エラーに %q が含まれていません。err=%v", msg, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package env
+
+import (
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/fsnotify/fsnotify"
+)
+
+// defaultDebounce 保存時に連続して発生するファイルイベントをまとめる時間
+const defaultDebounce = 100 * time.Millisecond
+
+// Watcher コンフィグファイルの変更を監視する
+type Watcher struct {
+	mu       sync.RWMutex
+	config   reflect.Value
+	cfgName  string
+	dirPath  string
+	opts     options
+	onChange func(old, new any)
+	timer    *time.Timer
+	stopped  bool
+	// fsw 設定ディレクトリを監視する。Stop で閉じる
+	fsw *fsnotify.Watcher
+	// done 監視のゴルーチンの終了時に閉じる
+	done chan struct{}
+}
+
+// Watch は Read と同様にコンフィグを読み込み、YAMLファイルの変更を監視する
+// 環境ごとの設定ファイルに加え、WithBaseFile で指定した共通の設定ファイルの変更も監視する。
+// 変更された場合は新しいコンフィグを読み込んで `validate` タグと Validator で検証し、成功した場合のみ config を差し替えて onChange を呼ぶ。
+// onChange の old と new は config と同じ型のポインタで、差し替え前と差し替え後の値のコピー。
+// 再起動せずにログレベルやレート制限などを変更するために使用する。
+func Watch(config any, onChange func(old, new any), opts ...Option) (*Watcher, error) {
+	return watch(config, onChange, getConfigDirPath(2), opts)
+}
+
+// WatchWithConfigDirPath は指定の設定ディレクトリで Watch を行う
+func WatchWithConfigDirPath(config any, cfgDirPath string, onChange func(old, new any), opts ...Option) (*Watcher, error) {
+	return watch(config, onChange, cfgDirPath, append(opts, WithConfigDirPath(cfgDirPath)))
+}
+
+// watch はconfigを読み込み、監視を開始する
+func watch(config any, onChange func(old, new any), cfgDirPath string, opts []Option) (*Watcher, error) {
+	rv := reflect.ValueOf(config)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, errors.Errorf("config must be a non-nil pointer: %T", config)
+	}
+	appEnv, err := GetAppEnv()
+	if err != nil {
+		return nil, errors.Errorf("get appEnv error: %w", err)
+	}
+
+	o := newOptions(opts)
+	if o.fsys != nil {
+		return nil, errors.New("cannot watch config in fs.FS")
+	}
+	cfgDirPath = resolveDirPath(o, cfgDirPath)
+	w := &Watcher{
+		config:   rv,
+		cfgName:  appEnv,
+		dirPath:  cfgDirPath,
+		opts:     o,
+		onChange: onChange,
+	}
+	if err := w.load(config); err != nil {
+		return nil, err
+	}
+
+	// エディタの保存やリネームによる置き換えも検知できるよう、ファイルではなくディレクトリを監視する
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Errorf("new watcher error: %w", err)
+	}
+	if err := fsw.Add(w.dirPath); err != nil {
+		_ = fsw.Close()
+		return nil, errors.Errorf("watch dir error: %w", err)
+	}
+	w.fsw = fsw
+	w.done = make(chan struct{})
+	go w.run()
+
+	return w, nil
+}
+
+// run は監視を終了するまでファイルイベントを受け取り、設定ファイルの変更であれば再読み込みを予約する
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if ev.Has(fsnotify.Chmod) && !ev.Has(fsnotify.Write) {
+				continue
+			}
+			if w.isConfigFile(ev.Name) {
+				w.schedule()
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.handleError(errors.Errorf("watch config error: %w", err))
+		}
+	}
+}
+
+// isConfigFile は file が読み込み対象の設定ファイル（環境ごと、もしくは共通の設定ファイル）かを返す
+// 拡張子は問わず、.yaml、.yml、暗号化した .yaml.enc のいずれも対象とする。
+func (w *Watcher) isConfigFile(file string) bool {
+	base := filepath.Base(file)
+	for _, name := range []string{w.cfgName, w.opts.baseName} {
+		if name != "" && strings.HasPrefix(base, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// View は config の差し替えと競合しないように f を実行する
+func (w *Watcher) View(f func()) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	f()
+}
+
+// Stop は監視を終了する。以降の変更は反映しない
+// ファイルの監視を閉じ、監視のゴルーチンが終了するまで待つ。
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+
+	// 監視のゴルーチンは schedule で mu を取得するため、ロックを外してから待つ
+	_ = w.fsw.Close()
+	<-w.done
+}
+
+// schedule は最後の変更から debounce 後に再読み込みする
+func (w *Watcher) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stopped {
+		return
+	}
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.opts.debounce, w.reload)
+		return
+	}
+	w.timer.Reset(w.opts.debounce)
+}
+
+// reload は新しいコンフィグを読み込んで検証し、config を差し替える
+func (w *Watcher) reload() {
+	next := reflect.New(w.config.Elem().Type())
+	if err := w.load(next.Interface()); err != nil {
+		w.handleError(err)
+		return
+	}
+
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	old := reflect.New(w.config.Elem().Type())
+	old.Elem().Set(w.config.Elem())
+	w.config.Elem().Set(next.Elem())
+	w.mu.Unlock()
+
+	if w.onChange != nil {
+		w.onChange(old.Interface(), next.Interface())
+	}
+}
+
+// load はコンフィグを読み込んで検証する
+func (w *Watcher) load(cfg any) error {
+	return read(cfg, w.cfgName, w.dirPath, w.opts)
+}
+
+// handleError は再読み込みのエラーを通知する
+func (w *Watcher) handleError(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+		return
+	}
+	log.Printf("reload config error: %s \n", err)
+}
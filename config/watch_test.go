@@ -0,0 +1,129 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+type watchConfig struct {
+	LogLevel  string `mapstructure:"log_level"`
+	RateLimit int    `mapstructure:"rate_limit"`
+}
+
+func (c *watchConfig) Validate() error {
+	if c.RateLimit <= 0 {
+		return errors.New("rate_limit must be positive")
+	}
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultEnv+".yaml")
+	writeFile(t, path, "log_level: info\nrate_limit: 10\n")
+
+	changed := make(chan [2]*watchConfig, 1)
+	errs := make(chan error, 1)
+	var cfg watchConfig
+	w, err := WatchWithConfigDirPath(&cfg, dir, func(old, new any) {
+		changed <- [2]*watchConfig{old.(*watchConfig), new.(*watchConfig)}
+	}, WithDebounce(20*time.Millisecond), WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatalf("Watch error: %v", err)
+	}
+	defer w.Stop()
+	if cfg.LogLevel != "info" || cfg.RateLimit != 10 {
+		t.Fatalf("初回の読み込みが想定外です。got=%+v", cfg)
+	}
+
+	// 検証に失敗する変更は反映しない
+	writeFile(t, path, "log_level: debug\nrate_limit: 0\n")
+	select {
+	case err := <-errs:
+		t.Logf("reload error: %v", err)
+	case <-changed:
+		t.Fatal("検証に失敗した変更が反映されています")
+	case <-time.After(3 * time.Second):
+		t.Fatal("再読み込みされていません")
+	}
+
+	writeFile(t, path, "log_level: debug\nrate_limit: 20\n")
+	select {
+	case got := <-changed:
+		if got[0].LogLevel != "info" || got[1].LogLevel != "debug" || got[1].RateLimit != 20 {
+			t.Errorf("変更の通知が想定外です。old=%+v, new=%+v", got[0], got[1])
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("変更が通知されていません")
+	}
+	w.View(func() {
+		if cfg.LogLevel != "debug" || cfg.RateLimit != 20 {
+			t.Errorf("コンフィグが差し替えられていません。got=%+v", cfg)
+		}
+	})
+}
+
+func TestWatch_BaseFile(t *testing.T) {
+	t.Setenv(Key, DefaultEnv)
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.yaml")
+	writeFile(t, basePath, "log_level: info\nrate_limit: 10\n")
+	writeFile(t, filepath.Join(dir, DefaultEnv+".yaml"), "log_level: warn\n")
+
+	changed := make(chan *watchConfig, 1)
+	var cfg watchConfig
+	w, err := WatchWithConfigDirPath(&cfg, dir, func(old, new any) {
+		changed <- new.(*watchConfig)
+	}, WithBaseFile("base"), WithDebounce(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Watch error: %v", err)
+	}
+	if cfg.LogLevel != "warn" || cfg.RateLimit != 10 {
+		t.Fatalf("初回の読み込みが想定外です。got=%+v", cfg)
+	}
+
+	// 共通の設定ファイルの変更も反映する
+	writeFile(t, basePath, "log_level: info\nrate_limit: 20\n")
+	select {
+	case got := <-changed:
+		if got.LogLevel != "warn" || got.RateLimit != 20 {
+			t.Errorf("変更の通知が想定外です。got=%+v", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("共通の設定ファイルの変更が通知されていません")
+	}
+
+	// Stop 後は監視のゴルーチンが終了し、変更を反映しない
+	w.Stop()
+	select {
+	case <-w.done:
+	default:
+		t.Fatal("Stop 後も監視のゴルーチンが終了していません")
+	}
+	writeFile(t, basePath, "log_level: info\nrate_limit: 30\n")
+	select {
+	case got := <-changed:
+		t.Errorf("Stop 後に変更が通知されています。got=%+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatch_InvalidConfig(t *testing.T) {
+	if _, err := WatchWithConfigDirPath(watchConfig{}, t.TempDir(), nil); err == nil {
+		t.Error("ポインタ以外でエラーになっていません")
+	}
+}
+
+func writeFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write file error: %v", err)
+	}
+}
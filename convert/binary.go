@@ -0,0 +1,251 @@
+package convert
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrBinaryTag binタグの指定が不正な場合のエラー
+var ErrBinaryTag = errors.New("invalid bin tag")
+
+// binTagName 固定長レイアウトを指定するタグ名
+const binTagName = "bin"
+
+// binField 構造体フィールドのバイト配置
+type binField struct {
+	index  int
+	name   string
+	offset int
+	length int
+}
+
+// MarshalBinary は `bin:"offset,len"` タグに従って構造体を固定長のbyte配列へ変換
+// offsetを省略した場合は直前のフィールドの直後に配置し、lenを省略した場合は型のサイズを使用する。
+// タグの無いフィールドと `bin:"-"` は対象外。
+func MarshalBinary(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.Errorf("marshal binary nil pointer: %w", ErrConvertToByte)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.Errorf("marshal binary %T is not struct: %w", v, ErrConvertToByte)
+	}
+
+	fields, size, err := binLayout(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, size)
+	for _, f := range fields {
+		if err := putBinField(b[f.offset:f.offset+f.length], rv.Field(f.index)); err != nil {
+			return nil, errors.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary は `bin:"offset,len"` タグに従ってbyte配列を構造体へ変換
+// vは構造体のポインタである必要がある。
+func UnmarshalBinary(b []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("unmarshal binary %T is not struct pointer: %w", v, ErrConvertFromByte)
+	}
+	rv = rv.Elem()
+
+	fields, size, err := binLayout(rv.Type())
+	if err != nil {
+		return err
+	}
+	if len(b) < size {
+		return errors.Errorf("unmarshal binary need %d bytes, got %d: %w", size, len(b), ErrConvertFromByte)
+	}
+
+	for _, f := range fields {
+		if err := getBinField(b[f.offset:f.offset+f.length], rv.Field(f.index)); err != nil {
+			return errors.Errorf("field %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// BinarySize は構造体のbinタグから必要なバイト長を算出
+func BinarySize(v any) (int, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return 0, errors.Errorf("binary size %T is not struct: %w", v, ErrBinaryTag)
+	}
+	_, size, err := binLayout(t)
+	return size, err
+}
+
+// binLayout は構造体のbinタグを解析し、各フィールドの配置と全体のバイト長を返す
+func binLayout(t reflect.Type) ([]binField, int, error) {
+	var fields []binField
+	cursor, size := 0, 0
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup(binTagName)
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		offset, length := cursor, 0
+		offStr, lenStr, _ := strings.Cut(tag, ",")
+		if offStr = strings.TrimSpace(offStr); offStr != "" {
+			n, err := strconv.Atoi(offStr)
+			if err != nil || n < 0 {
+				return nil, 0, errors.Errorf("field %s offset %q: %w", sf.Name, offStr, ErrBinaryTag)
+			}
+			offset = n
+		}
+		if lenStr = strings.TrimSpace(lenStr); lenStr != "" {
+			n, err := strconv.Atoi(lenStr)
+			if err != nil || n <= 0 {
+				return nil, 0, errors.Errorf("field %s len %q: %w", sf.Name, lenStr, ErrBinaryTag)
+			}
+			length = n
+		}
+
+		natural := binNaturalSize(sf.Type)
+		switch {
+		case natural < 0:
+			return nil, 0, errors.Errorf("field %s type %s is unsupported: %w", sf.Name, sf.Type, ErrBinaryTag)
+		case length == 0 && natural == 0:
+			// string, []byte は長さの指定が必須
+			return nil, 0, errors.Errorf("field %s requires len: %w", sf.Name, ErrBinaryTag)
+		case length == 0:
+			length = natural
+		case natural != 0 && length != natural:
+			return nil, 0, errors.Errorf("field %s len %d must be %d: %w", sf.Name, length, natural, ErrBinaryTag)
+		}
+
+		fields = append(fields, binField{index: i, name: sf.Name, offset: offset, length: length})
+		cursor = offset + length
+		if cursor > size {
+			size = cursor
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, 0, errors.Errorf("%s has no bin tags: %w", t, ErrBinaryTag)
+	}
+	return fields, size, nil
+}
+
+// binNaturalSize は型ごとのバイト長を返す
+// 0 は可変長（len指定が必要）、-1 は未対応の型を表す。
+func binNaturalSize(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return 1
+	case reflect.Int16, reflect.Uint16:
+		return 2
+	case reflect.Int32, reflect.Uint32:
+		return 4
+	case reflect.Int64, reflect.Uint64:
+		return 8
+	case reflect.String:
+		return 0
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return 0
+		}
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return t.Len()
+		}
+	}
+	return -1
+}
+
+// putBinField はフィールドの値をbig endianでdstへ書き込む
+func putBinField(dst []byte, fv reflect.Value) error {
+	switch fv.Kind() {
+	case reflect.Bool:
+		if fv.Bool() {
+			dst[0] = 1
+		}
+	case reflect.Int8:
+		dst[0] = byte(fv.Int())
+	case reflect.Uint8:
+		dst[0] = byte(fv.Uint())
+	case reflect.Int16:
+		binary.BigEndian.PutUint16(dst, uint16(fv.Int()))
+	case reflect.Uint16:
+		binary.BigEndian.PutUint16(dst, uint16(fv.Uint()))
+	case reflect.Int32:
+		binary.BigEndian.PutUint32(dst, uint32(fv.Int()))
+	case reflect.Uint32:
+		binary.BigEndian.PutUint32(dst, uint32(fv.Uint()))
+	case reflect.Int64:
+		binary.BigEndian.PutUint64(dst, uint64(fv.Int()))
+	case reflect.Uint64:
+		binary.BigEndian.PutUint64(dst, fv.Uint())
+	case reflect.String:
+		// 足りない分は0埋めする
+		if fv.Len() > len(dst) {
+			return errors.Errorf("string length %d exceeds %d: %w", fv.Len(), len(dst), ErrConvertToByte)
+		}
+		copy(dst, fv.String())
+	case reflect.Slice:
+		if fv.Len() > len(dst) {
+			return errors.Errorf("bytes length %d exceeds %d: %w", fv.Len(), len(dst), ErrConvertToByte)
+		}
+		copy(dst, fv.Bytes())
+	case reflect.Array:
+		reflect.Copy(reflect.ValueOf(dst), fv)
+	default:
+		return errors.Errorf("kind %s: %w", fv.Kind(), ErrConvertToByte)
+	}
+	return nil
+}
+
+// getBinField はsrcをbig endianとして解釈し、フィールドへ設定する
+func getBinField(src []byte, fv reflect.Value) error {
+	switch fv.Kind() {
+	case reflect.Bool:
+		fv.SetBool(src[0] != 0)
+	case reflect.Int8:
+		fv.SetInt(int64(int8(src[0])))
+	case reflect.Uint8:
+		fv.SetUint(uint64(src[0]))
+	case reflect.Int16:
+		fv.SetInt(int64(int16(binary.BigEndian.Uint16(src))))
+	case reflect.Uint16:
+		fv.SetUint(uint64(binary.BigEndian.Uint16(src)))
+	case reflect.Int32:
+		fv.SetInt(int64(int32(binary.BigEndian.Uint32(src))))
+	case reflect.Uint32:
+		fv.SetUint(uint64(binary.BigEndian.Uint32(src)))
+	case reflect.Int64:
+		fv.SetInt(int64(binary.BigEndian.Uint64(src)))
+	case reflect.Uint64:
+		fv.SetUint(binary.BigEndian.Uint64(src))
+	case reflect.String:
+		// 0埋めされた末尾は取り除く
+		fv.SetString(strings.TrimRight(string(src), "\x00"))
+	case reflect.Slice:
+		// 容量を指定しないと、slice元のデータを引き継ぐのでコピーする
+		b := make([]byte, len(src))
+		copy(b, src)
+		fv.SetBytes(b)
+	case reflect.Array:
+		reflect.Copy(fv, reflect.ValueOf(src))
+	default:
+		return errors.Errorf("kind %s: %w", fv.Kind(), ErrConvertFromByte)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testKind int8
+
+type testHeader struct {
+	Format    string   `bin:"0,3"`
+	Version   int8     `bin:"3"`
+	Kind      testKind `bin:""`
+	Flag      bool     `bin:""`
+	Extension [5]byte  `bin:"7"`
+	Length    int32    `bin:"12,4"`
+	Body      []byte   // タグ無しは対象外
+	Ignored   uint64   `bin:"-"`
+	Payload   []byte   `bin:"16,2"`
+	Seq       uint16   `bin:""`
+}
+
+func TestMarshalBinary(t *testing.T) {
+	h := testHeader{
+		Format:    "TNN",
+		Version:   1,
+		Kind:      -1,
+		Flag:      true,
+		Extension: [5]byte{1, 2, 3, 4, 5},
+		Length:    258,
+		Body:      []byte("body"),
+		Ignored:   99,
+		Payload:   []byte{0xAA},
+		Seq:       0x0102,
+	}
+
+	got, err := MarshalBinary(&h)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		'T', 'N', 'N', 0x01, 0xFF, 0x01, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x00, 0x00, 0x01, 0x02,
+		0xAA, 0x00, 0x01, 0x02,
+	}, got)
+
+	size, err := BinarySize(testHeader{})
+	assert.NoError(t, err)
+	assert.Len(t, got, size)
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	want := testHeader{
+		Format:    "TN",
+		Version:   1,
+		Kind:      3,
+		Extension: [5]byte{9, 8, 7, 6, 5},
+		Length:    -2,
+		Payload:   []byte{0x01, 0x02},
+		Seq:       65535,
+	}
+
+	b, err := MarshalBinary(want)
+	assert.NoError(t, err)
+
+	var got testHeader
+	assert.NoError(t, UnmarshalBinary(b, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestBinaryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "異常値: 構造体ではない",
+			run: func() error {
+				_, err := MarshalBinary(1)
+				return err
+			},
+			wantErr: ErrConvertToByte,
+		},
+		{
+			name: "異常値: 文字列が長すぎる",
+			run: func() error {
+				_, err := MarshalBinary(testHeader{Format: "TOOLONG"})
+				return err
+			},
+			wantErr: ErrConvertToByte,
+		},
+		{
+			name: "異常値: バイト数不足",
+			run: func() error {
+				return UnmarshalBinary(make([]byte, 10), &testHeader{})
+			},
+			wantErr: ErrConvertFromByte,
+		},
+		{
+			name: "異常値: ポインタではない",
+			run: func() error {
+				return UnmarshalBinary(make([]byte, 20), testHeader{})
+			},
+			wantErr: ErrConvertFromByte,
+		},
+		{
+			name: "異常値: 数値の長さ不一致",
+			run: func() error {
+				_, err := MarshalBinary(struct {
+					A int32 `bin:"0,2"`
+				}{})
+				return err
+			},
+			wantErr: ErrBinaryTag,
+		},
+		{
+			name: "異常値: 文字列の長さ未指定",
+			run: func() error {
+				_, err := MarshalBinary(struct {
+					A string `bin:"0"`
+				}{})
+				return err
+			},
+			wantErr: ErrBinaryTag,
+		},
+		{
+			name: "異常値: 未対応の型",
+			run: func() error {
+				_, err := MarshalBinary(struct {
+					A float64 `bin:"0,8"`
+				}{})
+				return err
+			},
+			wantErr: ErrBinaryTag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
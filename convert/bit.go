@@ -0,0 +1,116 @@
+package convert
+
+import "github.com/cockroachdb/errors"
+
+// maxBits ReadBits/WriteBitsで一度に扱える最大ビット数
+const maxBits = 64
+
+// BitWriter はバイト境界に縛られずにビット単位で値を詰めて書き込む
+// 上位ビットから順に詰めていき、最後のバイトの余りは0埋めされる。
+type BitWriter struct {
+	b     []byte
+	nbits int
+	err   error
+}
+
+// NewBitWriter コンストラクタ
+func NewBitWriter() *BitWriter {
+	return &BitWriter{}
+}
+
+// Err は最初に発生したエラーを返す
+func (w *BitWriter) Err() error {
+	return w.err
+}
+
+// BitLen は書き込み済みのビット数を返す
+func (w *BitWriter) BitLen() int {
+	return w.nbits
+}
+
+// Bytes は書き込み済みのbyte配列を返す
+func (w *BitWriter) Bytes() []byte {
+	return w.b
+}
+
+// WriteBool はboolを1ビットで書き込む
+func (w *BitWriter) WriteBool(v bool) {
+	if v {
+		w.WriteBits(1, 1)
+		return
+	}
+	w.WriteBits(0, 1)
+}
+
+// WriteBits はvの下位nビットを書き込む
+// vがnビットに収まらない場合はエラーとする。
+func (w *BitWriter) WriteBits(v uint64, n int) {
+	if w.err != nil {
+		return
+	}
+	if n <= 0 || n > maxBits {
+		w.err = errors.Errorf("write %d bits: %w", n, ErrConvertToByte)
+		return
+	}
+	if n < maxBits && v>>n != 0 {
+		w.err = errors.Errorf("value %d overflows %d bits: %w", v, n, ErrConvertToByte)
+		return
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		if w.nbits%8 == 0 {
+			w.b = append(w.b, 0)
+		}
+		if v>>i&1 == 1 {
+			w.b[len(w.b)-1] |= 1 << (7 - w.nbits%8)
+		}
+		w.nbits++
+	}
+}
+
+// BitReader はBitWriterで詰めたbyte列をビット単位で読み取る
+type BitReader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+// NewBitReader コンストラクタ
+func NewBitReader(b []byte) *BitReader {
+	return &BitReader{b: b}
+}
+
+// Err は最初に発生したエラーを返す
+func (r *BitReader) Err() error {
+	return r.err
+}
+
+// Remaining は未読のビット数を返す
+func (r *BitReader) Remaining() int {
+	return len(r.b)*8 - r.pos
+}
+
+// ReadBool は1ビット読み取りboolへ変換
+func (r *BitReader) ReadBool() bool {
+	return r.ReadBits(1) == 1
+}
+
+// ReadBits はnビット読み取る
+// エラー発生以降はゼロ値を返す。
+func (r *BitReader) ReadBits(n int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if n <= 0 || n > maxBits || n > r.Remaining() {
+		r.err = errors.Errorf("read %d bits at %d of %d: %w", n, r.pos, len(r.b)*8, ErrConvertFromByte)
+		return 0
+	}
+
+	var v uint64
+	for i := 0; i < n; i++ {
+		bit := r.b[r.pos/8] >> (7 - r.pos%8) & 1
+		v = v<<1 | uint64(bit)
+		r.pos++
+	}
+	return v
+}
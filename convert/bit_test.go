@@ -0,0 +1,45 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitWriter(t *testing.T) {
+	w := NewBitWriter()
+	w.WriteBool(true)
+	w.WriteBool(false)
+	w.WriteBits(0b101, 3)
+	w.WriteBits(0b11111, 5)
+	assert.NoError(t, w.Err())
+	assert.Equal(t, 10, w.BitLen())
+	assert.Equal(t, []byte{0b10101111, 0b11000000}, w.Bytes())
+
+	// 桁あふれはエラー、以降の書き込みは無視される
+	w.WriteBits(4, 2)
+	w.WriteBool(true)
+	assert.ErrorIs(t, w.Err(), ErrConvertToByte)
+	assert.Equal(t, 10, w.BitLen())
+}
+
+func TestBitReader(t *testing.T) {
+	w := NewBitWriter()
+	w.WriteBool(true)
+	w.WriteBits(6, 3)
+	w.WriteBits(1<<63|1, 64)
+	w.WriteBool(false)
+	assert.NoError(t, w.Err())
+
+	r := NewBitReader(w.Bytes())
+	assert.True(t, r.ReadBool())
+	assert.Equal(t, uint64(6), r.ReadBits(3))
+	assert.Equal(t, uint64(1<<63|1), r.ReadBits(64))
+	assert.False(t, r.ReadBool())
+	assert.NoError(t, r.Err())
+
+	// 0埋めされた余りを超えて読むとエラー
+	assert.Equal(t, 3, r.Remaining())
+	assert.Equal(t, uint64(0), r.ReadBits(4))
+	assert.ErrorIs(t, r.Err(), ErrConvertFromByte)
+}
@@ -45,3 +45,38 @@ func Int32ToByte(i int32) []byte {
 func BytesToString(b []byte) (string, error) {
 	return string(b), nil
 }
+
+// BytesToInt64 byte列をint64へ変換
+func BytesToInt64(b []byte) (int64, error) {
+	if len(b) < 8 {
+		return 0, ErrConvertFromByte
+	}
+
+	u := binary.BigEndian.Uint64(b)
+	return int64(u), nil
+}
+
+// Int64ToByte int64をbyte配列へ変換
+func Int64ToByte(i int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(i))
+	return b
+}
+
+// SafeSlice b[from:to]を容量付きで切り出す
+// 範囲外の場合はpanicせずにErrConvertFromByteを返す。
+func SafeSlice(b []byte, from int, to int) ([]byte, error) {
+	if from < 0 || to < from || to > len(b) {
+		return nil, errors.Errorf("slice [%d:%d] of %d: %w", from, to, len(b), ErrConvertFromByte)
+	}
+	// 容量を指定しないと、slice元のデータを引き継ぐので注意
+	return b[from:to:to], nil
+}
+
+// ReadExactly posからちょうどnバイトを切り出す
+func ReadExactly(b []byte, pos int, n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.Errorf("read %d bytes: %w", n, ErrConvertFromByte)
+	}
+	return SafeSlice(b, pos, pos+n)
+}
@@ -1,6 +1,11 @@
 package convert
 
-import "testing"
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
 
 func TestBytesToInt8(t *testing.T) {
 	tests := []struct {
@@ -196,3 +201,60 @@ func TestInt32ToByte(t *testing.T) {
 		})
 	}
 }
+
+func TestSafeSlice(t *testing.T) {
+	b := []byte{0x00, 0x01, 0x02, 0x03}
+
+	tests := []struct {
+		name    string
+		from    int
+		to      int
+		want    []byte
+		wantErr bool
+	}{
+		{name: "正常値: 全体", from: 0, to: 4, want: []byte{0x00, 0x01, 0x02, 0x03}},
+		{name: "正常値: 途中", from: 1, to: 3, want: []byte{0x01, 0x02}},
+		{name: "正常値: 空", from: 4, to: 4, want: []byte{}},
+		{name: "異常値: 終端超過", from: 2, to: 5, wantErr: true},
+		{name: "異常値: 開始が負", from: -1, to: 2, wantErr: true},
+		{name: "異常値: 開始が終端より後", from: 3, to: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SafeSlice(b, tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SafeSlice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrConvertFromByte) {
+					t.Errorf("SafeSlice() error = %v, want ErrConvertFromByte", err)
+				}
+				return
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("SafeSlice() = %v, want %v", got, tt.want)
+			}
+			if cap(got) != len(got) {
+				t.Errorf("SafeSlice() の容量が不正: %d", cap(got))
+			}
+		})
+	}
+}
+
+func TestReadExactly(t *testing.T) {
+	b := []byte("header-body")
+
+	got, err := ReadExactly(b, 7, 4)
+	if err != nil || string(got) != "body" {
+		t.Errorf("ReadExactly() = %q, %v", got, err)
+	}
+
+	if _, err := ReadExactly(b, 7, 5); !errors.Is(err, ErrConvertFromByte) {
+		t.Errorf("ReadExactly() error = %v, want ErrConvertFromByte", err)
+	}
+	if _, err := ReadExactly(b, 0, -1); !errors.Is(err, ErrConvertFromByte) {
+		t.Errorf("ReadExactly() error = %v, want ErrConvertFromByte", err)
+	}
+}
@@ -0,0 +1,145 @@
+package convert
+
+import "github.com/cockroachdb/errors"
+
+// ByteReader はオフセットを保持しながらbyte配列を先頭から読み取る
+// 最初に発生したエラーを保持し、以降の読み取りはゼロ値を返すため、呼び出し毎のエラーチェックは不要。
+type ByteReader struct {
+	b   []byte
+	off int
+	err error
+}
+
+// NewByteReader コンストラクタ
+func NewByteReader(b []byte) *ByteReader {
+	return &ByteReader{b: b}
+}
+
+// Err は最初に発生したエラーを返す
+func (r *ByteReader) Err() error {
+	return r.err
+}
+
+// Offset は現在の読み取り位置を返す
+func (r *ByteReader) Offset() int {
+	return r.off
+}
+
+// Remaining は未読のバイト数を返す
+func (r *ByteReader) Remaining() int {
+	return len(r.b) - r.off
+}
+
+// Skip はnバイト読み飛ばす
+func (r *ByteReader) Skip(n int) {
+	r.next(n)
+}
+
+// ReadInt8 は1バイト読み取りint8へ変換
+func (r *ByteReader) ReadInt8() int8 {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+// ReadInt32 は4バイト読み取りint32へ変換
+func (r *ByteReader) ReadInt32() int32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	v, _ := BytesToInt32(b)
+	return v
+}
+
+// ReadBytes はnバイト読み取る
+// 容量を指定して切り出すため、戻り値へのappendで元のデータを上書きしない。
+func (r *ByteReader) ReadBytes(n int) []byte {
+	return r.next(n)
+}
+
+// ReadString はnバイト読み取りstringへ変換
+func (r *ByteReader) ReadString(n int) string {
+	return string(r.next(n))
+}
+
+// next はnバイト分のsliceを返し、オフセットを進める
+func (r *ByteReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	b, err := ReadExactly(r.b, r.off, n)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	r.off += n
+	return b
+}
+
+// ByteWriter はbyte配列を末尾へ追記していく
+// 最初に発生したエラーを保持し、以降の書き込みは無視される。
+type ByteWriter struct {
+	b   []byte
+	err error
+}
+
+// NewByteWriter コンストラクタ。sizeは事前に確保する容量
+func NewByteWriter(size int) *ByteWriter {
+	return &ByteWriter{b: make([]byte, 0, size)}
+}
+
+// Err は最初に発生したエラーを返す
+func (w *ByteWriter) Err() error {
+	return w.err
+}
+
+// Len は書き込み済みのバイト数を返す
+func (w *ByteWriter) Len() int {
+	return len(w.b)
+}
+
+// Bytes は書き込み済みのbyte配列を返す
+func (w *ByteWriter) Bytes() []byte {
+	return w.b
+}
+
+// WriteInt8 はint8を1バイトで書き込む
+func (w *ByteWriter) WriteInt8(i int8) {
+	if w.err != nil {
+		return
+	}
+	w.b = append(w.b, byte(i))
+}
+
+// WriteInt32 はint32を4バイトで書き込む
+func (w *ByteWriter) WriteInt32(i int32) {
+	if w.err != nil {
+		return
+	}
+	w.b = append(w.b, Int32ToByte(i)...)
+}
+
+// WriteBytes はbyte配列をそのまま書き込む
+func (w *ByteWriter) WriteBytes(b []byte) {
+	if w.err != nil {
+		return
+	}
+	w.b = append(w.b, b...)
+}
+
+// WriteFixedString は文字列をnバイト固定長で書き込む
+// 足りない分は0埋めし、nを超える場合はエラーとする。
+func (w *ByteWriter) WriteFixedString(s string, n int) {
+	if w.err != nil {
+		return
+	}
+	if len(s) > n {
+		w.err = errors.Errorf("string length %d exceeds %d: %w", len(s), n, ErrConvertToByte)
+		return
+	}
+	w.b = append(w.b, s...)
+	w.b = append(w.b, make([]byte, n-len(s))...)
+}
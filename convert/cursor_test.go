@@ -0,0 +1,47 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteReader(t *testing.T) {
+	b := []byte{'T', 'S', 'T', 0x01, 0xFF, 0x00, 0x00, 0x00, 0x02, 'a', 'b'}
+
+	r := NewByteReader(b)
+	assert.Equal(t, "TST", r.ReadString(3))
+	assert.Equal(t, int8(1), r.ReadInt8())
+	assert.Equal(t, int8(-1), r.ReadInt8())
+	assert.Equal(t, int32(2), r.ReadInt32())
+	assert.Equal(t, 9, r.Offset())
+	assert.Equal(t, 2, r.Remaining())
+
+	body := r.ReadBytes(2)
+	assert.Equal(t, []byte("ab"), body)
+	assert.Equal(t, 2, cap(body))
+	assert.NoError(t, r.Err())
+
+	// 読み取り超過以降はゼロ値を返し、最初のエラーを保持する
+	assert.Equal(t, int32(0), r.ReadInt32())
+	assert.Equal(t, int8(0), r.ReadInt8())
+	assert.Nil(t, r.ReadBytes(1))
+	assert.ErrorIs(t, r.Err(), ErrConvertFromByte)
+	assert.Equal(t, 11, r.Offset())
+}
+
+func TestByteWriter(t *testing.T) {
+	w := NewByteWriter(16)
+	w.WriteFixedString("TS", 3)
+	w.WriteInt8(-1)
+	w.WriteInt32(258)
+	w.WriteBytes([]byte("ab"))
+	assert.NoError(t, w.Err())
+	assert.Equal(t, []byte{'T', 'S', 0x00, 0xFF, 0x00, 0x00, 0x01, 0x02, 'a', 'b'}, w.Bytes())
+
+	// 固定長を超える文字列はエラー、以降の書き込みは無視される
+	w.WriteFixedString("TOOLONG", 3)
+	w.WriteInt8(1)
+	assert.ErrorIs(t, w.Err(), ErrConvertToByte)
+	assert.Equal(t, 10, w.Len())
+}
@@ -0,0 +1,72 @@
+package convert
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrDecodeString エンコード済み文字列の復元エラー
+var ErrDecodeString = errors.New("decode string error")
+
+// base32NoPad パディング無しのbase32
+var base32NoPad = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ToHex byte配列を16進数文字列へ変換
+func ToHex(b []byte) string {
+	return hex.EncodeToString(b)
+}
+
+// FromHex 16進数文字列をbyte配列へ変換
+func FromHex(s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("hex %v: %w", err, ErrDecodeString)
+	}
+	return b, nil
+}
+
+// ToBase64 byte配列を標準のbase64文字列へ変換
+func ToBase64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// FromBase64 標準のbase64文字列をbyte配列へ変換
+func FromBase64(s string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("base64 %v: %w", err, ErrDecodeString)
+	}
+	return b, nil
+}
+
+// ToBase64URL byte配列をURLセーフなbase64文字列へ変換
+// トークンやURLに埋め込むため、パディングは付けない。
+func ToBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// FromBase64URL URLセーフなbase64文字列をbyte配列へ変換
+func FromBase64URL(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("base64url %v: %w", err, ErrDecodeString)
+	}
+	return b, nil
+}
+
+// ToBase32 byte配列をパディング無しのbase32文字列へ変換
+func ToBase32(b []byte) string {
+	return base32NoPad.EncodeToString(b)
+}
+
+// FromBase32 パディング無しのbase32文字列をbyte配列へ変換
+func FromBase32(s string) ([]byte, error) {
+	b, err := base32NoPad.DecodeString(s)
+	if err != nil {
+		return nil, errors.Errorf("base32 %v: %w", err, ErrDecodeString)
+	}
+	return b, nil
+}
@@ -0,0 +1,38 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	input := []byte{0xFB, 0xFF, 0x00, 'a'}
+
+	tests := []struct {
+		name    string
+		encode  func([]byte) string
+		decode  func(string) ([]byte, error)
+		want    string
+		invalid string
+	}{
+		{name: "hex", encode: ToHex, decode: FromHex, want: "fbff0061", invalid: "zz"},
+		{name: "base64", encode: ToBase64, decode: FromBase64, want: "+/8AYQ==", invalid: "-_8AYQ"},
+		{name: "base64url", encode: ToBase64URL, decode: FromBase64URL, want: "-_8AYQ", invalid: "+/8AYQ=="},
+		{name: "base32", encode: ToBase32, decode: FromBase32, want: "7P7QAYI", invalid: "7P7QAYI="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.encode(input)
+			assert.Equal(t, tt.want, got)
+
+			b, err := tt.decode(got)
+			assert.NoError(t, err)
+			assert.Equal(t, input, b)
+
+			_, err = tt.decode(tt.invalid)
+			assert.ErrorIs(t, err, ErrDecodeString)
+		})
+	}
+}
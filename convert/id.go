@@ -0,0 +1,112 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+)
+
+// UUIDLen UUIDのバイト長
+const UUIDLen = 16
+
+// IDPadding 固定長IDの余りを埋めるバイト
+const IDPadding byte = 0x00
+
+// ErrIDNotASCII IDにASCII以外の文字が含まれている場合のエラー
+var ErrIDNotASCII = errors.New("id contains non-ascii character")
+
+// ErrIDTooLong IDが固定長を超えている場合のエラー
+var ErrIDTooLong = errors.New("id exceeds fixed length")
+
+// ErrIDLengthNegative 固定長に負の値を指定した場合のエラー
+var ErrIDLengthNegative = errors.New("id fixed length is negative")
+
+// UUIDToBytes UUID文字列を16バイトのbyte配列へ変換
+func UUIDToBytes(id string) ([]byte, error) {
+	u, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.Errorf("parse uuid %q: %v: %w", id, err, ErrConvertToByte)
+	}
+	b := make([]byte, UUIDLen)
+	copy(b, u[:])
+	return b, nil
+}
+
+// BytesToUUID 16バイトのbyte列をUUID文字列へ変換
+func BytesToUUID(b []byte) (string, error) {
+	if len(b) < UUIDLen {
+		return "", ErrConvertFromByte
+	}
+	u, err := uuid.FromBytes(b[:UUIDLen])
+	if err != nil {
+		return "", errors.Errorf("uuid from bytes: %v: %w", err, ErrConvertFromByte)
+	}
+	return u.String(), nil
+}
+
+// FixedIDToBytes ASCIIのIDをnバイト固定長へ変換
+// 足りない分はIDPaddingで埋め、nを超える場合はErrIDTooLongを返す。
+func FixedIDToBytes(id string, n int) ([]byte, error) {
+	if err := validateLength(n); err != nil {
+		return nil, err
+	}
+	if err := validateASCII(id); err != nil {
+		return nil, err
+	}
+	if len(id) > n {
+		return nil, errors.Errorf("id length %d > %d: %w", len(id), n, ErrIDTooLong)
+	}
+	return padID(id, n), nil
+}
+
+// TruncatedIDToBytes ASCIIのIDをnバイト固定長へ変換
+// nを超える場合は先頭nバイトに切り詰める。
+func TruncatedIDToBytes(id string, n int) ([]byte, error) {
+	if err := validateLength(n); err != nil {
+		return nil, err
+	}
+	if err := validateASCII(id); err != nil {
+		return nil, err
+	}
+	if len(id) > n {
+		id = id[:n]
+	}
+	return padID(id, n), nil
+}
+
+// BytesToFixedID 固定長のbyte列からIDを取得
+// 末尾のIDPaddingは取り除く。ASCII以外の文字が含まれる場合はErrIDNotASCIIとErrConvertFromByteの両方に該当するエラーを返す。
+func BytesToFixedID(b []byte) (string, error) {
+	id := string(bytes.TrimRight(b, string(IDPadding)))
+	if err := validateASCII(id); err != nil {
+		return "", fmt.Errorf("%w: %w", err, ErrConvertFromByte)
+	}
+	return id, nil
+}
+
+// padID はIDの末尾をnバイトまでIDPaddingで埋める
+func padID(id string, n int) []byte {
+	b := bytes.Repeat([]byte{IDPadding}, n)
+	copy(b, id)
+	return b
+}
+
+// validateLength は固定長が負の値でないか確認
+func validateLength(n int) error {
+	if n < 0 {
+		return errors.Errorf("length %d: %w", n, ErrIDLengthNegative)
+	}
+	return nil
+}
+
+// validateASCII は表示可能なASCII文字のみで構成されているか確認
+func validateASCII(id string) error {
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x20 || id[i] > 0x7E {
+			return errors.Errorf("id %q at %d: %w", id, i, ErrIDNotASCII)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDToBytes(t *testing.T) {
+	const id = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	b, err := UUIDToBytes(id)
+	assert.NoError(t, err)
+	assert.Len(t, b, UUIDLen)
+	assert.Equal(t, byte(0x6b), b[0])
+	assert.Equal(t, byte(0xc8), b[15])
+
+	got, err := BytesToUUID(b)
+	assert.NoError(t, err)
+	assert.Equal(t, id, got)
+
+	_, err = UUIDToBytes("not-a-uuid")
+	assert.ErrorIs(t, err, ErrConvertToByte)
+
+	_, err = BytesToUUID(b[:15])
+	assert.ErrorIs(t, err, ErrConvertFromByte)
+}
+
+func TestFixedIDToBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		n       int
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "正常値: 0埋め",
+			id:   "p01",
+			n:    5,
+			want: []byte{'p', '0', '1', 0x00, 0x00},
+		},
+		{
+			name: "正常値: ちょうどの長さ",
+			id:   "p0001",
+			n:    5,
+			want: []byte("p0001"),
+		},
+		{
+			name:    "異常値: 長すぎる",
+			id:      "p00001",
+			n:       5,
+			wantErr: ErrIDTooLong,
+		},
+		{
+			name:    "異常値: ASCII以外",
+			id:      "あ",
+			n:       5,
+			wantErr: ErrIDNotASCII,
+		},
+		{
+			name:    "異常値: 負の長さ",
+			id:      "p01",
+			n:       -1,
+			wantErr: ErrIDLengthNegative,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FixedIDToBytes(tt.id, tt.n)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			id, err := BytesToFixedID(got)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+func TestTruncatedIDToBytes(t *testing.T) {
+	got, err := TruncatedIDToBytes("player-123456", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("playe"), got)
+
+	got, err = TruncatedIDToBytes("p1", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'p', '1', 0x00}, got)
+
+	_, err = TruncatedIDToBytes("p\n1", 3)
+	assert.ErrorIs(t, err, ErrIDNotASCII)
+
+	_, err = TruncatedIDToBytes("p1", -1)
+	assert.ErrorIs(t, err, ErrIDLengthNegative)
+
+	_, err = BytesToFixedID([]byte{'p', 0xFF})
+	assert.ErrorIs(t, err, ErrConvertFromByte)
+	assert.ErrorIs(t, err, ErrIDNotASCII)
+}
@@ -0,0 +1,33 @@
+package convert
+
+import "time"
+
+// TimeToBytes 時刻をUnixミリ秒のbyte配列へ変換
+// redis_streamのレプリケーションID（13桁のUnixミリ秒）と同じ精度に揃えている。
+func TimeToBytes(t time.Time) []byte {
+	return Int64ToByte(t.UnixMilli())
+}
+
+// BytesToTime Unixミリ秒のbyte列を時刻へ変換
+func BytesToTime(b []byte) (time.Time, error) {
+	ms, err := BytesToInt64(b)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// DurationToBytes 時間間隔をミリ秒のbyte配列へ変換
+// ミリ秒未満は切り捨てる。
+func DurationToBytes(d time.Duration) []byte {
+	return Int64ToByte(d.Milliseconds())
+}
+
+// BytesToDuration ミリ秒のbyte列を時間間隔へ変換
+func BytesToDuration(b []byte) (time.Duration, error) {
+	ms, err := BytesToInt64(b)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,76 @@
+package convert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeToBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input time.Time
+		want  []byte
+	}{
+		{
+			name:  "Unixエポック",
+			input: time.UnixMilli(0),
+			want:  []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:  "13桁のUnixミリ秒",
+			input: time.UnixMilli(1765728634700),
+			want:  []byte{0x00, 0x00, 0x01, 0x9B, 0x1D, 0xA0, 0xC7, 0x4C},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TimeToBytes(tt.input)
+			assert.Equal(t, tt.want, got)
+
+			back, err := BytesToTime(got)
+			assert.NoError(t, err)
+			assert.True(t, tt.input.Equal(back))
+		})
+	}
+}
+
+func TestBytesToTime(t *testing.T) {
+	// ミリ秒未満は切り捨てられる
+	now := time.Now()
+	got, err := BytesToTime(TimeToBytes(now))
+	assert.NoError(t, err)
+	assert.Equal(t, now.UnixMilli(), got.UnixMilli())
+
+	_, err = BytesToTime([]byte{0x01, 0x02, 0x03})
+	assert.ErrorIs(t, err, ErrConvertFromByte)
+}
+
+func TestDurationToBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input time.Duration
+		want  time.Duration
+	}{
+		{name: "0", input: 0, want: 0},
+		{name: "1.5秒", input: 1500 * time.Millisecond, want: 1500 * time.Millisecond},
+		{name: "ミリ秒未満は切り捨て", input: 2*time.Millisecond + 999*time.Microsecond, want: 2 * time.Millisecond},
+		{name: "負の値", input: -3 * time.Second, want: -3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := DurationToBytes(tt.input)
+			assert.Len(t, b, 8)
+
+			got, err := BytesToDuration(b)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := BytesToDuration(nil)
+	assert.ErrorIs(t, err, ErrConvertFromByte)
+}
@@ -0,0 +1,105 @@
+// Package eventbus は型付きのイベントの Publish/Subscribe を提供する
+//
+// イベントは parser でバイト列に変換し、Backend で配信する。
+// プロセス内はメモリ、プロセス間は Redis の pub/sub または Streams を Backend にすることで、同じ API で扱える。
+//
+//	var UserCreated = eventbus.NewTopic[User]("user.created")
+//
+//	bus := eventbus.New(eventbus.NewMemory())
+//	sub, err := UserCreated.Subscribe(ctx, bus, func(ctx context.Context, u User) error { ... })
+//	err = UserCreated.Publish(ctx, bus, User{ID: 1})
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"valley-pkg/logging"
+	"valley-pkg/parser"
+)
+
+// ErrClosed 終了した Backend を使用した場合のエラー
+var ErrClosed = errors.New("eventbus closed")
+
+// Backend イベントのバイト列を配信する
+type Backend interface {
+	// Publish topic の購読者に payload を配信する
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe topic を購読し、イベントごとに handler を呼び出す
+	// ctx が終了するか Subscription を Close すると購読をやめる。
+	Subscribe(ctx context.Context, topic string, handler func(ctx context.Context, payload []byte) error) (Subscription, error)
+	// Close Backend を終了する
+	Close() error
+}
+
+// Subscription 購読
+type Subscription interface {
+	// Close 購読をやめ、実行中のハンドラーが戻るまで待つ
+	Close() error
+}
+
+// Bus イベントを変換して Backend で配信する
+type Bus struct {
+	backend Backend
+	opts    options
+}
+
+// New backend で配信する Bus を生成する
+func New(backend Backend, opts ...Option) *Bus {
+	return &Bus{
+		backend: backend,
+		opts:    newOptions(opts),
+	}
+}
+
+// Close Backend を終了する
+func (b *Bus) Close() error {
+	return b.backend.Close()
+}
+
+// Topic 型付きのイベントの配信先
+type Topic[T any] struct {
+	name string
+}
+
+// NewTopic 名前を指定してトピックを定義する
+func NewTopic[T any](name string) Topic[T] {
+	return Topic[T]{name: name}
+}
+
+// Name トピック名を返す
+func (t Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish event を変換してトピックの購読者に配信する
+func (t Topic[T]) Publish(ctx context.Context, b *Bus, event T) error {
+	payload, err := parser.Marshal(b.opts.parser, event)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", t.name, err)
+	}
+	if err := b.backend.Publish(ctx, t.name, payload); err != nil {
+		return fmt.Errorf("publish %s: %w", t.name, err)
+	}
+	return nil
+}
+
+// Subscribe トピックを購読し、イベントごとに handler を呼び出す
+// 変換できないイベントはログに出力して読み飛ばす。handler のエラーの扱いは Backend による。
+func (t Topic[T]) Subscribe(ctx context.Context, b *Bus, handler func(ctx context.Context, event T) error) (Subscription, error) {
+	logger := b.opts.logger.With(logging.F("topic", t.name))
+	sub, err := b.backend.Subscribe(ctx, t.name, func(ctx context.Context, payload []byte) error {
+		event, err := parser.Unmarshal[T](b.opts.parser, payload)
+		if err != nil {
+			// 再配信しても変換できないため、エラーにしない
+			logger.Error("unmarshal event error", logging.Err(err))
+			return nil
+		}
+		return handler(ctx, event)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subscribe %s: %w", t.name, err)
+	}
+	return sub, nil
+}
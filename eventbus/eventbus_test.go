@@ -0,0 +1,237 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"valley-pkg/logging"
+	"valley-pkg/parser"
+)
+
+type userCreated struct {
+	ID   int    `json:"id" msgpack:"id"`
+	Name string `json:"name" msgpack:"name"`
+}
+
+var topicUserCreated = NewTopic[userCreated]("user.created")
+
+// receive ch から値を1つ受け取る
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("イベントを受信できませんでした。")
+	}
+	var zero T
+	return zero
+}
+
+func TestTopic_PublishSubscribe(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "正常値: JSON",
+		},
+		{
+			name: "正常値: msgpack",
+			opts: []Option{WithParser(&parser.MsgpackParser{})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			bus := New(NewMemory(), tt.opts...)
+			defer bus.Close()
+
+			received1 := make(chan userCreated, 1)
+			received2 := make(chan userCreated, 1)
+			sub1, err := topicUserCreated.Subscribe(ctx, bus, func(ctx context.Context, e userCreated) error {
+				received1 <- e
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub1.Close()
+			sub2, err := topicUserCreated.Subscribe(ctx, bus, func(ctx context.Context, e userCreated) error {
+				received2 <- e
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub2.Close()
+
+			want := userCreated{ID: 1, Name: "alice"}
+			if err := topicUserCreated.Publish(ctx, bus, want); err != nil {
+				t.Fatal(err)
+			}
+			// 全ての購読者が受け取る
+			if got := receive(t, received1); got != want {
+				t.Errorf("イベントが想定外です。got=%+v, want=%+v", got, want)
+			}
+			if got := receive(t, received2); got != want {
+				t.Errorf("イベントが想定外です。got=%+v, want=%+v", got, want)
+			}
+		})
+	}
+}
+
+func TestTopic_Subscribe_Close(t *testing.T) {
+	ctx := context.Background()
+	bus := New(NewMemory())
+	defer bus.Close()
+
+	received := make(chan userCreated, 10)
+	sub, err := topicUserCreated.Subscribe(ctx, bus, func(ctx context.Context, e userCreated) error {
+		received <- e
+		return errors.New("handler failed")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ハンドラーのエラーでは購読をやめない
+	for i := range 2 {
+		if err := topicUserCreated.Publish(ctx, bus, userCreated{ID: i}); err != nil {
+			t.Fatal(err)
+		}
+		if got := receive(t, received); got.ID != i {
+			t.Errorf("イベントが想定外です。got=%+v, want=%v", got, i)
+		}
+	}
+
+	if err := sub.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := topicUserCreated.Publish(ctx, bus, userCreated{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-received:
+		t.Errorf("購読をやめた後にイベントを受信しました。got=%+v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTopic_Subscribe_ContextDone(t *testing.T) {
+	bus := New(NewMemory())
+	defer bus.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := topicUserCreated.Subscribe(ctx, bus, func(ctx context.Context, e userCreated) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		_ = sub.Close()
+		close(done)
+	}()
+	receive(t, done)
+}
+
+func TestTopic_Subscribe_InvalidPayload(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory()
+	bus := New(backend)
+	defer bus.Close()
+
+	received := make(chan userCreated, 1)
+	sub, err := topicUserCreated.Subscribe(ctx, bus, func(ctx context.Context, e userCreated) error {
+		received <- e
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	// 変換できないイベントは読み飛ばし、以降のイベントは受け取る
+	if err := backend.Publish(ctx, topicUserCreated.Name(), []byte("{")); err != nil {
+		t.Fatal(err)
+	}
+	if err := topicUserCreated.Publish(ctx, bus, userCreated{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, received); got.ID != 1 {
+		t.Errorf("イベントが想定外です。got=%+v", got)
+	}
+}
+
+func TestMemory_Closed(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemory()
+	if err := backend.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.Publish(ctx, "topic", []byte("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrClosed)
+	}
+	if _, err := backend.Subscribe(ctx, "topic", func(context.Context, []byte) error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrClosed)
+	}
+}
+
+func TestRunWithRestart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 2回失敗した後は ctx が終了するまで戻らない
+	calls := make(chan int, 10)
+	n := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runWithRestart(ctx, logging.Nop(), func(ctx context.Context) error {
+			n++
+			calls <- n
+			if n <= 2 {
+				return errors.New("connection reset")
+			}
+			<-ctx.Done()
+			return nil
+		})
+	}()
+
+	for want := 1; want <= 3; want++ {
+		if got := receive(t, calls); got != want {
+			t.Fatalf("実行回数が想定外です。got=%v, want=%v", got, want)
+		}
+	}
+	cancel()
+	receive(t, done)
+	if len(calls) != 0 {
+		t.Errorf("終了後に再実行しました。got=%v", len(calls))
+	}
+}
+
+func TestWithBlock(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want time.Duration
+	}{
+		{name: "正常値: 指定した値", d: 5 * time.Second, want: 5 * time.Second},
+		{name: "異常値: 0 は既定値のまま", d: 0, want: time.Second},
+		{name: "異常値: 負の値は既定値のまま", d: -time.Second, want: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions([]Option{WithBlock(tt.d)}).block; got != tt.want {
+				t.Errorf("block が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
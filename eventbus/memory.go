@@ -0,0 +1,121 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"valley-pkg/channel"
+	"valley-pkg/logging"
+)
+
+// Memory プロセス内でイベントを配信する Backend
+// トピックごとに channel.Broadcaster で購読者へ複製する。ハンドラーのエラーはログに出力し、再配信しない。
+type Memory struct {
+	opts options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+}
+
+// memoryTopic トピックごとの入力チャネルと Broadcaster
+type memoryTopic struct {
+	in chan<- []byte
+	br *channel.Broadcaster[[]byte]
+}
+
+var _ Backend = (*Memory)(nil)
+
+// NewMemory Memory を生成する
+func NewMemory(opts ...Option) *Memory {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Memory{
+		opts:   newOptions(opts),
+		ctx:    ctx,
+		cancel: cancel,
+		topics: make(map[string]*memoryTopic),
+	}
+}
+
+// topic トピックを返す。無い場合は生成する
+func (m *Memory) topic(name string) (*memoryTopic, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+	if t, ok := m.topics[name]; ok {
+		return t, nil
+	}
+	in := make(chan []byte)
+	t := &memoryTopic{
+		in: in,
+		br: channel.NewBroadcaster[[]byte](m.ctx, in, m.opts.buffer, m.opts.policy),
+	}
+	m.topics[name] = t
+	return t, nil
+}
+
+// Publish topic の購読者に payload を配信する
+// 購読者が payload を変更しないように、呼び出し側は送信後に payload を変更しないこと。
+func (m *Memory) Publish(ctx context.Context, topic string, payload []byte) error {
+	t, err := m.topic(topic)
+	if err != nil {
+		return err
+	}
+	select {
+	case t.in <- payload:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.ctx.Done():
+		return ErrClosed
+	}
+}
+
+// Subscribe topic を購読し、イベントごとに handler を呼び出す
+// Subscribe より前に Publish したイベントは受け取らない。
+func (m *Memory) Subscribe(ctx context.Context, topic string, handler func(ctx context.Context, payload []byte) error) (Subscription, error) {
+	t, err := m.topic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	c := t.br.Subscribe()
+	s := &memorySubscription{done: make(chan struct{})}
+	s.unsubscribe = sync.OnceFunc(func() { t.br.Unsubscribe(c) })
+	stop := context.AfterFunc(ctx, s.unsubscribe)
+	logger := m.opts.logger.With(logging.F("topic", topic))
+	go func() {
+		defer close(s.done)
+		defer stop()
+		for payload := range c {
+			if err := handler(ctx, payload); err != nil {
+				logger.Error("Error handling event", logging.Err(err))
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Close 全ての購読を終了し、以降の Publish と Subscribe は ErrClosed を返す
+func (m *Memory) Close() error {
+	m.cancel()
+	return nil
+}
+
+// memorySubscription Memory の購読
+type memorySubscription struct {
+	unsubscribe func()
+	done        chan struct{}
+}
+
+// Close 購読をやめ、実行中のハンドラーが戻るまで待つ
+func (s *memorySubscription) Close() error {
+	s.unsubscribe()
+	<-s.done
+	return nil
+}
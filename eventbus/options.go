@@ -0,0 +1,102 @@
+package eventbus
+
+import (
+	"time"
+
+	"valley-pkg/channel"
+	"valley-pkg/logging"
+	"valley-pkg/parser"
+)
+
+// Option Bus と Backend の設定
+// Backend に関係しない設定は無視する。
+type Option func(*options)
+
+type options struct {
+	parser    parser.Parser
+	logger    logging.Logger
+	buffer    int
+	policy    channel.SlowConsumerPolicy
+	group     string
+	maxLen    int64
+	block     time.Duration
+	claimIdle time.Duration
+}
+
+// WithParser Bus がイベントの変換に使用するパーサーを指定する。指定しない場合は JSON
+func WithParser(p parser.Parser) Option {
+	return func(o *options) {
+		o.parser = p
+	}
+}
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithBuffer Memory の購読者ごとのバッファ数を n にする。指定しない場合は 64
+// channel.DropNewest と channel.DropOldest の場合、1 未満は 1 とする。
+func WithBuffer(n int) Option {
+	return func(o *options) {
+		o.buffer = n
+	}
+}
+
+// WithSlowConsumerPolicy Memory の購読者のバッファが埋まっている場合の振る舞いを指定する。指定しない場合は channel.Block
+func WithSlowConsumerPolicy(p channel.SlowConsumerPolicy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithGroup RedisStream のコンシューマーグループ名を指定する。指定しない場合は "eventbus"
+// 同じグループの購読者はイベントを分担して受け取り、異なるグループの購読者はそれぞれ全てのイベントを受け取る。
+func WithGroup(name string) Option {
+	return func(o *options) {
+		o.group = name
+	}
+}
+
+// WithMaxLen RedisStream のストリームをおおよそ n 件に制限する（0 は無制限）
+func WithMaxLen(n int64) Option {
+	return func(o *options) {
+		o.maxLen = n
+	}
+}
+
+// WithBlock RedisStream でイベントが無い場合に1回の読み込みで待つ時間を d にする。指定しない場合は1秒
+// 待たずに読み込みを繰り返さないよう、d が 0 以下の場合は既定値のままにする。
+func WithBlock(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.block = d
+		}
+	}
+}
+
+// WithClaimIdle RedisStream で d 以上確認されていないイベントを回収して再配信する。指定しない場合は1分（0 は回収しない）
+func WithClaimIdle(d time.Duration) Option {
+	return func(o *options) {
+		o.claimIdle = d
+	}
+}
+
+// newOptions は初期値に opts を適用した設定を返す
+func newOptions(opts []Option) options {
+	o := options{
+		parser:    &parser.JSONParser{},
+		buffer:    64,
+		policy:    channel.Block,
+		group:     "eventbus",
+		block:     time.Second,
+		claimIdle: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.logger = logging.OrDefault(o.logger)
+	return o
+}
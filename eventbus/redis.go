@@ -0,0 +1,169 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"valley-pkg/logging"
+	"valley-pkg/redis"
+)
+
+// RedisPubSub Redis の pub/sub でプロセス間にイベントを配信する Backend
+// 購読していない間のイベントは受け取らず、ハンドラーのエラーはログに出力して再配信しない。
+type RedisPubSub struct {
+	pubsub *redis.PubSubService
+	opts   options
+}
+
+var _ Backend = (*RedisPubSub)(nil)
+
+// NewRedisPubSub RedisPubSub を生成する。トピック名をそのままチャネル名に使用する
+func NewRedisPubSub(rc *redis.RedisClient, opts ...Option) *RedisPubSub {
+	return &RedisPubSub{
+		pubsub: redis.NewPubSubService(rc),
+		opts:   newOptions(opts),
+	}
+}
+
+// Publish topic のチャネルに payload を送信する
+func (r *RedisPubSub) Publish(ctx context.Context, topic string, payload []byte) error {
+	_, err := r.pubsub.Publish(ctx, topic, payload)
+	return err
+}
+
+// Subscribe topic のチャネルを購読し、イベントごとに handler を呼び出す
+// 購読の開始を確認してから返る。
+func (r *RedisPubSub) Subscribe(ctx context.Context, topic string, handler func(ctx context.Context, payload []byte) error) (Subscription, error) {
+	logger := r.opts.logger.With(logging.F("topic", topic))
+	sub, err := r.pubsub.Subscribe(ctx, []string{topic}, func(ctx context.Context, msg redis.Message) error {
+		return handler(ctx, msg.Payload)
+	}, redis.WithErrorHandler(func(msg *redis.Message, err error) {
+		if msg == nil {
+			logger.Error("Error receiving event", logging.Err(err))
+			return
+		}
+		logger.Error("Error handling event", logging.Err(err))
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Close 何もしない。RedisClient は呼び出し側で閉じること
+func (r *RedisPubSub) Close() error {
+	return nil
+}
+
+// payloadField ストリームのエントリでイベントを格納するフィールド名
+const payloadField = "payload"
+
+// RedisStream Redis Streams でプロセス間にイベントを配信する Backend
+// 購読はコンシューマーグループで行うため、購読していない間のイベントも後から受け取れる。
+// ハンドラーがエラーを返したイベントは確認せずに残し、ClaimIdle を過ぎた後に再配信する。
+type RedisStream struct {
+	redis *redis.RedisClient
+	opts  options
+}
+
+var _ Backend = (*RedisStream)(nil)
+
+// NewRedisStream RedisStream を生成する。トピックごとに "eventbus:" を前置したストリームを使用する
+func NewRedisStream(rc *redis.RedisClient, opts ...Option) *RedisStream {
+	return &RedisStream{
+		redis: rc,
+		opts:  newOptions(opts),
+	}
+}
+
+// streamKey トピックのストリーム名
+func streamKey(topic string) string {
+	return "eventbus:" + topic
+}
+
+// Publish topic のストリームに payload を追加する
+func (r *RedisStream) Publish(ctx context.Context, topic string, payload []byte) error {
+	_, err := r.redis.XAdd(ctx, streamKey(topic), map[string]interface{}{payloadField: payload}, r.opts.maxLen)
+	return err
+}
+
+// Subscribe topic のストリームをコンシューマーグループで購読し、イベントごとに handler を呼び出す
+// グループが無い場合はストリームの先頭から読み込むグループを作成する。
+// 接続が切れるなどで読み込みに失敗した場合は、間隔を空けて ctx が終了するか Close を呼ぶまで読み込みを再開する。
+func (r *RedisStream) Subscribe(ctx context.Context, topic string, handler func(ctx context.Context, payload []byte) error) (Subscription, error) {
+	consumer, err := redis.NewStreamConsumer(ctx, r.redis, streamKey(topic), r.opts.group, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	consumer.Block = r.opts.block
+	consumer.ClaimIdle = r.opts.claimIdle
+
+	logger := r.opts.logger.With(logging.F("topic", topic), logging.F("group", r.opts.group))
+	ctx, cancel := context.WithCancel(ctx)
+	s := &streamSubscription{cancel: cancel}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		runWithRestart(ctx, logger, func(ctx context.Context) error {
+			return consumer.Run(ctx, func(ctx context.Context, msg redis.XMessage) error {
+				payload, _ := msg.Values[payloadField].(string)
+				if err := handler(ctx, []byte(payload)); err != nil {
+					logger.Error("Error handling event", logging.F("entry_id", msg.ID), logging.Err(err))
+					return err
+				}
+				return nil
+			})
+		})
+	}()
+	return s, nil
+}
+
+// 読み込みを再開するまでの間隔
+const (
+	restartMinInterval = 100 * time.Millisecond
+	restartMaxInterval = 10 * time.Second
+)
+
+// runWithRestart ctx が終了するまで run を実行し、エラーで戻った場合は間隔を空けて再実行する
+// 間隔は失敗が続くたびに restartMaxInterval まで倍にし、restartMaxInterval 以上続いた実行の後は最短に戻す。
+func runWithRestart(ctx context.Context, logger logging.Logger, run func(ctx context.Context) error) {
+	interval := restartMinInterval
+	for ctx.Err() == nil {
+		start := time.Now()
+		err := run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) >= restartMaxInterval {
+			interval = restartMinInterval
+		}
+		logger.Error("Error receiving event, restarting", logging.F("interval", interval.String()), logging.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, restartMaxInterval)
+	}
+}
+
+// Close 何もしない。RedisClient は呼び出し側で閉じること
+func (r *RedisStream) Close() error {
+	return nil
+}
+
+// streamSubscription RedisStream の購読
+type streamSubscription struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Close 購読をやめ、実行中のハンドラーが戻るまで待つ
+func (s *streamSubscription) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,209 @@
+package featureflag
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"valley-pkg/logging"
+)
+
+// Provider フラグの読み込み元
+type Provider interface {
+	// Load 全てのフラグをフラグ名をキーにして返す
+	Load(ctx context.Context) (map[string]Flag, error)
+	// Watch ctx が終了するまでフラグの変更を監視し、変更を検知するたびに notify を呼び出す
+	// ctx の終了時は nil を返す。
+	Watch(ctx context.Context, notify func()) error
+}
+
+// Option Client の設定
+type Option func(*Client)
+
+// WithTTL キャッシュの有効期限を d にする。指定しない場合は30秒
+// Watch を実行していない場合も、有効期限を過ぎると次の判定時に読み込み直す。
+func WithTTL(d time.Duration) Option {
+	return func(c *Client) {
+		c.ttl = d
+	}
+}
+
+// WithRetryInterval 読み込みに失敗した場合に、次に読み込み直すまでの間隔を d にする。指定しない場合は5秒
+// 読み込み元の障害中に、判定のたびに読み込みを待たないようにする。
+func WithRetryInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.retryInterval = d
+	}
+}
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// Client フラグをキャッシュして判定する
+type Client struct {
+	provider      Provider
+	ttl           time.Duration
+	retryInterval time.Duration
+	logger        logging.Logger
+
+	mu        sync.RWMutex
+	flags     map[string]Flag
+	expiresAt time.Time
+	// loadMu 読み込みを1つにまとめる
+	loadMu    sync.Mutex
+	listeners []func(names []string)
+}
+
+// New provider から読み込む Client を生成する
+func New(provider Provider, opts ...Option) *Client {
+	c := &Client{
+		provider:      provider,
+		ttl:           30 * time.Second,
+		retryInterval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.logger = logging.OrDefault(c.logger)
+	return c
+}
+
+// IsEnabled name のフラグが target に対して有効かを返す
+// フラグが存在しない場合と、一度も読み込めていない場合は false を返す。
+func (c *Client) IsEnabled(ctx context.Context, name string, target Target) bool {
+	f, ok := c.Flag(ctx, name)
+	if !ok {
+		return false
+	}
+	return f.Evaluate(target)
+}
+
+// Flag name のフラグを返す。存在しない場合は false を返す
+func (c *Client) Flag(ctx context.Context, name string) (Flag, bool) {
+	flags := c.snapshot(ctx)
+	f, ok := flags[name]
+	return f, ok
+}
+
+// Flags 全てのフラグを返す
+func (c *Client) Flags(ctx context.Context) map[string]Flag {
+	flags := c.snapshot(ctx)
+	out := make(map[string]Flag, len(flags))
+	for name, f := range flags {
+		out[name] = f
+	}
+	return out
+}
+
+// OnChange フラグが変更された場合に、変更されたフラグ名を受け取る fn を登録する
+// 追加・削除されたフラグも含む。fn は読み込みを行ったゴルーチンで呼ばれる。
+func (c *Client) OnChange(fn func(names []string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// Refresh Provider から読み込み直す
+func (c *Client) Refresh(ctx context.Context) error {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	return c.load(ctx)
+}
+
+// Watch ctx が終了するまで Provider の変更を監視し、変更を検知するたびに読み込み直す
+// 最初に読み込みを行い、失敗した場合はそのエラーを返す。
+func (c *Client) Watch(ctx context.Context) error {
+	if err := c.Refresh(ctx); err != nil {
+		return err
+	}
+	return c.provider.Watch(ctx, func() {
+		if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			c.logger.Error("reload feature flags error", logging.Err(err))
+		}
+	})
+}
+
+// snapshot キャッシュしたフラグを返す。有効期限を過ぎている場合は読み込み直す
+// 読み込みに失敗した場合は期限切れのフラグを使い続け、retryInterval の間は読み込み直さない。
+func (c *Client) snapshot(ctx context.Context) map[string]Flag {
+	c.mu.RLock()
+	flags, expired := c.flags, !time.Now().Before(c.expiresAt)
+	c.mu.RUnlock()
+	if !expired {
+		return flags
+	}
+
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+	// 待っている間に他のゴルーチンが読み込んだ場合はそれを使う
+	c.mu.RLock()
+	flags, expired = c.flags, !time.Now().Before(c.expiresAt)
+	c.mu.RUnlock()
+	if !expired {
+		return flags
+	}
+
+	if err := c.load(ctx); err != nil {
+		c.logger.Error("load feature flags error", logging.Err(err))
+		c.mu.Lock()
+		c.expiresAt = time.Now().Add(c.retryInterval)
+		c.mu.Unlock()
+		return flags
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.flags
+}
+
+// load Provider から読み込んでキャッシュを差し替え、変更があれば通知する
+// loadMu を取得した状態で呼び出すこと。
+func (c *Client) load(ctx context.Context) error {
+	flags, err := c.provider.Load(ctx)
+	if err != nil {
+		return err
+	}
+	for name, f := range flags {
+		f.Name = name
+		flags[name] = f
+	}
+
+	c.mu.Lock()
+	old, loaded := c.flags, c.flags != nil
+	c.flags = flags
+	c.expiresAt = time.Now().Add(c.ttl)
+	listeners := c.listeners
+	c.mu.Unlock()
+
+	if !loaded {
+		return nil
+	}
+	if changed := diff(old, flags); len(changed) > 0 {
+		for _, fn := range listeners {
+			fn(changed)
+		}
+	}
+	return nil
+}
+
+// diff 追加・削除・変更されたフラグ名を昇順で返す
+func diff(old, new map[string]Flag) []string {
+	var names []string
+	for name, f := range new {
+		if o, ok := old[name]; !ok || !reflect.DeepEqual(o, f) {
+			names = append(names, name)
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,211 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider テスト用の Provider
+type fakeProvider struct {
+	mu     sync.Mutex
+	flags  map[string]Flag
+	err    error
+	loads  int
+	notify chan struct{}
+}
+
+func newFakeProvider(flags map[string]Flag) *fakeProvider {
+	return &fakeProvider{flags: flags, notify: make(chan struct{})}
+}
+
+func (p *fakeProvider) Load(context.Context) (map[string]Flag, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loads++
+	if p.err != nil {
+		return nil, p.err
+	}
+	out := make(map[string]Flag, len(p.flags))
+	for name, f := range p.flags {
+		out[name] = f
+	}
+	return out, nil
+}
+
+func (p *fakeProvider) Watch(ctx context.Context, notify func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.notify:
+			notify()
+		}
+	}
+}
+
+func (p *fakeProvider) set(flags map[string]Flag, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flags, p.err = flags, err
+}
+
+func (p *fakeProvider) loadCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loads
+}
+
+func TestClient_IsEnabled(t *testing.T) {
+	ctx := context.Background()
+	p := newFakeProvider(map[string]Flag{"a": {Enabled: true}, "b": {Enabled: false}})
+	c := New(p)
+
+	tests := []struct {
+		name string
+		flag string
+		want bool
+	}{
+		{name: "正常値: 有効なフラグ", flag: "a", want: true},
+		{name: "正常値: 無効なフラグ", flag: "b", want: false},
+		{name: "異常値: 存在しないフラグ", flag: "c", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsEnabled(ctx, tt.flag, Target{}); got != tt.want {
+				t.Errorf("判定が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+
+	// 有効期限内は読み込み直さない
+	if got := p.loadCount(); got != 1 {
+		t.Errorf("読み込み回数が想定外です。got=%v, want=%v", got, 1)
+	}
+	// フラグ名はマップのキーで補完する
+	if f, _ := c.Flag(ctx, "a"); f.Name != "a" {
+		t.Errorf("フラグ名が想定外です。got=%v, want=%v", f.Name, "a")
+	}
+}
+
+func TestClient_TTL(t *testing.T) {
+	ctx := context.Background()
+	p := newFakeProvider(map[string]Flag{"a": {Enabled: true}})
+	c := New(p, WithTTL(10*time.Millisecond))
+
+	if !c.IsEnabled(ctx, "a", Target{}) {
+		t.Fatal("フラグが無効です。")
+	}
+	p.set(map[string]Flag{"a": {Enabled: false}}, nil)
+	if !c.IsEnabled(ctx, "a", Target{}) {
+		t.Error("有効期限内に読み込み直しました。")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if c.IsEnabled(ctx, "a", Target{}) {
+		t.Error("有効期限を過ぎても読み込み直していません。")
+	}
+
+	// 読み込みに失敗した場合は直前のフラグを使い続ける
+	p.set(nil, errors.New("unavailable"))
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Flag(ctx, "a"); !ok {
+		t.Error("読み込みの失敗でフラグが失われました。")
+	}
+}
+
+func TestClient_RetryInterval(t *testing.T) {
+	ctx := context.Background()
+	p := newFakeProvider(nil)
+	p.set(nil, errors.New("unavailable"))
+	c := New(p, WithTTL(time.Millisecond), WithRetryInterval(50*time.Millisecond))
+
+	// 読み込みに失敗した後は、再試行の間隔を過ぎるまで読み込み直さない
+	for range 10 {
+		c.IsEnabled(ctx, "a", Target{})
+	}
+	if got := p.loadCount(); got != 1 {
+		t.Errorf("読み込み回数が想定外です。got=%v, want=%v", got, 1)
+	}
+
+	p.set(map[string]Flag{"a": {Enabled: true}}, nil)
+	time.Sleep(60 * time.Millisecond)
+	if !c.IsEnabled(ctx, "a", Target{}) {
+		t.Error("再試行の間隔を過ぎても読み込み直していません。")
+	}
+	if got := p.loadCount(); got != 2 {
+		t.Errorf("読み込み回数が想定外です。got=%v, want=%v", got, 2)
+	}
+}
+
+func TestClient_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := newFakeProvider(map[string]Flag{"a": {Enabled: true}, "b": {Enabled: true}})
+	c := New(p, WithTTL(time.Hour))
+
+	changes := make(chan []string, 1)
+	c.OnChange(func(names []string) {
+		changes <- names
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Watch(ctx)
+	}()
+	// 最初の読み込みは変更として通知しない
+	p.notify <- struct{}{}
+	select {
+	case names := <-changes:
+		t.Fatalf("変更が無いのに通知されました。got=%v", names)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	p.set(map[string]Flag{"a": {Enabled: false}, "c": {Enabled: true}}, nil)
+	p.notify <- struct{}{}
+	select {
+	case names := <-changes:
+		if want := []string{"a", "b", "c"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("変更されたフラグが想定外です。got=%v, want=%v", names, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("変更が通知されませんでした。")
+	}
+	if c.IsEnabled(ctx, "a", Target{}) {
+		t.Error("変更が反映されていません。")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("エラーが想定外です。got=%v", err)
+	}
+}
+
+func TestClient_Watch_LoadError(t *testing.T) {
+	p := newFakeProvider(nil)
+	p.set(nil, errors.New("unavailable"))
+	c := New(p)
+
+	if err := c.Watch(context.Background()); err == nil {
+		t.Error("エラーが返されませんでした。")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := map[string]Flag{
+		"same":    {Name: "same", Enabled: true},
+		"changed": {Name: "changed", Enabled: true, Tenants: map[string]bool{"t1": true}},
+		"removed": {Name: "removed"},
+	}
+	new := map[string]Flag{
+		"same":    {Name: "same", Enabled: true},
+		"changed": {Name: "changed", Enabled: true, Tenants: map[string]bool{"t1": false}},
+		"added":   {Name: "added"},
+	}
+	want := []string{"added", "changed", "removed"}
+	if got := diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("変更されたフラグが想定外です。got=%v, want=%v", got, want)
+	}
+}
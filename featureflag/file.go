@@ -0,0 +1,101 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"valley-pkg/parser"
+)
+
+// fileDebounce 保存時に連続して発生するファイルイベントをまとめる時間
+const fileDebounce = 100 * time.Millisecond
+
+// FileProvider YAML または JSON のファイルからフラグを読み込む Provider
+// 拡張子が .json の場合は JSON、それ以外は YAML として読み込む。
+//
+//	flags:
+//	  new_shop:
+//	    enabled: true
+//	    percentage: 10
+//	    tenants:
+//	      tenant-a: true
+type FileProvider struct {
+	path string
+}
+
+var _ Provider = (*FileProvider)(nil)
+
+// fileFlags ファイルの形式
+type fileFlags struct {
+	Flags map[string]Flag `json:"flags" yaml:"flags"`
+}
+
+// NewFileProvider path のファイルから読み込む FileProvider を生成する
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Load ファイルを読み込む
+func (p *FileProvider) Load(ctx context.Context) (map[string]Flag, error) {
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", p.path, err)
+	}
+	var ps parser.Parser = &parser.YAMLParser{}
+	if strings.EqualFold(filepath.Ext(p.path), ".json") {
+		ps = &parser.JSONParser{}
+	}
+	var f fileFlags
+	if err := ps.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", p.path, err)
+	}
+	if f.Flags == nil {
+		f.Flags = make(map[string]Flag)
+	}
+	return f.Flags, nil
+}
+
+// Watch ファイルの変更を監視する
+// エディタが別のファイルに書き込んでから置き換える場合も検知できるよう、ディレクトリを監視する。
+func (p *FileProvider) Watch(ctx context.Context, notify func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(p.path)); err != nil {
+		return fmt.Errorf("watch %s: %w", p.path, err)
+	}
+
+	name := filepath.Clean(p.path)
+	timer := time.NewTimer(fileDebounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != name || event.Op == fsnotify.Chmod {
+				continue
+			}
+			timer.Reset(fileDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watch %s: %w", p.path, err)
+		case <-timer.C:
+			notify()
+		}
+	}
+}
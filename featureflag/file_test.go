@@ -0,0 +1,139 @@
+package featureflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileProvider_Load(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		want     map[string]Flag
+		wantErr  bool
+		noCreate bool
+	}{
+		{
+			name: "正常値: YAML",
+			file: "flags.yaml",
+			content: `flags:
+  new_shop:
+    enabled: true
+    percentage: 10
+    tenants:
+      tenant-a: true
+  old_shop:
+    enabled: false
+`,
+			want: map[string]Flag{
+				"new_shop": {Enabled: true, Percentage: Percentage(10), Tenants: map[string]bool{"tenant-a": true}},
+				"old_shop": {Enabled: false},
+			},
+		},
+		{
+			name:    "正常値: JSON",
+			file:    "flags.json",
+			content: `{"flags":{"new_shop":{"enabled":true}}}`,
+			want:    map[string]Flag{"new_shop": {Enabled: true}},
+		},
+		{
+			name:    "正常値: フラグなし",
+			file:    "flags.yaml",
+			content: ``,
+			want:    map[string]Flag{},
+		},
+		{
+			name:    "異常値: 形式が不正",
+			file:    "flags.json",
+			content: `{`,
+			wantErr: true,
+		},
+		{
+			name:     "異常値: ファイルが存在しない",
+			file:     "flags.yaml",
+			noCreate: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if !tt.noCreate {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := NewFileProvider(path).Load(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラーが想定外です。got=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("フラグの数が想定外です。got=%v, want=%v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				f := got[name]
+				if f.Enabled != want.Enabled || (f.Percentage == nil) != (want.Percentage == nil) ||
+					(f.Percentage != nil && *f.Percentage != *want.Percentage) || len(f.Tenants) != len(want.Tenants) {
+					t.Errorf("フラグが想定外です。name=%v, got=%+v, want=%+v", name, f, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFileProvider_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte("flags:\n  a:\n    enabled: false\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := New(NewFileProvider(path), WithTTL(time.Hour))
+	changes := make(chan []string, 1)
+	c.OnChange(func(names []string) {
+		changes <- names
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Watch(ctx)
+	}()
+
+	// 監視を開始するまで待つ
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := c.Flag(ctx, "a"); ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("flags:\n  a:\n    enabled: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case names := <-changes:
+		if len(names) != 1 || names[0] != "a" {
+			t.Errorf("変更されたフラグが想定外です。got=%v", names)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("変更が通知されませんでした。")
+	}
+	if !c.IsEnabled(ctx, "a", Target{}) {
+		t.Error("変更が反映されていません。")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("エラーが想定外です。got=%v", err)
+	}
+}
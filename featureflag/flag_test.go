@@ -0,0 +1,98 @@
+package featureflag
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFlag_Evaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   Flag
+		target Target
+		want   bool
+	}{
+		{
+			name:   "正常値: 有効",
+			flag:   Flag{Name: "a", Enabled: true},
+			target: Target{Tenant: "t1"},
+			want:   true,
+		},
+		{
+			name:   "正常値: 無効",
+			flag:   Flag{Name: "a"},
+			target: Target{Tenant: "t1"},
+			want:   false,
+		},
+		{
+			name:   "正常値: 無効の場合はテナントの上書きより優先する",
+			flag:   Flag{Name: "a", Tenants: map[string]bool{"t1": true}},
+			target: Target{Tenant: "t1"},
+			want:   false,
+		},
+		{
+			name:   "正常値: テナントで無効に上書き",
+			flag:   Flag{Name: "a", Enabled: true, Tenants: map[string]bool{"t1": false}},
+			target: Target{Tenant: "t1"},
+			want:   false,
+		},
+		{
+			name:   "正常値: テナントで有効に上書きした場合は割合より優先する",
+			flag:   Flag{Name: "a", Enabled: true, Percentage: Percentage(0), Tenants: map[string]bool{"t1": true}},
+			target: Target{Tenant: "t1", Key: "u1"},
+			want:   true,
+		},
+		{
+			name:   "正常値: 上書きの無いテナント",
+			flag:   Flag{Name: "a", Enabled: true, Tenants: map[string]bool{"t1": false}},
+			target: Target{Tenant: "t2"},
+			want:   true,
+		},
+		{
+			name:   "正常値: 割合が 0",
+			flag:   Flag{Name: "a", Enabled: true, Percentage: Percentage(0)},
+			target: Target{Key: "u1"},
+			want:   false,
+		},
+		{
+			name:   "正常値: 割合が 100",
+			flag:   Flag{Name: "a", Enabled: true, Percentage: Percentage(100)},
+			target: Target{Key: "u1"},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flag.Evaluate(tt.target); got != tt.want {
+				t.Errorf("判定が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlag_Evaluate_Percentage(t *testing.T) {
+	f := Flag{Name: "rollout", Enabled: true, Percentage: Percentage(25)}
+
+	const n = 10000
+	enabled := 0
+	for i := range n {
+		target := Target{Key: strconv.Itoa(i)}
+		got := f.Evaluate(target)
+		// 同じ対象は常に同じ結果になる
+		if got != f.Evaluate(target) {
+			t.Fatalf("判定が一定ではありません。key=%v", i)
+		}
+		if got {
+			enabled++
+		}
+	}
+	if enabled < n*20/100 || enabled > n*30/100 {
+		t.Errorf("有効になった割合が想定外です。got=%v/%v", enabled, n)
+	}
+
+	// Key が空の場合は Tenant で判定する
+	target := Target{Tenant: "tenant-a"}
+	if got, want := f.Evaluate(target), f.Evaluate(Target{Key: "tenant-a"}); got != want {
+		t.Errorf("判定が想定外です。got=%v, want=%v", got, want)
+	}
+}
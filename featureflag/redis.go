@@ -0,0 +1,85 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"valley-pkg/redis"
+)
+
+// RedisProvider Redis のハッシュからフラグを読み込む Provider
+// ハッシュのフィールドがフラグ名、値がフラグの JSON となる。
+// Watch はキースペース通知を使用するため、Redis の notify-keyspace-events に "Kh"（または "KA"）を設定すること。
+type RedisProvider struct {
+	redis *redis.RedisClient
+	key   string
+}
+
+var _ Provider = (*RedisProvider)(nil)
+
+// NewRedisProvider key のハッシュから読み込む RedisProvider を生成する
+func NewRedisProvider(rc *redis.RedisClient, key string) *RedisProvider {
+	return &RedisProvider{redis: rc, key: key}
+}
+
+// Load ハッシュを読み込む
+func (p *RedisProvider) Load(ctx context.Context) (map[string]Flag, error) {
+	values, err := p.redis.HGetAllContext(ctx, p.key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFlags(values)
+}
+
+// Set フラグを保存する
+func (p *RedisProvider) Set(ctx context.Context, f Flag) error {
+	if f.Name == "" {
+		return errors.New("flag name is empty")
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", f.Name, err)
+	}
+	return p.redis.HSetContext(ctx, p.key, map[string]interface{}{f.Name: b})
+}
+
+// Delete フラグを削除する
+func (p *RedisProvider) Delete(ctx context.Context, name string) error {
+	_, err := p.redis.HDelContext(ctx, p.key, name)
+	return err
+}
+
+// Watch キースペース通知でハッシュの変更を監視する
+func (p *RedisProvider) Watch(ctx context.Context, notify func()) error {
+	pubsub := redis.NewPubSubService(p.redis)
+	// DB 番号によらず購読するため、パターンで購読する
+	sub, err := pubsub.PSubscribe(ctx, []string{"__keyspace@*__:" + p.key}, func(ctx context.Context, msg redis.Message) error {
+		notify()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	select {
+	case <-ctx.Done():
+	case <-sub.Done():
+	}
+	return nil
+}
+
+// decodeFlags ハッシュの値をフラグにする
+func decodeFlags(values map[string]string) (map[string]Flag, error) {
+	flags := make(map[string]Flag, len(values))
+	for name, v := range values {
+		var f Flag
+		if err := json.Unmarshal([]byte(v), &f); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", name, err)
+		}
+		flags[name] = f
+	}
+	return flags, nil
+}
@@ -0,0 +1,38 @@
+package featureflag
+
+import "testing"
+
+func TestDecodeFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:   "正常値: フラグあり",
+			values: map[string]string{"a": `{"enabled":true,"percentage":50}`, "b": `{"enabled":false}`},
+			want:   2,
+		},
+		{
+			name:   "正常値: ハッシュが空",
+			values: map[string]string{},
+		},
+		{
+			name:    "異常値: JSON ではない",
+			values:  map[string]string{"a": `{`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeFlags(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラーが想定外です。got=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("フラグの数が想定外です。got=%v, want=%v", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package grpctransport
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"valley-pkg/crypter"
+	"valley-pkg/logging"
+	"valley-pkg/tcp"
+)
+
+// ErrClosed は Close 後に書き込んだ場合のエラー
+var ErrClosed = errors.New("grpc stream is closed")
+
+// stream はクライアントとサーバーのストリームで共通の操作
+type stream interface {
+	Context() context.Context
+	SendMsg(m any) error
+	RecvMsg(m any) error
+}
+
+// Option はConnの設定
+type Option func(*Conn)
+
+// WithLogger はログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(c *Conn) {
+		c.logger = l
+	}
+}
+
+// recvResult は受信したメッセージ
+type recvResult struct {
+	b   []byte
+	err error
+}
+
+// Conn は gRPC のストリームでメッセージをやり取りする tcp.Conn の実装
+// 読み取りと書き込みはそれぞれ1つのゴルーチンから行う想定だが、同時に呼び出しても安全。
+type Conn struct {
+	stream    stream
+	closeSend func() error
+	format    string
+
+	mu         sync.Mutex
+	parser     tcp.ParserType
+	compressor tcp.CompressorType
+	crypter    crypter.Crypter
+	deadline   time.Time
+	closed     bool
+
+	sendMu sync.Mutex
+	recvMu sync.Mutex
+	recv   chan recvResult
+	quit   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+	logger logging.Logger
+}
+
+var _ tcp.Conn = (*Conn)(nil)
+
+// newConn はストリームの受信を開始した Conn を返す
+func newConn(s stream, format string, closeSend func() error, opts []Option) *Conn {
+	c := &Conn{
+		stream:     s,
+		closeSend:  closeSend,
+		format:     format,
+		parser:     tcp.DefaultParser,
+		compressor: tcp.DefaultCompressor,
+		recv:       make(chan recvResult),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.logger = logging.OrDefault(c.logger)
+	go c.receive()
+	return c
+}
+
+// receive はストリームの受信を ReadMessage に渡す
+// 締め切りで ReadMessage が返っても受信中のメッセージを失わないように、受信は専用のゴルーチンで行う。
+func (c *Conn) receive() {
+	defer close(c.done)
+	for {
+		v := &wrapperspb.BytesValue{}
+		err := c.stream.RecvMsg(v)
+		select {
+		case c.recv <- recvResult{b: v.GetValue(), err: err}:
+		case <-c.quit:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// stop は以降の受信を読み捨て、受信のゴルーチンが終了できるようにする
+func (c *Conn) stop() {
+	c.once.Do(func() {
+		close(c.quit)
+	})
+}
+
+// RemoteAddr は接続先のアドレス
+func (c *Conn) RemoteAddr() net.Addr {
+	if p, ok := peer.FromContext(c.stream.Context()); ok {
+		return p.Addr
+	}
+	return nil
+}
+
+// SetParser はParserを設定する
+func (c *Conn) SetParser(p tcp.ParserType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.parser = p
+}
+
+// SetCompressor はCompressorを設定する
+func (c *Conn) SetCompressor(comp tcp.CompressorType) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compressor = comp
+}
+
+// SetCrypter はcrypterを設定する
+func (c *Conn) SetCrypter(cr crypter.Crypter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.crypter = cr
+}
+
+// SetDeadLine は読み取りの締め切りを現在から seconds 秒後に設定する
+// 締め切りを過ぎた ReadMessage は os.ErrDeadlineExceeded を返す。0 以下の場合は締め切りを解除する。
+func (c *Conn) SetDeadLine(seconds int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if seconds <= 0 {
+		c.deadline = time.Time{}
+		return
+	}
+	c.deadline = time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// WriteMessage はストリームにメッセージを書き込む
+func (c *Conn) WriteMessage(kind int8, m proto.Message) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	message := tcp.NewMessage(c.format, kind, c.parser, c.compressor, c.crypter)
+	c.mu.Unlock()
+
+	if err := message.PackWriteBody(m); err != nil {
+		return errors.Errorf("failed to create message: %w", err)
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	if err := c.stream.SendMsg(wrapperspb.Bytes(message.ToByte())); err != nil {
+		return convertError(err)
+	}
+	return nil
+}
+
+// ReadMessage はストリームからメッセージの読み取りを行う
+func (c *Conn) ReadMessage() (*tcp.TcpMessage, error) {
+	c.recvMu.Lock()
+	defer c.recvMu.Unlock()
+
+	c.mu.Lock()
+	deadline, cr := c.deadline, c.crypter
+	c.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var r recvResult
+	select {
+	case r = <-c.recv:
+	case <-c.quit:
+		return nil, tcp.ErrClosedConnection
+	case <-c.done:
+		return nil, tcp.ErrClosedConnection
+	case <-timeout:
+		return nil, errors.Errorf("grpc read error: %w", os.ErrDeadlineExceeded)
+	}
+	if r.err != nil {
+		return nil, convertError(r.err)
+	}
+	if len(r.b) == 0 {
+		return nil, tcp.ErrHealthCheck
+	}
+
+	message, err := tcp.NewMessageFromByte(c.format, r.b, cr)
+	if err != nil {
+		c.logger.Info("grpc message is invalid", logging.Err(err), logging.F("remote_addr", c.RemoteAddr()))
+		return nil, err
+	}
+	return message, nil
+}
+
+// Close は送信を終了する
+// サーバー側の Conn では何もしない。サーバー側は Handler が返るとストリームを終了する。
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	c.stop()
+	if c.closeSend == nil {
+		return nil
+	}
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.closeSend()
+}
+
+// convertError は gRPC のエラーを tcp パッケージのエラーに変換する
+// ハンドラーが tcp の場合と同じようにエラーを判定できるようにする。
+func convertError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return tcp.ErrEof
+	}
+	switch status.Code(err) {
+	case codes.Canceled, codes.Unavailable:
+		return tcp.ErrClosedConnection
+	case codes.DeadlineExceeded:
+		return errors.Errorf("grpc stream error: %v: %w", err, os.ErrDeadlineExceeded)
+	}
+	return errors.Errorf("grpc stream error: %w", err)
+}
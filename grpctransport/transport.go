@@ -0,0 +1,77 @@
+// Package grpctransport は tcp パッケージと同じメッセージを gRPC の双方向ストリームで送受信する
+// ストリーム上の1メッセージは tcp.TcpMessage.ToByte と同じバイト列で、tcp.Conn をそのまま実装するため、
+// HTTP/2 のみを通すロードバランサーの背後でも tcp 向けのハンドラーを再利用できる。
+package grpctransport
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"valley-pkg/tcp"
+)
+
+const (
+	// ServiceName は gRPC のサービス名
+	ServiceName = "valley.transport.Transport"
+	// StreamName は双方向ストリームのメソッド名
+	StreamName = "Stream"
+	// streamMethod はストリームのフルメソッド名
+	streamMethod = "/" + ServiceName + "/" + StreamName
+)
+
+// Handler はストリームごとに呼び出される処理
+// Handler が返るとストリームを終了する。
+type Handler func(conn tcp.Conn) error
+
+// streamServer はサービスの実装
+type streamServer struct {
+	format  string
+	handler Handler
+	opts    []Option
+}
+
+// RegisterServer は s にストリームを受け付けるサービスを登録する
+// クライアントがストリームを開くたびに、tcp.NewConn の代わりとなる Conn を handler に渡す。
+func RegisterServer(s grpc.ServiceRegistrar, format string, handler Handler, opts ...Option) {
+	s.RegisterService(&serviceDesc, &streamServer{format: format, handler: handler, opts: opts})
+}
+
+// serviceDesc は protoc で生成する代わりに手書きしたサービス定義
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    StreamName,
+			Handler:       streamHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// streamHandler はストリームを Conn に変換して Handler を呼び出す
+func streamHandler(srv any, stream grpc.ServerStream) error {
+	s := srv.(*streamServer)
+	conn := newConn(stream, s.format, nil, s.opts)
+	defer conn.stop()
+	return s.handler(conn)
+}
+
+// Dial は cc でストリームを開き、tcp.Conn として扱える Conn を返す
+// Close を呼ぶと送信を終了し、サーバーがストリームを終了した時点で資源を解放する。強制的に終了する場合は ctx をキャンセルする。
+func Dial(ctx context.Context, cc grpc.ClientConnInterface, format string, opts ...Option) (*Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := cc.NewStream(ctx, &serviceDesc.Streams[0], streamMethod)
+	if err != nil {
+		cancel()
+		return nil, errors.Errorf("failed to open grpc stream: %w", err)
+	}
+	conn := newConn(stream, format, stream.CloseSend, opts)
+	go func() {
+		<-conn.done
+		cancel()
+	}()
+	return conn, nil
+}
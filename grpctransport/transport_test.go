@@ -0,0 +1,159 @@
+package grpctransport
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"valley-pkg/tcp"
+)
+
+const testFormat = "TGR"
+
+// newTestClient は handler を登録したサーバーに接続したクライアントを返す
+func newTestClient(t *testing.T, handler Handler) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	RegisterServer(s, testFormat, handler)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient error: %v", err)
+	}
+	t.Cleanup(func() { _ = cc.Close() })
+	return cc
+}
+
+// echo は受信したメッセージの Kind を1つ増やして返す
+func echo(conn tcp.Conn) error {
+	for {
+		msg, err := conn.ReadMessage()
+		if err != nil {
+			if errors.Is(err, tcp.ErrEof) {
+				return nil
+			}
+			return err
+		}
+		v := &wrapperspb.StringValue{}
+		if err := msg.UnpackReadBody(v); err != nil {
+			return err
+		}
+		if err := conn.WriteMessage(msg.Kind+1, v); err != nil {
+			return err
+		}
+	}
+}
+
+func TestConn_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		parser     tcp.ParserType
+		compressor tcp.CompressorType
+		value      string
+	}{
+		{name: "正常値: JSON", parser: tcp.JSON, compressor: tcp.None, value: "hello"},
+		{name: "正常値: PROTOBUF と ZSTD", parser: tcp.PROTOBUF, compressor: tcp.ZSTD, value: string(make([]byte, 1024))},
+	}
+	cc := newTestClient(t, echo)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := Dial(context.Background(), cc, testFormat)
+			if err != nil {
+				t.Fatalf("Dial error: %v", err)
+			}
+			defer conn.Close()
+			conn.SetParser(tt.parser)
+			conn.SetCompressor(tt.compressor)
+
+			if err := conn.WriteMessage(1, wrapperspb.String(tt.value)); err != nil {
+				t.Fatalf("WriteMessage error: %v", err)
+			}
+			msg, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("ReadMessage error: %v", err)
+			}
+			if msg.Kind != 2 {
+				t.Errorf("Kind が想定外です。got=%v, want=%v", msg.Kind, 2)
+			}
+			got := &wrapperspb.StringValue{}
+			if err := msg.UnpackReadBody(got); err != nil {
+				t.Fatalf("UnpackReadBody error: %v", err)
+			}
+			if got.GetValue() != tt.value {
+				t.Errorf("値が想定外です。got=%q, want=%q", got.GetValue(), tt.value)
+			}
+		})
+	}
+}
+
+func TestConn_ReadMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler Handler
+		setup   func(conn *Conn)
+		wantErr error
+	}{
+		{
+			name:    "異常値: サーバーがストリームを終了",
+			handler: func(tcp.Conn) error { return nil },
+			wantErr: tcp.ErrEof,
+		},
+		{
+			name: "異常値: 締め切りを過ぎた",
+			handler: func(conn tcp.Conn) error {
+				_, err := conn.ReadMessage()
+				return err
+			},
+			setup:   func(conn *Conn) { conn.SetDeadLine(1) },
+			wantErr: os.ErrDeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := newTestClient(t, tt.handler)
+			conn, err := Dial(context.Background(), cc, testFormat)
+			if err != nil {
+				t.Fatalf("Dial error: %v", err)
+			}
+			defer conn.Close()
+			if tt.setup != nil {
+				tt.setup(conn)
+			}
+
+			start := time.Now()
+			_, err = conn.ReadMessage()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v (elapsed=%v)", err, tt.wantErr, time.Since(start))
+			}
+		})
+	}
+}
+
+func TestConn_WriteMessage_Closed(t *testing.T) {
+	cc := newTestClient(t, echo)
+	conn, err := Dial(context.Background(), cc, testFormat)
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if err := conn.WriteMessage(1, wrapperspb.String("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrClosed)
+	}
+}
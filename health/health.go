@@ -0,0 +1,235 @@
+// Package health は各コンポーネントの状態を集約し、readiness と liveness のプローブに使える形で公開する
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// defaultTimeout 1つのチェックにかける時間のデフォルト
+const defaultTimeout = 5 * time.Second
+
+// Status コンポーネントまたは全体の状態
+type Status string
+
+const (
+	// StatusUp 正常
+	StatusUp Status = "up"
+	// StatusDegraded 必須ではないコンポーネントが異常
+	StatusDegraded Status = "degraded"
+	// StatusDown 必須のコンポーネントが異常
+	StatusDown Status = "down"
+)
+
+// ErrTimeout チェックが時間内に終わらなかった場合のエラー
+var ErrTimeout = errors.New("health check timeout")
+
+// Checker コンポーネントの状態を確認する
+// 正常な場合は nil を返す。ctx の期限までに返す必要がある。
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 関数を Checker として扱う
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// ComponentStatus コンポーネントごとの結果
+type ComponentStatus struct {
+	Status Status `json:"status"`
+	// Error 異常な場合のエラーメッセージ
+	Error string `json:"error,omitempty"`
+	// Duration チェックにかかった時間
+	Duration time.Duration `json:"duration"`
+}
+
+// Report 全体とコンポーネントごとの結果
+type Report struct {
+	Status     Status                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+	CheckedAt  time.Time                  `json:"checked_at"`
+}
+
+// Healthy は全体の状態が StatusDown でないかを返す
+func (r Report) Healthy() bool {
+	return r.Status != StatusDown
+}
+
+// check 登録されたチェック
+type check struct {
+	name     string
+	checker  Checker
+	liveness bool
+	optional bool
+	timeout  time.Duration
+}
+
+// CheckOption 登録するチェックの設定
+type CheckOption func(*check)
+
+// WithLiveness は readiness に加えて liveness のチェックにも含める
+// プロセスを再起動しないと回復しない状態のみを対象にする。
+func WithLiveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+// WithOptional は異常な場合に全体を StatusDown ではなく StatusDegraded とする
+func WithOptional() CheckOption {
+	return func(c *check) {
+		c.optional = true
+	}
+}
+
+// WithCheckTimeout はこのチェックの時間の上限を指定する
+func WithCheckTimeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// Option Aggregator の設定
+type Option func(*Aggregator)
+
+// WithTimeout はチェックの時間の上限のデフォルトを指定する。指定しない場合は 5秒
+func WithTimeout(d time.Duration) Option {
+	return func(a *Aggregator) {
+		a.timeout = d
+	}
+}
+
+// Aggregator 登録されたチェックを並行して実行し、結果を集約する
+type Aggregator struct {
+	mu      sync.RWMutex
+	checks  map[string]check
+	timeout time.Duration
+}
+
+// NewAggregator コンストラクタ
+func NewAggregator(opts ...Option) *Aggregator {
+	a := &Aggregator{checks: map[string]check{}, timeout: defaultTimeout}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Register は name でチェックを登録する
+// 同じ name の二重登録や nil の登録はプログラムの誤りなので panic する。
+func (a *Aggregator) Register(name string, checker Checker, opts ...CheckOption) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if checker == nil {
+		panic(fmt.Sprintf("health: Register checker is nil for %s", name))
+	}
+	if _, dup := a.checks[name]; dup {
+		panic(fmt.Sprintf("health: Register called twice for %s", name))
+	}
+	c := check{name: name, checker: checker, timeout: a.timeout}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	a.checks[name] = c
+}
+
+// Unregister は name のチェックを削除する
+func (a *Aggregator) Unregister(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.checks, name)
+}
+
+// Readiness は登録されたすべてのチェックを実行する
+// リクエストを受け付けられる状態かの判定に使用する。
+func (a *Aggregator) Readiness(ctx context.Context) Report {
+	return a.run(ctx, func(check) bool { return true })
+}
+
+// Liveness は WithLiveness で登録したチェックのみを実行する
+// プロセスの再起動が必要かの判定に使用する。
+func (a *Aggregator) Liveness(ctx context.Context) Report {
+	return a.run(ctx, func(c check) bool { return c.liveness })
+}
+
+// run は filter に一致するチェックを並行して実行する
+func (a *Aggregator) run(ctx context.Context, filter func(check) bool) Report {
+	a.mu.RLock()
+	var targets []check
+	for _, c := range a.checks {
+		if filter(c) {
+			targets = append(targets, c)
+		}
+	}
+	a.mu.RUnlock()
+	sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+
+	results := make([]ComponentStatus, len(targets))
+	var wg sync.WaitGroup
+	for i, c := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Components: make(map[string]ComponentStatus, len(targets)), CheckedAt: time.Now()}
+	for i, c := range targets {
+		report.Components[c.name] = results[i]
+		if results[i].Status == StatusUp {
+			continue
+		}
+		if !c.optional {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run はチェックを実行する
+// 時間内に終わらない場合や panic した場合は異常とする。
+func (c check) run(ctx context.Context) ComponentStatus {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errors.Errorf("health check panic: %v", r)
+			}
+		}()
+		done <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.Errorf("%v: %w", context.Cause(ctx), ErrTimeout)
+	}
+
+	status := ComponentStatus{Status: StatusUp, Duration: time.Since(start)}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
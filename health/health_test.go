@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("down")
+
+func up(context.Context) error   { return nil }
+func down(context.Context) error { return errDown }
+
+func TestAggregator_Readiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(a *Aggregator)
+		want     Status
+		wantErrs map[string]bool
+	}{
+		{
+			name:     "正常値: チェックなし",
+			register: func(a *Aggregator) {},
+			want:     StatusUp,
+		},
+		{
+			name: "正常値: すべて正常",
+			register: func(a *Aggregator) {
+				a.Register("redis", CheckerFunc(up))
+				a.Register("mysql", CheckerFunc(up))
+			},
+			want:     StatusUp,
+			wantErrs: map[string]bool{"redis": false, "mysql": false},
+		},
+		{
+			name: "異常値: 必須のチェックが異常",
+			register: func(a *Aggregator) {
+				a.Register("redis", CheckerFunc(up))
+				a.Register("mysql", CheckerFunc(down))
+			},
+			want:     StatusDown,
+			wantErrs: map[string]bool{"redis": false, "mysql": true},
+		},
+		{
+			name: "異常値: 必須ではないチェックのみ異常",
+			register: func(a *Aggregator) {
+				a.Register("redis", CheckerFunc(up))
+				a.Register("cache", CheckerFunc(down), WithOptional())
+			},
+			want:     StatusDegraded,
+			wantErrs: map[string]bool{"redis": false, "cache": true},
+		},
+		{
+			name: "異常値: タイムアウト",
+			register: func(a *Aggregator) {
+				a.Register("slow", CheckerFunc(func(ctx context.Context) error {
+					time.Sleep(200 * time.Millisecond)
+					return nil
+				}), WithCheckTimeout(10*time.Millisecond))
+			},
+			want:     StatusDown,
+			wantErrs: map[string]bool{"slow": true},
+		},
+		{
+			name: "異常値: panic",
+			register: func(a *Aggregator) {
+				a.Register("panic", CheckerFunc(func(context.Context) error { panic("boom") }))
+			},
+			want:     StatusDown,
+			wantErrs: map[string]bool{"panic": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAggregator()
+			tt.register(a)
+
+			got := a.Readiness(context.Background())
+			if got.Status != tt.want {
+				t.Errorf("Status が想定外です。got=%v, want=%v", got.Status, tt.want)
+			}
+			if len(got.Components) != len(tt.wantErrs) {
+				t.Errorf("Components の数が想定外です。got=%v, want=%v", len(got.Components), len(tt.wantErrs))
+			}
+			for name, wantErr := range tt.wantErrs {
+				if c := got.Components[name]; (c.Error != "") != wantErr {
+					t.Errorf("%s の Error が想定外です。got=%q, wantErr=%v", name, c.Error, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregator_Liveness(t *testing.T) {
+	a := NewAggregator()
+	a.Register("process", CheckerFunc(up), WithLiveness())
+	a.Register("mysql", CheckerFunc(down))
+
+	got := a.Liveness(context.Background())
+	if got.Status != StatusUp {
+		t.Errorf("Status が想定外です。got=%v, want=%v", got.Status, StatusUp)
+	}
+	if _, ok := got.Components["mysql"]; ok {
+		t.Errorf("liveness に含めないチェックが実行されています")
+	}
+}
+
+func TestAggregator_Register(t *testing.T) {
+	a := NewAggregator()
+	a.Register("redis", CheckerFunc(up))
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("二重登録で panic しません")
+		}
+	}()
+	a.Register("redis", CheckerFunc(up))
+}
+
+func TestAggregator_Unregister(t *testing.T) {
+	a := NewAggregator()
+	a.Register("mysql", CheckerFunc(down))
+	a.Unregister("mysql")
+
+	if got := a.Readiness(context.Background()); got.Status != StatusUp {
+		t.Errorf("Status が想定外です。got=%v, want=%v", got.Status, StatusUp)
+	}
+}
@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ReadinessHandler は Readiness の結果を JSON で返す http.Handler を返す
+// StatusDown の場合はステータスコード 503 を返す。
+func (a *Aggregator) ReadinessHandler() http.Handler {
+	return reportHandler(a.Readiness)
+}
+
+// LivenessHandler は Liveness の結果を JSON で返す http.Handler を返す
+// StatusDown の場合はステータスコード 503 を返す。
+func (a *Aggregator) LivenessHandler() http.Handler {
+	return reportHandler(a.Liveness)
+}
+
+// reportHandler は check の結果を JSON で返す
+func reportHandler(check func(ctx context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !report.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
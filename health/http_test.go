@@ -0,0 +1,42 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAggregator_ReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		checker    Checker
+		opts       []CheckOption
+		wantCode   int
+		wantStatus Status
+	}{
+		{name: "正常値: 正常", checker: CheckerFunc(up), wantCode: http.StatusOK, wantStatus: StatusUp},
+		{name: "正常値: 必須ではないチェックが異常", checker: CheckerFunc(down), opts: []CheckOption{WithOptional()}, wantCode: http.StatusOK, wantStatus: StatusDegraded},
+		{name: "異常値: 異常", checker: CheckerFunc(down), wantCode: http.StatusServiceUnavailable, wantStatus: StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAggregator()
+			a.Register("component", tt.checker, tt.opts...)
+
+			rec := httptest.NewRecorder()
+			a.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("ステータスコードが想定外です。got=%v, want=%v", rec.Code, tt.wantCode)
+			}
+			var report Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("json.Unmarshal error: %v", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("Status が想定外です。got=%v, want=%v", report.Status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Package idempotency は Redis を使った冪等キーを提供する
+//
+// クライアントがリトライした同じ要求（購入など）を二重に適用しないために、
+// 要求ごとの冪等キーで処理中の状態と処理結果を保存する。
+//
+//	store := idempotency.NewStore(rc)
+//	res, err := idempotency.Do(ctx, store, req.RequestID, 24*time.Hour, func(ctx context.Context) (*PurchaseResult, error) {
+//		return purchase(ctx, req)
+//	})
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"valley-pkg/parser"
+	"valley-pkg/redis"
+)
+
+var (
+	// ErrInProgress 同じキーの処理が実行中の場合のエラー
+	ErrInProgress = errors.New("idempotency key in progress")
+	// ErrEmptyKey キーが空の場合のエラー
+	ErrEmptyKey = errors.New("idempotency key is empty")
+	// ErrNotOwner 処理中の状態が有効期限切れなどで他の呼び出しのものになっている場合のエラー
+	ErrNotOwner = errors.New("idempotency key is not owned by this call")
+)
+
+// State Begin の結果
+type State int
+
+const (
+	// StateProceed 初めての要求のため処理を実行する。終了後に Complete または Abort を呼ぶこと
+	StateProceed State = iota + 1
+	// StateInProgress 同じキーの処理が実行中
+	StateInProgress
+	// StateCompleted 同じキーの処理が完了済みで、Outcome.Result に結果がある
+	StateCompleted
+)
+
+// String 状態の名前を返す
+func (s State) String() string {
+	switch s {
+	case StateProceed:
+		return "proceed"
+	case StateInProgress:
+		return "in_progress"
+	case StateCompleted:
+		return "completed"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Outcome Begin の結果
+type Outcome struct {
+	State State
+	// Result StateCompleted の場合、Complete で保存した結果
+	Result []byte
+	// Token StateProceed の場合、この呼び出しの処理中の状態を表すトークン。Complete と Abort に渡す
+	Token string
+}
+
+// 保存する値の先頭の1文字で状態を表す
+// 処理中の値は呼び出しごとのトークンを付けた "p:<uuid>" とする。
+const (
+	markInProgress = "p"
+	markCompleted  = "c"
+)
+
+// beginScript キーが無い場合は処理中として保存して 0 を、ある場合は保存済みの値を返す
+// SET NX の失敗後にキーが期限切れになった場合は SET NX からやり直す。
+// KEYS[1]: キー、ARGV[1]: 処理中の値、ARGV[2]: 処理中の有効期限（ミリ秒）
+const beginScript = `
+for i = 1, 3 do
+	if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+		return 0
+	end
+	local v = redis.call('GET', KEYS[1])
+	if v then
+		return v
+	end
+end
+return redis.error_reply('idempotency key expired repeatedly')
+`
+
+// completeScript 自身の処理中の状態の場合のみ結果を保存し 1 を返す。それ以外は 0 を返す
+// KEYS[1]: キー、ARGV[1]: 処理中の値、ARGV[2]: 保存する値、ARGV[3]: 有効期限（ミリ秒、0 は無期限）
+const completeScript = `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`
+
+// abortScript 自身の処理中の状態の場合のみキーを削除する
+// KEYS[1]: キー、ARGV[1]: 処理中の値
+const abortScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// Option Store の設定
+type Option func(*Store)
+
+// WithPrefix キーの接頭辞を指定する。指定しない場合は "idempotency:"
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// WithInProgressTTL 処理中の状態の有効期限を d にする。指定しない場合は1分
+// Complete も Abort も呼ばずに異常終了した場合、d を過ぎると同じキーで再度処理できる。
+// 処理の最長の所要時間より長くすること。
+func WithInProgressTTL(d time.Duration) Option {
+	return func(s *Store) {
+		s.inProgressTTL = d
+	}
+}
+
+// WithParser Do と Wrap が結果の変換に使用するパーサーを指定する。指定しない場合は JSON
+func WithParser(p parser.Parser) Option {
+	return func(s *Store) {
+		s.parser = p
+	}
+}
+
+// Store 冪等キーの状態を Redis に保存する
+type Store struct {
+	redis         *redis.RedisClient
+	prefix        string
+	inProgressTTL time.Duration
+	parser        parser.Parser
+}
+
+// NewStore Store を生成する
+func NewStore(rc *redis.RedisClient, opts ...Option) *Store {
+	s := &Store{
+		redis:         rc,
+		prefix:        "idempotency:",
+		inProgressTTL: time.Minute,
+		parser:        &parser.JSONParser{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Begin key の処理を開始する
+// 初めてのキーは処理中として保存して StateProceed とトークンを、処理中のキーは StateInProgress を、
+// 完了済みのキーは保存済みの結果と StateCompleted を返す。
+func (s *Store) Begin(ctx context.Context, key string) (Outcome, error) {
+	if key == "" {
+		return Outcome{}, ErrEmptyKey
+	}
+	token := inProgressValue(uuid.NewString())
+	result, err := s.redis.Eval(ctx, beginScript, []string{s.prefix + key}, token, s.inProgressTTL.Milliseconds())
+	if err != nil {
+		return Outcome{}, fmt.Errorf("begin %s: %w", key, err)
+	}
+	outcome, err := decodeOutcome(result)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if outcome.State == StateProceed {
+		outcome.Token = token
+	}
+	return outcome, nil
+}
+
+// Complete key の処理の結果を保存し、ttl の間は同じキーの Begin で結果を返す
+// token は Begin が返したトークン。処理中の状態が期限切れで他の呼び出しのものになっている場合は、
+// 保存せずに ErrNotOwner を返す。ttl が 0 の場合は有効期限を設定しない。
+func (s *Store) Complete(ctx context.Context, key, token string, result []byte, ttl time.Duration) error {
+	if key == "" {
+		return ErrEmptyKey
+	}
+	saved, err := s.redis.Eval(ctx, completeScript, []string{s.prefix + key}, token, markCompleted+string(result), ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("complete %s: %w", key, err)
+	}
+	if saved != int64(1) {
+		return fmt.Errorf("complete %s: %w", key, ErrNotOwner)
+	}
+	return nil
+}
+
+// Abort 処理中の key を削除し、同じキーで再度処理できるようにする
+// token は Begin が返したトークン。処理が失敗し、リトライで再実行してよい場合に呼ぶ。
+// 完了済みのキーや、期限切れで他の呼び出しのものになった処理中のキーは削除しない。
+func (s *Store) Abort(ctx context.Context, key, token string) error {
+	if key == "" {
+		return ErrEmptyKey
+	}
+	if _, err := s.redis.Eval(ctx, abortScript, []string{s.prefix + key}, token); err != nil {
+		return fmt.Errorf("abort %s: %w", key, err)
+	}
+	return nil
+}
+
+// inProgressValue トークンから処理中の値を作る
+func inProgressValue(token string) string {
+	return markInProgress + ":" + token
+}
+
+// decodeOutcome beginScript の戻り値を Outcome にする
+func decodeOutcome(result interface{}) (Outcome, error) {
+	switch v := result.(type) {
+	case int64:
+		return Outcome{State: StateProceed}, nil
+	case string:
+		if len(v) > 0 && v[:1] == markInProgress {
+			return Outcome{State: StateInProgress}, nil
+		}
+		if len(v) > 0 && v[:1] == markCompleted {
+			return Outcome{State: StateCompleted, Result: []byte(v[1:])}, nil
+		}
+	}
+	return Outcome{}, fmt.Errorf("unexpected idempotency value: %v", result)
+}
+
+// Do key が初めての場合は fn を実行して結果を ttl の間保存し、完了済みの場合は保存済みの結果を返す
+// 同じキーの処理が実行中の場合は ErrInProgress を返す。
+// fn がエラーを返した場合は結果を保存せず、同じキーで再度実行できるようにする。
+func Do[T any](ctx context.Context, s *Store, key string, ttl time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	outcome, err := s.Begin(ctx, key)
+	if err != nil {
+		return zero, err
+	}
+	switch outcome.State {
+	case StateCompleted:
+		v, err := parser.Unmarshal[T](s.parser, outcome.Result)
+		if err != nil {
+			return zero, fmt.Errorf("unmarshal %s: %w", key, err)
+		}
+		return v, nil
+	case StateInProgress:
+		return zero, fmt.Errorf("%s: %w", key, ErrInProgress)
+	}
+
+	// 結果の保存は ctx が終了していても行う
+	bg := context.WithoutCancel(ctx)
+	v, err := fn(ctx)
+	if err != nil {
+		if abortErr := s.Abort(bg, key, outcome.Token); abortErr != nil {
+			return zero, errors.Join(err, abortErr)
+		}
+		return zero, err
+	}
+	// fn は適用済みのため、以降のエラーでは Abort せずに処理中のまま残す
+	b, err := parser.Marshal(s.parser, v)
+	if err != nil {
+		return zero, fmt.Errorf("marshal %s: %w", key, err)
+	}
+	if err := s.Complete(bg, key, outcome.Token, b, ttl); err != nil {
+		return zero, err
+	}
+	return v, nil
+}
+
+// Wrap ハンドラーを冪等にする
+// key で要求から冪等キーを求め、Do と同様に実行する。key が空文字を返した要求はそのまま h を実行する。
+// tcp のメッセージハンドラーや mysql の更新処理を包み、クライアントのリトライによる二重適用を防ぐ。
+func Wrap[Req, Res any](s *Store, key func(Req) string, ttl time.Duration, h func(ctx context.Context, req Req) (Res, error)) func(ctx context.Context, req Req) (Res, error) {
+	return func(ctx context.Context, req Req) (Res, error) {
+		k := key(req)
+		if k == "" {
+			return h(ctx, req)
+		}
+		return Do(ctx, s, k, ttl, func(ctx context.Context) (Res, error) {
+			return h(ctx, req)
+		})
+	}
+}
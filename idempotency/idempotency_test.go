@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  interface{}
+		want    Outcome
+		wantErr bool
+	}{
+		{
+			name:   "正常値: 初めてのキー",
+			result: int64(0),
+			want:   Outcome{State: StateProceed},
+		},
+		{
+			name:   "正常値: 処理中",
+			result: inProgressValue("3f1c9a52-0d4e-4b8a-9f1e-7c2d5a6b8e90"),
+			want:   Outcome{State: StateInProgress},
+		},
+		{
+			name:   "正常値: 完了済み",
+			result: markCompleted + `{"id":1}`,
+			want:   Outcome{State: StateCompleted, Result: []byte(`{"id":1}`)},
+		},
+		{
+			name:   "正常値: 完了済みで結果が空",
+			result: markCompleted,
+			want:   Outcome{State: StateCompleted, Result: []byte{}},
+		},
+		{
+			name:    "異常値: 想定外の値",
+			result:  "x",
+			wantErr: true,
+		},
+		{
+			name:    "異常値: 空文字",
+			result:  "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeOutcome(tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("エラーが想定外です。got=%v, wantErr=%v", err, tt.wantErr)
+			}
+			if got.State != tt.want.State || string(got.Result) != string(tt.want.Result) {
+				t.Errorf("結果が想定外です。got=%+v, want=%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestState_String(t *testing.T) {
+	tests := []struct {
+		state State
+		want  string
+	}{
+		{StateProceed, "proceed"},
+		{StateInProgress, "in_progress"},
+		{StateCompleted, "completed"},
+		{State(0), "State(0)"},
+	}
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.want {
+			t.Errorf("名前が想定外です。got=%v, want=%v", got, tt.want)
+		}
+	}
+}
+
+func TestStore_EmptyKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(nil)
+
+	if _, err := s.Begin(ctx, ""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Begin のエラーが想定外です。got=%v, want=%v", err, ErrEmptyKey)
+	}
+	if err := s.Complete(ctx, "", "", nil, time.Minute); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Complete のエラーが想定外です。got=%v, want=%v", err, ErrEmptyKey)
+	}
+	if err := s.Abort(ctx, "", ""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Abort のエラーが想定外です。got=%v, want=%v", err, ErrEmptyKey)
+	}
+}
+
+func TestWrap_WithoutKey(t *testing.T) {
+	calls := 0
+	h := Wrap(NewStore(nil), func(req string) string { return "" }, time.Minute, func(ctx context.Context, req string) (string, error) {
+		calls++
+		return "ok:" + req, nil
+	})
+
+	// 冪等キーの無い要求は毎回実行する
+	for range 2 {
+		got, err := h(context.Background(), "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "ok:a" {
+			t.Errorf("結果が想定外です。got=%v, want=%v", got, "ok:a")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("実行回数が想定外です。got=%v, want=%v", calls, 2)
+	}
+}
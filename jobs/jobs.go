@@ -0,0 +1,150 @@
+// Package jobs は Redis Streams 上のジョブキューを提供する
+//
+// Producer が登録したジョブは Worker がコンシューマーグループとして並行に処理する。
+// 失敗したジョブは backoff の設定に従って遅延ジョブとして再登録し、
+// リトライしない場合は dead-letter ストリームに移動する。
+//
+//	var SendMail = jobs.Define[Mail]("send_mail")
+//
+//	p := jobs.NewProducer(rc, "mail")
+//	_, err := SendMail.Enqueue(ctx, p, Mail{To: "a@example.com"}, jobs.WithDelay(time.Minute))
+//
+//	w, err := jobs.NewWorker(ctx, rc, "mail", jobs.WithConcurrency(4))
+//	jobs.Handle(w, SendMail, func(ctx context.Context, job jobs.Job[Mail]) error { ... })
+//	err = w.Run(ctx)
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrUnknownJob ハンドラーが登録されていないジョブの場合のエラー
+	ErrUnknownJob = errors.New("unknown job")
+	// ErrPanic ハンドラーが panic した場合のエラー
+	ErrPanic = errors.New("job handler panicked")
+)
+
+// entryField ストリームのエントリでジョブを格納するフィールド名
+const entryField = "job"
+
+// Definition 型付きのジョブ定義
+// 名前で Producer と Worker のジョブを対応付け、ペイロードは JSON で受け渡す。
+type Definition[T any] struct {
+	name string
+}
+
+// Define 名前を指定してジョブを定義する
+func Define[T any](name string) Definition[T] {
+	return Definition[T]{name: name}
+}
+
+// Name ジョブ名を返す
+func (d Definition[T]) Name() string {
+	return d.name
+}
+
+// Job ハンドラーに渡すジョブ
+type Job[T any] struct {
+	// ID ジョブごとに採番した ID（リトライしても変わらない）
+	ID string
+	// Name ジョブ名
+	Name string
+	// Payload ジョブの引数
+	Payload T
+	// Attempt 何回目の実行か（最初の実行は 1）
+	Attempt int
+	// EnqueuedAt 最初に登録した時刻
+	EnqueuedAt time.Time
+}
+
+// envelope ストリームと遅延ジョブのセットに格納するジョブ
+type envelope struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempt    int             `json:"attempt"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	// Error 直前の実行のエラー
+	Error string `json:"error,omitempty"`
+	// FailedAt 直前の実行が失敗した時刻
+	FailedAt time.Time `json:"failed_at,omitempty"`
+}
+
+// newEnvelope payload を JSON に変換して最初の実行のジョブを生成する
+func newEnvelope(name string, payload any) (envelope, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return envelope{}, fmt.Errorf("marshal %s payload: %w", name, err)
+	}
+	return envelope{
+		ID:         uuid.NewString(),
+		Name:       name,
+		Payload:    b,
+		Attempt:    1,
+		EnqueuedAt: time.Now(),
+	}, nil
+}
+
+// decodeEnvelope ストリームのフィールドからジョブを取り出す
+func decodeEnvelope(values map[string]interface{}) (envelope, error) {
+	s, ok := values[entryField].(string)
+	if !ok {
+		return envelope{}, fmt.Errorf("missing %q field", entryField)
+	}
+	var env envelope
+	if err := json.Unmarshal([]byte(s), &env); err != nil {
+		return envelope{}, fmt.Errorf("unmarshal job: %w", err)
+	}
+	return env, nil
+}
+
+// encode JSON に変換する
+func (e envelope) encode() (string, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("marshal job %s: %w", e.ID, err)
+	}
+	return string(b), nil
+}
+
+// toJob ペイロードを型 T に変換する
+func toJob[T any](e envelope) (Job[T], error) {
+	var payload T
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return Job[T]{}, fmt.Errorf("unmarshal %s payload: %w", e.Name, err)
+	}
+	return Job[T]{
+		ID:         e.ID,
+		Name:       e.Name,
+		Payload:    payload,
+		Attempt:    e.Attempt,
+		EnqueuedAt: e.EnqueuedAt,
+	}, nil
+}
+
+// keys キュー名から求めた Redis のキー
+type keys struct {
+	// stream 実行待ちのジョブのストリーム
+	stream string
+	// delayed 実行時刻をスコアにした遅延ジョブのソート済みセット
+	delayed string
+	// dead リトライしないジョブのストリーム
+	dead string
+}
+
+// newKeys キーを生成する
+// Redis Cluster でもアトミックに移動できるよう、ハッシュタグで同じスロットに配置する。
+func newKeys(queue string) keys {
+	prefix := "jobs:{" + queue + "}"
+	return keys{
+		stream:  prefix,
+		delayed: prefix + ":delayed",
+		dead:    prefix + ":dead",
+	}
+}
@@ -0,0 +1,253 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"valley-pkg/backoff"
+)
+
+type mail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	env, err := newEnvelope("send_mail", mail{To: "a@example.com", Subject: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := env.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decodeEnvelope(map[string]interface{}{entryField: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := toJob[mail](got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != env.ID || job.Name != "send_mail" || job.Attempt != 1 {
+		t.Errorf("ジョブが想定外です。got=%+v", job)
+	}
+	if job.Payload != (mail{To: "a@example.com", Subject: "hi"}) {
+		t.Errorf("ペイロードが想定外です。got=%+v", job.Payload)
+	}
+	if !job.EnqueuedAt.Equal(env.EnqueuedAt) {
+		t.Errorf("登録時刻が想定外です。got=%v, want=%v", job.EnqueuedAt, env.EnqueuedAt)
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "正常値: ジョブのフィールドあり",
+			values: map[string]interface{}{entryField: `{"id":"1","name":"a","payload":{},"attempt":2}`},
+		},
+		{
+			name:    "異常値: フィールドなし",
+			values:  map[string]interface{}{"other": "x"},
+			wantErr: true,
+		},
+		{
+			name:    "異常値: JSON ではない",
+			values:  map[string]interface{}{entryField: "{"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeEnvelope(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("エラーが想定外です。got=%v, wantErr=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToJob_InvalidPayload(t *testing.T) {
+	_, err := toJob[mail](envelope{Name: "send_mail", Payload: []byte(`"not an object"`)})
+	if err == nil {
+		t.Error("エラーが返されませんでした。")
+	}
+}
+
+func TestNewKeys(t *testing.T) {
+	k := newKeys("mail")
+	want := keys{stream: "jobs:{mail}", delayed: "jobs:{mail}:delayed", dead: "jobs:{mail}:dead"}
+	if k != want {
+		t.Errorf("キーが想定外です。got=%+v, want=%+v", k, want)
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []Option
+		wantBlock        time.Duration
+		wantPollInterval time.Duration
+	}{
+		{
+			name:             "正常値: 既定値",
+			wantBlock:        time.Second,
+			wantPollInterval: time.Second,
+		},
+		{
+			name:             "正常値: 指定した値",
+			opts:             []Option{WithBlock(5 * time.Second), WithPollInterval(100 * time.Millisecond)},
+			wantBlock:        5 * time.Second,
+			wantPollInterval: 100 * time.Millisecond,
+		},
+		{
+			name:             "異常値: 0 は既定値のまま",
+			opts:             []Option{WithBlock(0), WithPollInterval(0)},
+			wantBlock:        time.Second,
+			wantPollInterval: time.Second,
+		},
+		{
+			name:             "異常値: 負の値は既定値のまま",
+			opts:             []Option{WithBlock(-time.Second), WithPollInterval(-time.Second)},
+			wantBlock:        time.Second,
+			wantPollInterval: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions(tt.opts)
+			if o.block != tt.wantBlock {
+				t.Errorf("block が想定外です。got=%v, want=%v", o.block, tt.wantBlock)
+			}
+			if o.pollInterval != tt.wantPollInterval {
+				t.Errorf("pollInterval が想定外です。got=%v, want=%v", o.pollInterval, tt.wantPollInterval)
+			}
+		})
+	}
+}
+
+func TestWorker_RetryDelay(t *testing.T) {
+	errFailed := errors.New("failed")
+	w := &Worker{opts: newOptions([]Option{
+		WithBackoff(
+			backoff.WithInitialInterval(time.Second),
+			backoff.WithMultiplier(2),
+			backoff.WithRandomizationFactor(0),
+			backoff.WithMaxTries(3),
+		),
+	})}
+
+	tests := []struct {
+		name      string
+		attempt   int
+		err       error
+		want      time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "正常値: 1回目の失敗",
+			attempt:   1,
+			err:       errFailed,
+			want:      time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "正常値: 2回目の失敗は間隔が伸びる",
+			attempt:   2,
+			err:       errFailed,
+			want:      2 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:    "異常値: 最大回数に達した",
+			attempt: 3,
+			err:     errFailed,
+		},
+		{
+			name:    "異常値: Permanent なエラー",
+			attempt: 1,
+			err:     backoff.Permanent(errFailed),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, retry := w.retryDelay(tt.attempt, tt.err)
+			if retry != tt.wantRetry {
+				t.Errorf("リトライの有無が想定外です。got=%v, want=%v", retry, tt.wantRetry)
+			}
+			if got != tt.want {
+				t.Errorf("リトライ間隔が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorker_Invoke(t *testing.T) {
+	def := Define[mail]("send_mail")
+	w := &Worker{handlers: make(map[string]func(ctx context.Context, env envelope) error)}
+	Handle(w, def, func(ctx context.Context, job Job[mail]) error {
+		if job.Payload.To == "" {
+			panic("empty to")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name          string
+		env           envelope
+		wantErr       error
+		wantPermanent bool
+	}{
+		{
+			name: "正常値: ハンドラーが成功",
+			env:  envelope{Name: "send_mail", Payload: []byte(`{"to":"a@example.com"}`)},
+		},
+		{
+			name:    "異常値: ハンドラーが panic",
+			env:     envelope{Name: "send_mail", Payload: []byte(`{}`)},
+			wantErr: ErrPanic,
+		},
+		{
+			name:          "異常値: 未登録のジョブ",
+			env:           envelope{Name: "unknown", Payload: []byte(`{}`)},
+			wantErr:       ErrUnknownJob,
+			wantPermanent: true,
+		},
+		{
+			name:          "異常値: ペイロードが変換できない",
+			env:           envelope{Name: "send_mail", Payload: []byte(`1`)},
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.invoke(context.Background(), tt.env)
+			if tt.wantErr == nil && !tt.wantPermanent && err != nil {
+				t.Fatalf("エラーが返されました。err=%v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			// Permanent なエラーはリトライしない
+			if _, retry := (&Worker{opts: newOptions(nil)}).retryDelay(1, err); err != nil && retry == tt.wantPermanent {
+				t.Errorf("リトライの有無が想定外です。got=%v, wantPermanent=%v", retry, tt.wantPermanent)
+			}
+		})
+	}
+}
+
+func TestWithDelay(t *testing.T) {
+	var o enqueueOptions
+	before := time.Now()
+	WithDelay(time.Minute)(&o)
+	if o.runAt.Before(before.Add(time.Minute)) {
+		t.Errorf("実行時刻が想定外です。got=%v", o.runAt)
+	}
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"time"
+
+	"valley-pkg/backoff"
+	"valley-pkg/logging"
+)
+
+// Option Producer と Worker の設定
+type Option func(*options)
+
+type options struct {
+	maxLen       int64
+	group        string
+	consumer     string
+	concurrency  int
+	block        time.Duration
+	claimIdle    time.Duration
+	pollInterval time.Duration
+	backoff      []backoff.Option
+	logger       logging.Logger
+}
+
+// WithMaxLen ストリームをおおよそ n 件に制限する（0 は無制限）
+func WithMaxLen(n int64) Option {
+	return func(o *options) {
+		o.maxLen = n
+	}
+}
+
+// WithGroup Worker のコンシューマーグループ名を指定する。指定しない場合は "workers"
+func WithGroup(name string) Option {
+	return func(o *options) {
+		o.group = name
+	}
+}
+
+// WithConsumer Worker のコンシューマー名を指定する。指定しない場合は UUID を採番する
+// 再起動しても同じ名前を使用すると、処理中だったジョブを ClaimIdle を待たずに再処理できる。
+func WithConsumer(name string) Option {
+	return func(o *options) {
+		o.consumer = name
+	}
+}
+
+// WithConcurrency Worker が同時に実行するジョブの数を n にする。指定しない場合は 1
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithBlock ジョブが無い場合に1回の読み込みで待つ時間を d にする。指定しない場合は1秒
+// 待たずに読み込みを繰り返さないよう、d が 0 以下の場合は既定値のままにする。
+func WithBlock(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.block = d
+		}
+	}
+}
+
+// WithClaimIdle d 以上確認されていないジョブを回収して再処理する。指定しない場合は1分（0 は回収しない）
+// 異常終了した Worker のジョブを回収するため、ジョブの最長の処理時間より長くすること。
+func WithClaimIdle(d time.Duration) Option {
+	return func(o *options) {
+		o.claimIdle = d
+	}
+}
+
+// WithPollInterval 実行時刻を過ぎた遅延ジョブを確認する間隔を d にする。指定しない場合は1秒
+// d が 0 以下の場合は既定値のままにする。
+func WithPollInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.pollInterval = d
+		}
+	}
+}
+
+// WithBackoff 失敗したジョブのリトライ間隔と回数を指定する
+// 指定しない場合は1秒から指数的に伸ばし、最初の実行を含めて5回まで実行する。
+// backoff.Permanent で包んだエラーを返したジョブはリトライせずに dead-letter に移動する。
+func WithBackoff(opts ...backoff.Option) Option {
+	return func(o *options) {
+		o.backoff = append(o.backoff, opts...)
+	}
+}
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// newOptions は初期値に opts を適用した設定を返す
+func newOptions(opts []Option) options {
+	o := options{
+		group:        "workers",
+		concurrency:  1,
+		block:        time.Second,
+		claimIdle:    time.Minute,
+		pollInterval: time.Second,
+		backoff: []backoff.Option{
+			backoff.WithInitialInterval(time.Second),
+			backoff.WithMaxTries(5),
+			// リトライ間隔は実行ごとに求めるため、経過時間では打ち切らない
+			backoff.WithMaxElapsedTime(0),
+		},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.concurrency < 1 {
+		o.concurrency = 1
+	}
+	o.logger = logging.OrDefault(o.logger)
+	return o
+}
@@ -0,0 +1,142 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"valley-pkg/redis"
+)
+
+// Producer キューにジョブを登録する
+type Producer struct {
+	redis *redis.RedisClient
+	keys  keys
+	opts  options
+}
+
+// NewProducer キュー名を指定して Producer を生成する
+func NewProducer(rc *redis.RedisClient, queue string, opts ...Option) *Producer {
+	return &Producer{
+		redis: rc,
+		keys:  newKeys(queue),
+		opts:  newOptions(opts),
+	}
+}
+
+// EnqueueOption ジョブを登録する際の設定
+type EnqueueOption func(*enqueueOptions)
+
+type enqueueOptions struct {
+	runAt time.Time
+}
+
+// WithDelay d 後に実行する
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = time.Now().Add(d)
+	}
+}
+
+// WithRunAt t 以降に実行する
+func WithRunAt(t time.Time) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.runAt = t
+	}
+}
+
+// Enqueue payload を引数にしたジョブを登録し、ジョブの ID を返す
+// WithDelay や WithRunAt で実行時刻を指定した場合は、時刻を過ぎた後に Worker が実行待ちに移動する。
+func (d Definition[T]) Enqueue(ctx context.Context, p *Producer, payload T, opts ...EnqueueOption) (string, error) {
+	return p.enqueue(ctx, d.name, payload, opts)
+}
+
+// enqueue ジョブを実行待ちのストリームまたは遅延ジョブのセットに追加する
+func (p *Producer) enqueue(ctx context.Context, name string, payload any, opts []EnqueueOption) (string, error) {
+	var o enqueueOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	env, err := newEnvelope(name, payload)
+	if err != nil {
+		return "", err
+	}
+	s, err := env.encode()
+	if err != nil {
+		return "", err
+	}
+
+	if o.runAt.After(time.Now()) {
+		if _, err := p.redis.ZAdd(ctx, p.keys.delayed, redis.Z{Score: score(o.runAt), Member: s}); err != nil {
+			return "", fmt.Errorf("schedule %s: %w", name, err)
+		}
+		return env.ID, nil
+	}
+	if _, err := p.redis.XAdd(ctx, p.keys.stream, map[string]interface{}{entryField: s}, p.opts.maxLen); err != nil {
+		return "", fmt.Errorf("enqueue %s: %w", name, err)
+	}
+	return env.ID, nil
+}
+
+// Len 実行待ちと遅延中のジョブの数を返す
+func (p *Producer) Len(ctx context.Context) (pending int64, delayed int64, err error) {
+	pending, err = p.redis.XLen(ctx, p.keys.stream)
+	if err != nil {
+		return 0, 0, err
+	}
+	delayed, err = p.redis.ZCard(ctx, p.keys.delayed)
+	if err != nil {
+		return 0, 0, err
+	}
+	return pending, delayed, nil
+}
+
+// DeadLetter リトライせずに dead-letter に移動したジョブ
+type DeadLetter struct {
+	// EntryID dead-letter ストリームのエントリ ID
+	EntryID string
+	// ID ジョブの ID
+	ID string
+	// Name ジョブ名
+	Name string
+	// Payload ジョブの引数の JSON
+	Payload json.RawMessage
+	// Attempt 実行した回数
+	Attempt int
+	// Error 最後の実行のエラー
+	Error string
+	// FailedAt 最後の実行が失敗した時刻
+	FailedAt time.Time
+}
+
+// DeadLetters dead-letter に移動したジョブを古い順に返す
+func (p *Producer) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	msgs, err := p.redis.XRange(ctx, p.keys.dead, "-", "+")
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		env, err := decodeEnvelope(msg.Values)
+		if err != nil {
+			return nil, fmt.Errorf("dead letter %s: %w", msg.ID, err)
+		}
+		letters = append(letters, DeadLetter{
+			EntryID:  msg.ID,
+			ID:       env.ID,
+			Name:     env.Name,
+			Payload:  env.Payload,
+			Attempt:  env.Attempt,
+			Error:    env.Error,
+			FailedAt: env.FailedAt,
+		})
+	}
+	return letters, nil
+}
+
+// score 実行時刻を遅延ジョブのスコアにする
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
@@ -0,0 +1,294 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"valley-pkg/backoff"
+	"valley-pkg/logging"
+	"valley-pkg/redis"
+)
+
+// promoteScript 実行時刻を過ぎた遅延ジョブを実行待ちのストリームに移動する
+// KEYS[1]: 遅延ジョブのセット、KEYS[2]: ストリーム、ARGV[1]: 現在時刻、ARGV[2]: 最大件数、ARGV[3]: ストリームの最大長
+const promoteScript = `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, job in ipairs(due) do
+	redis.call('ZREM', KEYS[1], job)
+	if tonumber(ARGV[3]) > 0 then
+		redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[3], '*', 'job', job)
+	else
+		redis.call('XADD', KEYS[2], '*', 'job', job)
+	end
+end
+return #due
+`
+
+// promoteBatch 1回に移動する遅延ジョブの最大件数
+const promoteBatch = 100
+
+// Handler 型付きのジョブを処理する
+// エラーを返した場合はリトライし、backoff.Permanent で包んだエラーはリトライしない。
+type Handler[T any] func(ctx context.Context, job Job[T]) error
+
+// Worker コンシューマーグループの一員としてジョブを処理する
+type Worker struct {
+	redis *redis.RedisClient
+	keys  keys
+	opts  options
+
+	mu       sync.RWMutex
+	handlers map[string]func(ctx context.Context, env envelope) error
+}
+
+// NewWorker キュー名を指定して Worker を生成する。コンシューマーグループが無い場合は作成する
+func NewWorker(ctx context.Context, rc *redis.RedisClient, queue string, opts ...Option) (*Worker, error) {
+	o := newOptions(opts)
+	if o.consumer == "" {
+		o.consumer = uuid.NewString()
+	}
+	k := newKeys(queue)
+	if err := rc.XGroupCreate(ctx, k.stream, o.group, "0"); err != nil {
+		return nil, fmt.Errorf("create group %s: %w", o.group, err)
+	}
+	return &Worker{
+		redis:    rc,
+		keys:     k,
+		opts:     o,
+		handlers: make(map[string]func(ctx context.Context, env envelope) error),
+	}, nil
+}
+
+// Handle def のジョブを h で処理するように登録する
+// 同じジョブ名を登録した場合は後から登録した h で上書きする。
+func Handle[T any](w *Worker, def Definition[T], h Handler[T]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handlers[def.name] = func(ctx context.Context, env envelope) error {
+		job, err := toJob[T](env)
+		if err != nil {
+			// 引数が変換できないジョブはリトライしても成功しない
+			return backoff.Permanent(err)
+		}
+		return h(ctx, job)
+	}
+}
+
+// Run ctx が終了するまでジョブを処理する
+// ctx の終了時は実行中のジョブの終了を待って nil を返し、Redis のエラーはそのまま返す。
+func (w *Worker) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.promoteLoop(ctx)
+	}()
+
+	err := w.fetchLoop(ctx, &wg)
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// fetchLoop 空きがある分だけジョブを読み込み、ゴルーチンで処理する
+func (w *Worker) fetchLoop(ctx context.Context, wg *sync.WaitGroup) error {
+	slots := make(chan struct{}, w.opts.concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		// 他にも空きがあればまとめて読み込む
+		free := 1
+	acquire:
+		for free < w.opts.concurrency {
+			select {
+			case slots <- struct{}{}:
+				free++
+			default:
+				break acquire
+			}
+		}
+
+		msgs, err := w.next(ctx, int64(free))
+		if err != nil {
+			for i := 0; i < free; i++ {
+				<-slots
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for i := len(msgs); i < free; i++ {
+			<-slots
+		}
+		for _, msg := range msgs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				w.process(ctx, msg)
+			}()
+		}
+	}
+}
+
+// next 回収対象のジョブがあればそれを、無ければ新しいジョブを最大 count 件返す
+func (w *Worker) next(ctx context.Context, count int64) ([]redis.XMessage, error) {
+	if w.opts.claimIdle > 0 {
+		claimed, _, err := w.redis.XAutoClaim(ctx, w.keys.stream, w.opts.group, w.opts.consumer, w.opts.claimIdle, "0", count)
+		if err != nil {
+			return nil, err
+		}
+		if len(claimed) > 0 {
+			return claimed, nil
+		}
+	}
+	return w.redis.XReadGroup(ctx, w.keys.stream, w.opts.group, w.opts.consumer, count, w.opts.block)
+}
+
+// process ジョブを実行し、結果に応じて確認・リトライ・dead-letter への移動を行う
+func (w *Worker) process(ctx context.Context, msg redis.XMessage) {
+	// 結果の反映は ctx が終了していても行う
+	bg := context.WithoutCancel(ctx)
+
+	env, err := decodeEnvelope(msg.Values)
+	if err != nil {
+		// 読み込めないエントリは処理できないため確認済みにして破棄する
+		w.opts.logger.Error("invalid job entry", logging.F("entry_id", msg.ID), logging.Err(err))
+		if _, err := w.redis.XAck(bg, w.keys.stream, w.opts.group, msg.ID); err != nil {
+			w.opts.logger.Error("ack job error", logging.F("entry_id", msg.ID), logging.Err(err))
+		}
+		return
+	}
+	logger := w.opts.logger.With(logging.F("job_id", env.ID), logging.F("job", env.Name), logging.F("attempt", env.Attempt))
+
+	err = w.invoke(ctx, env)
+	if err == nil {
+		if _, err := w.redis.XAck(bg, w.keys.stream, w.opts.group, msg.ID); err != nil {
+			logger.Error("ack job error", logging.Err(err))
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		// 終了による失敗は回数に数えず、未確認のまま残して ClaimIdle 後に再処理する
+		logger.Info("job interrupted", logging.Err(err))
+		return
+	}
+
+	env.Error = err.Error()
+	env.FailedAt = time.Now()
+	delay, retry := w.retryDelay(env.Attempt, err)
+	if retry {
+		logger.Info("job failed, retrying", logging.F("delay", delay), logging.Err(err))
+		env.Attempt++
+		err = w.moveTo(bg, msg.ID, env, func(p redis.Pipeliner, s string) {
+			p.ZAdd(bg, w.keys.delayed, redis.Z{Score: score(env.FailedAt.Add(delay)), Member: s})
+		})
+	} else {
+		logger.Error("job failed, moving to dead letter", logging.Err(err))
+		err = w.moveTo(bg, msg.ID, env, func(p redis.Pipeliner, s string) {
+			p.XAdd(bg, &redis.XAddArgs{Stream: w.keys.dead, Values: map[string]interface{}{entryField: s}})
+		})
+	}
+	if err != nil {
+		logger.Error("reschedule job error", logging.Err(err))
+	}
+}
+
+// invoke ハンドラーを呼び出す。panic はエラーとして返す
+func (w *Worker) invoke(ctx context.Context, env envelope) (err error) {
+	w.mu.RLock()
+	h, ok := w.handlers[env.Name]
+	w.mu.RUnlock()
+	if !ok {
+		return backoff.Permanent(fmt.Errorf("%s: %w", env.Name, ErrUnknownJob))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v: %w", r, ErrPanic)
+		}
+	}()
+	return h(ctx, env)
+}
+
+// retryDelay attempt 回目の実行が err で失敗した場合の、次の実行までの間隔を返す
+// リトライしない場合は false を返す。
+func (w *Worker) retryDelay(attempt int, err error) (time.Duration, bool) {
+	// Retrier はプロセス内の状態のため、これまでの失敗を再現して attempt 回目の間隔を求める
+	r := backoff.NewRetrier(context.Background(), w.opts.backoff...)
+	var (
+		delay time.Duration
+		ok    bool
+	)
+	for i := 0; i < attempt; i++ {
+		if delay, ok = r.NextDelay(err); !ok {
+			return 0, false
+		}
+	}
+	return delay, true
+}
+
+// moveTo ストリームのエントリを確認済みにし、env を add で別のキーへアトミックに追加する
+func (w *Worker) moveTo(ctx context.Context, entryID string, env envelope, add func(p redis.Pipeliner, s string)) error {
+	s, err := env.encode()
+	if err != nil {
+		return err
+	}
+	_, err = w.redis.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.XAck(ctx, w.keys.stream, w.opts.group, entryID)
+		add(p, s)
+		return nil
+	})
+	return err
+}
+
+// promoteLoop pollInterval ごとに実行時刻を過ぎた遅延ジョブを移動する
+func (w *Worker) promoteLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Promote(ctx); err != nil && ctx.Err() == nil {
+			w.opts.logger.Error("promote delayed jobs error", logging.Err(err))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Promote 実行時刻を過ぎた遅延ジョブを実行待ちに移動し、移動した件数を返す
+// Run の実行中は定期的に呼ばれるため、通常は呼び出す必要はない。
+func (w *Worker) Promote(ctx context.Context) (int64, error) {
+	var total int64
+	for {
+		result, err := w.redis.Eval(ctx, promoteScript, []string{w.keys.delayed, w.keys.stream},
+			strconv.FormatFloat(score(time.Now()), 'f', 0, 64), promoteBatch, w.opts.maxLen)
+		if err != nil {
+			return total, err
+		}
+		n, ok := result.(int64)
+		if !ok {
+			return total, errors.New("unexpected promote result")
+		}
+		total += n
+		if n < promoteBatch {
+			return total, nil
+		}
+	}
+}
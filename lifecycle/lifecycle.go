@@ -0,0 +1,233 @@
+// Package lifecycle はアプリケーションの終了処理をまとめて管理する
+//
+// 各コンポーネントは生成時に停止処理を Register し、アプリケーションは Run で
+// SIGTERM / SIGINT を待つ。シグナルを受け取るか ctx が終了すると、登録と逆の順序で
+// コンポーネントを1つずつ停止する。シグナルの処理はこのパッケージだけが行う。
+//
+//	rc, err := redis.NewRedisClientWithOptions(ctx, redis.Config{Lifecycle: lifecycle.Default()})
+//	...
+//	if err := lifecycle.Run(ctx); err != nil {
+//		logger.Error("shutdown failed", logging.Err(err))
+//	}
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"valley-pkg/logging"
+)
+
+// StopFunc コンポーネントを停止する
+// ctx はコンポーネントごとのタイムアウトで終了する。
+type StopFunc func(ctx context.Context) error
+
+// component 登録されたコンポーネント
+type component struct {
+	name    string
+	stop    StopFunc
+	timeout time.Duration
+}
+
+// Lifecycle コンポーネントの停止処理を管理する
+type Lifecycle struct {
+	mu         sync.Mutex
+	components []component
+
+	// ctx 終了処理の開始時にキャンセルする
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	once sync.Once
+	done chan struct{}
+	err  error
+
+	timeout time.Duration
+	signals []os.Signal
+	logger  logging.Logger
+}
+
+// Option Lifecycle の設定
+type Option func(*Lifecycle)
+
+// WithTimeout コンポーネントごとの停止のタイムアウトの既定値を設定する（デフォルト: 10 秒）
+func WithTimeout(d time.Duration) Option {
+	return func(l *Lifecycle) {
+		l.timeout = d
+	}
+}
+
+// WithSignals Run で待つシグナルを設定する（デフォルト: SIGTERM, SIGINT）
+func WithSignals(sigs ...os.Signal) Option {
+	return func(l *Lifecycle) {
+		l.signals = sigs
+	}
+}
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(logger logging.Logger) Option {
+	return func(l *Lifecycle) {
+		l.logger = logger
+	}
+}
+
+// New Lifecycle を生成する
+func New(opts ...Option) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &Lifecycle{
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		timeout: 10 * time.Second,
+		signals: []os.Signal{syscall.SIGTERM, syscall.SIGINT},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.logger = logging.OrDefault(l.logger)
+	return l
+}
+
+// RegisterOption コンポーネントごとの設定
+type RegisterOption func(*component)
+
+// WithStopTimeout このコンポーネントの停止のタイムアウトを設定する
+func WithStopTimeout(d time.Duration) RegisterOption {
+	return func(c *component) {
+		c.timeout = d
+	}
+}
+
+// Register 停止処理を登録する
+// 終了処理では登録と逆の順序で停止するため、依存されるコンポーネントを先に登録する。
+// 終了処理の開始後に登録した場合は、その場で停止する。
+func (l *Lifecycle) Register(name string, stop StopFunc, opts ...RegisterOption) {
+	c := component{name: name, stop: stop, timeout: l.timeout}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	l.mu.Lock()
+	if l.ctx.Err() == nil {
+		l.components = append(l.components, c)
+		l.mu.Unlock()
+		return
+	}
+	l.mu.Unlock()
+
+	l.logger.Info("component registered after shutdown started", logging.F("component", name))
+	if err := l.stop(context.Background(), c); err != nil {
+		l.logger.Error("failed to stop component", logging.F("component", name), logging.Err(err))
+	}
+}
+
+// Context 終了処理の開始時にキャンセルされるコンテキストを返す
+// 接続のリトライなど、終了処理の開始時に中断したい処理に渡す。
+// Lifecycle は1回限りのため、終了処理の開始後はキャンセルされたままになる。
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Run シグナルを受け取るか ctx が終了するまで待ち、Shutdown を実行する
+func (l *Lifecycle) Run(ctx context.Context) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, l.signals...)
+	defer signal.Stop(sigCh)
+
+	select {
+	case sig := <-sigCh:
+		l.logger.Info("signal received, shutting down", logging.F("signal", sig.String()))
+	case <-ctx.Done():
+		l.logger.Info("context done, shutting down")
+	case <-l.ctx.Done():
+		// 他で Shutdown が呼び出された
+	}
+	return l.Shutdown(context.WithoutCancel(ctx))
+}
+
+// Shutdown 登録と逆の順序でコンポーネントを停止する
+// 各コンポーネントの停止はタイムアウトで打ち切り、次のコンポーネントの停止に進む。
+// 複数回呼び出した場合は、最初の終了処理の完了を待って同じ結果を返す。
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.once.Do(func() {
+		defer close(l.done)
+
+		l.mu.Lock()
+		l.cancel()
+		components := l.components
+		l.components = nil
+		l.mu.Unlock()
+
+		var errs []error
+		for i := len(components) - 1; i >= 0; i-- {
+			c := components[i]
+			start := time.Now()
+			if err := l.stop(ctx, c); err != nil {
+				l.logger.Error("failed to stop component", logging.F("component", c.name), logging.Err(err))
+				errs = append(errs, err)
+				continue
+			}
+			l.logger.Info("component stopped", logging.F("component", c.name), logging.F("elapsed", time.Since(start).String()))
+		}
+		l.err = errors.Join(errs...)
+	})
+
+	select {
+	case <-l.done:
+		return l.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop タイムアウトまでコンポーネントの停止を待つ
+// StopFunc が ctx を無視して戻らない場合も、タイムアウトで打ち切る。
+func (l *Lifecycle) stop(ctx context.Context, c component) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		errCh <- c.stop(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("stop %s: %w", c.name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("stop %s: %w", c.name, ctx.Err())
+	}
+}
+
+var defaultLifecycle = New()
+
+// Default パッケージ共通の Lifecycle を返す
+// プロセスで1つの Lifecycle のため、終了処理の後は Context がキャンセルされたままになる。
+// テストなどで終了処理を繰り返す場合は New で生成した Lifecycle を使用する。
+func Default() *Lifecycle {
+	return defaultLifecycle
+}
+
+// Register Default に停止処理を登録する
+func Register(name string, stop StopFunc, opts ...RegisterOption) {
+	defaultLifecycle.Register(name, stop, opts...)
+}
+
+// Run Default の Run を実行する
+func Run(ctx context.Context) error {
+	return defaultLifecycle.Run(ctx)
+}
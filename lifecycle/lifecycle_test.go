@@ -0,0 +1,186 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"valley-pkg/logging"
+)
+
+// recorder 停止した順序を記録する
+type recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *recorder) stop(name string, err error) StopFunc {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.names = append(r.names, name)
+		return err
+	}
+}
+
+func (r *recorder) stopped() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.names...)
+}
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	errStop := errors.New("stop failed")
+	tests := []struct {
+		name    string
+		setup   func(l *Lifecycle, r *recorder)
+		want    []string
+		wantErr []error
+	}{
+		{
+			name: "正常値: 登録と逆の順序で停止",
+			setup: func(l *Lifecycle, r *recorder) {
+				l.Register("redis", r.stop("redis", nil))
+				l.Register("jobs", r.stop("jobs", nil))
+				l.Register("server", r.stop("server", nil))
+			},
+			want: []string{"server", "jobs", "redis"},
+		},
+		{
+			name: "異常値: 失敗しても残りを停止する",
+			setup: func(l *Lifecycle, r *recorder) {
+				l.Register("redis", r.stop("redis", nil))
+				l.Register("server", r.stop("server", errStop))
+			},
+			want:    []string{"server", "redis"},
+			wantErr: []error{errStop},
+		},
+		{
+			name: "異常値: タイムアウトで打ち切る",
+			setup: func(l *Lifecycle, r *recorder) {
+				l.Register("redis", r.stop("redis", nil))
+				l.Register("slow", func(context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}, WithStopTimeout(10*time.Millisecond))
+			},
+			want:    []string{"redis"},
+			wantErr: []error{context.DeadlineExceeded},
+		},
+		{
+			name: "異常値: panic",
+			setup: func(l *Lifecycle, r *recorder) {
+				l.Register("redis", r.stop("redis", nil))
+				l.Register("panic", func(context.Context) error {
+					panic("boom")
+				})
+			},
+			want:    []string{"redis"},
+			wantErr: []error{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(WithLogger(logging.Nop()))
+			r := &recorder{}
+			tt.setup(l, r)
+
+			err := l.Shutdown(context.Background())
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("エラーが想定外です。got=%v, wantErr=%v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("エラーが想定外です。got=%v, want=%v", err, want)
+				}
+			}
+			if got := r.stopped(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("停止した順序が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifecycle_Shutdown_Once(t *testing.T) {
+	l := New(WithLogger(logging.Nop()))
+	r := &recorder{}
+	l.Register("redis", r.stop("redis", nil))
+
+	for range 2 {
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Errorf("エラーが想定外です。got=%v", err)
+		}
+	}
+	if got := r.stopped(); len(got) != 1 {
+		t.Errorf("停止した回数が想定外です。got=%v, want=%v", len(got), 1)
+	}
+	// 終了処理の後は1回限りで、コンテキストはキャンセルされたままになる
+	if l.Context().Err() == nil {
+		t.Error("コンテキストがキャンセルされていません。")
+	}
+
+	// 終了処理の開始後に登録した場合はその場で停止する
+	l.Register("late", r.stop("late", nil))
+	if got, want := r.stopped(), []string{"redis", "late"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("停止したコンポーネントが想定外です。got=%v, want=%v", got, want)
+	}
+}
+
+func TestLifecycle_Run(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger func(l *Lifecycle, cancel context.CancelFunc)
+	}{
+		{
+			name: "正常値: シグナル",
+			trigger: func(*Lifecycle, context.CancelFunc) {
+				_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+			},
+		},
+		{
+			name: "正常値: コンテキストの終了",
+			trigger: func(_ *Lifecycle, cancel context.CancelFunc) {
+				cancel()
+			},
+		},
+		{
+			name: "正常値: Shutdown の呼び出し",
+			trigger: func(l *Lifecycle, _ context.CancelFunc) {
+				go l.Shutdown(context.Background())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(WithSignals(syscall.SIGUSR1), WithLogger(logging.Nop()))
+			r := &recorder{}
+			l.Register("redis", r.stop("redis", nil))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error, 1)
+			go func() {
+				done <- l.Run(ctx)
+			}()
+			// シグナルの登録を待つ
+			time.Sleep(20 * time.Millisecond)
+			tt.trigger(l, cancel)
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("エラーが想定外です。got=%v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Run が終了しませんでした。")
+			}
+			if got := r.stopped(); len(got) != 1 {
+				t.Errorf("停止したコンポーネントが想定外です。got=%v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package logging は各パッケージが出力するログの送り先を差し替えるためのインターフェースを提供する
+// 各パッケージはオプションで Logger を受け取り、アプリケーションの構造化ロガーにログをまとめられる。
+package logging
+
+import (
+	"context"
+)
+
+// Field ログに付加する項目
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F は Field を作成する
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err は "error" をキーとする Field を作成する
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Logger 構造化ログの出力先
+// 実装は複数のゴルーチンから同時に呼び出されても安全である必要がある。
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	// With は fields を常に付加する Logger を返す
+	With(fields ...Field) Logger
+}
+
+// Nop は何も出力しない Logger を返す
+func Nop() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...Field) {}
+func (nopLogger) Info(string, ...Field)  {}
+func (nopLogger) Error(string, ...Field) {}
+func (l nopLogger) With(...Field) Logger {
+	return l
+}
+
+// OrDefault は l が nil の場合に Default を返す
+func OrDefault(l Logger) Logger {
+	if l == nil {
+		return Default()
+	}
+	return l
+}
+
+type contextKey struct{}
+
+// NewContext は l を保持した context を返す
+// mysql のクエリビルダーのようにコンストラクタを持たない処理へ Logger を渡すために使用する。
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext は ctx が保持する Logger を返す。保持していない場合は fallback を返す
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok && l != nil {
+		return l
+	}
+	return fallback
+}
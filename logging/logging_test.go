@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestLogrus() (*bytes.Buffer, Logger) {
+	buf := &bytes.Buffer{}
+	l := logrus.New()
+	l.SetOutput(buf)
+	l.SetLevel(logrus.DebugLevel)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+	return buf, Logrus(logrus.NewEntry(l))
+}
+
+func TestLogrus(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l Logger)
+		want []string
+	}{
+		{
+			name: "正常値: Debug",
+			log:  func(l Logger) { l.Debug("debug message", F("key", "value")) },
+			want: []string{"level=debug", `msg="debug message"`, "key=value"},
+		},
+		{
+			name: "正常値: Info",
+			log:  func(l Logger) { l.Info("info") },
+			want: []string{"level=info", "msg=info"},
+		},
+		{
+			name: "正常値: Error",
+			log:  func(l Logger) { l.Error("failed", Err(errors.New("boom"))) },
+			want: []string{"level=error", "msg=failed", "error=boom"},
+		},
+		{
+			name: "正常値: With で付加した項目",
+			log:  func(l Logger) { l.With(F("component", "tcp")).Info("info", F("kind", 1)) },
+			want: []string{"component=tcp", "kind=1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, l := newTestLogrus()
+			tt.log(l)
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("出力が想定外です。got=%q, want=%q", buf.String(), w)
+				}
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, l := newTestLogrus()
+	fallback := Nop()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Logger
+	}{
+		{name: "正常値: ctx の Logger", ctx: NewContext(context.Background(), l), want: l},
+		{name: "正常値: 未設定の場合は fallback", ctx: context.Background(), want: fallback},
+		{name: "正常値: nil の場合は fallback", ctx: NewContext(context.Background(), nil), want: fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromContext(tt.ctx, fallback); got != tt.want {
+				t.Errorf("Logger が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrDefault(t *testing.T) {
+	if OrDefault(nil) == nil {
+		t.Errorf("nil の場合に Default が返りません")
+	}
+	l := Nop()
+	if got := OrDefault(l); got != l {
+		t.Errorf("Logger が想定外です。got=%v, want=%v", got, l)
+	}
+}
@@ -0,0 +1,46 @@
+package logging
+
+import "github.com/sirupsen/logrus"
+
+// Logrus は logrus に出力する Logger を返す
+func Logrus(entry *logrus.Entry) Logger {
+	return logrusLogger{entry: entry}
+}
+
+// Default は logrus の標準ロガーに出力する Logger を返す
+// Logger を指定しない場合に各パッケージが使用する。
+func Default() Logger {
+	return Logrus(logrus.NewEntry(logrus.StandardLogger()))
+}
+
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+func (l logrusLogger) Debug(msg string, fields ...Field) {
+	l.with(fields).Debug(msg)
+}
+
+func (l logrusLogger) Info(msg string, fields ...Field) {
+	l.with(fields).Info(msg)
+}
+
+func (l logrusLogger) Error(msg string, fields ...Field) {
+	l.with(fields).Error(msg)
+}
+
+func (l logrusLogger) With(fields ...Field) Logger {
+	return logrusLogger{entry: l.with(fields)}
+}
+
+// with は fields を付加した logrus.Entry を返す
+func (l logrusLogger) with(fields []Field) *logrus.Entry {
+	if len(fields) == 0 {
+		return l.entry
+	}
+	f := make(logrus.Fields, len(fields))
+	for _, field := range fields {
+		f[field.Key] = field.Value
+	}
+	return l.entry.WithFields(f)
+}
@@ -0,0 +1,111 @@
+// Package metrics はカウンター・ゲージ・ヒストグラムの最小限の計測 API を提供する
+//
+// 計測する側は Meter から取得した計測器に値を記録し、Registry が集計した値を
+// Prometheus 形式の HTTP ハンドラーまたは OTLP エクスポーターで出力する。
+// Meter を指定しない場合は Nop を使用し、計測のコストは掛からない。
+//
+//	reg := metrics.NewRegistry()
+//	http.Handle("/metrics", metrics.PrometheusHandler(reg))
+//	rc, err := redis.NewRedisClientWithOptions(ctx, redis.Config{Metrics: reg})
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Label 計測値のラベル
+type Label struct {
+	Key   string
+	Value string
+}
+
+// L はラベルを生成する
+func L(key, value string) Label {
+	return Label{Key: key, Value: value}
+}
+
+// Counter 増加のみする値
+type Counter interface {
+	// Add v を加算する。負の値は無視する
+	Add(v float64, labels ...Label)
+}
+
+// Gauge 増減する値
+type Gauge interface {
+	// Set 値を v にする
+	Set(v float64, labels ...Label)
+	// Add v を加算する
+	Add(v float64, labels ...Label)
+}
+
+// Histogram 値の分布
+type Histogram interface {
+	// Observe v を記録する
+	Observe(v float64, labels ...Label)
+}
+
+// Meter 計測器を生成する
+// 同じ名前で取得した計測器は同じ値を共有する。実装は複数のゴルーチンから同時に呼び出されても安全である必要がある。
+type Meter interface {
+	Counter(name, help string) Counter
+	Gauge(name, help string) Gauge
+	// Histogram buckets はバケットの上限値。nil の場合は DefaultBuckets を使用する
+	Histogram(name, help string, buckets []float64) Histogram
+}
+
+// DefaultBuckets 秒単位の処理時間を想定したヒストグラムの既定のバケット
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// namePattern 計測器の名前とラベル名に使用できる文字列（Prometheus と同じ）
+var namePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// validateName 名前を検証する
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid metric name: %q", name)
+	}
+	return nil
+}
+
+// Nop 何も記録しない Meter を返す
+func Nop() Meter {
+	return nopMeter{}
+}
+
+// OrNop m が nil の場合は Nop を返す
+func OrNop(m Meter) Meter {
+	if m == nil {
+		return Nop()
+	}
+	return m
+}
+
+type nopMeter struct{}
+
+type nopInstrument struct{}
+
+func (nopMeter) Counter(string, string) Counter                { return nopInstrument{} }
+func (nopMeter) Gauge(string, string) Gauge                    { return nopInstrument{} }
+func (nopMeter) Histogram(string, string, []float64) Histogram { return nopInstrument{} }
+
+func (nopInstrument) Add(float64, ...Label)     {}
+func (nopInstrument) Set(float64, ...Label)     {}
+func (nopInstrument) Observe(float64, ...Label) {}
+
+type contextKey struct{}
+
+// NewContext m を設定した ctx を返す
+// コンストラクタを持たない処理（mysql のクエリビルダーなど）に Meter を渡すために使用する。
+func NewContext(ctx context.Context, m Meter) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// FromContext ctx に設定された Meter を返す。設定されていない場合は Nop を返す
+func FromContext(ctx context.Context) Meter {
+	if m, ok := ctx.Value(contextKey{}).(Meter); ok && m != nil {
+		return m
+	}
+	return Nop()
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRegistry_Counter(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("requests_total", "リクエスト数")
+	c.Add(1, L("method", "GET"))
+	c.Add(2, L("method", "GET"))
+	c.Add(1, L("method", "POST"))
+	// 負の値は無視する
+	c.Add(-1, L("method", "GET"))
+	// 同じ名前は同じ値を共有する
+	r.Counter("requests_total", "").Add(1, L("method", "POST"))
+
+	fs := r.Gather()
+	if len(fs) != 1 {
+		t.Fatalf("計測値の数が想定外です。got=%v, want=%v", len(fs), 1)
+	}
+	want := []Series{
+		{Labels: []Label{L("method", "GET")}, Value: 3},
+		{Labels: []Label{L("method", "POST")}, Value: 2},
+	}
+	if !reflect.DeepEqual(fs[0].Series, want) {
+		t.Errorf("計測値が想定外です。got=%+v, want=%+v", fs[0].Series, want)
+	}
+	if fs[0].Kind != KindCounter || fs[0].Help != "リクエスト数" {
+		t.Errorf("種類または説明が想定外です。got=%v, %v", fs[0].Kind, fs[0].Help)
+	}
+}
+
+func TestRegistry_Gauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.Gauge("connections", "")
+	g.Set(10)
+	g.Add(-3)
+
+	fs := r.Gather()
+	if got := fs[0].Series[0].Value; got != 7 {
+		t.Errorf("計測値が想定外です。got=%v, want=%v", got, 7)
+	}
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("latency_seconds", "", []float64{1, 0.1})
+	for _, v := range []float64{0.05, 0.1, 0.5, 2} {
+		h.Observe(v)
+	}
+
+	f := r.Gather()[0]
+	if want := []float64{0.1, 1}; !reflect.DeepEqual(f.Bounds, want) {
+		t.Errorf("バケットが想定外です。got=%v, want=%v", f.Bounds, want)
+	}
+	s := f.Series[0]
+	// 上限値と等しい値はそのバケットに含める
+	if want := []uint64{2, 1, 1}; !reflect.DeepEqual(s.BucketCounts, want) {
+		t.Errorf("バケットの件数が想定外です。got=%v, want=%v", s.BucketCounts, want)
+	}
+	if s.Count != 4 || s.Sum != 2.65 {
+		t.Errorf("件数または合計が想定外です。got=%v, %v", s.Count, s.Sum)
+	}
+}
+
+func TestRegistry_LabelOrder(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("c", "")
+	c.Add(1, L("b", "2"), L("a", "1"))
+	c.Add(1, L("a", "1"), L("b", "2"))
+
+	s := r.Gather()[0].Series
+	if len(s) != 1 || s[0].Value != 2 {
+		t.Errorf("ラベルの順序で別の値になりました。got=%+v", s)
+	}
+}
+
+func TestRegistry_Panic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{
+			name: "異常値: 名前が不正",
+			fn: func(r *Registry) {
+				r.Counter("invalid-name", "")
+			},
+		},
+		{
+			name: "異常値: 種類が異なる",
+			fn: func(r *Registry) {
+				r.Counter("c", "")
+				r.Gauge("c", "")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("panic しませんでした。")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("c", "")
+	h := r.Histogram("h", "", nil)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c.Add(1)
+				h.Observe(0.01)
+				r.Gather()
+			}
+		}()
+	}
+	wg.Wait()
+
+	fs := r.Gather()
+	if got := fs[0].Series[0].Value; got != 1000 {
+		t.Errorf("カウンターが想定外です。got=%v, want=%v", got, 1000)
+	}
+	if got := fs[1].Series[0].Count; got != 1000 {
+		t.Errorf("ヒストグラムの件数が想定外です。got=%v, want=%v", got, 1000)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	r := NewRegistry()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Meter
+	}{
+		{name: "正常値: 設定済み", ctx: NewContext(context.Background(), r), want: r},
+		{name: "正常値: 未設定", ctx: context.Background(), want: Nop()},
+		{name: "正常値: nil を設定", ctx: NewContext(context.Background(), nil), want: Nop()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromContext(tt.ctx); got != tt.want {
+				t.Errorf("Meter が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
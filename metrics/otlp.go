@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"valley-pkg/logging"
+)
+
+// scopeName OTLP の計測スコープ名
+const scopeName = "valley-pkg/metrics"
+
+// OTLPExporter Registry の計測値を OTLP/HTTP（JSON）で定期的に送信する
+type OTLPExporter struct {
+	registry *Registry
+	endpoint string
+
+	client      *http.Client
+	interval    time.Duration
+	headers     map[string]string
+	serviceName string
+	logger      logging.Logger
+}
+
+// OTLPOption OTLPExporter のオプション
+type OTLPOption func(*OTLPExporter)
+
+// WithInterval 送信間隔を設定する（デフォルト: 1 分）
+func WithInterval(d time.Duration) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.interval = d
+	}
+}
+
+// WithHeader リクエストに付与するヘッダーを追加する
+func WithHeader(key, value string) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.headers[key] = value
+	}
+}
+
+// WithServiceName リソース属性 service.name を設定する
+func WithServiceName(name string) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.serviceName = name
+	}
+}
+
+// WithHTTPClient 送信に使用する http.Client を設定する
+func WithHTTPClient(c *http.Client) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.client = c
+	}
+}
+
+// WithLogger 送信に失敗した場合のロガーを設定する
+func WithLogger(l logging.Logger) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.logger = l
+	}
+}
+
+// NewOTLPExporter OTLPExporter を生成する
+// endpoint は http://localhost:4318/v1/metrics のようにパスを含めて指定する。
+func NewOTLPExporter(r *Registry, endpoint string, opts ...OTLPOption) *OTLPExporter {
+	e := &OTLPExporter{
+		registry: r,
+		endpoint: endpoint,
+		client:   http.DefaultClient,
+		interval: time.Minute,
+		headers:  make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.logger = logging.OrDefault(e.logger)
+	return e
+}
+
+// Run ctx が終了するまで定期的に送信する
+// 終了時に最後の計測値を送信する。
+func (e *OTLPExporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// 親のコンテキストは終了しているため、送信用に別のコンテキストを使用する
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			err := e.Export(flushCtx)
+			cancel()
+			if err != nil {
+				e.logger.Error("failed to export metrics", logging.Err(err))
+			}
+			return nil
+		case <-ticker.C:
+			if err := e.Export(ctx); err != nil {
+				e.logger.Error("failed to export metrics", logging.Err(err))
+			}
+		}
+	}
+}
+
+// Export 現在の計測値を送信する
+func (e *OTLPExporter) Export(ctx context.Context) error {
+	body, err := json.Marshal(e.encode(e.registry.Gather(), time.Now()))
+	if err != nil {
+		return fmt.Errorf("marshal metrics: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("export metrics: status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// 以下は OTLP の JSON エンコーディング（opentelemetry-proto の metrics.proto）に対応する型
+// 64 ビット整数は文字列で表す。
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+}
+
+// otlpCumulative AGGREGATION_TEMPORALITY_CUMULATIVE
+const otlpCumulative = 2
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// encode 計測値を OTLP のリクエストにする
+func (e *OTLPExporter) encode(families []Family, now time.Time) otlpRequest {
+	start := strconv.FormatInt(e.registry.Start().UnixNano(), 10)
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := make([]otlpMetric, 0, len(families))
+	for _, f := range families {
+		m := otlpMetric{Name: f.Name, Description: f.Help}
+		switch f.Kind {
+		case KindCounter, KindGauge:
+			points := make([]otlpNumberDataPoint, 0, len(f.Series))
+			for _, s := range f.Series {
+				points = append(points, otlpNumberDataPoint{
+					Attributes:        otlpAttributes(s.Labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					AsDouble:          s.Value,
+				})
+			}
+			if f.Kind == KindCounter {
+				m.Sum = &otlpSum{DataPoints: points, AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			} else {
+				m.Gauge = &otlpGauge{DataPoints: points}
+			}
+		case KindHistogram:
+			points := make([]otlpHistogramDataPoint, 0, len(f.Series))
+			for _, s := range f.Series {
+				counts := make([]string, len(s.BucketCounts))
+				for i, n := range s.BucketCounts {
+					counts[i] = strconv.FormatUint(n, 10)
+				}
+				points = append(points, otlpHistogramDataPoint{
+					Attributes:        otlpAttributes(s.Labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(s.Count, 10),
+					Sum:               s.Sum,
+					BucketCounts:      counts,
+					ExplicitBounds:    f.Bounds,
+				})
+			}
+			m.Histogram = &otlpHistogram{DataPoints: points, AggregationTemporality: otlpCumulative}
+		}
+		metrics = append(metrics, m)
+	}
+
+	var resource otlpResource
+	if e.serviceName != "" {
+		resource.Attributes = otlpAttributes([]Label{L("service.name", e.serviceName)})
+	}
+	return otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: resource,
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: scopeName},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(labels []Label) []otlpAttribute {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := make([]otlpAttribute, len(labels))
+	for i, l := range labels {
+		out[i] = otlpAttribute{Key: l.Key, Value: otlpAnyValue{StringValue: l.Value}}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOTLPExporter_Export(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("requests_total", "リクエスト数").Add(3, L("path", "/a"))
+	r.Gauge("connections", "").Set(2)
+	r.Histogram("latency_seconds", "", []float64{0.1, 1}).Observe(0.5)
+
+	var (
+		gotBody   []byte
+		gotHeader http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotBody, _ = io.ReadAll(req.Body)
+		gotHeader = req.Header
+	}))
+	defer srv.Close()
+
+	e := NewOTLPExporter(r, srv.URL+"/v1/metrics", WithServiceName("api"), WithHeader("Authorization", "Bearer token"))
+	if err := e.Export(context.Background()); err != nil {
+		t.Fatalf("エラーが想定外です。got=%v", err)
+	}
+	if got := gotHeader.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("ヘッダーが想定外です。got=%v", got)
+	}
+
+	var req otlpRequest
+	if err := json.Unmarshal(gotBody, &req); err != nil {
+		t.Fatal(err)
+	}
+	rm := req.ResourceMetrics[0]
+	if want := otlpAttributes([]Label{L("service.name", "api")}); !reflect.DeepEqual(rm.Resource.Attributes, want) {
+		t.Errorf("リソース属性が想定外です。got=%+v, want=%+v", rm.Resource.Attributes, want)
+	}
+	ms := rm.ScopeMetrics[0].Metrics
+	if len(ms) != 3 {
+		t.Fatalf("計測値の数が想定外です。got=%v, want=%v", len(ms), 3)
+	}
+	// 名前順に並ぶ
+	if ms[0].Gauge == nil || ms[0].Gauge.DataPoints[0].AsDouble != 2 {
+		t.Errorf("ゲージが想定外です。got=%+v", ms[0])
+	}
+	if h := ms[1].Histogram; h == nil || h.DataPoints[0].Count != "1" ||
+		!reflect.DeepEqual(h.DataPoints[0].BucketCounts, []string{"0", "1", "0"}) ||
+		!reflect.DeepEqual(h.DataPoints[0].ExplicitBounds, []float64{0.1, 1}) {
+		t.Errorf("ヒストグラムが想定外です。got=%+v", ms[1].Histogram)
+	}
+	if s := ms[2].Sum; s == nil || !s.IsMonotonic || s.AggregationTemporality != otlpCumulative ||
+		s.DataPoints[0].AsDouble != 3 || s.DataPoints[0].Attributes[0].Key != "path" {
+		t.Errorf("カウンターが想定外です。got=%+v", ms[2].Sum)
+	}
+}
+
+func TestOTLPExporter_Export_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	e := NewOTLPExporter(NewRegistry(), srv.URL)
+	if err := e.Export(context.Background()); err == nil {
+		t.Error("エラーが返されませんでした。")
+	}
+}
+
+func TestOTLPExporter_Run(t *testing.T) {
+	exported := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		exported <- struct{}{}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := NewOTLPExporter(NewRegistry(), srv.URL, WithInterval(10*time.Millisecond))
+	done := make(chan error, 1)
+	go func() {
+		done <- e.Run(ctx)
+	}()
+
+	select {
+	case <-exported:
+	case <-time.After(time.Second):
+		t.Fatal("定期的に送信されませんでした。")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("エラーが想定外です。got=%v", err)
+	}
+}
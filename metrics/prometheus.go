@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// prometheusContentType Prometheus のテキスト形式の Content-Type
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusHandler r の計測値を Prometheus のテキスト形式で返す http.Handler を返す
+func PrometheusHandler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", prometheusContentType)
+		if err := WritePrometheus(w, r.Gather()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// WritePrometheus 計測値を Prometheus のテキスト形式で書き込む
+func WritePrometheus(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if f.Help != "" {
+			bw.WriteString("# HELP " + f.Name + " " + escapeHelp(f.Help) + "\n")
+		}
+		bw.WriteString("# TYPE " + f.Name + " " + f.Kind.String() + "\n")
+		for _, s := range f.Series {
+			if f.Kind != KindHistogram {
+				writeSample(bw, f.Name, s.Labels, formatFloat(s.Value))
+				continue
+			}
+			// バケットは累積の件数で出力する
+			var cumulative uint64
+			for i, n := range s.BucketCounts {
+				cumulative += n
+				le := "+Inf"
+				if i < len(f.Bounds) {
+					le = formatFloat(f.Bounds[i])
+				}
+				labels := append(append([]Label(nil), s.Labels...), L("le", le))
+				writeSample(bw, f.Name+"_bucket", labels, strconv.FormatUint(cumulative, 10))
+			}
+			writeSample(bw, f.Name+"_sum", s.Labels, formatFloat(s.Sum))
+			writeSample(bw, f.Name+"_count", s.Labels, strconv.FormatUint(s.Count, 10))
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name string, labels []Label, value string) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(l.Key + `="` + escapeLabelValue(l.Value) + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteString(" " + value + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
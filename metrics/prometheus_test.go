@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("requests_total", "リクエスト数\n改行").Add(3, L("path", `/a"b`))
+	r.Gauge("connections", "").Set(2)
+	h := r.Histogram("latency_seconds", "処理時間", []float64{0.1, 1})
+	h.Observe(0.05, L("op", "get"))
+	h.Observe(0.5, L("op", "get"))
+	h.Observe(5, L("op", "get"))
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(r).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != prometheusContentType {
+		t.Errorf("Content-Type が想定外です。got=%v, want=%v", got, prometheusContentType)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	want := `# TYPE connections gauge
+connections 2
+# HELP latency_seconds 処理時間
+# TYPE latency_seconds histogram
+latency_seconds_bucket{op="get",le="0.1"} 1
+latency_seconds_bucket{op="get",le="1"} 2
+latency_seconds_bucket{op="get",le="+Inf"} 3
+latency_seconds_sum{op="get"} 5.55
+latency_seconds_count{op="get"} 3
+# HELP requests_total リクエスト数\n改行
+# TYPE requests_total counter
+requests_total{path="/a\"b"} 3
+`
+	if string(body) != want {
+		t.Errorf("出力が想定外です。got=\n%v\nwant=\n%v", string(body), want)
+	}
+}
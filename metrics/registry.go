@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind 計測器の種類
+type Kind int
+
+const (
+	KindCounter Kind = iota + 1
+	KindGauge
+	KindHistogram
+)
+
+// String 種類の名前を返す
+func (k Kind) String() string {
+	switch k {
+	case KindCounter:
+		return "counter"
+	case KindGauge:
+		return "gauge"
+	case KindHistogram:
+		return "histogram"
+	default:
+		return "unknown"
+	}
+}
+
+// Family 同じ名前の計測値の集まり
+type Family struct {
+	Name string
+	Help string
+	Kind Kind
+	// Bounds ヒストグラムのバケットの上限値
+	Bounds []float64
+	Series []Series
+}
+
+// Series ラベルの組み合わせごとの計測値
+type Series struct {
+	Labels []Label
+	// Value カウンターとゲージの値
+	Value float64
+	// BucketCounts ヒストグラムのバケットごとの件数（累積ではない）。末尾は上限値を超えた件数
+	BucketCounts []uint64
+	// Count ヒストグラムの件数
+	Count uint64
+	// Sum ヒストグラムの合計値
+	Sum float64
+}
+
+// Registry 計測値をメモリ上で集計する Meter
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+	start    time.Time
+}
+
+var _ Meter = (*Registry)(nil)
+
+// NewRegistry Registry を生成する
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+		start:    time.Now(),
+	}
+}
+
+// Start Registry を生成した時刻を返す
+func (r *Registry) Start() time.Time {
+	return r.start
+}
+
+// Counter カウンターを返す
+// 名前が不正な場合、または同じ名前で別の種類の計測器を登録済みの場合は panic する。
+func (r *Registry) Counter(name, help string) Counter {
+	return r.register(name, help, KindCounter, nil)
+}
+
+// Gauge ゲージを返す
+// 名前が不正な場合、または同じ名前で別の種類の計測器を登録済みの場合は panic する。
+func (r *Registry) Gauge(name, help string) Gauge {
+	return r.register(name, help, KindGauge, nil)
+}
+
+// Histogram ヒストグラムを返す
+// 名前が不正な場合、または同じ名前で別の種類の計測器を登録済みの場合は panic する。
+func (r *Registry) Histogram(name, help string, buckets []float64) Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	return r.register(name, help, KindHistogram, bounds)
+}
+
+func (r *Registry) register(name, help string, kind Kind, bounds []float64) *family {
+	if err := validateName(name); err != nil {
+		panic(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind {
+			panic(fmt.Sprintf("metric %q is already registered as %s", name, f.kind))
+		}
+		return f
+	}
+	f := &family{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		bounds: bounds,
+		series: make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// Gather 現在の計測値を名前順に返す
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	fs := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		fs = append(fs, f)
+	}
+	r.mu.RUnlock()
+
+	out := make([]Family, 0, len(fs))
+	for _, f := range fs {
+		out = append(out, f.snapshot())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// family 同じ名前の計測器
+// Counter / Gauge / Histogram のすべてを実装し、kind に応じて使い分ける。
+type family struct {
+	name   string
+	help   string
+	kind   Kind
+	bounds []float64
+
+	mu     sync.RWMutex
+	series map[string]*series
+}
+
+// series ラベルの組み合わせごとの値
+type series struct {
+	labels []Label
+
+	// value float64 のビット列
+	value atomic.Uint64
+
+	// 以下はヒストグラムでのみ使用する
+	mu      sync.Mutex
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func (f *family) Add(v float64, labels ...Label) {
+	if f.kind == KindCounter && v < 0 {
+		return
+	}
+	s := f.get(labels)
+	for {
+		old := s.value.Load()
+		if s.value.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+func (f *family) Set(v float64, labels ...Label) {
+	f.get(labels).value.Store(math.Float64bits(v))
+}
+
+func (f *family) Observe(v float64, labels ...Label) {
+	s := f.get(labels)
+	i := sort.SearchFloat64s(f.bounds, v)
+	s.mu.Lock()
+	s.buckets[i]++
+	s.count++
+	s.sum += v
+	s.mu.Unlock()
+}
+
+// get ラベルの組み合わせに対応する値を返す
+func (f *family) get(labels []Label) *series {
+	labels = sortLabels(labels)
+	key := labelKey(labels)
+
+	f.mu.RLock()
+	s, ok := f.series[key]
+	f.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.series[key]; ok {
+		return s
+	}
+	s = &series{labels: labels}
+	if f.kind == KindHistogram {
+		s.buckets = make([]uint64, len(f.bounds)+1)
+	}
+	f.series[key] = s
+	return s
+}
+
+func (f *family) snapshot() Family {
+	out := Family{
+		Name:   f.name,
+		Help:   f.help,
+		Kind:   f.kind,
+		Bounds: f.bounds,
+	}
+
+	f.mu.RLock()
+	ss := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		ss = append(ss, s)
+	}
+	f.mu.RUnlock()
+
+	for _, s := range ss {
+		v := Series{Labels: s.labels}
+		if f.kind == KindHistogram {
+			s.mu.Lock()
+			v.BucketCounts = append([]uint64(nil), s.buckets...)
+			v.Count = s.count
+			v.Sum = s.sum
+			s.mu.Unlock()
+		} else {
+			v.Value = math.Float64frombits(s.value.Load())
+		}
+		out.Series = append(out.Series, v)
+	}
+	sort.Slice(out.Series, func(i, j int) bool {
+		return labelKey(out.Series[i].Labels) < labelKey(out.Series[j].Labels)
+	})
+	return out
+}
+
+// sortLabels キーの順に並べたラベルを返す
+func sortLabels(labels []Label) []Label {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := append([]Label(nil), labels...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// labelKey ラベルの組み合わせを識別する文字列を返す
+func labelKey(labels []Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.Key)
+		b.WriteByte(0)
+		b.WriteString(l.Value)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
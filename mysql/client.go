@@ -1,18 +1,39 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"github.com/go-sql-driver/mysql"
 	"time"
+	"valley-pkg/health"
+	"valley-pkg/logging"
 )
 
 type MysqlClient struct {
 	config *mysql.Config
 	db     *sql.DB
+	logger logging.Logger
+}
+
+// Option MysqlClient の設定
+type Option func(*MysqlClient)
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+// クエリビルダーのログは ctx に logging.NewContext で設定した Logger に出力する。
+func WithLogger(l logging.Logger) Option {
+	return func(c *MysqlClient) {
+		c.logger = l
+	}
 }
 
 // NewMysqlClient コンストラクタ
-func NewMysqlClient() (*MysqlClient, error) {
+func NewMysqlClient(opts ...Option) (*MysqlClient, error) {
+	client := &MysqlClient{}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.logger = logging.OrDefault(client.logger)
+
 	jst, _ := time.LoadLocation("Asia/Tokyo")
 	c := mysql.Config{
 		DBName:               "sample",
@@ -28,6 +49,7 @@ func NewMysqlClient() (*MysqlClient, error) {
 
 	db, err := sql.Open("mysql", c.FormatDSN())
 	if err != nil {
+		client.logger.Error("failed to open mysql", logging.Err(err), logging.F("addr", c.Addr), logging.F("db", c.DBName))
 		return nil, err
 	}
 
@@ -36,5 +58,15 @@ func NewMysqlClient() (*MysqlClient, error) {
 	db.SetMaxIdleConns(10)
 	db.SetConnMaxLifetime(10 * time.Minute)
 
-	return &MysqlClient{config: &c, db: db}, nil
+	client.config = &c
+	client.db = db
+	client.logger.Debug("mysql client opened", logging.F("addr", c.Addr), logging.F("db", c.DBName))
+	return client, nil
+}
+
+// HealthChecker MySQL に ping を送る health.Checker を返す
+func (c *MysqlClient) HealthChecker() health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		return c.db.PingContext(ctx)
+	})
 }
@@ -3,6 +3,7 @@ package mysql
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -60,12 +61,124 @@ func NotEq(col string, v any) *WhereCond {
 	return &WhereCond{sql: fmt.Sprintf("%s <> ?", col), args: []any{v}}
 }
 
+// IsNull NULL である条件
+func IsNull(col string) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s IS NULL", col)}
+}
+
+// IsNotNull NULL でない条件
+func IsNotNull(col string) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s IS NOT NULL", col)}
+}
+
+// Gt より大きい条件
+func Gt(col string, v any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s > ?", col), args: []any{v}}
+}
+
+// Gte 以上の条件
+func Gte(col string, v any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s >= ?", col), args: []any{v}}
+}
+
+// Lt より小さい条件
+func Lt(col string, v any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s < ?", col), args: []any{v}}
+}
+
+// Lte 以下の条件
+func Lte(col string, v any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s <= ?", col), args: []any{v}}
+}
+
+// Between 範囲条件（from, to を含む）
+func Between(col string, from, to any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s BETWEEN ? AND ?", col), args: []any{from, to}}
+}
+
+// NotBetween 範囲外の条件
+func NotBetween(col string, from, to any) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s NOT BETWEEN ? AND ?", col), args: []any{from, to}}
+}
+
+// Like LIKE 条件
+// pattern はそのまま使用するため、利用者の入力を含める場合は EscapeLike でエスケープすること。
+func Like(col string, pattern string) *WhereCond {
+	return &WhereCond{sql: fmt.Sprintf("%s LIKE ?", col), args: []any{pattern}}
+}
+
+// HasPrefix 前方一致の条件。s の % と _ はエスケープする
+func HasPrefix(col string, s string) *WhereCond {
+	return Like(col, EscapeLike(s)+"%")
+}
+
+// HasSuffix 後方一致の条件。s の % と _ はエスケープする
+func HasSuffix(col string, s string) *WhereCond {
+	return Like(col, "%"+EscapeLike(s))
+}
+
+// Contains 部分一致の条件。s の % と _ はエスケープする
+func Contains(col string, s string) *WhereCond {
+	return Like(col, "%"+EscapeLike(s)+"%")
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike は LIKE のワイルドカード（% と _）とエスケープ文字（\）をエスケープする
+// MySQL の既定のエスケープ文字（\）を前提とする。
+func EscapeLike(s string) string {
+	return likeReplacer.Replace(s)
+}
+
+// In IN 条件
+// values に1つのスライスを渡した場合は要素に展開する。値が空の場合は常に偽となる条件を返す。
+func In(col string, values ...any) *WhereCond {
+	args := flattenArgs(values)
+	if len(args) == 0 {
+		return &WhereCond{sql: "1 = 0"}
+	}
+	return &WhereCond{sql: fmt.Sprintf("%s IN (%s)", col, placeholders(len(args))), args: args}
+}
+
+// NotIn NOT IN 条件
+// values に1つのスライスを渡した場合は要素に展開する。値が空の場合は常に真となる条件を返す。
+func NotIn(col string, values ...any) *WhereCond {
+	args := flattenArgs(values)
+	if len(args) == 0 {
+		return &WhereCond{sql: "1 = 1"}
+	}
+	return &WhereCond{sql: fmt.Sprintf("%s NOT IN (%s)", col, placeholders(len(args))), args: args}
+}
+
+// flattenArgs は values が1つのスライスの場合に要素を展開する
+// []byte は1つの値として扱う。
+func flattenArgs(values []any) []any {
+	if len(values) != 1 {
+		return values
+	}
+	v := reflect.ValueOf(values[0])
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return values
+	}
+	args := make([]any, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+	return args
+}
+
+// placeholders は n 個のプレースホルダーをカンマ区切りで返す
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
 // And And句
+// 引数を持たない条件（IsNull など）や nil も組み合わせられる。nil と空の条件は無視する。
 func And(conds ...*WhereCond) *WhereCond {
 	var parts []string
 	var args []any
 	for _, c := range conds {
-		if c.isEmpty() {
+		if c == nil || c.isEmpty() {
 			continue
 		}
 		parts = append(parts, "("+c.sql+")")
@@ -77,11 +190,12 @@ func And(conds ...*WhereCond) *WhereCond {
 }
 
 // Or Or句
+// 引数を持たない条件（IsNull など）や nil も組み合わせられる。nil と空の条件は無視する。
 func Or(conds ...*WhereCond) *WhereCond {
 	var parts []string
 	var args []any
 	for _, c := range conds {
-		if c.isEmpty() {
+		if c == nil || c.isEmpty() {
 			continue
 		}
 		parts = append(parts, "("+c.sql+")")
@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWhereCond(t *testing.T) {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		cond     *WhereCond
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:     "正常値: Gt",
+			cond:     Gt("age", 20),
+			wantSQL:  "age > ?",
+			wantArgs: []any{20},
+		},
+		{
+			name:     "正常値: Gte",
+			cond:     Gte("created_at", from),
+			wantSQL:  "created_at >= ?",
+			wantArgs: []any{from},
+		},
+		{
+			name:     "正常値: Lt",
+			cond:     Lt("age", 65),
+			wantSQL:  "age < ?",
+			wantArgs: []any{65},
+		},
+		{
+			name:     "正常値: Lte",
+			cond:     Lte("created_at", to),
+			wantSQL:  "created_at <= ?",
+			wantArgs: []any{to},
+		},
+		{
+			name:     "正常値: Between",
+			cond:     Between("created_at", from, to),
+			wantSQL:  "created_at BETWEEN ? AND ?",
+			wantArgs: []any{from, to},
+		},
+		{
+			name:     "正常値: NotBetween",
+			cond:     NotBetween("score", 10, 20),
+			wantSQL:  "score NOT BETWEEN ? AND ?",
+			wantArgs: []any{10, 20},
+		},
+		{
+			name:     "正常値: 範囲の組み合わせ",
+			cond:     And(Eq("tenant_id", "t1"), Gte("created_at", from), Lt("created_at", to)),
+			wantSQL:  "(tenant_id = ?) AND (created_at >= ?) AND (created_at < ?)",
+			wantArgs: []any{"t1", from, to},
+		},
+		{
+			name:    "正常値: IsNull",
+			cond:    IsNull("deleted_at"),
+			wantSQL: "deleted_at IS NULL",
+		},
+		{
+			name:    "正常値: IsNotNull",
+			cond:    IsNotNull("deleted_at"),
+			wantSQL: "deleted_at IS NOT NULL",
+		},
+		{
+			name:     "正常値: And で IsNull と組み合わせ",
+			cond:     And(Eq("tenant_id", "t1"), IsNull("deleted_at")),
+			wantSQL:  "(tenant_id = ?) AND (deleted_at IS NULL)",
+			wantArgs: []any{"t1"},
+		},
+		{
+			name:     "正常値: Or で IsNotNull と組み合わせ",
+			cond:     Or(IsNotNull("deleted_at"), Eq("status", "archived")),
+			wantSQL:  "(deleted_at IS NOT NULL) OR (status = ?)",
+			wantArgs: []any{"archived"},
+		},
+		{
+			name:    "正常値: nil は無視する",
+			cond:    And(nil, IsNull("deleted_at")),
+			wantSQL: "(deleted_at IS NULL)",
+		},
+		{
+			name:     "正常値: Like",
+			cond:     Like("name", "A%_"),
+			wantSQL:  "name LIKE ?",
+			wantArgs: []any{"A%_"},
+		},
+		{
+			name:     "正常値: HasPrefix",
+			cond:     HasPrefix("name", "Al"),
+			wantSQL:  "name LIKE ?",
+			wantArgs: []any{"Al%"},
+		},
+		{
+			name:     "正常値: HasSuffix",
+			cond:     HasSuffix("email", "@example.com"),
+			wantSQL:  "email LIKE ?",
+			wantArgs: []any{"%@example.com"},
+		},
+		{
+			name:     "正常値: Contains",
+			cond:     Contains("name", "li"),
+			wantSQL:  "name LIKE ?",
+			wantArgs: []any{"%li%"},
+		},
+		{
+			name:     "正常値: Contains ワイルドカードをエスケープ",
+			cond:     Contains("name", `50%_off\`),
+			wantSQL:  "name LIKE ?",
+			wantArgs: []any{`%50\%\_off\\%`},
+		},
+		{
+			name:     "正常値: In",
+			cond:     In("id", 1, 2, 3),
+			wantSQL:  "id IN (?, ?, ?)",
+			wantArgs: []any{1, 2, 3},
+		},
+		{
+			name:     "正常値: In スライスを展開",
+			cond:     In("id", []int{1, 2}),
+			wantSQL:  "id IN (?, ?)",
+			wantArgs: []any{1, 2},
+		},
+		{
+			name:     "正常値: In []byte は展開しない",
+			cond:     In("hash", []byte("ab")),
+			wantSQL:  "hash IN (?)",
+			wantArgs: []any{[]byte("ab")},
+		},
+		{
+			name:    "正常値: In 空の場合は常に偽",
+			cond:    In("id", []string{}),
+			wantSQL: "1 = 0",
+		},
+		{
+			name:     "正常値: NotIn",
+			cond:     NotIn("status", "deleted", "banned"),
+			wantSQL:  "status NOT IN (?, ?)",
+			wantArgs: []any{"deleted", "banned"},
+		},
+		{
+			name:    "正常値: NotIn 空の場合は常に真",
+			cond:    NotIn("status"),
+			wantSQL: "1 = 1",
+		},
+		{
+			name:     "正常値: And と組み合わせ",
+			cond:     And(Eq("tenant_id", "t1"), In("id", []int64{1, 2})),
+			wantSQL:  "(tenant_id = ?) AND (id IN (?, ?))",
+			wantArgs: []any{"t1", int64(1), int64(2)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cond.GetSQL(); got != tt.wantSQL {
+				t.Errorf("SQL が想定外です。got=%v, want=%v", got, tt.wantSQL)
+			}
+			if got := tt.cond.GwtArgs(); !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("引数が想定外です。got=%v, want=%v", got, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -3,8 +3,8 @@ package mysql
 import (
 	"context"
 	"fmt"
-	"github.com/jmoiron/sqlx"
 	"strings"
+	"time"
 )
 
 type deleteBuilder struct {
@@ -52,17 +52,18 @@ func (d DeleteWithoutWhere) Where(c *WhereCond) DeleteWithWhere {
 
 // Exec は、指定されたコンテキスト内で提供されたデータベース接続に対して、ビルダーによって定義された DELETE SQL クエリを実行します。
 // 実行が成功した場合、影響を受けた行数を返します。失敗した場合はエラーを返します。
-func (d DeleteWithWhere) Exec(ctx context.Context, db *sqlx.DB) (int64, error) {
+func (d DeleteWithWhere) Exec(ctx context.Context, db Execer) (int64, error) {
 	q, args, err := d.builder.build()
 	if err != nil {
 		return 0, err
 	}
 	q = db.Rebind(q)
 
-	fmt.Printf("delete query: %s\n", q)
-	fmt.Printf("delete args: %#v\n", args)
+	logQuery(ctx, q, args)
 
+	start := time.Now()
 	res, err := db.ExecContext(ctx, q, args...)
+	observeQuery(ctx, "delete", start, err)
 	if err != nil {
 		return 0, err
 	}
@@ -4,15 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jmoiron/sqlx"
+	"reflect"
 	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrValuesRequired        = errors.New("insert requires values")
+	ErrRowLengthMismatch     = errors.New("insert rows must have the same number of values")
+	ErrColumnsMismatch       = errors.New("insert columns and values length mismatch")
+	ErrBulkValuesNotSlice    = errors.New("bulk values must be a slice of struct or *struct")
+	ErrUpdateAllNeedsColumns = errors.New("on duplicate key update all requires columns")
+	ErrTooManyPlaceholders   = errors.New("insert row exceeds max placeholders")
+	ErrPartialInsert         = errors.New("insert partially executed")
 )
 
-var ErrValuesRequired = errors.New("insert requires values")
+// DefaultMaxPlaceholders は1文あたりのプレースホルダー数の上限の既定値（MySQL のプリペアドステートメントの上限）
+const DefaultMaxPlaceholders = 65535
 
 type InsertBuilder struct {
-	table  string
-	values *InsertCond
+	table           string
+	columns         []string
+	rows            []*InsertCond
+	maxPlaceholders int
+	onDuplicate     []UpdateCond
+	onDuplicateAll  bool
+	err             error
+}
+
+// insertStatement は分割した INSERT 文の1つ
+type insertStatement struct {
+	query string
+	args  []any
+	rows  int
 }
 
 // InsertFrom は指定されたテーブル用の InsertBuilder を初期化し、返します。
@@ -22,52 +48,265 @@ func InsertFrom(table string) InsertBuilder {
 
 // Values 指定された InsertCond 条件を InsertBuilder に追加し、更新された InsertBuilder を返します。
 func (b InsertBuilder) Values(conds *InsertCond) InsertBuilder {
-	b.values = conds
+	b.rows = nil
+	if conds != nil {
+		b.rows = []*InsertCond{conds}
+	}
 	return b
 }
 
-// Exec 実行
-func (b InsertBuilder) Exec(ctx context.Context, db *sqlx.DB) (int64, error) {
-	q, args, err := b.build()
+// Rows は複数行の値を追加し、更新された InsertBuilder を返します。
+// 各行の値の数は同じである必要があります。
+func (b InsertBuilder) Rows(rows ...*InsertCond) InsertBuilder {
+	b.rows = append(append([]*InsertCond(nil), b.rows...), rows...)
+	return b
+}
+
+// Columns は INSERT する列を設定し、更新された InsertBuilder を返します。
+func (b InsertBuilder) Columns(cols ...string) InsertBuilder {
+	b.columns = append([]string(nil), cols...)
+	return b
+}
+
+// BulkValues は構造体のスライスの各要素を1行として追加し、更新された InsertBuilder を返します。
+// 列は db タグから決定します。rows が構造体（またはそのポインタ）のスライスでない場合、Exec でエラーを返します。
+func (b InsertBuilder) BulkValues(rows any) InsertBuilder {
+	cols, conds, err := rowsFromStructs(rows)
 	if err != nil {
-		return 0, err
+		b.err = err
+		return b
 	}
-	q = db.Rebind(q)
+	b.columns = cols
+	return b.Rows(conds...)
+}
 
-	fmt.Printf("update query: %s\n", q)
-	fmt.Printf("update args: %#v\n", args)
+// MaxPlaceholders は1文あたりのプレースホルダー数の上限を設定し、更新された InsertBuilder を返します。
+// 上限を超える場合は複数の INSERT 文に分割します。0 以下の場合は DefaultMaxPlaceholders を使用します。
+// 1行と ON DUPLICATE KEY UPDATE の値だけで上限を超える場合、Exec で ErrTooManyPlaceholders を返します。
+func (b InsertBuilder) MaxPlaceholders(n int) InsertBuilder {
+	b.maxPlaceholders = n
+	return b
+}
+
+// OnDuplicateKeyUpdate は重複したキーがある場合に更新する列を追加し、更新された InsertBuilder を返します。
+// 複数の文に分割した場合は、各文に同じ ON DUPLICATE KEY UPDATE 句を付与します。
+func (b InsertBuilder) OnDuplicateKeyUpdate(conds ...UpdateCond) InsertBuilder {
+	b.onDuplicate = append(append([]UpdateCond(nil), b.onDuplicate...), conds...)
+	return b
+}
+
+// OnDuplicateKeyUpdateAll は重複したキーがある場合に、INSERT するすべての列を挿入しようとした値で更新します。
+// 列は Columns または BulkValues で指定されている必要があります。OnDuplicateKeyUpdate と併用した場合は、その列を優先します。
+func (b InsertBuilder) OnDuplicateKeyUpdateAll() InsertBuilder {
+	b.onDuplicateAll = true
+	return b
+}
 
-	res, err := db.ExecContext(ctx, q, args...)
+// Exec 実行
+// 最初に挿入した行の ID を返します。複数の文に分割した場合、db が *sqlx.DB であればトランザクション内で実行し、
+// 途中で失敗した場合はすべてロールバックします。それ以外（*sqlx.Tx など）では1文ずつ実行し、
+// 途中の文で失敗した場合は、それまでの文が反映済みであることを示す ErrPartialInsert を返します。
+func (b InsertBuilder) Exec(ctx context.Context, db Execer) (int64, error) {
+	stmts, err := b.build()
 	if err != nil {
 		return 0, err
 	}
 
-	id, err := res.LastInsertId()
-	if err != nil {
-		return 0, err
+	if sqlDB, ok := db.(*sqlx.DB); ok && len(stmts) > 1 {
+		var firstID int64
+		err := WithTransaction(ctx, sqlDB, func(tx *sqlx.Tx) error {
+			var err error
+			firstID, err = execInsertStatements(ctx, tx, stmts)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return firstID, nil
+	}
+	return execInsertStatements(ctx, db, stmts)
+}
+
+// execInsertStatements は分割した INSERT 文を順に実行し、最初に挿入した行の ID を返します。
+// 2文目以降で失敗した場合は、反映済みの文と行の数を含めた ErrPartialInsert を返します。
+func execInsertStatements(ctx context.Context, db Execer, stmts []insertStatement) (int64, error) {
+	var firstID int64
+	var insertedRows int
+	for i, stmt := range stmts {
+		q := db.Rebind(stmt.query)
+
+		logQuery(ctx, q, stmt.args)
+
+		start := time.Now()
+		res, err := db.ExecContext(ctx, q, stmt.args...)
+		observeQuery(ctx, "insert", start, err)
+		if err != nil {
+			if i > 0 {
+				return 0, fmt.Errorf("%w: %d of %d statements (%d rows) already executed: %w", ErrPartialInsert, i, len(stmts), insertedRows, err)
+			}
+			return 0, err
+		}
+		insertedRows += stmt.rows
+		if i > 0 {
+			continue
+		}
+
+		firstID, err = res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
 	}
-	return id, nil
+	return firstID, nil
 }
 
 // build は SQL INSERT クエリ文字列を構築し、対応する値を準備し、無効な場合はエラーを返します。
-func (b InsertBuilder) build() (string, []any, error) {
-	if b.values == nil {
-		return "", nil, ErrValuesRequired
+// プレースホルダー数が上限を超える場合は複数の文に分割します。
+func (b InsertBuilder) build() ([]insertStatement, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.rows) == 0 {
+		return nil, ErrValuesRequired
 	}
 	if !safeIdent(b.table) {
-		return "", nil, fmt.Errorf("unsafe table: %s", b.table)
+		return nil, fmt.Errorf("unsafe table: %s", b.table)
+	}
+	for _, c := range b.columns {
+		if !safeIdent(c) {
+			return nil, fmt.Errorf("unsafe column: %s", c)
+		}
 	}
 
-	valStrs := make([]string, 0, len(b.values.Arg))
-	for range b.values.Arg {
-		valStrs = append(valStrs, "?")
+	if b.rows[0] == nil || len(b.rows[0].Arg) == 0 {
+		return nil, ErrValuesRequired
+	}
+	width := len(b.rows[0].Arg)
+	for _, r := range b.rows {
+		if r == nil || len(r.Arg) != width {
+			return nil, ErrRowLengthMismatch
+		}
+	}
+	if len(b.columns) > 0 && len(b.columns) != width {
+		return nil, ErrColumnsMismatch
 	}
 
-	sb := strings.Builder{}
-	sb.WriteString("INSERT INTO ")
-	sb.WriteString(b.table)
-	sb.WriteString(" VALUES ")
-	sb.WriteString("(" + strings.Join(valStrs, ", ") + ")")
+	tail, tailArgs, err := b.buildOnDuplicate()
+	if err != nil {
+		return nil, err
+	}
 
-	return sb.String(), b.values.Arg, nil
+	maxPlaceholders := b.maxPlaceholders
+	if maxPlaceholders <= 0 {
+		maxPlaceholders = DefaultMaxPlaceholders
+	}
+	if width+len(tailArgs) > maxPlaceholders {
+		return nil, fmt.Errorf("%w: %d placeholders per row, max %d", ErrTooManyPlaceholders, width+len(tailArgs), maxPlaceholders)
+	}
+	rowsPerStmt := (maxPlaceholders - len(tailArgs)) / width
+
+	head := new(strings.Builder)
+	head.WriteString("INSERT INTO ")
+	head.WriteString(b.table)
+	if len(b.columns) > 0 {
+		head.WriteString(" (" + strings.Join(b.columns, ", ") + ")")
+	}
+	head.WriteString(" VALUES ")
+	rowStr := "(" + placeholders(width) + ")"
+
+	stmts := make([]insertStatement, 0, (len(b.rows)+rowsPerStmt-1)/rowsPerStmt)
+	for start := 0; start < len(b.rows); start += rowsPerStmt {
+		chunk := b.rows[start:min(start+rowsPerStmt, len(b.rows))]
+
+		sb := strings.Builder{}
+		sb.WriteString(head.String())
+		args := make([]any, 0, len(chunk)*width+len(tailArgs))
+		for i, r := range chunk {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(rowStr)
+			args = append(args, r.Arg...)
+		}
+		sb.WriteString(tail)
+		args = append(args, tailArgs...)
+		stmts = append(stmts, insertStatement{query: sb.String(), args: args, rows: len(chunk)})
+	}
+	return stmts, nil
+}
+
+// buildOnDuplicate は ON DUPLICATE KEY UPDATE 句とその引数を構築します。指定がない場合は空文字を返します。
+func (b InsertBuilder) buildOnDuplicate() (string, []any, error) {
+	if len(b.onDuplicate) == 0 && !b.onDuplicateAll {
+		return "", nil, nil
+	}
+	if b.onDuplicateAll && len(b.columns) == 0 {
+		return "", nil, ErrUpdateAllNeedsColumns
+	}
+
+	setStrs := make([]string, 0, len(b.onDuplicate)+len(b.columns))
+	setArgs := make([]any, 0, len(b.onDuplicate))
+	explicit := make(map[string]struct{}, len(b.onDuplicate))
+	for _, s := range b.onDuplicate {
+		if !safeIdent(s.Set) {
+			return "", nil, fmt.Errorf("unsafe column: %s", s.Set)
+		}
+		setStrs = append(setStrs, fmt.Sprintf("%s = ?", s.Set))
+		setArgs = append(setArgs, s.Arg)
+		explicit[s.Set] = struct{}{}
+	}
+	if b.onDuplicateAll {
+		for _, c := range b.columns {
+			if _, ok := explicit[c]; ok {
+				continue
+			}
+			setStrs = append(setStrs, fmt.Sprintf("%s = VALUES(%s)", c, c))
+		}
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(setStrs, ", "), setArgs, nil
+}
+
+// rowsFromStructs は構造体のスライスから db タグの列名と各行の値を取り出します。
+func rowsFromStructs(rows any) ([]string, []*InsertCond, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return nil, nil, ErrBulkValuesNotSlice
+	}
+	t := v.Type().Elem()
+	ptr := t.Kind() == reflect.Ptr
+	if ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil, ErrBulkValuesNotSlice
+	}
+
+	cols, indexes, err := fieldsFromDBTags(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cols) == 0 {
+		return nil, nil, ErrNoDBTags
+	}
+	for _, idx := range indexes {
+		if f := t.Field(idx); !f.IsExported() {
+			return nil, nil, fmt.Errorf("unexported field with db tag: %s", f.Name)
+		}
+	}
+
+	conds := make([]*InsertCond, 0, v.Len())
+	for i := range v.Len() {
+		rv := v.Index(i)
+		if ptr {
+			if rv.IsNil() {
+				return nil, nil, fmt.Errorf("bulk values[%d] is nil", i)
+			}
+			rv = rv.Elem()
+		}
+		args := make([]any, len(indexes))
+		for j, idx := range indexes {
+			args[j] = rv.Field(idx).Interface()
+		}
+		conds = append(conds, &InsertCond{Arg: args})
+	}
+	return cols, conds, nil
 }
@@ -2,9 +2,12 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func TestBuildInsert(t *testing.T) {
@@ -33,3 +36,210 @@ func TestBuildInsert(t *testing.T) {
 
 	t.Logf("ins: %d", ins)
 }
+
+func TestInsertBuilder_BulkValues(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	now := time.Date(2025, 12, 20, 10, 0, 0, 0, time.UTC)
+	users := []User{
+		{Id: 1, TenantId: "tenant-1", Name: "Alice", Email: "alice@example.com", CreatedAt: now},
+		{Id: 2, TenantId: "tenant-1", Name: "Bob", Email: "bob@example.com", CreatedAt: now},
+		{Id: 3, TenantId: "tenant-1", Name: "Carol", Email: "carol@example.com", CreatedAt: now},
+	}
+	cols := "INSERT INTO users (id, tenant_id, name, email, created_at, deleted_at) VALUES "
+	row := "(?, ?, ?, ?, ?, ?)"
+
+	// 1文あたり2行に分割され、トランザクション内で実行される
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(cols+row+", "+row)).
+		WithArgs(1, "tenant-1", "Alice", "alice@example.com", now, nil, 2, "tenant-1", "Bob", "bob@example.com", now, nil).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta(cols+row)).
+		WithArgs(3, "tenant-1", "Carol", "carol@example.com", now, nil).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	id, err := InsertFrom("users").BulkValues(users).MaxPlaceholders(12).Exec(ctx, db)
+	if err != nil {
+		t.Fatalf("Insert error: %v", err)
+	}
+	if id != 1 {
+		t.Errorf("ID が想定外です。got=%v, want=%v", id, 1)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("実行されたクエリが想定外です。%v", err)
+	}
+}
+
+func TestInsertBuilder_Exec_Split(t *testing.T) {
+	ctx := context.Background()
+	rows := []*InsertCond{
+		{Arg: []any{1, "Alice"}},
+		{Arg: []any{2, "Bob"}},
+		{Arg: []any{3, "Carol"}},
+	}
+	firstSQL := "INSERT INTO users VALUES (?, ?), (?, ?)"
+	secondSQL := "INSERT INTO users VALUES (?, ?)"
+	execErr := errors.New("deadlock")
+
+	t.Run("異常値: *sqlx.DB では途中で失敗するとロールバックする", func(t *testing.T) {
+		db, mock, cleanup := newMockDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(firstSQL)).WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectExec(regexp.QuoteMeta(secondSQL)).WillReturnError(execErr)
+		mock.ExpectRollback()
+
+		_, err := InsertFrom("users").MaxPlaceholders(4).Rows(rows...).Exec(ctx, db)
+		if !errors.Is(err, execErr) {
+			t.Errorf("エラーが想定外です。got=%v, want=%v", err, execErr)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("実行されたクエリが想定外です。%v", err)
+		}
+	})
+
+	t.Run("異常値: *sqlx.Tx では途中で失敗すると反映済みであることを返す", func(t *testing.T) {
+		db, mock, cleanup := newMockDB(t)
+		defer cleanup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(firstSQL)).WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectExec(regexp.QuoteMeta(secondSQL)).WillReturnError(execErr)
+
+		tx, err := db.Beginx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = InsertFrom("users").MaxPlaceholders(4).Rows(rows...).Exec(ctx, tx)
+		if !errors.Is(err, ErrPartialInsert) || !errors.Is(err, execErr) {
+			t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrPartialInsert)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("実行されたクエリが想定外です。%v", err)
+		}
+	})
+}
+
+func TestInsertBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name      string
+		builder   InsertBuilder
+		wantQuery []string
+		wantArgs  [][]any
+		wantErr   error
+	}{
+		{
+			name: "正常値: 列を指定した複数行",
+			builder: InsertFrom("users").Columns("id", "name").Rows(
+				&InsertCond{Arg: []any{1, "Alice"}},
+				&InsertCond{Arg: []any{2, "Bob"}},
+			),
+			wantQuery: []string{"INSERT INTO users (id, name) VALUES (?, ?), (?, ?)"},
+		},
+		{
+			name: "正常値: 上限と1行の値の数が同じ場合は1行ずつ",
+			builder: InsertFrom("users").MaxPlaceholders(2).Rows(
+				&InsertCond{Arg: []any{1, "Alice"}},
+				&InsertCond{Arg: []any{2, "Bob"}},
+			),
+			wantQuery: []string{"INSERT INTO users VALUES (?, ?)", "INSERT INTO users VALUES (?, ?)"},
+		},
+		{
+			name: "正常値: ON DUPLICATE KEY UPDATE",
+			builder: InsertFrom("users").Columns("id", "name").
+				Values(&InsertCond{Arg: []any{1, "Alice"}}).
+				OnDuplicateKeyUpdate(UpdateCond{Set: "name", Arg: "Alice"}),
+			wantQuery: []string{"INSERT INTO users (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = ?"},
+			wantArgs:  [][]any{{1, "Alice", "Alice"}},
+		},
+		{
+			name: "正常値: すべての列を更新。指定した列を優先する",
+			builder: InsertFrom("users").Columns("id", "name", "email").
+				Values(&InsertCond{Arg: []any{1, "Alice", "alice@example.com"}}).
+				OnDuplicateKeyUpdate(UpdateCond{Set: "email", Arg: "fixed@example.com"}).
+				OnDuplicateKeyUpdateAll(),
+			wantQuery: []string{"INSERT INTO users (id, name, email) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE email = ?, id = VALUES(id), name = VALUES(name)"},
+			wantArgs:  [][]any{{1, "Alice", "alice@example.com", "fixed@example.com"}},
+		},
+		{
+			name: "正常値: 分割した各文に付与する",
+			builder: InsertFrom("users").Columns("id", "name").MaxPlaceholders(5).Rows(
+				&InsertCond{Arg: []any{1, "Alice"}},
+				&InsertCond{Arg: []any{2, "Bob"}},
+				&InsertCond{Arg: []any{3, "Carol"}},
+			).OnDuplicateKeyUpdate(UpdateCond{Set: "updated_by", Arg: "batch"}),
+			wantQuery: []string{
+				"INSERT INTO users (id, name) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE updated_by = ?",
+				"INSERT INTO users (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE updated_by = ?",
+			},
+			wantArgs: [][]any{{1, "Alice", 2, "Bob", "batch"}, {3, "Carol", "batch"}},
+		},
+		{
+			name: "異常値: 上限より1行の値が多い",
+			builder: InsertFrom("users").MaxPlaceholders(1).Rows(
+				&InsertCond{Arg: []any{1, "Alice"}},
+			),
+			wantErr: ErrTooManyPlaceholders,
+		},
+		{
+			name: "異常値: 1行と ON DUPLICATE KEY UPDATE の値が上限より多い",
+			builder: InsertFrom("users").Columns("id", "name").MaxPlaceholders(2).
+				Values(&InsertCond{Arg: []any{1, "Alice"}}).
+				OnDuplicateKeyUpdate(UpdateCond{Set: "name", Arg: "Alice"}),
+			wantErr: ErrTooManyPlaceholders,
+		},
+		{
+			name:    "異常値: 列を指定せずにすべての列を更新",
+			builder: InsertFrom("users").Values(&InsertCond{Arg: []any{1, "Alice"}}).OnDuplicateKeyUpdateAll(),
+			wantErr: ErrUpdateAllNeedsColumns,
+		},
+		{
+			name:    "異常値: 値なし",
+			builder: InsertFrom("users").Rows(),
+			wantErr: ErrValuesRequired,
+		},
+		{
+			name: "異常値: 行の値の数が異なる",
+			builder: InsertFrom("users").Rows(
+				&InsertCond{Arg: []any{1, "Alice"}},
+				&InsertCond{Arg: []any{2}},
+			),
+			wantErr: ErrRowLengthMismatch,
+		},
+		{
+			name:    "異常値: 列と値の数が異なる",
+			builder: InsertFrom("users").Columns("id").Values(&InsertCond{Arg: []any{1, "Alice"}}),
+			wantErr: ErrColumnsMismatch,
+		},
+		{
+			name:    "異常値: 構造体のスライスでない",
+			builder: InsertFrom("users").BulkValues([]int{1, 2}),
+			wantErr: ErrBulkValuesNotSlice,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmts, err := tt.builder.build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			var got []string
+			var gotArgs [][]any
+			for _, s := range stmts {
+				got = append(got, s.query)
+				gotArgs = append(gotArgs, s.args)
+			}
+			if !reflect.DeepEqual(got, tt.wantQuery) {
+				t.Errorf("クエリが想定外です。got=%v, want=%v", got, tt.wantQuery)
+			}
+			if tt.wantArgs != nil && !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("引数が想定外です。got=%v, want=%v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package mysql
+
+import (
+	"context"
+	"valley-pkg/logging"
+)
+
+// logQuery は実行するクエリを ctx の Logger に出力する
+// クエリビルダーはコンストラクタを持たないため、Logger は logging.NewContext で ctx に設定する。設定しない場合は出力しない。
+func logQuery(ctx context.Context, q string, args []any) {
+	logging.FromContext(ctx, logging.Nop()).Debug("mysql query", logging.F("query", q), logging.F("args", args))
+}
@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"valley-pkg/metrics"
+)
+
+// observeQuery はクエリの実行時間とエラーを ctx の Meter に記録する
+// Logger と同様に、Meter は metrics.NewContext で ctx に設定する。設定しない場合は記録しない。
+// 該当する行が無い（sql.ErrNoRows）場合はエラーとして数えない。
+func observeQuery(ctx context.Context, operation string, start time.Time, err error) {
+	m := metrics.FromContext(ctx)
+	label := metrics.L("operation", operation)
+	m.Histogram("mysql_query_duration_seconds", "MySQL クエリの実行時間（秒）", nil).Observe(time.Since(start).Seconds(), label)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.Counter("mysql_query_errors_total", "MySQL クエリのエラー数").Add(1, label)
+	}
+}
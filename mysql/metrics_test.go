@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"regexp"
+	"testing"
+	"valley-pkg/metrics"
+)
+
+func TestObserveQuery(t *testing.T) {
+	reg := metrics.NewRegistry()
+	ctx := metrics.NewContext(context.Background(), reg)
+
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+
+	expectedSQL := "DELETE FROM users WHERE tenant_id = ?"
+	mock.ExpectExec(regexp.QuoteMeta(expectedSQL)).
+		WithArgs("tenant-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(expectedSQL)).
+		WithArgs("tenant-2").
+		WillReturnError(errors.New("boom"))
+
+	if _, err := DeleteFrom("users").Where(Eq("tenant_id", "tenant-1")).Exec(ctx, db); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if _, err := DeleteFrom("users").Where(Eq("tenant_id", "tenant-2")).Exec(ctx, db); err == nil {
+		t.Fatal("エラーが返されませんでした。")
+	}
+
+	got := make(map[string]metrics.Series)
+	for _, f := range reg.Gather() {
+		got[f.Name] = f.Series[0]
+	}
+	label := metrics.L("operation", "delete")
+	if s := got["mysql_query_duration_seconds"]; s.Count != 2 || s.Labels[0] != label {
+		t.Errorf("実行時間が想定外です。got=%+v", s)
+	}
+	if s := got["mysql_query_errors_total"]; s.Value != 1 || s.Labels[0] != label {
+		t.Errorf("エラー数が想定外です。got=%+v", s)
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jmoiron/sqlx"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -80,15 +80,9 @@ func (b selectBuilder[S]) buildWithWhere() (string, []any, error) {
 		return "", nil, err
 	}
 
-	fmt.Printf("sb:  %s\n", sb.String())
-
 	sb.WriteString(" WHERE ")
-	fmt.Printf("sb:  %s\n", sb.String())
-
 	sb.WriteString(b.where.GetSQL())
 
-	fmt.Printf("sb:  %s\n", sb.String())
-
 	b.buildTail(sb)
 	return sb.String(), b.where.GwtArgs(), nil
 }
@@ -197,7 +191,14 @@ func (b selectBuilder[S]) columnsOf() ([]string, error) {
 // columnsFromDBTags は、構造体フィールドから「db」タグを持つ列名を抽出します。一意性を保証し、指定されたフィールドはスキップします。
 // 列名のスライスを返します。重複タグが存在する場合やその他の問題が発生した場合はエラーを返します。
 func columnsFromDBTags(t reflect.Type) ([]string, error) {
+	cols, _, err := fieldsFromDBTags(t)
+	return cols, err
+}
+
+// fieldsFromDBTags は columnsFromDBTags と同じ列名と、対応するフィールドのインデックスを返します。
+func fieldsFromDBTags(t reflect.Type) ([]string, []int, error) {
 	var cols []string
+	var indexes []int
 	seen := map[string]struct{}{}
 
 	for i := 0; i < t.NumField(); i++ {
@@ -215,12 +216,13 @@ func columnsFromDBTags(t reflect.Type) ([]string, error) {
 			continue
 		}
 		if _, ok := seen[name]; ok {
-			return nil, ErrDuplicateDBTag
+			return nil, nil, ErrDuplicateDBTag
 		}
 		seen[name] = struct{}{}
 		cols = append(cols, name)
+		indexes = append(indexes, i)
 	}
-	return cols, nil
+	return cols, indexes, nil
 }
 
 // ---- 共通：identifier の超最低限チェック（任意） ----
@@ -317,62 +319,78 @@ func (s SelectWithoutWhere[S]) Offset(offset int) SelectWithoutWhere[S] {
 }
 
 // FetchAll は、構築されたクエリとバインディングに基づいて SQL SELECT クエリを実行し、一致するすべての行をスライスとして返します。
-func (s SelectWithWhere[S]) FetchAll(ctx context.Context, db *sqlx.DB) ([]S, error) {
+func (s SelectWithWhere[S]) FetchAll(ctx context.Context, db Queryer) ([]S, error) {
 	q, args, err := s.builder.buildWithWhere()
 	if err != nil {
 		return nil, err
 	}
 	q = db.Rebind(q)
+	logQuery(ctx, q, args)
 
 	var dest []S
-	if err := db.SelectContext(ctx, &dest, q, args...); err != nil {
+	start := time.Now()
+	err = db.SelectContext(ctx, &dest, q, args...)
+	observeQuery(ctx, "select", start, err)
+	if err != nil {
 		return nil, err
 	}
 	return dest, nil
 }
 
 // FetchAll は構築された SQL SELECT クエリを実行し、すべての行を S 型のスライスとして取得します。
-func (s SelectWithoutWhere[S]) FetchAll(ctx context.Context, db *sqlx.DB) ([]S, error) {
+func (s SelectWithoutWhere[S]) FetchAll(ctx context.Context, db Queryer) ([]S, error) {
 	q, args, err := s.builder.buildWithoutWhere()
 	if err != nil {
 		return nil, err
 	}
 	q = db.Rebind(q)
+	logQuery(ctx, q, args)
 
 	var dest []S
-	if err := db.SelectContext(ctx, &dest, q, args...); err != nil {
+	start := time.Now()
+	err = db.SelectContext(ctx, &dest, q, args...)
+	observeQuery(ctx, "select", start, err)
+	if err != nil {
 		return nil, err
 	}
 	return dest, nil
 }
 
 // Fetch は SQL SELECT クエリを実行し、構築されたクエリとバインディングに基づいて結果の単一行を取得します。
-func (s SelectWithWhere[S]) Fetch(ctx context.Context, db *sqlx.DB) (S, error) {
+func (s SelectWithWhere[S]) Fetch(ctx context.Context, db Queryer) (S, error) {
 	q, args, err := s.builder.buildWithWhere()
 	if err != nil {
 		var zero S
 		return zero, err
 	}
 	q = db.Rebind(q)
+	logQuery(ctx, q, args)
 
 	var dest S
-	if err := db.GetContext(ctx, &dest, q, args...); err != nil {
+	start := time.Now()
+	err = db.GetContext(ctx, &dest, q, args...)
+	observeQuery(ctx, "select", start, err)
+	if err != nil {
 		return dest, err
 	}
 	return dest, nil
 }
 
 // Fetch は SQL SELECT クエリを実行し、構築されたクエリとバインディングに基づいて結果の単一行を取得します。
-func (s SelectWithoutWhere[S]) Fetch(ctx context.Context, db *sqlx.DB) (S, error) {
+func (s SelectWithoutWhere[S]) Fetch(ctx context.Context, db Queryer) (S, error) {
 	q, args, err := s.builder.buildWithoutWhere()
 	if err != nil {
 		var zero S
 		return zero, err
 	}
 	q = db.Rebind(q)
+	logQuery(ctx, q, args)
 
 	var dest S
-	if err := db.GetContext(ctx, &dest, q, args...); err != nil {
+	start := time.Now()
+	err = db.GetContext(ctx, &dest, q, args...)
+	observeQuery(ctx, "select", start, err)
+	if err != nil {
 		return dest, err
 	}
 	return dest, nil
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// Queryer は SELECT を実行する接続。*sqlx.DB と *sqlx.Tx が満たす
+type Queryer interface {
+	Rebind(query string) string
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// Execer は INSERT / UPDATE / DELETE を実行する接続。*sqlx.DB と *sqlx.Tx が満たす
+type Execer interface {
+	Rebind(query string) string
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var (
+	_ Queryer = (*sqlx.DB)(nil)
+	_ Queryer = (*sqlx.Tx)(nil)
+	_ Execer  = (*sqlx.DB)(nil)
+	_ Execer  = (*sqlx.Tx)(nil)
+)
+
+// WithTransaction はトランザクション内で fn を実行する
+// fn がエラーを返すか panic した場合はロールバックし、それ以外はコミットする。
+// fn 内のクエリビルダーには db ではなく tx を渡すこと。
+func WithTransaction(ctx context.Context, db *sqlx.DB, fn func(tx *sqlx.Tx) error) (err error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
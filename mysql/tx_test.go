@@ -0,0 +1,94 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"regexp"
+	"testing"
+)
+
+func TestWithTransaction(t *testing.T) {
+	errFn := errors.New("fn failed")
+	deleteSQL := "DELETE FROM users WHERE tenant_id = ?"
+	updateSQL := "UPDATE users SET name = ? WHERE id = ?"
+
+	tests := []struct {
+		name    string
+		expect  func(mock sqlmock.Sqlmock)
+		fn      func(ctx context.Context, tx *sqlx.Tx) error
+		wantErr error
+	}{
+		{
+			name: "正常値: コミット",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(deleteSQL)).WithArgs("tenant-1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(updateSQL)).WithArgs("Alice", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			fn: func(ctx context.Context, tx *sqlx.Tx) error {
+				if _, err := DeleteFrom("users").Where(Eq("tenant_id", "tenant-1")).Exec(ctx, tx); err != nil {
+					return err
+				}
+				_, err := UpdateFrom[User]("users").Set(UpdateCond{Set: "name", Arg: "Alice"}).Where(Eq("id", 1)).Exec(ctx, tx)
+				return err
+			},
+		},
+		{
+			name: "異常値: エラーでロールバック",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(deleteSQL)).WithArgs("tenant-1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			fn: func(ctx context.Context, tx *sqlx.Tx) error {
+				if _, err := DeleteFrom("users").Where(Eq("tenant_id", "tenant-1")).Exec(ctx, tx); err != nil {
+					return err
+				}
+				return errFn
+			},
+			wantErr: errFn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			db, mock, cleanup := newMockDB(t)
+			defer cleanup()
+			tt.expect(mock)
+
+			err := WithTransaction(ctx, db, func(tx *sqlx.Tx) error {
+				return tt.fn(ctx, tx)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("実行されたクエリが想定外です。%v", err)
+			}
+		})
+	}
+}
+
+func TestWithTransaction_Panic(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic が伝播しませんでした。")
+			}
+		}()
+		_ = WithTransaction(context.Background(), db, func(tx *sqlx.Tx) error {
+			panic("boom")
+		})
+	}()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("ロールバックされていません。%v", err)
+	}
+}
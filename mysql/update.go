@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jmoiron/sqlx"
 	"strings"
+	"time"
 )
 
 var ErrSetRequired = errors.New("update requires set")
@@ -82,17 +82,18 @@ func (u UpdateWithoutWhere[S]) Where(c *WhereCond) UpdateWithWhere[S] {
 
 // Exec は、指定されたデータベース接続とコンテキストを使用して、構築された SQL UPDATE 文を実行します。
 // 操作が成功した場合、影響を受けた行数を返します。失敗した場合はエラーを返します。
-func (u UpdateWithWhere[S]) Exec(ctx context.Context, db *sqlx.DB) (int64, error) {
+func (u UpdateWithWhere[S]) Exec(ctx context.Context, db Execer) (int64, error) {
 	q, args, err := u.builder.build()
 	if err != nil {
 		return 0, err
 	}
 	q = db.Rebind(q)
 
-	fmt.Printf("update query: %s\n", q)
-	fmt.Printf("update args: %#v\n", args)
+	logQuery(ctx, q, args)
 
+	start := time.Now()
 	res, err := db.ExecContext(ctx, q, args...)
+	observeQuery(ctx, "update", start, err)
 	if err != nil {
 		return 0, err
 	}
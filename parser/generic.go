@@ -0,0 +1,28 @@
+package parser
+
+import "reflect"
+
+// Marshal は型を指定してパーサーでbyteに変換する
+func Marshal[T any](p Parser, v T) ([]byte, error) {
+	return p.Marshal(v)
+}
+
+// Unmarshal はパーサーでbyteを型 T に変換して返す
+// T がポインタ型（protobuf のメッセージなど）の場合は、指す先を新しく確保してから変換する。
+func Unmarshal[T any](p Parser, b []byte) (T, error) {
+	var v T
+	if t := reflect.TypeFor[T](); t.Kind() == reflect.Ptr {
+		v = reflect.New(t.Elem()).Interface().(T)
+		if err := p.Unmarshal(b, v); err != nil {
+			var zero T
+			return zero, err
+		}
+		return v, nil
+	}
+
+	if err := p.Unmarshal(b, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"valley-pkg/parser/pb_go"
+)
+
+func TestUnmarshal(t *testing.T) {
+	type testStruct struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	t.Run("正常系: 構造体", func(t *testing.T) {
+		got, err := Unmarshal[testStruct](&JSONParser{}, []byte(`{"name":"田中太郎","age":30}`))
+		assert.NoError(t, err)
+		assert.Equal(t, testStruct{Name: "田中太郎", Age: 30}, got)
+	})
+
+	t.Run("正常系: 構造体のポインタ", func(t *testing.T) {
+		got, err := Unmarshal[*testStruct](&JSONParser{}, []byte(`{"name":"田中太郎"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, &testStruct{Name: "田中太郎"}, got)
+	})
+
+	t.Run("正常系: map", func(t *testing.T) {
+		got, err := Unmarshal[map[string]int](&JSONParser{}, []byte(`{"a":1}`))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1}, got)
+	})
+
+	t.Run("正常系: protobuf", func(t *testing.T) {
+		want := &pb_go.CommonRequestParam{PlayerId: "player123"}
+		b, err := Marshal(&PbParser{}, want)
+		assert.NoError(t, err)
+
+		got, err := Unmarshal[*pb_go.CommonRequestParam](&PbParser{}, b)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(want, got))
+	})
+
+	t.Run("異常系: 不正なデータはゼロ値を返す", func(t *testing.T) {
+		got, err := Unmarshal[*testStruct](&JSONParser{}, []byte(`{"name":`))
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
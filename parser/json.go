@@ -1,16 +1,202 @@
 package parser
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ErrFieldCase はCaseSensitive指定時に、大文字小文字だけが異なるフィールド名を受け取った場合のエラー
+var ErrFieldCase = fmt.Errorf("json field name case mismatch")
 
 // JSONParser はjson用のパーサー
-type JSONParser struct{}
+// ゼロ値は encoding/json の標準の動作となる。JSONの実装はビルドタグで切り替えられる（JSONBackend を参照）。
+type JSONParser struct {
+	// DisallowUnknownFields は変換先に存在しないフィールドをエラーにする
+	DisallowUnknownFields bool
+	// UseNumber は interface{} への数値を float64 ではなく json.Number で受け取り、int64 の精度を保つ
+	UseNumber bool
+	// CaseSensitive はフィールド名の大文字小文字を区別し、大文字小文字だけが異なるフィールド名を ErrFieldCase とする
+	CaseSensitive bool
+}
 
 // Marshal は構造体をbyteに変換する
 func (p *JSONParser) Marshal(i any) ([]byte, error) {
-	return json.Marshal(i)
+	return jsonMarshal(i)
 }
 
 // Unmarshal は構造体に変換する
+// 変換先が Validator を実装している場合や `validate` タグを持つ場合は、変換後に検証する。
 func (p *JSONParser) Unmarshal(b []byte, i any) error {
-	return json.Unmarshal(b, &i)
+	if p.CaseSensitive {
+		if err := checkFieldCase(b, reflect.TypeOf(i)); err != nil {
+			return err
+		}
+	}
+
+	if !p.DisallowUnknownFields && !p.UseNumber {
+		if err := jsonUnmarshal(b, &i); err != nil {
+			return err
+		}
+		return validate(i)
+	}
+
+	dec := p.newJSONDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&i); err != nil {
+		return err
+	}
+	// json.Unmarshal と同様に、値の後ろに余分なデータがある場合はエラーとする
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level value")
+	}
+	return validate(i)
+}
+
+// NewDecoder はJSONを逐次読み取るデコーダーを返す
+// 改行区切りのJSON（NDJSON）や連続したJSONを1つずつ読み取れる。
+func (p *JSONParser) NewDecoder(r io.Reader) Decoder {
+	if p.CaseSensitive {
+		return &jsonDecoder{p: p, dec: jsonNewDecoder(r)}
+	}
+	return p.newJSONDecoder(r)
+}
+
+// NewEncoder はJSONを逐次書き込むエンコーダーを返す
+// 値ごとに改行を付加するため、NDJSONとして書き込まれる。
+func (p *JSONParser) NewEncoder(w io.Writer) Encoder {
+	return jsonNewEncoder(w)
+}
+
+// jsonStreamDecoder は各JSONの実装のデコーダーが共通して持つメソッド
+type jsonStreamDecoder interface {
+	Decode(any) error
+	Token() (json.Token, error)
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// newJSONDecoder はオプションを反映したデコーダーを返す
+func (p *JSONParser) newJSONDecoder(r io.Reader) jsonStreamDecoder {
+	dec := jsonNewDecoder(r)
+	if p.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if p.UseNumber {
+		dec.UseNumber()
+	}
+	return dec
+}
+
+// jsonDecoder はCaseSensitive指定時のデコーダー
+// フィールド名を確認するため、値を1つずつ読み取ってから Unmarshal する。
+type jsonDecoder struct {
+	p   *JSONParser
+	dec jsonStreamDecoder
+}
+
+// Decode は次の値を読み取る。読み取る値が無い場合は io.EOF を返す。
+func (d *jsonDecoder) Decode(v any) error {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		return err
+	}
+	return d.p.Unmarshal(raw, v)
+}
+
+// checkFieldCase は変換先の構造体のフィールド名と、大文字小文字だけが異なるキーが無いかを再帰的に確認する
+// JSONの形が変換先と合わない場合の検出は json.Unmarshal に任せ、ここではエラーにしない。
+func checkFieldCase(b []byte, t reflect.Type) error {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface) {
+		if t.Kind() == reflect.Interface {
+			return nil
+		}
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if jsonUnmarshal(b, &obj) != nil {
+			return nil
+		}
+		fields := jsonFields(t)
+		for key, raw := range obj {
+			if ft, ok := fields[key]; ok {
+				if err := checkFieldCase(raw, ft); err != nil {
+					return fmt.Errorf("%s.%w", key, err)
+				}
+				continue
+			}
+			for name := range fields {
+				if strings.EqualFold(name, key) {
+					return fmt.Errorf("%q must be %q: %w", key, name, ErrFieldCase)
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var arr []json.RawMessage
+		if jsonUnmarshal(b, &arr) != nil {
+			return nil
+		}
+		for _, raw := range arr {
+			if err := checkFieldCase(raw, t.Elem()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if jsonUnmarshal(b, &obj) != nil {
+			return nil
+		}
+		for _, raw := range obj {
+			if err := checkFieldCase(raw, t.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFields は構造体のJSONのフィールド名と型の対応を返す
+// タグの無い埋め込み構造体のフィールドは、encoding/json と同様に親のフィールドとして扱う。
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, typ := range jsonFields(ft) {
+					// 親のフィールドが優先される
+					if _, ok := fields[n]; !ok {
+						fields[n] = typ
+					}
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = sf.Type
+	}
+	return fields
 }
@@ -0,0 +1,28 @@
+//go:build !gojson
+
+package parser
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONBackend はJSONParserが使用しているJSONの実装
+// `-tags gojson` でビルドした場合は github.com/goccy/go-json を使用する。
+const JSONBackend = "encoding/json"
+
+func jsonMarshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func jsonUnmarshal(b []byte, v any) error {
+	return json.Unmarshal(b, v)
+}
+
+func jsonNewDecoder(r io.Reader) jsonStreamDecoder {
+	return json.NewDecoder(r)
+}
+
+func jsonNewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
@@ -0,0 +1,29 @@
+//go:build gojson
+
+package parser
+
+import (
+	"io"
+
+	"github.com/goccy/go-json"
+)
+
+// JSONBackend はJSONParserが使用しているJSONの実装
+// encoding/json と互換のAPIを持つ github.com/goccy/go-json を使用する。
+const JSONBackend = "github.com/goccy/go-json"
+
+func jsonMarshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func jsonUnmarshal(b []byte, v any) error {
+	return json.Unmarshal(b, v)
+}
+
+func jsonNewDecoder(r io.Reader) jsonStreamDecoder {
+	return json.NewDecoder(r)
+}
+
+func jsonNewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	stdjson "encoding/json"
+	"testing"
+
+	gojson "github.com/goccy/go-json"
+)
+
+type benchItem struct {
+	ID    int64    `json:"id"`
+	Name  string   `json:"name"`
+	Score float64  `json:"score"`
+	Tags  []string `json:"tags"`
+}
+
+type benchMessage struct {
+	PlayerID string      `json:"player_id"`
+	Kind     int         `json:"kind"`
+	Items    []benchItem `json:"items"`
+}
+
+// newBenchMessage はメッセージ処理を想定したベンチマーク用のデータを返す
+func newBenchMessage() benchMessage {
+	m := benchMessage{PlayerID: "player123", Kind: 1}
+	for i := 0; i < 50; i++ {
+		m.Items = append(m.Items, benchItem{ID: int64(i), Name: "item", Score: float64(i) * 1.5, Tags: []string{"a", "b", "c"}})
+	}
+	return m
+}
+
+// BenchmarkJSONBackend_Marshal は encoding/json と go-json のMarshalを比較する
+func BenchmarkJSONBackend_Marshal(b *testing.B) {
+	m := newBenchMessage()
+	backends := []struct {
+		name    string
+		marshal func(any) ([]byte, error)
+	}{
+		{name: "encoding/json", marshal: stdjson.Marshal},
+		{name: "go-json", marshal: gojson.Marshal},
+	}
+
+	for _, bb := range backends {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := bb.marshal(&m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkJSONBackend_Unmarshal は encoding/json と go-json のUnmarshalを比較する
+func BenchmarkJSONBackend_Unmarshal(b *testing.B) {
+	data, err := stdjson.Marshal(newBenchMessage())
+	if err != nil {
+		b.Fatal(err)
+	}
+	backends := []struct {
+		name      string
+		unmarshal func([]byte, any) error
+	}{
+		{name: "encoding/json", unmarshal: stdjson.Unmarshal},
+		{name: "go-json", unmarshal: gojson.Unmarshal},
+	}
+
+	for _, bb := range backends {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var m benchMessage
+				if err := bb.unmarshal(data, &m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkJSONParser はビルドタグで選択された実装でのJSONParserの性能を測る
+func BenchmarkJSONParser(b *testing.B) {
+	m := newBenchMessage()
+	p := &JSONParser{}
+	data, err := p.Marshal(&m)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Marshal/"+JSONBackend, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := p.Marshal(&m); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Unmarshal/"+JSONBackend, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var got benchMessage
+			if err := p.Unmarshal(data, &got); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
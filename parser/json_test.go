@@ -2,7 +2,9 @@ package parser
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -125,3 +127,112 @@ func TestJSONParser_Unmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONParser_Options(t *testing.T) {
+	type inner struct {
+		ID int64 `json:"id"`
+	}
+	type testStruct struct {
+		Name  string  `json:"name"`
+		Items []inner `json:"items"`
+	}
+
+	tests := []struct {
+		name    string
+		parser  *JSONParser
+		input   string
+		target  any
+		want    any
+		wantErr error
+	}{
+		{
+			name:   "正常系: デフォルトは未知のフィールドを無視",
+			parser: &JSONParser{},
+			input:  `{"name":"a","nmae":"b"}`,
+			target: &testStruct{},
+			want:   &testStruct{Name: "a"},
+		},
+		{
+			name:    "異常系: DisallowUnknownFields",
+			parser:  &JSONParser{DisallowUnknownFields: true},
+			input:   `{"name":"a","nmae":"b"}`,
+			target:  &testStruct{},
+			wantErr: errAny,
+		},
+		{
+			name:   "正常系: UseNumber で int64 の精度を保つ",
+			parser: &JSONParser{UseNumber: true},
+			input:  `{"id":9007199254740993}`,
+			target: &map[string]any{},
+			want:   &map[string]any{"id": json.Number("9007199254740993")},
+		},
+		{
+			name:   "正常系: デフォルトは大文字小文字を区別しない",
+			parser: &JSONParser{},
+			input:  `{"NAME":"a"}`,
+			target: &testStruct{},
+			want:   &testStruct{Name: "a"},
+		},
+		{
+			name:    "異常系: CaseSensitive",
+			parser:  &JSONParser{CaseSensitive: true},
+			input:   `{"NAME":"a"}`,
+			target:  &testStruct{},
+			wantErr: ErrFieldCase,
+		},
+		{
+			name:    "異常系: CaseSensitive はネストしたフィールドも確認する",
+			parser:  &JSONParser{CaseSensitive: true},
+			input:   `{"name":"a","items":[{"id":1},{"Id":2}]}`,
+			target:  &testStruct{},
+			wantErr: ErrFieldCase,
+		},
+		{
+			name:   "正常系: CaseSensitive で一致",
+			parser: &JSONParser{CaseSensitive: true, DisallowUnknownFields: true},
+			input:  `{"name":"a","items":[{"id":1}]}`,
+			target: &testStruct{},
+			want:   &testStruct{Name: "a", Items: []inner{{ID: 1}}},
+		},
+		{
+			name:    "異常系: 値の後ろに余分なデータ",
+			parser:  &JSONParser{UseNumber: true},
+			input:   `{"name":"a"} {}`,
+			target:  &testStruct{},
+			wantErr: errAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parser.Unmarshal([]byte(tt.input), tt.target)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if tt.wantErr != errAny {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.target)
+		})
+	}
+}
+
+// errAny はエラーの種類を問わないことを表す
+var errAny = errors.New("any error")
+
+func TestJSONParser_CaseSensitiveDecoder(t *testing.T) {
+	type testStruct struct {
+		Name string `json:"name"`
+	}
+
+	dec := (&JSONParser{CaseSensitive: true}).NewDecoder(strings.NewReader("{\"name\":\"a\"}\n{\"Name\":\"b\"}\n"))
+
+	var v testStruct
+	assert.NoError(t, dec.Decode(&v))
+	assert.Equal(t, "a", v.Name)
+	assert.ErrorIs(t, dec.Decode(&v), ErrFieldCase)
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MsgpackParser はMessagePack用のパーサー
+// 構造体のフィールド名は `msgpack` タグで指定し、タグが無い場合はフィールド名をそのまま使用する。
+type MsgpackParser struct{}
+
+// Marshal は構造体をbyteに変換する
+func (p *MsgpackParser) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal は構造体に変換する
+// 変換先が Validator を実装している場合や `validate` タグを持つ場合は、変換後に検証する。
+func (p *MsgpackParser) Unmarshal(b []byte, v any) error {
+	if err := msgpack.Unmarshal(b, v); err != nil {
+		return err
+	}
+	return validate(v)
+}
+
+// NewDecoder はMessagePackを逐次読み取るデコーダーを返す
+func (p *MsgpackParser) NewDecoder(r io.Reader) Decoder {
+	return msgpack.NewDecoder(r)
+}
+
+// NewEncoder はMessagePackを逐次書き込むエンコーダーを返す
+func (p *MsgpackParser) NewEncoder(w io.Writer) Encoder {
+	return msgpack.NewEncoder(w)
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgpackParser(t *testing.T) {
+	type testStruct struct {
+		Name string `msgpack:"name"`
+		Age  int    `msgpack:"age"`
+	}
+
+	tests := []struct {
+		name    string
+		input   any
+		target  any
+		want    any
+		wantErr bool
+	}{
+		{
+			name:   "正常系: 構造体の相互変換",
+			input:  testStruct{Name: "田中太郎", Age: 30},
+			target: &testStruct{},
+			want:   &testStruct{Name: "田中太郎", Age: 30},
+		},
+		{
+			name:   "正常系: mapから構造体への変換",
+			input:  map[string]any{"name": "山田花子", "age": 25},
+			target: &testStruct{},
+			want:   &testStruct{Name: "山田花子", Age: 25},
+		},
+		{
+			name:    "異常系: 型が不一致",
+			input:   map[string]any{"name": 1},
+			target:  &testStruct{},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 変換できない値",
+			input:   func() {},
+			wantErr: true,
+		},
+	}
+
+	parser := &MsgpackParser{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := parser.Marshal(tt.input)
+			if err == nil {
+				err = parser.Unmarshal(b, tt.target)
+			}
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.target)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"io"
 )
 
 // ErrTypeAssert はデータの型がおかしい場合のエラー
@@ -12,3 +13,21 @@ type Parser interface {
 	Marshal(any) ([]byte, error)
 	Unmarshal([]byte, any) error
 }
+
+// Decoder はストリームから値を1つずつ読み取るデコーダー用のインターフェース
+// 読み取る値が無くなった場合は io.EOF を返す。
+type Decoder interface {
+	Decode(any) error
+}
+
+// Encoder はストリームへ値を1つずつ書き込むエンコーダー用のインターフェース
+type Encoder interface {
+	Encode(any) error
+}
+
+// StreamParser ストリームの逐次処理に対応したパーサー用のインターフェース
+type StreamParser interface {
+	Parser
+	NewDecoder(io.Reader) Decoder
+	NewEncoder(io.Writer) Encoder
+}
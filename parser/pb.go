@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"bufio"
 	"fmt"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
+	"io"
 )
 
 // PbParser はprotobuf用のパーサー
@@ -18,10 +22,97 @@ func (p *PbParser) Marshal(v any) ([]byte, error) {
 }
 
 // Unmarshal byte配列を構造体に変換
+// 変換先が Validator を実装している場合は、変換後に検証する。
 func (p *PbParser) Unmarshal(data []byte, v any) error {
 	m, ok := v.(proto.Message)
 	if !ok {
 		return fmt.Errorf("PbParser.Unmarshal: value does not implement proto.Message: %T", v)
 	}
-	return proto.Unmarshal(data, m)
+	if err := proto.Unmarshal(data, m); err != nil {
+		return err
+	}
+	// 生成コードの構造体は `validate` タグを持たないので、Validator のみ確認する
+	return callValidator(m)
+}
+
+// MarshalDelimited はメッセージの長さ(varint)とメッセージを b の末尾に追加したbyte配列を返す
+// 追加した結果を続けて書き込むことで、複数のメッセージを1つのファイルやストリームに書き込める。
+// NewEncoder と同じ形式のため、NewDecoder でも読み取れる。
+func (p *PbParser) MarshalDelimited(b []byte, v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("PbParser.MarshalDelimited: value does not implement proto.Message: %T", v)
+	}
+	b = protowire.AppendVarint(b, uint64(proto.Size(m)))
+	return proto.MarshalOptions{}.MarshalAppend(b, m)
+}
+
+// UnmarshalDelimited は b の先頭から長さ(varint)付きのメッセージを1つ読み取り、読み取ったバイト数を返す
+// 返されたバイト数だけ b を進めて繰り返し呼ぶことで、連続したメッセージを読み取れる。
+// b が空の場合は io.EOF、データが途中で切れている場合は io.ErrUnexpectedEOF を返す。
+func (p *PbParser) UnmarshalDelimited(b []byte, v any) (int, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return 0, fmt.Errorf("PbParser.UnmarshalDelimited: value does not implement proto.Message: %T", v)
+	}
+
+	size, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		if len(b) == 0 {
+			return 0, io.EOF
+		}
+		return 0, fmt.Errorf("PbParser.UnmarshalDelimited: invalid length: %w", protowire.ParseError(n))
+	}
+	if uint64(len(b)-n) < size {
+		return 0, fmt.Errorf("PbParser.UnmarshalDelimited: need %d bytes, got %d: %w", size, len(b)-n, io.ErrUnexpectedEOF)
+	}
+
+	end := n + int(size)
+	if err := proto.Unmarshal(b[n:end], m); err != nil {
+		return 0, err
+	}
+	return end, callValidator(m)
+}
+
+// NewDecoder は長さ(varint)を先頭に付加したprotobufを逐次読み取るデコーダーを返す
+func (p *PbParser) NewDecoder(r io.Reader) Decoder {
+	br, ok := r.(protodelim.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &pbDecoder{r: br}
+}
+
+// NewEncoder はprotobufの先頭に長さ(varint)を付加して逐次書き込むエンコーダーを返す
+func (p *PbParser) NewEncoder(w io.Writer) Encoder {
+	return &pbEncoder{w: w}
+}
+
+// pbDecoder は長さ区切りのprotobufのデコーダー
+type pbDecoder struct {
+	r protodelim.Reader
+}
+
+// Decode は次のメッセージを読み取る。読み取るメッセージが無い場合は io.EOF を返す。
+func (d *pbDecoder) Decode(v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("PbParser.Decode: value does not implement proto.Message: %T", v)
+	}
+	return protodelim.UnmarshalFrom(d.r, m)
+}
+
+// pbEncoder は長さ区切りのprotobufのエンコーダー
+type pbEncoder struct {
+	w io.Writer
+}
+
+// Encode はメッセージを書き込む
+func (e *pbEncoder) Encode(v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("PbParser.Encode: value does not implement proto.Message: %T", v)
+	}
+	_, err := protodelim.MarshalTo(e.w, m)
+	return err
 }
@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"bytes"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/protobuf/proto"
+	"io"
 	"testing"
 	"valley-pkg/parser/pb_go"
 )
@@ -61,3 +63,48 @@ func TestPbParser_Marshal(t *testing.T) {
 		})
 	}
 }
+
+func TestPbParser_Delimited(t *testing.T) {
+	inputs := []*pb_go.CommonRequestParam{
+		{PlayerId: "player123", PlatformUserId: "platform456"},
+		{},
+		{PlayerId: "player789"},
+	}
+
+	parser := &PbParser{}
+	var b []byte
+	for _, in := range inputs {
+		var err error
+		b, err = parser.MarshalDelimited(b, in)
+		assert.NoError(t, err)
+	}
+
+	// NewDecoder と同じ形式で読み取れる
+	dec := parser.NewDecoder(bytes.NewReader(b))
+	for _, want := range inputs {
+		got := &pb_go.CommonRequestParam{}
+		assert.NoError(t, dec.Decode(got))
+		assert.True(t, proto.Equal(want, got))
+	}
+
+	rest := b
+	for _, want := range inputs {
+		got := &pb_go.CommonRequestParam{}
+		n, err := parser.UnmarshalDelimited(rest, got)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(want, got))
+		rest = rest[n:]
+	}
+	_, err := parser.UnmarshalDelimited(rest, &pb_go.CommonRequestParam{})
+	assert.ErrorIs(t, err, io.EOF)
+
+	// 途中で切れている
+	_, err = parser.UnmarshalDelimited(b[:3], &pb_go.CommonRequestParam{})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// proto.Message ではない
+	_, err = parser.MarshalDelimited(nil, "not proto")
+	assert.Error(t, err)
+	_, err = parser.UnmarshalDelimited(b, "not proto")
+	assert.Error(t, err)
+}
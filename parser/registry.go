@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+)
+
+// 通信データのヘッダーに書き込むパーサーの識別子
+const (
+	// IDJSON はJSONParserの識別子
+	IDJSON byte = 1
+	// IDProtobuf はPbParserの識別子
+	IDProtobuf byte = 2
+	// IDMsgpack はMsgpackParserの識別子
+	IDMsgpack byte = 3
+	// IDYAML はYAMLParserの識別子
+	IDYAML byte = 4
+)
+
+// ErrUnregistered は識別子に対応するパーサーが登録されていない場合のエラー
+var ErrUnregistered = fmt.Errorf("parser is not registered")
+
+// Factory はパーサーを生成する関数
+type Factory func() Parser
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[byte]Factory{}
+)
+
+func init() {
+	Register(IDJSON, func() Parser { return &JSONParser{} })
+	Register(IDProtobuf, func() Parser { return &PbParser{} })
+	Register(IDMsgpack, func() Parser { return &MsgpackParser{} })
+	Register(IDYAML, func() Parser { return &YAMLParser{} })
+}
+
+// Register は識別子にパーサーの生成関数を登録する
+// 同じ識別子の二重登録や nil の登録はプログラムの誤りなので panic する。init で呼び出すことを想定している。
+func Register(id byte, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic(fmt.Sprintf("parser: Register factory is nil for id %d", id))
+	}
+	if _, dup := registry[id]; dup {
+		panic(fmt.Sprintf("parser: Register called twice for id %d", id))
+	}
+	registry[id] = factory
+}
+
+// Get は識別子に対応するパーサーを生成して返す
+func Get(id byte) (Parser, error) {
+	registryMu.RLock()
+	factory, ok := registry[id]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("id %d: %w", id, ErrUnregistered)
+	}
+	return factory(), nil
+}
+
+// IsRegistered は識別子に対応するパーサーが登録されているかを返す
+func IsRegistered(id byte) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, ok := registry[id]
+	return ok
+}
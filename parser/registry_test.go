@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testParser struct {
+	JSONParser
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      byte
+		want    Parser
+		wantErr error
+	}{
+		{
+			name: "正常系: JSON",
+			id:   IDJSON,
+			want: &JSONParser{},
+		},
+		{
+			name: "正常系: Protocol Buffer",
+			id:   IDProtobuf,
+			want: &PbParser{},
+		},
+		{
+			name: "正常系: MessagePack",
+			id:   IDMsgpack,
+			want: &MsgpackParser{},
+		},
+		{
+			name: "正常系: YAML",
+			id:   IDYAML,
+			want: &YAMLParser{},
+		},
+		{
+			name:    "異常系: 未登録",
+			id:      0,
+			wantErr: ErrUnregistered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(tt.id)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				assert.False(t, IsRegistered(tt.id))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, IsRegistered(tt.id))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	const id byte = 200
+	Register(id, func() Parser { return &testParser{} })
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, id)
+		registryMu.Unlock()
+	})
+
+	got, err := Get(id)
+	assert.NoError(t, err)
+	assert.IsType(t, &testParser{}, got)
+
+	// 二重登録と nil の登録は panic する
+	assert.Panics(t, func() { Register(id, func() Parser { return &testParser{} }) })
+	assert.Panics(t, func() { Register(201, nil) })
+}
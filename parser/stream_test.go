@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"valley-pkg/parser/pb_go"
+)
+
+// 各パーサーが StreamParser を満たすことを保証する
+var (
+	_ StreamParser = (*JSONParser)(nil)
+	_ StreamParser = (*PbParser)(nil)
+	_ StreamParser = (*MsgpackParser)(nil)
+	_ StreamParser = (*YAMLParser)(nil)
+)
+
+func TestJSONParser_Stream(t *testing.T) {
+	type testStruct struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	inputs := []testStruct{{Name: "田中太郎", Age: 30}, {Name: "山田花子", Age: 25}}
+
+	parser := &JSONParser{}
+	var buf bytes.Buffer
+	enc := parser.NewEncoder(&buf)
+	for _, in := range inputs {
+		assert.NoError(t, enc.Encode(in))
+	}
+	assert.Equal(t, "{\"name\":\"田中太郎\",\"age\":30}\n{\"name\":\"山田花子\",\"age\":25}\n", buf.String())
+
+	dec := parser.NewDecoder(&buf)
+	var got []testStruct
+	for {
+		var v testStruct
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		got = append(got, v)
+	}
+	assert.Equal(t, inputs, got)
+}
+
+func TestPbParser_Stream(t *testing.T) {
+	inputs := []*pb_go.CommonRequestParam{
+		{PlayerId: "player123", PlatformUserId: "platform456"},
+		{PlayerId: "player789"},
+	}
+
+	parser := &PbParser{}
+	var buf bytes.Buffer
+	enc := parser.NewEncoder(&buf)
+	for _, in := range inputs {
+		assert.NoError(t, enc.Encode(in))
+	}
+	assert.Error(t, enc.Encode("not proto"))
+
+	// bufio を経由しない io.Reader でも読み取れる
+	dec := parser.NewDecoder(io.MultiReader(&buf))
+	for _, want := range inputs {
+		got := &pb_go.CommonRequestParam{}
+		assert.NoError(t, dec.Decode(got))
+		assert.True(t, proto.Equal(want, got))
+	}
+	assert.ErrorIs(t, dec.Decode(&pb_go.CommonRequestParam{}), io.EOF)
+}
+
+func TestMsgpackParser_Stream(t *testing.T) {
+	type testStruct struct {
+		Name string `msgpack:"name"`
+	}
+	inputs := []testStruct{{Name: "a"}, {Name: "b"}}
+
+	parser := &MsgpackParser{}
+	var buf bytes.Buffer
+	enc := parser.NewEncoder(&buf)
+	for _, in := range inputs {
+		assert.NoError(t, enc.Encode(in))
+	}
+
+	dec := parser.NewDecoder(&buf)
+	for _, want := range inputs {
+		var got testStruct
+		assert.NoError(t, dec.Decode(&got))
+		assert.Equal(t, want, got)
+	}
+	assert.ErrorIs(t, dec.Decode(&testStruct{}), io.EOF)
+}
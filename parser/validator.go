@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrValidation はUnmarshal後の値の検証に失敗した場合のエラー
+var ErrValidation = fmt.Errorf("validation error")
+
+// Validator はUnmarshal後に値を検証するためのインターフェース
+// Unmarshal先の値が実装している場合、変換後に Validate が呼ばれる。
+type Validator interface {
+	Validate() error
+}
+
+var (
+	structValidatorOnce sync.Once
+	structValidator     *validator.Validate
+)
+
+// getStructValidator は `validate` タグを検証するバリデーターを返す
+// 構造体の解析結果をキャッシュするため、1つのインスタンスを使い回す。
+func getStructValidator() *validator.Validate {
+	structValidatorOnce.Do(func() {
+		structValidator = validator.New(validator.WithRequiredStructEnabled())
+	})
+	return structValidator
+}
+
+// validate はUnmarshal後の値を `validate` タグと Validator の順に検証する
+// エラーは ErrValidation でラップし、元のエラー（validator.ValidationErrors など）も errors.As で取り出せる。
+func validate(v any) error {
+	if isStruct(v) {
+		if err := getStructValidator().Struct(v); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+	return callValidator(v)
+}
+
+// callValidator は値が Validator を実装している場合のみ Validate を呼ぶ
+func callValidator(v any) error {
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrValidation, err)
+		}
+	}
+	return nil
+}
+
+// isStruct は構造体、もしくは nil ではない構造体のポインタかを返す
+func isStruct(v any) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Struct
+}
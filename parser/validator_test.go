@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+// errNegativeAge はテスト用の Validate のエラー
+var errNegativeAge = errors.New("age must not be negative")
+
+type validatedStruct struct {
+	Name string `json:"name" msgpack:"name" validate:"required"`
+	Age  int    `json:"age" msgpack:"age"`
+}
+
+func (v *validatedStruct) Validate() error {
+	if v.Age < 0 {
+		return errNegativeAge
+	}
+	return nil
+}
+
+func TestUnmarshal_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   map[string]any
+		wantErr error
+	}{
+		{
+			name:  "正常系: 検証成功",
+			input: map[string]any{"name": "田中太郎", "age": 30},
+		},
+		{
+			name:    "異常系: validate タグの違反",
+			input:   map[string]any{"age": 30},
+			wantErr: ErrValidation,
+		},
+		{
+			name:    "異常系: Validate のエラー",
+			input:   map[string]any{"name": "田中太郎", "age": -1},
+			wantErr: errNegativeAge,
+		},
+	}
+
+	parsers := map[string]Parser{
+		"json":    &JSONParser{},
+		"msgpack": &MsgpackParser{},
+	}
+
+	for pname, p := range parsers {
+		for _, tt := range tests {
+			t.Run(pname+"/"+tt.name, func(t *testing.T) {
+				b, err := p.Marshal(tt.input)
+				assert.NoError(t, err)
+
+				err = p.Unmarshal(b, &validatedStruct{})
+				if tt.wantErr == nil {
+					assert.NoError(t, err)
+					return
+				}
+				assert.ErrorIs(t, err, ErrValidation)
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+	}
+}
+
+func TestUnmarshal_ValidationErrors(t *testing.T) {
+	err := (&JSONParser{}).Unmarshal([]byte(`{"age":1}`), &validatedStruct{})
+
+	// タグ検証の詳細を取り出せる
+	var verrs validator.ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Equal(t, "Name", verrs[0].Field())
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"io"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// YAMLParser はyaml用のパーサー
+// 構造体のフィールド名は `yaml` タグで指定し、タグが無い場合はフィールド名を小文字にしたものを使用する。
+type YAMLParser struct{}
+
+// Marshal は構造体をbyteに変換する
+func (p *YAMLParser) Marshal(v any) ([]byte, error) {
+	return yaml.Marshal(v)
+}
+
+// Unmarshal は構造体に変換する
+// 変換先が Validator を実装している場合や `validate` タグを持つ場合は、変換後に検証する。
+func (p *YAMLParser) Unmarshal(b []byte, v any) error {
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return err
+	}
+	return validate(v)
+}
+
+// NewDecoder は `---` で区切られた複数のドキュメントを逐次読み取るデコーダーを返す
+func (p *YAMLParser) NewDecoder(r io.Reader) Decoder {
+	return yaml.NewDecoder(r)
+}
+
+// NewEncoder は値ごとに `---` で区切られたドキュメントとして書き込むエンコーダーを返す
+// 書き込みはバッファされる場合があるため、すべて書き込んだ後に Close を呼ぶ必要がある。
+func (p *YAMLParser) NewEncoder(w io.Writer) Encoder {
+	return yaml.NewEncoder(w)
+}
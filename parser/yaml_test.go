@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYAMLParser(t *testing.T) {
+	type testStruct struct {
+		Name string   `yaml:"name"`
+		Age  int      `yaml:"age"`
+		Tags []string `yaml:"tags"`
+	}
+
+	tests := []struct {
+		name    string
+		input   []byte
+		want    *testStruct
+		wantErr bool
+	}{
+		{
+			name:  "正常系: YAMLから構造体への変換",
+			input: []byte("name: 田中太郎\nage: 30\ntags:\n  - a\n  - b\n"),
+			want:  &testStruct{Name: "田中太郎", Age: 30, Tags: []string{"a", "b"}},
+		},
+		{
+			name:  "正常系: 空のドキュメント",
+			input: []byte(""),
+			want:  &testStruct{},
+		},
+		{
+			name:    "異常系: 型が不一致",
+			input:   []byte("age: invalid\n"),
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不正なYAML",
+			input:   []byte("name: [a\n"),
+			wantErr: true,
+		},
+	}
+
+	parser := &YAMLParser{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &testStruct{}
+			err := parser.Unmarshal(tt.input, got)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestYAMLParser_Stream(t *testing.T) {
+	type testStruct struct {
+		Name string `yaml:"name"`
+	}
+	inputs := []testStruct{{Name: "a"}, {Name: "b"}}
+
+	parser := &YAMLParser{}
+	var buf bytes.Buffer
+	enc := parser.NewEncoder(&buf)
+	for _, in := range inputs {
+		assert.NoError(t, enc.Encode(in))
+	}
+	assert.NoError(t, enc.(io.Closer).Close())
+	assert.Equal(t, "name: a\n---\nname: b\n", buf.String())
+
+	dec := parser.NewDecoder(&buf)
+	for _, want := range inputs {
+		var got testStruct
+		assert.NoError(t, dec.Decode(&got))
+		assert.Equal(t, want, got)
+	}
+	assert.ErrorIs(t, dec.Decode(&testStruct{}), io.EOF)
+}
@@ -1,28 +1,60 @@
 package rand
 
 import (
-	"crypto/rand"
 	"fmt"
 )
 
 // Letters URL-safe な英数字
 const Letters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 
+// Charset ランダムな文字列に使用する文字種
+type Charset string
+
+// 用途別の文字種
+const (
+	// CharsetAlphanumeric 英数字
+	CharsetAlphanumeric Charset = Letters
+	// CharsetHex 16進数（小文字）
+	CharsetHex Charset = "0123456789abcdef"
+	// CharsetURLSafe URL-safe な base64 の文字種
+	CharsetURLSafe Charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// CharsetDigits 数字のみ
+	CharsetDigits Charset = "0123456789"
+	// CharsetUnambiguous 見間違えやすい文字（0/O/o, 1/I/l など）を除いた英数字。招待コードなど人が入力する値に使用する
+	CharsetUnambiguous Charset = "23456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz"
+)
+
 // GenerateRandomString 指定された数のランダムな文字列を生成します
 func GenerateRandomString(length int) (string, error) {
+	return GenerateRandomStringWithSource(defaultSource, length)
+}
+
+// GenerateRandomStringWithSource 指定された生成元から指定された数のランダムな文字列を生成します
+func GenerateRandomStringWithSource(src Source, length int) (string, error) {
+	return GenerateRandomStringFromCharsetWithSource(src, length, CharsetAlphanumeric)
+}
+
+// GenerateRandomStringFromCharset 指定された文字種から指定された数のランダムな文字列を生成します
+func GenerateRandomStringFromCharset(length int, charset Charset) (string, error) {
+	return GenerateRandomStringFromCharsetWithSource(defaultSource, length, charset)
+}
+
+// GenerateRandomStringFromCharsetWithSource 指定された生成元と文字種から指定された数のランダムな文字列を生成します
+// length は文字数で、マルチバイト文字を含む文字種も使用できます。
+func GenerateRandomStringFromCharsetWithSource(src Source, length int, charset Charset) (string, error) {
 	if length <= 0 {
 		return "", fmt.Errorf("length must be a positive integer: %d", length)
 	}
-
-	// crypto/randを使用して乱数を生成
-	bytes := make([]byte, length)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", fmt.Errorf("failed to generate random bytes: %v", err)
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		return "", fmt.Errorf("charset must not be empty")
 	}
 
+	runes := make([]rune, length)
 	for i := 0; i < length; i++ {
-		bytes[i] = Letters[int(bytes[i])%len(Letters)]
+		// 剰余による偏りが出ないよう、文字種の数で一様に選ぶ
+		runes[i] = chars[intN(src, len(chars))]
 	}
 
-	return string(bytes), nil
+	return string(runes), nil
 }
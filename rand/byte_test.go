@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"log"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +43,37 @@ func TestDuplicateProbability(t *testing.T) {
 	t.Logf("使用可能な文字種: %d", len(Letters))
 	t.Logf("理論上の組み合わせ総数: %.0f", math.Pow(float64(len(Letters)), float64(length)))
 }
+
+func TestGenerateRandomStringFromCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  int
+		charset Charset
+		wantErr bool
+	}{
+		{name: "正常: 英数字", length: 32, charset: CharsetAlphanumeric},
+		{name: "正常: 16進数", length: 32, charset: CharsetHex},
+		{name: "正常: URL-safe", length: 32, charset: CharsetURLSafe},
+		{name: "正常: 数字のみ", length: 6, charset: CharsetDigits},
+		{name: "正常: 見間違えにくい文字", length: 8, charset: CharsetUnambiguous},
+		{name: "正常: マルチバイト文字", length: 4, charset: "あいう"},
+		{name: "異常: 長さが0", length: 0, charset: CharsetHex, wantErr: true},
+		{name: "異常: 文字種が空", length: 8, charset: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateRandomStringFromCharset(tt.length, tt.charset)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.length, len([]rune(got)))
+			for _, r := range got {
+				assert.True(t, strings.ContainsRune(string(tt.charset), r), "unexpected char %q", r)
+			}
+		})
+	}
+}
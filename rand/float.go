@@ -0,0 +1,53 @@
+package rand
+
+import (
+	mrand "math/rand/v2"
+	"time"
+)
+
+// RandomFloatBetween [min, max) の範囲からランダムな小数を取得
+// min == max の場合は min を返します。
+func RandomFloatBetween(min float64, max float64) float64 {
+	return RandomFloatBetweenWithSource(defaultSource, min, max)
+}
+
+// RandomFloatBetweenWithSource 指定された生成元を使用して [min, max) の範囲からランダムな小数を取得
+func RandomFloatBetweenWithSource(src Source, min float64, max float64) float64 {
+	if min > max {
+		panic("min must be <= max")
+	}
+	return min + mrand.New(src).Float64()*(max-min)
+}
+
+// RandomDuration [min, max] の範囲からランダムな時間を取得
+// 出現タイマーや負荷試験の送信間隔などに使用します。
+func RandomDuration(min time.Duration, max time.Duration) time.Duration {
+	return RandomDurationWithSource(defaultSource, min, max)
+}
+
+// RandomDurationWithSource 指定された生成元を使用して [min, max] の範囲からランダムな時間を取得
+func RandomDurationWithSource(src Source, min time.Duration, max time.Duration) time.Duration {
+	if min > max {
+		panic("min must be <= max")
+	}
+	// max-min+1 が溢れないよう uint64 で扱う
+	return min + time.Duration(mrand.New(src).Uint64N(uint64(max-min)+1))
+}
+
+// Jitter d を中心に ±d*factor の範囲でばらつかせた時間を取得
+// リトライ間隔を分散させ、一斉に再接続が集中するのを防ぐために使用します。factor は 0 から 1 の範囲に丸めます。
+func Jitter(d time.Duration, factor float64) time.Duration {
+	return JitterWithSource(defaultSource, d, factor)
+}
+
+// JitterWithSource 指定された生成元を使用して d を中心に ±d*factor の範囲でばらつかせた時間を取得
+func JitterWithSource(src Source, d time.Duration, factor float64) time.Duration {
+	switch {
+	case factor <= 0 || d <= 0:
+		return d
+	case factor > 1:
+		factor = 1
+	}
+	delta := float64(d) * factor
+	return time.Duration(float64(d) - delta + mrand.New(src).Float64()*2*delta)
+}
@@ -0,0 +1,69 @@
+package rand
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomFloatBetween(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		got := RandomFloatBetween(-1.5, 2.5)
+		assert.GreaterOrEqual(t, got, -1.5)
+		assert.Less(t, got, 2.5)
+	}
+	assert.Equal(t, 3.0, RandomFloatBetween(3, 3))
+	assert.Panics(t, func() { RandomFloatBetween(2, 1) })
+}
+
+func TestRandomDuration(t *testing.T) {
+	values := make(map[time.Duration]bool)
+	for i := 0; i < 1000; i++ {
+		got := RandomDuration(time.Millisecond, 4*time.Millisecond)
+		assert.GreaterOrEqual(t, got, time.Millisecond)
+		assert.LessOrEqual(t, got, 4*time.Millisecond)
+		values[got] = true
+	}
+	assert.Greater(t, len(values), 1)
+
+	// 両端を含む
+	assert.Equal(t, time.Second, RandomDuration(time.Second, time.Second))
+	src := NewSeededSource(1)
+	var gotMin, gotMax bool
+	for i := 0; i < 1000; i++ {
+		switch RandomDurationWithSource(src, 0, 2) {
+		case 0:
+			gotMin = true
+		case 2:
+			gotMax = true
+		}
+	}
+	assert.True(t, gotMin && gotMax)
+	assert.Panics(t, func() { RandomDuration(time.Second, time.Millisecond) })
+}
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		name    string
+		d       time.Duration
+		factor  float64
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "正常: ±10%", d: time.Second, factor: 0.1, wantMin: 900 * time.Millisecond, wantMax: 1100 * time.Millisecond},
+		{name: "正常: factor が0", d: time.Second, factor: 0, wantMin: time.Second, wantMax: time.Second},
+		{name: "正常: factor が1を超える", d: time.Second, factor: 5, wantMin: 0, wantMax: 2 * time.Second},
+		{name: "正常: d が0", d: 0, factor: 0.5, wantMin: 0, wantMax: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				got := Jitter(tt.d, tt.factor)
+				assert.GreaterOrEqual(t, got, tt.wantMin)
+				assert.LessOrEqual(t, got, tt.wantMax)
+			}
+		})
+	}
+}
@@ -1,37 +1,111 @@
 package rand
 
-import "math/rand"
+import (
+	"fmt"
+	"math"
+	mrand "math/rand/v2"
+	"reflect"
+)
+
+// ErrRange 範囲の指定が不正な場合のエラー
+var ErrRange = fmt.Errorf("invalid range")
+
+// Number RandomBetween で扱える数値型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
 
 // RandomIntBetweenInclusive 特定範囲からランダム値を取得
+// 範囲が不正な場合は panic します。範囲を設定値などから受け取る場合は RandomIntBetween を使用してください。
 func RandomIntBetweenInclusive(min int, max int, isMinInclusive bool, isMaxInclusive bool) int {
+	return RandomIntBetweenInclusiveWithSource(defaultSource, min, max, isMinInclusive, isMaxInclusive)
+}
+
+// RandomIntBetweenInclusiveWithSource 指定された生成元を使用して特定範囲からランダム値を取得
+// 範囲が不正な場合は panic します。
+func RandomIntBetweenInclusiveWithSource(src Source, min int, max int, isMinInclusive bool, isMaxInclusive bool) int {
+	v, err := RandomIntBetweenWithSource(src, min, max, isMinInclusive, isMaxInclusive)
+	if err != nil {
+		panic(err.Error())
+	}
+	return v
+}
+
+// RandomIntBetween 特定範囲からランダム値を取得
+// 範囲が不正な場合は ErrRange を返します。
+func RandomIntBetween(min int, max int, isMinInclusive bool, isMaxInclusive bool) (int, error) {
+	return RandomIntBetweenWithSource(defaultSource, min, max, isMinInclusive, isMaxInclusive)
+}
+
+// RandomIntBetweenWithSource 指定された生成元を使用して特定範囲からランダム値を取得
+// 範囲が不正な場合は ErrRange を返します。
+func RandomIntBetweenWithSource(src Source, min int, max int, isMinInclusive bool, isMaxInclusive bool) (int, error) {
 	if min > max {
-		panic("min must be <= max")
+		return 0, fmt.Errorf("min must be <= max: %w", ErrRange)
 	}
 
 	// 両端は含む
 	if isMinInclusive && isMaxInclusive {
-		return rand.Intn(max-min+1) + min
+		return intN(src, max-min+1) + min, nil
 	}
 
 	// 最小は含む
 	if isMinInclusive {
 		if max-min < 1 {
-			panic("need min < max for [min, max)")
+			return 0, fmt.Errorf("need min < max for [min, max): %w", ErrRange)
 		}
-		return rand.Intn(max-min) + min
+		return intN(src, max-min) + min, nil
 	}
 
 	// 最大は含む
 	if isMaxInclusive {
 		if max-min < 1 {
-			panic("need min < max for (min, max]")
+			return 0, fmt.Errorf("need min < max for (min, max]: %w", ErrRange)
 		}
-		return rand.Intn(max-min) + (min + 1)
+		return intN(src, max-min) + (min + 1), nil
 	}
 
 	// 両端は含まない
 	if max-min < 2 {
-		panic("need max-min >= 2 for (min, max)")
+		return 0, fmt.Errorf("need max-min >= 2 for (min, max): %w", ErrRange)
+	}
+	return intN(src, max-min-1) + (min + 1), nil
+}
+
+// RandomBetween 特定範囲からランダム値を取得
+// 整数型は [min, max] の両端を含み、小数型は [min, max) の範囲から取得します。min > max の場合は ErrRange を返します。
+func RandomBetween[T Number](min T, max T) (T, error) {
+	return RandomBetweenWithSource(defaultSource, min, max)
+}
+
+// RandomBetweenWithSource 指定された生成元を使用して特定範囲からランダム値を取得
+func RandomBetweenWithSource[T Number](src Source, min T, max T) (T, error) {
+	if min > max {
+		var zero T
+		return zero, fmt.Errorf("min %v must be <= max %v: %w", min, max, ErrRange)
+	}
+	r := mrand.New(src)
+
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(float64(min)) || math.IsNaN(float64(max)) || math.IsInf(float64(max-min), 0) {
+			var zero T
+			return zero, fmt.Errorf("min %v, max %v: %w", min, max, ErrRange)
+		}
+		v := min + T(r.Float64())*(max-min)
+		// float32 への丸めで max になった場合は範囲に収める
+		if v >= max && max > min {
+			v = min
+		}
+		return v, nil
+	default:
+		// 符号付きの整数は uint64 への変換で符号拡張されるため、差は2の補数として正しく求まる
+		span := uint64(max) - uint64(min)
+		if span == math.MaxUint64 {
+			return T(r.Uint64()), nil
+		}
+		return T(uint64(min) + r.Uint64N(span+1)), nil
 	}
-	return rand.Intn(max-min-1) + (min + 1)
 }
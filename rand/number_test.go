@@ -1,7 +1,10 @@
 package rand
 
 import (
+	"math"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRandomIntBetweenInclusive(t *testing.T) {
@@ -101,3 +104,86 @@ func TestRandomIntBetweenInclusive(t *testing.T) {
 		})
 	}
 }
+
+func TestRandomIntBetween(t *testing.T) {
+	_, err := RandomIntBetween(5, 3, true, true)
+	assert.ErrorIs(t, err, ErrRange)
+	_, err = RandomIntBetween(2, 3, false, false)
+	assert.ErrorIs(t, err, ErrRange)
+
+	for i := 0; i < 100; i++ {
+		got, err := RandomIntBetween(2, 5, false, true)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, got, 3)
+		assert.LessOrEqual(t, got, 5)
+	}
+}
+
+func TestRandomBetween(t *testing.T) {
+	t.Run("正常: int", func(t *testing.T) {
+		values := make(map[int]bool)
+		for i := 0; i < 200; i++ {
+			got, err := RandomBetween(-2, 2)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, got, -2)
+			assert.LessOrEqual(t, got, 2)
+			values[got] = true
+		}
+		// 両端を含む
+		assert.Len(t, values, 5)
+	})
+
+	t.Run("正常: uint8 の全範囲", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			_, err := RandomBetween[uint8](0, math.MaxUint8)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("正常: int64 の全範囲", func(t *testing.T) {
+		_, err := RandomBetween[int64](math.MinInt64, math.MaxInt64)
+		assert.NoError(t, err)
+	})
+
+	t.Run("正常: 負の int8", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			got, err := RandomBetween[int8](-128, -120)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, got, int8(-128))
+			assert.LessOrEqual(t, got, int8(-120))
+		}
+	})
+
+	t.Run("正常: float64", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			got, err := RandomBetween(0.5, 1.5)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, got, 0.5)
+			assert.Less(t, got, 1.5)
+		}
+	})
+
+	t.Run("正常: 同値", func(t *testing.T) {
+		got, err := RandomBetween[float32](2, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(2), got)
+	})
+
+	t.Run("異常: min > max", func(t *testing.T) {
+		_, err := RandomBetween(3, 1)
+		assert.ErrorIs(t, err, ErrRange)
+		_, err = RandomBetween(3.0, 1.0)
+		assert.ErrorIs(t, err, ErrRange)
+	})
+
+	t.Run("異常: 無限大", func(t *testing.T) {
+		_, err := RandomBetween(0, math.Inf(1))
+		assert.ErrorIs(t, err, ErrRange)
+	})
+
+	t.Run("正常: 同じシード値からは同じ結果", func(t *testing.T) {
+		a, _ := RandomBetweenWithSource(NewSeededSource(7), 0, 1000000)
+		b, _ := RandomBetweenWithSource(NewSeededSource(7), 0, 1000000)
+		assert.Equal(t, a, b)
+	})
+}
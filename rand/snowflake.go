@@ -0,0 +1,170 @@
+package rand
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSnowflakeConfig Snowflakeの設定が不正な場合のエラー
+var ErrSnowflakeConfig = fmt.Errorf("invalid snowflake config")
+
+// ErrClockMovedBackwards 時計が許容範囲を超えて巻き戻った場合のエラー
+var ErrClockMovedBackwards = fmt.Errorf("clock moved backwards")
+
+// ErrTimestampOverflow エポックからの経過時間がタイムスタンプのビット数を超えた場合のエラー
+var ErrTimestampOverflow = fmt.Errorf("snowflake timestamp overflow")
+
+// デフォルトの設定値
+const (
+	// DefaultNodeBits ノードIDのビット数（最大1024ノード）
+	DefaultNodeBits = 10
+	// DefaultSequenceBits シーケンスのビット数（1ミリ秒あたり最大4096個）
+	DefaultSequenceBits = 12
+	// DefaultMaxClockBackward 時計の巻き戻りを待って吸収する最大時間
+	DefaultMaxClockBackward = 100 * time.Millisecond
+)
+
+// DefaultEpoch タイムスタンプの起点 (2024-01-01T00:00:00Z)
+var DefaultEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeConfig Snowflakeの設定
+// ゼロ値の項目はデフォルトの設定値を使用します。
+type SnowflakeConfig struct {
+	// NodeID 生成するプロセスごとに一意な値。0 から 2^NodeBits-1 の範囲で指定します
+	NodeID int64
+	// Epoch タイムスタンプの起点
+	Epoch time.Time
+	// NodeBits ノードIDのビット数
+	NodeBits uint8
+	// SequenceBits シーケンスのビット数
+	SequenceBits uint8
+	// MaxClockBackward 時計の巻き戻りを待って吸収する最大時間。超えた場合は ErrClockMovedBackwards を返します
+	MaxClockBackward time.Duration
+}
+
+// Snowflake タイムスタンプ・ノードID・シーケンスを組み合わせた64ビットのIDを生成する
+// 上位から「符号(1ビット)・エポックからのミリ秒・ノードID・シーケンス」の順に並ぶため、同じノードで生成したIDは単調増加します。
+// 複数のゴルーチンから同時に使用できます。
+type Snowflake struct {
+	mu sync.Mutex
+
+	epoch        time.Time
+	nodeID       int64
+	nodeBits     uint8
+	sequenceBits uint8
+	maxBackward  time.Duration
+	maxTimestamp int64
+	maxSequence  int64
+
+	lastTimestamp int64
+	sequence      int64
+
+	now func() time.Time
+}
+
+// SnowflakeID IDを分解した値
+type SnowflakeID struct {
+	Time     time.Time
+	NodeID   int64
+	Sequence int64
+}
+
+// NewSnowflake Snowflakeを生成します
+func NewSnowflake(cfg SnowflakeConfig) (*Snowflake, error) {
+	if cfg.Epoch.IsZero() {
+		cfg.Epoch = DefaultEpoch
+	}
+	if cfg.NodeBits == 0 {
+		cfg.NodeBits = DefaultNodeBits
+	}
+	if cfg.SequenceBits == 0 {
+		cfg.SequenceBits = DefaultSequenceBits
+	}
+	if cfg.MaxClockBackward == 0 {
+		cfg.MaxClockBackward = DefaultMaxClockBackward
+	}
+
+	// タイムスタンプに最低でも32ビット（約49日）は残す
+	if int(cfg.NodeBits)+int(cfg.SequenceBits) > 31 {
+		return nil, fmt.Errorf("node bits %d + sequence bits %d must be <= 31: %w", cfg.NodeBits, cfg.SequenceBits, ErrSnowflakeConfig)
+	}
+	maxNode := int64(1)<<cfg.NodeBits - 1
+	if cfg.NodeID < 0 || cfg.NodeID > maxNode {
+		return nil, fmt.Errorf("node id %d must be between 0 and %d: %w", cfg.NodeID, maxNode, ErrSnowflakeConfig)
+	}
+
+	return &Snowflake{
+		epoch:         cfg.Epoch,
+		nodeID:        cfg.NodeID,
+		nodeBits:      cfg.NodeBits,
+		sequenceBits:  cfg.SequenceBits,
+		maxBackward:   cfg.MaxClockBackward,
+		maxTimestamp:  int64(1)<<(63-cfg.NodeBits-cfg.SequenceBits) - 1,
+		maxSequence:   int64(1)<<cfg.SequenceBits - 1,
+		lastTimestamp: -1,
+		now:           time.Now,
+	}, nil
+}
+
+// Next 次のIDを生成します
+// 同じミリ秒内でシーケンスを使い切った場合は、次のミリ秒まで待ちます。
+// 時計が MaxClockBackward 以内で巻き戻った場合は追いつくまで待ち、それを超える場合は ErrClockMovedBackwards を返します。
+func (s *Snowflake) Next() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := s.timestamp()
+	if ts < 0 {
+		return 0, fmt.Errorf("current time is before epoch %v: %w", s.epoch, ErrSnowflakeConfig)
+	}
+	if ts < s.lastTimestamp {
+		backward := time.Duration(s.lastTimestamp-ts) * time.Millisecond
+		if backward > s.maxBackward {
+			return 0, fmt.Errorf("%v behind the last id: %w", backward, ErrClockMovedBackwards)
+		}
+		ts = s.waitAfter(s.lastTimestamp - 1)
+	}
+
+	if ts == s.lastTimestamp {
+		s.sequence = (s.sequence + 1) & s.maxSequence
+		if s.sequence == 0 {
+			// シーケンスを使い切ったので次のミリ秒まで待つ
+			ts = s.waitAfter(s.lastTimestamp)
+		}
+	} else {
+		s.sequence = 0
+	}
+
+	if ts > s.maxTimestamp {
+		return 0, fmt.Errorf("timestamp %d exceeds %d: %w", ts, s.maxTimestamp, ErrTimestampOverflow)
+	}
+	s.lastTimestamp = ts
+
+	return ts<<(s.nodeBits+s.sequenceBits) | s.nodeID<<s.sequenceBits | s.sequence, nil
+}
+
+// Parse IDをタイムスタンプ・ノードID・シーケンスに分解します
+func (s *Snowflake) Parse(id int64) SnowflakeID {
+	ts := id >> (s.nodeBits + s.sequenceBits)
+	return SnowflakeID{
+		Time:     s.epoch.Add(time.Duration(ts) * time.Millisecond),
+		NodeID:   id >> s.sequenceBits & (int64(1)<<s.nodeBits - 1),
+		Sequence: id & s.maxSequence,
+	}
+}
+
+// timestamp エポックからの経過ミリ秒を返します
+func (s *Snowflake) timestamp() int64 {
+	return s.now().Sub(s.epoch).Milliseconds()
+}
+
+// waitAfter 経過ミリ秒が last より大きくなるまで待ち、その値を返します
+func (s *Snowflake) waitAfter(last int64) int64 {
+	ts := s.timestamp()
+	for ts <= last {
+		time.Sleep(time.Duration(last-ts+1) * time.Millisecond / 2)
+		ts = s.timestamp()
+	}
+	return ts
+}
@@ -0,0 +1,156 @@
+package rand
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock テスト用の時計
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+	// step Now を呼ぶたびに進める時間
+	step time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.t
+	c.t = c.t.Add(c.step)
+	return now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+func TestNewSnowflake(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SnowflakeConfig
+		wantErr bool
+	}{
+		{name: "正常: デフォルト", cfg: SnowflakeConfig{NodeID: 1}},
+		{name: "正常: ノードIDの最大値", cfg: SnowflakeConfig{NodeID: 1023}},
+		{name: "異常: ノードIDが範囲外", cfg: SnowflakeConfig{NodeID: 1024}, wantErr: true},
+		{name: "異常: ノードIDが負", cfg: SnowflakeConfig{NodeID: -1}, wantErr: true},
+		{name: "異常: ビット数が多すぎる", cfg: SnowflakeConfig{NodeBits: 20, SequenceBits: 12}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSnowflake(tt.cfg)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrSnowflakeConfig)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSnowflake_Next(t *testing.T) {
+	s, err := NewSnowflake(SnowflakeConfig{NodeID: 5})
+	assert.NoError(t, err)
+
+	// 並行に生成しても重複せず、ゴルーチン内では単調増加する
+	const goroutines, perGoroutine = 8, 2000
+	ids := make(chan int64, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var prev int64
+			for j := 0; j < perGoroutine; j++ {
+				id, err := s.Next()
+				assert.NoError(t, err)
+				assert.Greater(t, id, prev)
+				prev = id
+				ids <- id
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int64]bool)
+	for id := range ids {
+		assert.False(t, seen[id], "duplicate id %d", id)
+		seen[id] = true
+		assert.Equal(t, int64(5), s.Parse(id).NodeID)
+	}
+}
+
+func TestSnowflake_Parse(t *testing.T) {
+	clock := &fakeClock{t: DefaultEpoch.Add(1500 * time.Millisecond)}
+	s, err := NewSnowflake(SnowflakeConfig{NodeID: 3})
+	assert.NoError(t, err)
+	s.now = clock.Now
+
+	first, err := s.Next()
+	assert.NoError(t, err)
+	second, err := s.Next()
+	assert.NoError(t, err)
+
+	assert.Equal(t, SnowflakeID{Time: DefaultEpoch.Add(1500 * time.Millisecond), NodeID: 3, Sequence: 0}, s.Parse(first))
+	assert.Equal(t, SnowflakeID{Time: DefaultEpoch.Add(1500 * time.Millisecond), NodeID: 3, Sequence: 1}, s.Parse(second))
+}
+
+func TestSnowflake_sequenceOverflow(t *testing.T) {
+	clock := &fakeClock{t: DefaultEpoch.Add(time.Second)}
+	s, err := NewSnowflake(SnowflakeConfig{SequenceBits: 2})
+	assert.NoError(t, err)
+	s.now = clock.Now
+
+	var ids []int64
+	for i := 0; i < 4; i++ {
+		id, err := s.Next()
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// シーケンスを使い切ったら時計が進むまで待つ
+	clock.step = time.Millisecond
+	id, err := s.Next()
+	assert.NoError(t, err)
+	assert.Greater(t, id, ids[3])
+	parsed := s.Parse(id)
+	assert.Equal(t, int64(0), parsed.Sequence)
+	assert.True(t, parsed.Time.After(s.Parse(ids[3]).Time))
+}
+
+func TestSnowflake_clockBackward(t *testing.T) {
+	base := DefaultEpoch.Add(time.Hour)
+	clock := &fakeClock{t: base}
+	s, err := NewSnowflake(SnowflakeConfig{MaxClockBackward: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	s.now = clock.Now
+
+	last, err := s.Next()
+	assert.NoError(t, err)
+
+	// 許容範囲内の巻き戻りは追いつくまで待つ
+	clock.Set(base.Add(-5 * time.Millisecond))
+	clock.step = time.Millisecond
+	id, err := s.Next()
+	assert.NoError(t, err)
+	assert.Greater(t, id, last)
+
+	// 許容範囲を超える巻き戻りはエラー
+	clock.Set(base.Add(-time.Second))
+	clock.step = 0
+	_, err = s.Next()
+	assert.ErrorIs(t, err, ErrClockMovedBackwards)
+
+	// エポックより前はエラー
+	clock.Set(DefaultEpoch.Add(-time.Second))
+	_, err = s.Next()
+	assert.ErrorIs(t, err, ErrSnowflakeConfig)
+}
@@ -0,0 +1,59 @@
+package rand
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	mrand "math/rand/v2"
+	"sync"
+)
+
+// Source 乱数の生成元
+// math/rand/v2 の Source と同じメソッドを持つため、そのまま math/rand/v2 の Source としても使用できる。
+type Source interface {
+	Uint64() uint64
+}
+
+// defaultSource 各関数のデフォルトで使用する生成元
+var defaultSource Source = NewCryptoSource()
+
+// cryptoSource crypto/rand を使用する生成元
+type cryptoSource struct{}
+
+// NewCryptoSource crypto/rand を使用する生成元を返します
+// 予測できない値が必要な場合に使用します。複数のゴルーチンから同時に使用できます。
+func NewCryptoSource() Source {
+	return cryptoSource{}
+}
+
+// Uint64 ランダムな uint64 を返します
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	// crypto/rand.Read はエラーを返さない
+	_, _ = crand.Read(b[:])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// seededSource シード値から決定的な値を返す生成元
+type seededSource struct {
+	mu  sync.Mutex
+	pcg *mrand.PCG
+}
+
+// NewSeededSource シード値から決定的な値を返す生成元を返します
+// 同じシード値からは同じ順序で同じ値が生成されるため、シミュレーションのテストや再現に使用します。
+// 予測可能なため、トークンなどの秘匿する値の生成には使用しないでください。複数のゴルーチンから同時に使用できます。
+func NewSeededSource(seed uint64) Source {
+	return &seededSource{pcg: mrand.NewPCG(seed, seed)}
+}
+
+// Uint64 シード値から決まる次の uint64 を返します
+func (s *seededSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pcg.Uint64()
+}
+
+// intN 生成元から [0, n) の一様な値を返します
+func intN(src Source, n int) int {
+	return mrand.New(src).IntN(n)
+}
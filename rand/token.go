@@ -0,0 +1,86 @@
+package rand
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidToken トークンの形式やチェックサムが正しくない場合のエラー
+var ErrInvalidToken = fmt.Errorf("invalid token")
+
+// tokenSeparator プレフィックスと本体の区切り文字
+const tokenSeparator = "_"
+
+// tokenChecksumLen チェックサム(CRC32)を62進数で表した文字数
+const tokenChecksumLen = 6
+
+// GenerateToken "<prefix>_<62進数のランダム値><チェックサム>" 形式のトークンを生成します
+// APIキーやセッショントークンなど、秘匿する値に使用します。ランダム値は crypto/rand から entropyBytes バイト分を取得します。
+// 末尾のチェックサムにより、Redis などへ問い合わせる前に ValidateToken で打ち間違いや改ざんを検出できます。
+func GenerateToken(prefix string, entropyBytes int) (string, error) {
+	if prefix == "" {
+		return "", fmt.Errorf("prefix must not be empty")
+	}
+	if entropyBytes <= 0 {
+		return "", fmt.Errorf("entropy bytes must be a positive integer: %d", entropyBytes)
+	}
+
+	entropy := make([]byte, entropyBytes)
+	if _, err := crand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	body := encodeBase62(new(big.Int).SetBytes(entropy), base62Len(entropyBytes))
+	return prefix + tokenSeparator + body + tokenChecksum(prefix, body), nil
+}
+
+// ValidateToken トークンが prefix で始まり、チェックサムが一致するかを検証します
+// トークンが実在するかは検証しないため、通過した後に保存先へ問い合わせてください。
+func ValidateToken(token string, prefix string) error {
+	rest, ok := strings.CutPrefix(token, prefix+tokenSeparator)
+	if !ok {
+		return fmt.Errorf("prefix is not %q: %w", prefix, ErrInvalidToken)
+	}
+	if len(rest) <= tokenChecksumLen {
+		return fmt.Errorf("token is too short: %w", ErrInvalidToken)
+	}
+	for _, c := range rest {
+		if !strings.ContainsRune(Letters, c) {
+			return fmt.Errorf("token contains invalid character %q: %w", c, ErrInvalidToken)
+		}
+	}
+
+	body, checksum := rest[:len(rest)-tokenChecksumLen], rest[len(rest)-tokenChecksumLen:]
+	if tokenChecksum(prefix, body) != checksum {
+		return fmt.Errorf("checksum mismatch: %w", ErrInvalidToken)
+	}
+	return nil
+}
+
+// tokenChecksum プレフィックスと本体の CRC32 を62進数の固定長で返します
+func tokenChecksum(prefix string, body string) string {
+	sum := crc32.ChecksumIEEE([]byte(prefix + tokenSeparator + body))
+	return encodeBase62(new(big.Int).SetUint64(uint64(sum)), tokenChecksumLen)
+}
+
+// base62Len n バイトの値を62進数で表すのに必要な文字数を返します
+func base62Len(n int) int {
+	return int(math.Ceil(float64(n*8) / math.Log2(62)))
+}
+
+// encodeBase62 値を62進数の固定長の文字列に変換します
+// 足りない桁は先頭を "0" で埋めます。
+func encodeBase62(v *big.Int, length int) string {
+	b := make([]byte, length)
+	base := big.NewInt(int64(len(Letters)))
+	mod := new(big.Int)
+	for i := length - 1; i >= 0; i-- {
+		v.DivMod(v, base, mod)
+		b[i] = Letters[mod.Int64()]
+	}
+	return string(b)
+}
@@ -0,0 +1,66 @@
+package rand
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken("sess", 24)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "sess_"))
+	// 24バイトは62進数で33文字、チェックサムが6文字
+	assert.Len(t, token, len("sess_")+33+6)
+	assert.NoError(t, ValidateToken(token, "sess"))
+
+	other, err := GenerateToken("sess", 24)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	_, err = GenerateToken("", 24)
+	assert.Error(t, err)
+	_, err = GenerateToken("sess", 0)
+	assert.Error(t, err)
+}
+
+func TestValidateToken(t *testing.T) {
+	token, err := GenerateToken("api", 16)
+	assert.NoError(t, err)
+
+	// 本体の1文字を書き換える
+	tampered := []byte(token)
+	i := len("api_") + 3
+	if tampered[i] == 'a' {
+		tampered[i] = 'b'
+	} else {
+		tampered[i] = 'a'
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		prefix  string
+		wantErr bool
+	}{
+		{name: "正常: 生成したトークン", token: token, prefix: "api"},
+		{name: "異常: プレフィックス違い", token: token, prefix: "sess", wantErr: true},
+		{name: "異常: プレフィックスの書き換え", token: "sess" + strings.TrimPrefix(token, "api"), prefix: "sess", wantErr: true},
+		{name: "異常: 本体の書き換え", token: string(tampered), prefix: "api", wantErr: true},
+		{name: "異常: 短すぎる", token: "api_abc", prefix: "api", wantErr: true},
+		{name: "異常: 不正な文字", token: token[:len(token)-1] + "-", prefix: "api", wantErr: true},
+		{name: "異常: 空", token: "", prefix: "api", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateToken(tt.token, tt.prefix)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
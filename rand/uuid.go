@@ -0,0 +1,45 @@
+package rand
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUID4 ランダムな UUID (version 4) を "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" 形式の文字列で生成します
+func UUID4() (string, error) {
+	u, err := UUID4Bytes()
+	if err != nil {
+		return "", err
+	}
+	return uuid.UUID(u).String(), nil
+}
+
+// UUID4Bytes ランダムな UUID (version 4) を16バイトで生成します
+func UUID4Bytes() ([16]byte, error) {
+	u, err := uuid.NewRandom()
+	if err != nil {
+		return [16]byte{}, fmt.Errorf("failed to generate uuid v4: %w", err)
+	}
+	return u, nil
+}
+
+// UUID7 時刻順に並ぶ UUID (version 7) を "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" 形式の文字列で生成します
+// 先頭がミリ秒単位の Unix 時刻のため、生成順に文字列としてもソートできます。DBの主キーなどに使用します。
+func UUID7() (string, error) {
+	u, err := UUID7Bytes()
+	if err != nil {
+		return "", err
+	}
+	return uuid.UUID(u).String(), nil
+}
+
+// UUID7Bytes 時刻順に並ぶ UUID (version 7) を16バイトで生成します
+// 同じプロセス内では、同じミリ秒内に生成しても単調増加します。
+func UUID7Bytes() ([16]byte, error) {
+	u, err := uuid.NewV7()
+	if err != nil {
+		return [16]byte{}, fmt.Errorf("failed to generate uuid v7: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,47 @@
+package rand
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uuidPattern 小文字のハイフン区切り形式
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-([0-9a-f])[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUID4(t *testing.T) {
+	s, err := UUID4()
+	assert.NoError(t, err)
+	m := uuidPattern.FindStringSubmatch(s)
+	assert.NotNil(t, m, s)
+	assert.Equal(t, "4", m[1])
+
+	b, err := UUID4Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x40), b[6]&0xF0)
+
+	other, err := UUID4()
+	assert.NoError(t, err)
+	assert.NotEqual(t, s, other)
+}
+
+func TestUUID7(t *testing.T) {
+	s, err := UUID7()
+	assert.NoError(t, err)
+	m := uuidPattern.FindStringSubmatch(s)
+	assert.NotNil(t, m, s)
+	assert.Equal(t, "7", m[1])
+
+	// 生成順に並ぶ
+	prev, err := UUID7Bytes()
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x70), prev[6]&0xF0)
+	for i := 0; i < 1000; i++ {
+		next, err := UUID7Bytes()
+		assert.NoError(t, err)
+		assert.Equal(t, -1, bytes.Compare(prev[:], next[:]))
+		prev = next
+	}
+}
@@ -0,0 +1,160 @@
+package rand
+
+import (
+	"fmt"
+	"math"
+	mrand "math/rand/v2"
+)
+
+// ErrWeights 重みの指定が不正な場合のエラー
+var ErrWeights = fmt.Errorf("invalid weights")
+
+// WeightedChoice 重みに比例した確率で items から1つ選びます
+// 1回だけ選ぶ場合に使用します。同じ重みで繰り返し選ぶ場合は WeightedTable を使用してください。
+func WeightedChoice[T any](items []T, weights []float64) (T, error) {
+	return WeightedChoiceWithSource(defaultSource, items, weights)
+}
+
+// WeightedChoiceWithSource 指定された生成元を使用して、重みに比例した確率で items から1つ選びます
+func WeightedChoiceWithSource[T any](src Source, items []T, weights []float64) (T, error) {
+	var zero T
+	total, err := validateWeights(len(items), weights)
+	if err != nil {
+		return zero, err
+	}
+
+	r := mrand.New(src).Float64() * total
+	for i, w := range weights {
+		if r < w {
+			return items[i], nil
+		}
+		r -= w
+	}
+
+	// 浮動小数点の誤差で選ばれなかった場合は、重みが0でない最後の要素とする
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return items[i], nil
+		}
+	}
+	return zero, ErrWeights
+}
+
+// WeightedTable 重みに比例した確率で要素を選ぶ抽選テーブル
+// エイリアス法により、生成時に O(n)、1回の抽選を O(1) で行います。生成後は複数のゴルーチンから同時に使用できます。
+type WeightedTable[T any] struct {
+	items []T
+	prob  []float64
+	alias []int
+}
+
+// NewWeightedTable 抽選テーブルを生成します
+// items と weights は同じ長さで、weights は0以上かつ合計が0より大きい必要があります。
+func NewWeightedTable[T any](items []T, weights []float64) (*WeightedTable[T], error) {
+	total, err := validateWeights(len(items), weights)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(weights)
+	table := &WeightedTable[T]{
+		items: append([]T(nil), items...),
+		prob:  make([]float64, n),
+		alias: make([]int, n),
+	}
+
+	// 平均が1になるように正規化し、1未満と1以上に振り分ける
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	// 1未満の枠の不足分を1以上の要素で埋める
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+
+		table.prob[s] = scaled[s]
+		table.alias[s] = l
+
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+
+	// 残りは浮動小数点の誤差を除いて1なので、自身で枠を埋める
+	for _, i := range large {
+		table.prob[i] = 1
+	}
+	for _, i := range small {
+		if weights[i] > 0 {
+			table.prob[i] = 1
+			continue
+		}
+		// 重みが0の要素は選ばれないよう、重みのある要素に振り替える
+		table.prob[i] = 0
+		table.alias[i] = firstPositive(weights)
+	}
+
+	return table, nil
+}
+
+// Len 要素数を返します
+func (t *WeightedTable[T]) Len() int {
+	return len(t.items)
+}
+
+// Pick 重みに比例した確率で要素を1つ選びます
+func (t *WeightedTable[T]) Pick() T {
+	return t.PickWithSource(defaultSource)
+}
+
+// PickWithSource 指定された生成元を使用して、重みに比例した確率で要素を1つ選びます
+func (t *WeightedTable[T]) PickWithSource(src Source) T {
+	r := mrand.New(src)
+	i := r.IntN(len(t.items))
+	if r.Float64() < t.prob[i] {
+		return t.items[i]
+	}
+	return t.items[t.alias[i]]
+}
+
+// firstPositive 重みが0より大きい最初の要素の位置を返します
+func firstPositive(weights []float64) int {
+	for i, w := range weights {
+		if w > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// validateWeights 重みを検証し、合計を返します
+func validateWeights(n int, weights []float64) (float64, error) {
+	if n == 0 {
+		return 0, fmt.Errorf("items must not be empty: %w", ErrWeights)
+	}
+	if n != len(weights) {
+		return 0, fmt.Errorf("items length %d and weights length %d differ: %w", n, len(weights), ErrWeights)
+	}
+
+	var total float64
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return 0, fmt.Errorf("weights[%d] = %v: %w", i, w, ErrWeights)
+		}
+		total += w
+	}
+	if total <= 0 || math.IsInf(total, 0) {
+		return 0, fmt.Errorf("total weight %v: %w", total, ErrWeights)
+	}
+	return total, nil
+}
@@ -0,0 +1,86 @@
+package rand
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightedChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		weights []float64
+		wantErr bool
+	}{
+		{name: "正常: 重み付き", items: []string{"N", "R", "SR"}, weights: []float64{70, 25, 5}},
+		{name: "正常: 重みが0の要素を含む", items: []string{"N", "R"}, weights: []float64{0, 1}},
+		{name: "異常: 要素が空", items: nil, weights: nil, wantErr: true},
+		{name: "異常: 長さが異なる", items: []string{"N"}, weights: []float64{1, 2}, wantErr: true},
+		{name: "異常: 負の重み", items: []string{"N", "R"}, weights: []float64{-1, 2}, wantErr: true},
+		{name: "異常: NaN", items: []string{"N"}, weights: []float64{math.NaN()}, wantErr: true},
+		{name: "異常: 合計が0", items: []string{"N", "R"}, weights: []float64{0, 0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WeightedChoice(tt.items, tt.weights)
+			_, tableErr := NewWeightedTable(tt.items, tt.weights)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrWeights)
+				assert.ErrorIs(t, tableErr, ErrWeights)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, tableErr)
+			assert.Contains(t, tt.items, got)
+		})
+	}
+}
+
+// TestWeightedDistribution 重みに比例した頻度で選ばれることを確認
+func TestWeightedDistribution(t *testing.T) {
+	items := []string{"N", "R", "SR", "SSR"}
+	weights := []float64{60, 30, 9, 1}
+	const iterations = 200000
+
+	table, err := NewWeightedTable(items, weights)
+	assert.NoError(t, err)
+	assert.Equal(t, len(items), table.Len())
+
+	src := NewSeededSource(1)
+	pickers := map[string]func() string{
+		"WeightedChoice": func() string {
+			v, _ := WeightedChoiceWithSource(src, items, weights)
+			return v
+		},
+		"WeightedTable": func() string {
+			return table.PickWithSource(src)
+		},
+	}
+
+	for name, pick := range pickers {
+		t.Run(name, func(t *testing.T) {
+			counts := map[string]int{}
+			for i := 0; i < iterations; i++ {
+				counts[pick()]++
+			}
+			for i, item := range items {
+				want := weights[i] / 100
+				got := float64(counts[item]) / iterations
+				assert.InDelta(t, want, got, 0.01, "item %s", item)
+			}
+		})
+	}
+}
+
+func TestWeightedTable_zeroWeight(t *testing.T) {
+	table, err := NewWeightedTable([]string{"never", "always"}, []float64{0, 1})
+	assert.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, "always", table.Pick())
+	}
+}
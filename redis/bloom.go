@@ -0,0 +1,166 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Filter 要素が追加済みかを確率的に判定するフィルター
+// Exists が false の場合は確実に未追加で、true の場合は誤判定率の範囲で追加済みとなる。
+// 「既に受け取り済みか」を MySQL に問い合わせる前の事前確認に使用する。
+type Filter interface {
+	// Add 要素を追加する。新たに追加された（追加前は未追加と判定された）場合は true を返す
+	Add(ctx context.Context, item string) (bool, error)
+	// Exists 要素が追加済みかを返す
+	Exists(ctx context.Context, item string) (bool, error)
+}
+
+// NewBloomFilter 想定する要素数 capacity と誤判定率 errorRate を指定してブルームフィルターを生成する
+// RedisBloom モジュールが使用できる場合は BF.* コマンドを、使用できない場合はビットマップでの実装を使用する。
+func NewBloomFilter(ctx context.Context, rc *RedisClient, name string, capacity uint64, errorRate float64) (Filter, error) {
+	f, err := NewRedisBloomFilter(ctx, rc, name, capacity, errorRate)
+	if err == nil {
+		return f, nil
+	}
+	if !isUnknownCommand(err) {
+		return nil, err
+	}
+	return NewBitmapBloomFilter(rc, name, capacity, errorRate)
+}
+
+// redisBloomFilter RedisBloom モジュールのブルームフィルター
+type redisBloomFilter struct {
+	redis *RedisClient
+	key   string
+}
+
+// NewRedisBloomFilter RedisBloom モジュールのブルームフィルターを生成する
+// フィルターが存在しない場合は capacity と errorRate で作成する。
+func NewRedisBloomFilter(ctx context.Context, rc *RedisClient, name string, capacity uint64, errorRate float64) (Filter, error) {
+	if err := validateBloom(capacity, errorRate); err != nil {
+		return nil, err
+	}
+	key := bloomKey(name)
+	err := rc.client.BFReserve(ctx, key, errorRate, int64(capacity)).Err()
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "item exists") {
+		return nil, err
+	}
+	return &redisBloomFilter{redis: rc, key: key}, nil
+}
+
+func (f *redisBloomFilter) Add(ctx context.Context, item string) (bool, error) {
+	return f.redis.client.BFAdd(ctx, f.key, item).Result()
+}
+
+func (f *redisBloomFilter) Exists(ctx context.Context, item string) (bool, error) {
+	return f.redis.client.BFExists(ctx, f.key, item).Result()
+}
+
+// bitmapBloomFilter Redis のビットマップ（SETBIT / GETBIT）で実装したブルームフィルター
+type bitmapBloomFilter struct {
+	redis *RedisClient
+	key   string
+	// bits ビット数
+	bits uint64
+	// hashes 1要素あたりのハッシュ関数の数
+	hashes uint64
+}
+
+// NewBitmapBloomFilter ビットマップで実装したブルームフィルターを生成する
+// RedisBloom モジュールを使用できない環境向け。ビット数とハッシュ数は capacity と errorRate から算出する。
+func NewBitmapBloomFilter(rc *RedisClient, name string, capacity uint64, errorRate float64) (Filter, error) {
+	if err := validateBloom(capacity, errorRate); err != nil {
+		return nil, err
+	}
+	bits, hashes := bloomParams(capacity, errorRate)
+	return &bitmapBloomFilter{
+		redis:  rc,
+		key:    bloomKey(name),
+		bits:   bits,
+		hashes: hashes,
+	}, nil
+}
+
+func (f *bitmapBloomFilter) Add(ctx context.Context, item string) (bool, error) {
+	cmds, err := f.redis.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, offset := range f.offsets(item) {
+			p.SetBit(ctx, f.key, int64(offset), 1)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	// 1つでも 0 だったビットがあれば新たに追加された
+	for _, cmd := range cmds {
+		if cmd.(*redis.IntCmd).Val() == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *bitmapBloomFilter) Exists(ctx context.Context, item string) (bool, error) {
+	cmds, err := f.redis.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, offset := range f.offsets(item) {
+			p.GetBit(ctx, f.key, int64(offset))
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, cmd := range cmds {
+		if cmd.(*redis.IntCmd).Val() == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// offsets 要素のビット位置を二重ハッシュ法で算出する
+func (f *bitmapBloomFilter) offsets(item string) []uint64 {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(item))
+	sum := h.Sum(nil)
+
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[i+8])
+	}
+
+	offsets := make([]uint64, f.hashes)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % f.bits
+	}
+	return offsets
+}
+
+// bloomParams 要素数 n と誤判定率 p に対する最適なビット数とハッシュ数を返す
+func bloomParams(n uint64, p float64) (uint64, uint64) {
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	return uint64(m), uint64(max(k, 1))
+}
+
+func validateBloom(capacity uint64, errorRate float64) error {
+	if capacity == 0 || errorRate <= 0 || errorRate >= 1 {
+		return fmt.Errorf("bloom capacity %d error rate %v: %w", capacity, errorRate, ErrConfig)
+	}
+	return nil
+}
+
+func bloomKey(name string) string {
+	return fmt.Sprintf("bloom:%s", name)
+}
+
+// isUnknownCommand モジュールが読み込まれていないためのエラーかを返す
+func isUnknownCommand(err error) bool {
+	return strings.HasPrefix(strings.ToLower(err.Error()), "err unknown command")
+}
@@ -0,0 +1,102 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBloomFilter(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	_, _ = r.Del(ctx, bloomKey("test"))
+
+	// RedisBloom モジュールが無い環境ではビットマップの実装になる
+	f, err := NewBloomFilter(ctx, r, "test", 1000, 0.01)
+	assert.NoError(t, err)
+
+	added, err := f.Add(ctx, "player1:reward1")
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = f.Add(ctx, "player1:reward1")
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	ok, err := f.Exists(ctx, "player1:reward1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = f.Exists(ctx, "player2:reward1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = NewBloomFilter(ctx, r, "test", 0, 0.01)
+	assert.ErrorIs(t, err, ErrConfig)
+	_, err = NewBloomFilter(ctx, r, "test", 10, 1)
+	assert.ErrorIs(t, err, ErrConfig)
+}
+
+func TestBitmapBloomFilter_FalsePositiveRate(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	_, _ = r.Del(ctx, bloomKey("test-fp"))
+	f, err := NewBitmapBloomFilter(r, "test-fp", 500, 0.01)
+	assert.NoError(t, err)
+
+	for i := 0; i < 500; i++ {
+		_, err := f.Add(ctx, fmt.Sprintf("in:%d", i))
+		assert.NoError(t, err)
+	}
+	// 追加した要素は必ず存在する
+	for i := 0; i < 500; i++ {
+		ok, err := f.Exists(ctx, fmt.Sprintf("in:%d", i))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if ok, _ := f.Exists(ctx, fmt.Sprintf("out:%d", i)); ok {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 30)
+}
+
+func TestBloomParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          uint64
+		p          float64
+		wantBits   uint64
+		wantHashes uint64
+	}{
+		{name: "正常値: 1%", n: 1000, p: 0.01, wantBits: 9586, wantHashes: 7},
+		{name: "正常値: 0.1%", n: 1000, p: 0.001, wantBits: 14378, wantHashes: 10},
+		{name: "正常値: ハッシュは最低1つ", n: 1, p: 0.9, wantBits: 1, wantHashes: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bits, hashes := bloomParams(tt.n, tt.p)
+			assert.Equal(t, tt.wantBits, bits)
+			assert.Equal(t, tt.wantHashes, hashes)
+		})
+	}
+}
+
+func TestIsUnknownCommand(t *testing.T) {
+	assert.True(t, isUnknownCommand(errors.New("ERR unknown command 'BF.RESERVE'")))
+	assert.False(t, isUnknownCommand(errors.New("ERR item exists")))
+}
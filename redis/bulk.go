@@ -0,0 +1,164 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"valley-pkg/parser"
+)
+
+// BulkValue 一括取得したキーごとの結果
+type BulkValue[T any] struct {
+	Key   string
+	Value T
+	// Found キーが存在した場合に true
+	Found bool
+}
+
+// MGet keys の値を DefaultChunkSize 件ずつに分けて一括取得し、keys と同じ順で返す
+func (rc *RedisClient) MGet(ctx context.Context, keys ...string) ([]BulkValue[string], error) {
+	return rc.MGetChunked(ctx, DefaultChunkSize, keys)
+}
+
+// MGetChunked keys の値を chunkSize 件ずつに分けて1回の往復で取得し、keys と同じ順で返す
+// 存在しないキーは Found が false となる。chunkSize が 0 以下の場合は DefaultChunkSize を使用する。
+func (rc *RedisClient) MGetChunked(ctx context.Context, chunkSize int, keys []string) ([]BulkValue[string], error) {
+	results := make([]BulkValue[string], len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+	}
+	if len(keys) == 0 {
+		return results, nil
+	}
+
+	// Cluster 構成ではスロットをまたぐ MGET ができないため、キーごとの GET をパイプラインで送る
+	if _, ok := rc.client.(*redis.ClusterClient); ok {
+		cmds, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+			for _, key := range keys {
+				p.Get(ctx, key)
+			}
+			return nil
+		})
+		if err != nil && ignoreNil(err) != nil {
+			return nil, err
+		}
+		for i, cmd := range cmds {
+			v, err := cmd.(*redis.StringCmd).Result()
+			if ignoreNil(err) != nil {
+				return nil, err
+			}
+			results[i].Value, results[i].Found = v, err == nil
+		}
+		return results, nil
+	}
+
+	cmds, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, chunk := range chunkStrings(keys, chunkSize) {
+			p.MGet(ctx, chunk...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	i := 0
+	for _, cmd := range cmds {
+		for _, v := range cmd.(*redis.SliceCmd).Val() {
+			if s, ok := v.(string); ok {
+				results[i].Value, results[i].Found = s, true
+			}
+			i++
+		}
+	}
+	return results, nil
+}
+
+// MSet values を DefaultChunkSize 件ずつに分けて一括保存する
+func (rc *RedisClient) MSet(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	return rc.MSetChunked(ctx, DefaultChunkSize, values, ttl)
+}
+
+// MSetChunked values を chunkSize 件ずつに分けて1回の往復で保存する
+// ttl が 0 の場合は有効期限を設定しない。chunkSize が 0 以下の場合は DefaultChunkSize を使用する。
+func (rc *RedisClient) MSetChunked(ctx context.Context, chunkSize int, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	_, cluster := rc.client.(*redis.ClusterClient)
+
+	_, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+		// MSET は有効期限を指定できず、Cluster ではスロットをまたげないため、その場合はキーごとの SET を送る
+		if ttl > 0 || cluster {
+			for key, v := range values {
+				p.Set(ctx, key, v, ttl)
+			}
+			return nil
+		}
+
+		args := make([]interface{}, 0, 2*min(chunkSize, len(values)))
+		for key, v := range values {
+			args = append(args, key, v)
+			if len(args) == 2*chunkSize {
+				p.MSet(ctx, args...)
+				args = make([]interface{}, 0, cap(args))
+			}
+		}
+		if len(args) > 0 {
+			p.MSet(ctx, args...)
+		}
+		return nil
+	})
+	return err
+}
+
+// MGetJSON keys に保存されたJSONを一括取得して型 T に変換し、keys と同じ順で返す
+func MGetJSON[T any](ctx context.Context, rc *RedisClient, keys ...string) ([]BulkValue[T], error) {
+	raw, err := rc.MGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkValue[T], len(raw))
+	for i, r := range raw {
+		results[i] = BulkValue[T]{Key: r.Key, Found: r.Found}
+		if !r.Found {
+			continue
+		}
+		if results[i].Value, err = parser.Unmarshal[T](jsonParser, []byte(r.Value)); err != nil {
+			return nil, fmt.Errorf("unmarshal %s: %w", r.Key, err)
+		}
+	}
+	return results, nil
+}
+
+// MSetJSON values をJSONに変換して一括保存する
+func MSetJSON[T any](ctx context.Context, rc *RedisClient, values map[string]T, ttl time.Duration) error {
+	encoded := make(map[string]interface{}, len(values))
+	for key, v := range values {
+		b, err := parser.Marshal(jsonParser, v)
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", key, err)
+		}
+		encoded[key] = b
+	}
+	return rc.MSet(ctx, encoded, ttl)
+}
+
+// chunkStrings keys を size 件ずつに分割する
+func chunkStrings(keys []string, size int) [][]string {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	var result [][]string
+	for start := 0; start < len(keys); start += size {
+		result = append(result, keys[start:min(start+size, len(keys))])
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_MGetMSet(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	values := map[string]interface{}{}
+	var keys []string
+	for i := 0; i < 7; i++ {
+		key := fmt.Sprintf("test-bulk:%d", i)
+		keys = append(keys, key)
+		if i%2 == 0 {
+			values[key] = fmt.Sprint(i)
+		}
+	}
+	_, _ = r.Del(ctx, keys...)
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{name: "正常値: MSET"},
+		{name: "正常値: 有効期限付き", ttl: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, r.MSetChunked(ctx, 2, values, tt.ttl))
+
+			got, err := r.MGetChunked(ctx, 3, keys)
+			assert.NoError(t, err)
+			assert.Len(t, got, len(keys))
+			for i, v := range got {
+				assert.Equal(t, keys[i], v.Key)
+				assert.Equal(t, i%2 == 0, v.Found, v.Key)
+				if v.Found {
+					assert.Equal(t, fmt.Sprint(i), v.Value)
+				}
+			}
+
+			ttl, err := r.TTL(ctx, keys[0])
+			assert.NoError(t, err)
+			if tt.ttl > 0 {
+				assert.Greater(t, ttl, time.Duration(0))
+			} else {
+				assert.Equal(t, NoExpiry, ttl)
+			}
+		})
+	}
+
+	got, err := r.MGet(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	assert.NoError(t, r.MSet(ctx, nil, 0))
+}
+
+func TestMGetJSON(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	_, _ = r.Del(ctx, "test-bulk-json:missing")
+	assert.NoError(t, MSetJSON(ctx, r, map[string]testUser{
+		"test-bulk-json:1": {ID: 1, Name: "a"},
+		"test-bulk-json:2": {ID: 2, Name: "b"},
+	}, time.Minute))
+
+	got, err := MGetJSON[testUser](ctx, r, "test-bulk-json:2", "test-bulk-json:missing", "test-bulk-json:1")
+	assert.NoError(t, err)
+	assert.Equal(t, []BulkValue[testUser]{
+		{Key: "test-bulk-json:2", Value: testUser{ID: 2, Name: "b"}, Found: true},
+		{Key: "test-bulk-json:missing"},
+		{Key: "test-bulk-json:1", Value: testUser{ID: 1, Name: "a"}, Found: true},
+	}, got)
+}
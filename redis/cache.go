@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"valley-pkg/parser"
+)
+
+// キャッシュの既定値
+const (
+	// DefaultCacheLocalSize プロセス内に保持するエントリ数の既定値
+	DefaultCacheLocalSize = 1024
+	// DefaultCacheLocalTTL プロセス内に保持する期間の既定値
+	DefaultCacheLocalTTL = 10 * time.Second
+)
+
+// Cache プロセス内の LRU と Redis を重ねた2段のキャッシュ
+// 読み込みはプロセス内、Redis の順に探し、どちらにも無い場合は GetOrLoad のローダーで読み込む。
+// 同じキーの同時の読み込みは singleflight で1回にまとめる。
+// プロセス内の値は他のプロセスでの Set / Delete では無効化されないため、
+// ローカルの保持期間は古い値を許容できる長さにすること。
+type Cache struct {
+	redis    *RedisClient
+	prefix   string
+	local    *lru
+	localTTL time.Duration
+	group    singleflight.Group
+}
+
+// CacheOption Cache の設定
+type CacheOption func(*Cache)
+
+// WithLocalCache プロセス内に保持するエントリ数と期間を指定する
+// size が 0 以下の場合はプロセス内に保持しない。
+func WithLocalCache(size int, ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.local = newLRU(size)
+		c.localTTL = ttl
+	}
+}
+
+// NewCache キャッシュ名を指定してキャッシュを生成
+func NewCache(rc *RedisClient, name string, opts ...CacheOption) *Cache {
+	c := &Cache{
+		redis:    rc,
+		prefix:   fmt.Sprintf("cache:%s:", name),
+		local:    newLRU(DefaultCacheLocalSize),
+		localTTL: DefaultCacheLocalTTL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get キーの値を返す。プロセス内にも Redis にも無い場合は ErrNotFound を返す
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	if b, ok := c.local.get(key); ok {
+		return b, nil
+	}
+
+	redisKey := c.prefix + key
+	b, err := c.redis.client.Get(ctx, redisKey).Bytes()
+	if err != nil {
+		return nil, notFound(redisKey, err)
+	}
+
+	// Redis の残り期間より長くプロセス内に保持しない
+	localTTL := c.localTTL
+	if ttl, err := c.redis.client.PTTL(ctx, redisKey).Result(); err == nil && ttl > 0 && ttl < localTTL {
+		localTTL = ttl
+	}
+	c.local.set(key, b, localTTL)
+	return b, nil
+}
+
+// Set キーに値を ttl で保存する。ttl が 0 の場合は Redis 上の有効期限を設定しない
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.redis.client.Set(ctx, c.prefix+key, value, ttl).Err(); err != nil {
+		return err
+	}
+	c.local.set(key, value, c.localExpiry(ttl))
+	return nil
+}
+
+// Delete キーを削除する
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		c.local.delete(key)
+		redisKeys[i] = c.prefix + key
+	}
+	return c.redis.client.Del(ctx, redisKeys...).Err()
+}
+
+// GetOrLoad キーの値を返し、無い場合は loader で読み込んで ttl で保存する（cache-aside）
+// 同じキーの読み込みが同時に発生した場合、loader は1回だけ呼び出され結果を共有する。
+// loader は呼び出し元の ctx がキャンセルされても他の待ち手のために実行を続ける。
+func (c *Cache) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	if b, ok := c.local.get(key); ok {
+		return b, nil
+	}
+
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		b, err := c.Get(loadCtx, key)
+		if err == nil {
+			return b, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		b, err = loader(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Set(loadCtx, key, b, ttl); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// 同時に待っていた呼び出し元で同じスライスを共有しないように複製する
+		return bytes.Clone(res.Val.([]byte)), nil
+	}
+}
+
+// localExpiry Redis の有効期限 ttl に対するプロセス内の保持期間を返す
+func (c *Cache) localExpiry(ttl time.Duration) time.Duration {
+	if ttl > 0 && ttl < c.localTTL {
+		return ttl
+	}
+	return c.localTTL
+}
+
+// GetOrLoadJSON GetOrLoad の型付き版。値はJSONで保存する
+func GetOrLoadJSON[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	b, err := c.GetOrLoad(ctx, key, ttl, func(ctx context.Context) ([]byte, error) {
+		v, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return parser.Marshal(jsonParser, v)
+	})
+	if err != nil {
+		return zero, err
+	}
+	return parser.Unmarshal[T](jsonParser, b)
+}
+
+// lru 有効期限付きの LRU
+// size が 0 以下の場合は何も保持しない。
+// 呼び出し元が返した値を書き換えても他の読み込みに影響しないよう、値は文字列として保持し取り出すたびに複製する。
+type lru struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key      string
+	value    string
+	expireAt time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (l *lru) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if time.Now().After(entry.expireAt) {
+		l.ll.Remove(e)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.ll.MoveToFront(e)
+	return []byte(entry.value), true
+}
+
+func (l *lru) set(key string, value []byte, ttl time.Duration) {
+	if l.size <= 0 || ttl <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expireAt := time.Now().Add(ttl)
+	if e, ok := l.items[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value, entry.expireAt = string(value), expireAt
+		l.ll.MoveToFront(e)
+		return
+	}
+
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: string(value), expireAt: expireAt})
+	if l.ll.Len() > l.size {
+		oldest := l.ll.Back()
+		l.ll.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lru) delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.items[key]; ok {
+		l.ll.Remove(e)
+		delete(l.items, key)
+	}
+}
+
+func (l *lru) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
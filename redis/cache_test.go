@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	c := NewCache(r, "test")
+	_ = c.Delete(ctx, "a")
+
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	got, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), got)
+
+	// 別プロセスのキャッシュは Redis から読み込む
+	other := NewCache(r, "test")
+	got, err = other.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), got)
+	assert.Equal(t, 1, other.local.len())
+
+	assert.NoError(t, c.Delete(ctx, "a"))
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	c := NewCache(r, "test-load")
+	_ = c.Delete(ctx, "k")
+
+	// 同時の読み込みは1回にまとめられる
+	var calls int32
+	loader := func(context.Context) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		return []byte("loaded"), nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := c.GetOrLoad(ctx, "k", time.Minute, loader)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("loaded"), got)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 保存済みの値は loader を呼ばない
+	_, err = c.GetOrLoad(ctx, "k", time.Minute, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// loader のエラーは保存しない
+	errLoad := errors.New("load failed")
+	_, err = c.GetOrLoad(ctx, "missing", time.Minute, func(context.Context) ([]byte, error) {
+		return nil, errLoad
+	})
+	assert.ErrorIs(t, err, errLoad)
+	_, err = c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	type item struct {
+		Name string `json:"name"`
+	}
+	_ = c.Delete(ctx, "json")
+	v, err := GetOrLoadJSON(ctx, c, "json", time.Minute, func(context.Context) (item, error) {
+		return item{Name: "valley"}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, item{Name: "valley"}, v)
+}
+
+func TestLRU(t *testing.T) {
+	l := newLRU(2)
+	l.set("a", []byte("1"), time.Minute)
+	l.set("b", []byte("2"), time.Minute)
+	_, _ = l.get("a")
+	// 最も古く使われた b が追い出される
+	l.set("c", []byte("3"), time.Minute)
+	_, ok := l.get("b")
+	assert.False(t, ok)
+	_, ok = l.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, l.len())
+
+	// 期限切れは返さない
+	l.set("d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = l.get("d")
+	assert.False(t, ok)
+
+	// 保存した値や返した値を書き換えても保持している値は変わらない
+	v := []byte("5")
+	l.set("e", v, time.Minute)
+	v[0] = 'x'
+	got, _ := l.get("e")
+	got[0] = 'y'
+	got, _ = l.get("e")
+	assert.Equal(t, []byte("5"), got)
+
+	// size 0 は保持しない
+	none := newLRU(0)
+	none.set("a", []byte("1"), time.Minute)
+	assert.Equal(t, 0, none.len())
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Incr 値を1増やし、増やした後の値を返す。キーが存在しない場合は 0 から数える
+func (rc *RedisClient) Incr(ctx context.Context, key string) (int64, error) {
+	return rc.client.Incr(ctx, key).Result()
+}
+
+// IncrBy 値を n 増やし、増やした後の値を返す
+func (rc *RedisClient) IncrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return rc.client.IncrBy(ctx, key, n).Result()
+}
+
+// DecrBy 値を n 減らし、減らした後の値を返す
+func (rc *RedisClient) DecrBy(ctx context.Context, key string, n int64) (int64, error) {
+	return rc.client.DecrBy(ctx, key, n).Result()
+}
+
+// IncrByFloat 値を f 増やし、増やした後の値を返す
+func (rc *RedisClient) IncrByFloat(ctx context.Context, key string, f float64) (float64, error) {
+	return rc.client.IncrByFloat(ctx, key, f).Result()
+}
+
+// boundedIncrScript 加算後の値が範囲内の場合のみ加算する
+// 戻り値は {加算できたか(1/0), 現在の値}。
+const boundedIncrScript = `
+        local current = tonumber(redis.call("get", KEYS[1]) or ARGV[4])
+        local next = current + tonumber(ARGV[1])
+        if next > tonumber(ARGV[2]) or next < tonumber(ARGV[3]) then
+            return {0, current}
+        end
+        redis.call("set", KEYS[1], next, "KEEPTTL")
+        if tonumber(ARGV[5]) > 0 and redis.call("pttl", KEYS[1]) < 0 then
+            redis.call("pexpire", KEYS[1], ARGV[5])
+        end
+        return {1, next}
+    `
+
+// BoundedCounter 上限と下限を持つカウンター
+// 範囲の確認と加算を Lua でアトミックに行うため、同時に加算しても範囲を超えない。
+// 回数制限（上限）や在庫の引き当て（下限 0 で減算）に使用する。
+type BoundedCounter struct {
+	redis *RedisClient
+	key   string
+	min   int64
+	max   int64
+	// initial キーが存在しない場合の値
+	initial int64
+	// ttl キーを作成した際に設定する有効期限。0 の場合は設定しない
+	ttl time.Duration
+}
+
+// NewBoundedCounter 名前と範囲を指定して生成する
+// キーが存在しない場合は initial から数え、作成時に ttl を設定する（0 の場合は設定しない）。
+func NewBoundedCounter(rc *RedisClient, name string, min, max, initial int64, ttl time.Duration) *BoundedCounter {
+	return &BoundedCounter{
+		redis:   rc,
+		key:     fmt.Sprintf("counter:%s", name),
+		min:     min,
+		max:     max,
+		initial: initial,
+		ttl:     ttl,
+	}
+}
+
+// Add n を加算し、加算後の値を返す（n が負の場合は減算）
+// 加算後の値が範囲外となる場合は加算せず、現在の値と false を返す。
+func (c *BoundedCounter) Add(ctx context.Context, n int64) (int64, bool, error) {
+	result, err := c.redis.client.Eval(ctx, boundedIncrScript, []string{c.key}, n, c.max, c.min, c.initial, c.ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	return result[1], result[0] == 1, nil
+}
+
+// Get 現在の値を返す。キーが存在しない場合は initial を返す
+func (c *BoundedCounter) Get(ctx context.Context) (int64, error) {
+	v, err := c.redis.client.Get(ctx, c.key).Int64()
+	if err != nil {
+		if ignoreNil(err) == nil {
+			return c.initial, nil
+		}
+		return 0, err
+	}
+	return v, nil
+}
+
+// Reset カウンターを削除し、initial に戻す
+func (c *BoundedCounter) Reset(ctx context.Context) error {
+	return c.redis.client.Del(ctx, c.key).Err()
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Counter(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-counter"
+	_, _ = r.Del(ctx, key, key+":f")
+
+	n, err := r.Incr(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = r.IncrBy(ctx, key, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+	n, err = r.DecrBy(ctx, key, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), n)
+	f, err := r.IncrByFloat(ctx, key+":f", 1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+}
+
+func TestBoundedCounter(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		initial int64
+		add     []int64
+		want    int64
+		wantOK  []bool
+	}{
+		{name: "正常値: 上限まで加算", initial: 0, add: []int64{2, 3}, want: 5, wantOK: []bool{true, true}},
+		{name: "異常値: 上限を超える加算", initial: 0, add: []int64{4, 2}, want: 4, wantOK: []bool{true, false}},
+		{name: "正常値: 在庫の減算", initial: 3, add: []int64{-1, -2}, want: 0, wantOK: []bool{true, true}},
+		{name: "異常値: 在庫不足", initial: 1, add: []int64{-2, -1}, want: 0, wantOK: []bool{false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewBoundedCounter(r, "test-bounded", 0, 5, tt.initial, time.Minute)
+			assert.NoError(t, c.Reset(ctx))
+
+			for i, n := range tt.add {
+				_, ok, err := c.Add(ctx, n)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantOK[i], ok)
+			}
+			got, err := c.Get(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBoundedCounter_Concurrent(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	c := NewBoundedCounter(r, "test-bounded-concurrent", 0, 100, 0, time.Minute)
+	assert.NoError(t, c.Reset(ctx))
+
+	// 同時に加算しても上限を超えない
+	var ok int32
+	var wg sync.WaitGroup
+	for i := 0; i < 150; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, added, err := c.Add(ctx, 1); err == nil && added {
+				atomic.AddInt32(&ok, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(100), ok)
+
+	got, err := c.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), got)
+	ttl, err := r.TTL(ctx, c.key)
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
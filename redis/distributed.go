@@ -1,48 +1,276 @@
 package redis
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"sync"
 	"time"
+	"valley-pkg/backoff"
 )
 
+// DefaultLockExpiry ロックの有効期限の既定値
+const DefaultLockExpiry = 30 * time.Second
+
+// ErrLockNotOwned 自分が保持していないロックを操作しようとした場合のエラー
+var ErrLockNotOwned = errors.New("lock not owned")
+
+// releaseScript 自分が保持しているロックのみ削除する
+const releaseScript = `
+        if redis.call("get", KEYS[1]) == ARGV[1] then
+            return redis.call("del", KEYS[1])
+        else
+            return 0
+        end
+    `
+
+// extendScript 自分が保持しているロックのみ有効期限を延長する
+const extendScript = `
+        if redis.call("get", KEYS[1]) == ARGV[1] then
+            return redis.call("pexpire", KEYS[1], ARGV[2])
+        else
+            return 0
+        end
+    `
+
 type DistributedLock struct {
 	redis  *RedisClient
 	key    string
 	value  string
 	expiry time.Duration
+	// renewInterval 0 より大きい場合、取得中は renewInterval ごとに有効期限を延長する
+	renewInterval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+	lost chan struct{}
+}
+
+// LockOption DistributedLock の設定
+type LockOption func(*DistributedLock)
+
+// WithLockExpiry ロックの有効期限を指定する
+func WithLockExpiry(expiry time.Duration) LockOption {
+	return func(dl *DistributedLock) {
+		dl.expiry = expiry
+	}
 }
 
-func NewDistributedLock(rc *RedisClient, key string) *DistributedLock {
-	return &DistributedLock{
+// WithAutoRenew 取得中は interval ごとに有効期限を延長する
+// interval が 0 以下の場合は有効期限の 1/3 を使用する。処理が有効期限より長く掛かってもロックを失わない。
+func WithAutoRenew(interval time.Duration) LockOption {
+	return func(dl *DistributedLock) {
+		if interval <= 0 {
+			interval = -1
+		}
+		dl.renewInterval = interval
+	}
+}
+
+func NewDistributedLock(rc *RedisClient, key string, opts ...LockOption) *DistributedLock {
+	dl := &DistributedLock{
 		redis:  rc,
 		key:    fmt.Sprintf("lock:%s", key),
 		value:  uuid.New().String(),
-		expiry: 30 * time.Second,
+		expiry: DefaultLockExpiry,
+		lost:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(dl)
 	}
+	if dl.renewInterval < 0 {
+		dl.renewInterval = dl.expiry / 3
+	}
+	return dl
 }
 
-// Acquire ロックの取得
+// Acquire ロックの取得。生成時の context を使用する
 func (dl *DistributedLock) Acquire() (bool, error) {
-	return dl.redis.client.SetNX(dl.redis.ctx, dl.key, dl.value, dl.expiry).Result()
+	return dl.AcquireContext(dl.redis.ctx)
 }
 
-// Release　ロックの解放（自分が取得したロックのみ解放可能）1回のコマンド実行で「Get」と「Del」が実行されるので割り込みが発生しない。
+// AcquireContext ロックの取得
+// 自動延長が有効な場合は、取得に成功すると Release まで有効期限を延長し続ける。
+func (dl *DistributedLock) AcquireContext(ctx context.Context) (bool, error) {
+	ok, err := dl.redis.client.SetNX(ctx, dl.key, dl.value, dl.expiry).Result()
+	if err != nil || !ok {
+		return ok, err
+	}
+	dl.startWatchdog(ctx)
+	return true, nil
+}
+
+// Release ロックの解放。生成時の context を使用する
 func (dl *DistributedLock) Release() error {
+	return dl.ReleaseContext(dl.redis.ctx)
+}
+
+// ReleaseContext ロックの解放（自分が取得したロックのみ解放可能）1回のコマンド実行で「Get」と「Del」が実行されるので割り込みが発生しない。
+func (dl *DistributedLock) ReleaseContext(ctx context.Context) error {
+	dl.stopWatchdog()
+
 	// Luaスクリプトを使用して、アトミックに確認と削除を行う
-	script := `
-        if redis.call("get", KEYS[1]) == ARGV[1] then
-            return redis.call("del", KEYS[1])
-        else
-            return 0
-        end
-    `
-	result, err := dl.redis.client.Eval(dl.redis.ctx, script, []string{dl.key}, dl.value).Result()
+	result, err := dl.redis.client.Eval(ctx, releaseScript, []string{dl.key}, dl.value).Result()
+	if err != nil {
+		return err
+	}
+	if result.(int64) == 0 {
+		return ErrLockNotOwned
+	}
+	return nil
+}
+
+// Extend 自分が保持しているロックの有効期限を ttl に延長する
+// ロックを保持していない場合は ErrLockNotOwned を返す。
+func (dl *DistributedLock) Extend(ctx context.Context, ttl time.Duration) error {
+	result, err := dl.redis.client.Eval(ctx, extendScript, []string{dl.key}, dl.value, ttl.Milliseconds()).Result()
 	if err != nil {
 		return err
 	}
 	if result.(int64) == 0 {
-		return fmt.Errorf("lock not owned")
+		return ErrLockNotOwned
 	}
 	return nil
 }
+
+// LostLock 自動延長中にロックを失った場合にクローズされるチャネルを返す
+// 他のクライアントに奪われた場合や、Redis に接続できないまま有効期限を過ぎた場合に通知される。
+// 取得し直すと新しいチャネルに置き換わるため、取得後に呼び出すこと。
+func (dl *DistributedLock) LostLock() <-chan struct{} {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+	return dl.lost
+}
+
+// startWatchdog 有効期限を延長し続けるゴルーチンを起動する
+func (dl *DistributedLock) startWatchdog(ctx context.Context) {
+	if dl.renewInterval <= 0 {
+		return
+	}
+	dl.stopWatchdog()
+
+	dl.mu.Lock()
+	stop, done, lost := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	dl.stop, dl.done, dl.lost = stop, done, lost
+	dl.mu.Unlock()
+
+	// 取得時の context がキャンセルされても延長を続ける
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(dl.renewInterval)
+		defer ticker.Stop()
+
+		renewed := time.Now()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			err := dl.Extend(ctx, dl.expiry)
+			switch {
+			case err == nil:
+				renewed = time.Now()
+			case errors.Is(err, ErrLockNotOwned), time.Since(renewed) >= dl.expiry:
+				close(lost)
+				return
+			}
+		}
+	}()
+}
+
+// stopWatchdog 延長のゴルーチンを停止し、終了を待つ
+func (dl *DistributedLock) stopWatchdog() {
+	dl.mu.Lock()
+	stop, done := dl.stop, dl.done
+	dl.stop, dl.done = nil, nil
+	dl.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// ロック取得のポーリング間隔
+const (
+	lockRetryInitialInterval = 50 * time.Millisecond
+	lockRetryMaxInterval     = time.Second
+)
+
+// ErrLockNotAcquired 待機時間内にロックを取得できなかった場合のエラー
+var ErrLockNotAcquired = errors.New("lock not acquired")
+
+// errLockBusy 他の保持者がいるため再試行する
+var errLockBusy = errors.New("lock busy")
+
+// AcquireWithRetry ロックを取得できるまで、ゆらぎを持たせた指数バックオフで最大 maxWait 待つ
+// maxWait が 0 以下の場合は ctx の終了まで待つ。
+// 待機時間内に取得できなかった場合は false と nil、ctx がキャンセルされた場合は ctx のエラーを返す。
+func (dl *DistributedLock) AcquireWithRetry(ctx context.Context, maxWait time.Duration) (bool, error) {
+	waitCtx, cancel := ctx, context.CancelFunc(func() {})
+	if maxWait > 0 {
+		waitCtx, cancel = context.WithTimeout(ctx, maxWait)
+	}
+	defer cancel()
+
+	b := backoff.NewBackoffWithInterval[any](waitCtx, lockRetryInitialInterval, lockRetryMaxInterval, 0.5, 1.5, 0)
+	b.SetDoOperation(func() (any, error) {
+		ok, err := dl.AcquireContext(ctx)
+		if err != nil {
+			// Redis のエラーは待っても解消しないものとして即座に返す
+			return nil, backoff.Permanent(err)
+		}
+		if !ok {
+			return nil, errLockBusy
+		}
+		return nil, nil
+	})
+
+	err := b.Run()
+	switch {
+	case err == nil:
+		return true, nil
+	case ctx.Err() != nil:
+		return false, ctx.Err()
+	case errors.Is(err, errLockBusy), waitCtx.Err() != nil:
+		return false, nil
+	}
+	return false, err
+}
+
+// WithLock ロックを取得してから fn を実行し、fn が終了すると（panic した場合も）ロックを解放する
+// ロックは ctx の終了まで待って取得し、取得できないまま ctx が終了した場合は ctx のエラーを返す。
+// 自動延長中にロックを失った場合は fn に渡す context がキャンセルされる。
+func (dl *DistributedLock) WithLock(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ok, err := dl.AcquireWithRetry(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", dl.key, ErrLockNotAcquired)
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-dl.LostLock():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+	defer func() {
+		cancel()
+		// ctx がキャンセルされていても解放する
+		if releaseErr := dl.ReleaseContext(context.WithoutCancel(ctx)); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	return fn(fnCtx)
+}
@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistributedLock_AutoRenew(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lock := NewDistributedLock(r, "test-renew-lock", WithLockExpiry(300*time.Millisecond), WithAutoRenew(50*time.Millisecond))
+	ok, err := lock.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 有効期限を過ぎても延長され続けている
+	time.Sleep(600 * time.Millisecond)
+	other := NewDistributedLock(r, "test-renew-lock")
+	ok, err = other.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	select {
+	case <-lock.LostLock():
+		t.Fatal("lock lost while renewing")
+	default:
+	}
+
+	assert.NoError(t, lock.ReleaseContext(ctx))
+	ok, err = other.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, other.ReleaseContext(ctx))
+}
+
+func TestDistributedLock_LostLock(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lock := NewDistributedLock(r, "test-lost-lock", WithLockExpiry(time.Second), WithAutoRenew(30*time.Millisecond))
+	ok, err := lock.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 他のクライアントにロックを奪われると通知される
+	assert.NoError(t, r.SetContext(ctx, lock.key, "other", time.Second))
+	select {
+	case <-lock.LostLock():
+	case <-time.After(time.Second):
+		t.Fatal("lost lock was not notified")
+	}
+	assert.ErrorIs(t, lock.ReleaseContext(ctx), ErrLockNotOwned)
+	_, _ = r.Del(ctx, lock.key)
+}
+
+func TestDistributedLock_Extend(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lock := NewDistributedLock(r, "test-extend-lock", WithLockExpiry(time.Second))
+	assert.ErrorIs(t, lock.Extend(ctx, time.Minute), ErrLockNotOwned)
+
+	ok, err := lock.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, lock.Extend(ctx, time.Minute))
+	ttl, err := r.TTL(ctx, lock.key)
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, 59*time.Second)
+	assert.NoError(t, lock.ReleaseContext(ctx))
+}
+
+func TestDistributedLock_AcquireWithRetry(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	holder := NewDistributedLock(r, "test-retry-lock", WithLockExpiry(time.Second))
+	ok, err := holder.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 保持者がいる間は待機時間を過ぎると false
+	waiter := NewDistributedLock(r, "test-retry-lock")
+	ok, err = waiter.AcquireWithRetry(ctx, 150*time.Millisecond)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// 待機中に解放されると取得できる
+	time.AfterFunc(100*time.Millisecond, func() { _ = holder.ReleaseContext(ctx) })
+	ok, err = waiter.AcquireWithRetry(ctx, 2*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// ctx がキャンセルされた場合はエラー
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	ok, err = holder.AcquireWithRetry(canceled, time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, ok)
+
+	assert.NoError(t, waiter.ReleaseContext(ctx))
+}
+
+func TestDistributedLock_WithLock(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lock := NewDistributedLock(r, "test-with-lock")
+
+	called := false
+	err = lock.WithLock(ctx, func(ctx context.Context) error {
+		called = true
+		n, err := r.Exists(ctx, lock.key)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	// panic した場合も解放される
+	assert.Panics(t, func() {
+		_ = lock.WithLock(ctx, func(context.Context) error {
+			panic("boom")
+		})
+	})
+	n, err := r.Exists(ctx, lock.key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	// 取得できない場合は fn を呼ばない
+	holder := NewDistributedLock(r, "test-with-lock")
+	ok, err := holder.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = lock.WithLock(timeout, func(context.Context) error {
+		t.Fatal("fn must not be called")
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoError(t, holder.ReleaseContext(ctx))
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GeoLocation 位置情報付きのメンバー
+// GeoSearch の結果では Dist に検索の中心からの距離が入る。
+type GeoLocation = redis.GeoLocation
+
+// GeoSearchQuery 位置の検索条件
+type GeoSearchQuery = redis.GeoSearchQuery
+
+// GeoAdd メンバーの位置を登録し、新規に追加された件数を返す
+func (rc *RedisClient) GeoAdd(ctx context.Context, key string, locations ...*GeoLocation) (int64, error) {
+	return rc.client.GeoAdd(ctx, key, locations...).Result()
+}
+
+// GeoPos メンバーの位置を members と同じ順で返す。存在しないメンバーは nil となる
+func (rc *RedisClient) GeoPos(ctx context.Context, key string, members ...string) ([]*redis.GeoPos, error) {
+	return rc.client.GeoPos(ctx, key, members...).Result()
+}
+
+// GeoDist 2つのメンバー間の距離をメートルで返す
+// いずれかのメンバーが存在しない場合は ErrNotFound を返す。
+func (rc *RedisClient) GeoDist(ctx context.Context, key, member1, member2 string) (float64, error) {
+	dist, err := rc.client.GeoDist(ctx, key, member1, member2, "m").Result()
+	if err != nil {
+		return 0, notFound(key, err)
+	}
+	return dist, nil
+}
+
+// GeoSearch 条件に一致するメンバーを距離と座標付きで返す
+func (rc *RedisClient) GeoSearch(ctx context.Context, key string, q *GeoSearchQuery) ([]GeoLocation, error) {
+	return rc.client.GeoSearchLocation(ctx, key, &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: *q,
+		WithCoord:      true,
+		WithDist:       true,
+	}).Result()
+}
+
+// NearbyEntities 経度 lon・緯度 lat から半径 radius メートル以内のメンバーを近い順に最大 limit 件返す
+// limit が 0 以下の場合は件数を制限しない。
+func (rc *RedisClient) NearbyEntities(ctx context.Context, key string, lon, lat, radius float64, limit int) ([]GeoLocation, error) {
+	return rc.GeoSearch(ctx, key, &GeoSearchQuery{
+		Longitude:  lon,
+		Latitude:   lat,
+		Radius:     radius,
+		RadiusUnit: "m",
+		Sort:       "ASC",
+		Count:      max(limit, 0),
+	})
+}
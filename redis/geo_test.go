@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Geo(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-geo"
+	_, _ = r.Del(ctx, key)
+
+	n, err := r.GeoAdd(ctx, key,
+		&GeoLocation{Name: "tokyo", Longitude: 139.7671, Latitude: 35.6812},
+		&GeoLocation{Name: "yokohama", Longitude: 139.6222, Latitude: 35.4657},
+		&GeoLocation{Name: "osaka", Longitude: 135.4959, Latitude: 34.7024},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	dist, err := r.GeoDist(ctx, key, "tokyo", "yokohama")
+	assert.NoError(t, err)
+	assert.InDelta(t, 27000, dist, 1000)
+	_, err = r.GeoDist(ctx, key, "tokyo", "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	pos, err := r.GeoPos(ctx, key, "osaka", "missing")
+	assert.NoError(t, err)
+	assert.InDelta(t, 135.4959, pos[0].Longitude, 0.001)
+	assert.Nil(t, pos[1])
+
+	tests := []struct {
+		name   string
+		radius float64
+		limit  int
+		want   []string
+	}{
+		{name: "正常値: 近い順", radius: 50000, want: []string{"tokyo", "yokohama"}},
+		{name: "正常値: 件数制限", radius: 1000000, limit: 2, want: []string{"tokyo", "yokohama"}},
+		{name: "正常値: 全件", radius: 1000000, want: []string{"tokyo", "yokohama", "osaka"}},
+		{name: "正常値: 範囲内に無い", radius: 100, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 東京駅から少し離れた地点を中心とする
+			got, err := r.NearbyEntities(ctx, key, 139.7700, 35.6850, tt.radius, tt.limit)
+			assert.NoError(t, err)
+			names := []string{}
+			for _, loc := range got {
+				names = append(names, loc.Name)
+			}
+			assert.Equal(t, tt.want, names)
+			if len(got) > 1 {
+				assert.LessOrEqual(t, got[0].Dist, got[1].Dist)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package redis
+
+import (
+	"context"
+
+	"valley-pkg/health"
+)
+
+// HealthChecker Redis に PING を送る health.Checker を返す
+func (rc *RedisClient) HealthChecker() health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		return rc.client.Ping(ctx).Err()
+	})
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_HyperLogLog(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	_, _ = r.Del(ctx, "test-hll-a", "test-hll-b", "test-hll-ab")
+
+	changed, err := r.PFAdd(ctx, "test-hll-a", "x", "y", "z")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = r.PFAdd(ctx, "test-hll-a", "x")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	_, err = r.PFAdd(ctx, "test-hll-b", "z", "w")
+	assert.NoError(t, err)
+
+	n, err := r.PFCount(ctx, "test-hll-a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	n, err = r.PFCount(ctx, "test-hll-a", "test-hll-b")
+	assert.NoError(t, err)
+	assert.InDelta(t, 4, n, 1)
+
+	assert.NoError(t, r.PFMerge(ctx, "test-hll-ab", "test-hll-a", "test-hll-b"))
+	n, err = r.PFCount(ctx, "test-hll-ab")
+	assert.NoError(t, err)
+	assert.InDelta(t, 4, n, 1)
+}
+
+func TestDailyUniqueCounter_Key(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	c := NewDailyUniqueCounter(nil, "test-dau", jst, 48*time.Hour)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{name: "正常値: 名前をハッシュタグにする", t: time.Date(2024, 1, 1, 23, 0, 0, 0, jst), want: "uniq:{test-dau}:20240101"},
+		{name: "正常値: UTC では同じ日だが JST では翌日", t: time.Date(2024, 1, 1, 16, 0, 0, 0, time.UTC), want: "uniq:{test-dau}:20240102"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Key(tt.t); got != tt.want {
+				t.Errorf("キーが想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+
+	// 範囲のキーは全て同じスロットに入る
+	for _, key := range c.keys(time.Date(2024, 1, 1, 0, 0, 0, 0, jst), time.Date(2024, 1, 7, 0, 0, 0, 0, jst)) {
+		if !strings.HasPrefix(key, "uniq:{test-dau}:") {
+			t.Errorf("キーが想定外です。got=%v", key)
+		}
+	}
+}
+
+func TestDailyUniqueCounter(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	jst := time.FixedZone("JST", 9*60*60)
+	c := NewDailyUniqueCounter(r, "test-dau", jst, 48*time.Hour)
+
+	// 保持期間を過ぎないよう現在の日付で数える
+	now := time.Now().In(jst)
+	day1 := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, jst)
+	day2 := day1.AddDate(0, 0, 1)
+	_, _ = r.Del(ctx, c.Key(day1), c.Key(day2))
+
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, c.Add(ctx, day1, fmt.Sprintf("user%d", i)))
+	}
+	assert.NoError(t, c.Add(ctx, day2, "user0", "user100"))
+
+	n, err := c.Count(ctx, day1)
+	assert.NoError(t, err)
+	assert.InDelta(t, 100, n, 2)
+	n, err = c.CountRange(ctx, day1, day2)
+	assert.NoError(t, err)
+	assert.InDelta(t, 101, n, 2)
+	n, err = c.CountRange(ctx, day2, day1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	// 保持期間は日の終わりから数える
+	ttl, err := r.TTL(ctx, c.Key(day1))
+	assert.NoError(t, err)
+	assert.NotEqual(t, NoExpiry, ttl)
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"valley-pkg/parser"
+)
+
+// ErrNotFound キーが存在しない場合のエラー
+var ErrNotFound = errors.New("redis key not found")
+
+// jsonParser SetJSON / GetJSON で使用するパーサー
+var jsonParser parser.Parser = &parser.JSONParser{}
+
+// SetJSON value をJSONに変換して key に保存する
+// ttl が 0 の場合は有効期限を設定しない。
+func SetJSON[T any](ctx context.Context, rc *RedisClient, key string, value T, ttl time.Duration) error {
+	b, err := parser.Marshal(jsonParser, value)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", key, err)
+	}
+	return rc.client.Set(ctx, key, b, ttl).Err()
+}
+
+// GetJSON key に保存されたJSONを型 T に変換して返す
+// キーが存在しない場合は ErrNotFound を返す。
+func GetJSON[T any](ctx context.Context, rc *RedisClient, key string) (T, error) {
+	var zero T
+	b, err := rc.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return zero, notFound(key, err)
+	}
+
+	v, err := parser.Unmarshal[T](jsonParser, b)
+	if err != nil {
+		return zero, fmt.Errorf("unmarshal %s: %w", key, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSON(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		key     string
+		set     bool
+		want    testUser
+		wantErr error
+	}{
+		{
+			name: "正常値: 保存した値を取得できる",
+			key:  "test-json-user",
+			set:  true,
+			want: testUser{ID: 1, Name: "valley"},
+		},
+		{
+			name:    "異常値: 存在しないキー",
+			key:     "test-json-missing",
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set {
+				assert.NoError(t, SetJSON(ctx, r, tt.key, tt.want, time.Minute))
+			}
+			got, err := GetJSON[testUser](ctx, r, tt.key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// ポインタ型でも取得できる
+	p, err := GetJSON[*testUser](ctx, r, "test-json-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "valley", p.Name)
+
+	// JSONではない値は変換エラー
+	assert.NoError(t, r.SetContext(ctx, "test-json-broken", "not json", 0))
+	_, err = GetJSON[testUser](ctx, r, "test-json-broken")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,143 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NoExpiry 有効期限が設定されていないキーに対して TTL が返す値
+const NoExpiry time.Duration = -1
+
+// DefaultScanCount ScanKeys で1回の SCAN に指定する COUNT の既定値
+const DefaultScanCount = 100
+
+// ErrStopScan ScanKeys のコールバックから返すと、エラーにせず走査を打ち切る
+var ErrStopScan = errors.New("stop scan")
+
+// Expire キーに有効期限を設定する。キーが存在しない場合は false を返す
+func (rc *RedisClient) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return rc.client.Expire(ctx, key, ttl).Result()
+}
+
+// ExpireAt キーの有効期限を時刻で設定する。キーが存在しない場合は false を返す
+func (rc *RedisClient) ExpireAt(ctx context.Context, key string, at time.Time) (bool, error) {
+	return rc.client.ExpireAt(ctx, key, at).Result()
+}
+
+// TTL キーの残り有効期限を返す
+// 有効期限が設定されていない場合は NoExpiry、キーが存在しない場合は ErrNotFound を返す。
+func (rc *RedisClient) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := rc.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// 存在しないキーは -2、有効期限なしは -1 が返る
+	switch ttl {
+	case -2:
+		return 0, fmt.Errorf("%s: %w", key, ErrNotFound)
+	case -1:
+		return NoExpiry, nil
+	}
+	return ttl, nil
+}
+
+// Persist キーの有効期限を解除する。解除した場合は true を返す
+func (rc *RedisClient) Persist(ctx context.Context, key string) (bool, error) {
+	return rc.client.Persist(ctx, key).Result()
+}
+
+// Del キーを削除し、削除した件数を返す
+func (rc *RedisClient) Del(ctx context.Context, keys ...string) (int64, error) {
+	return rc.client.Del(ctx, keys...).Result()
+}
+
+// Exists 存在するキーの件数を返す
+func (rc *RedisClient) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return rc.client.Exists(ctx, keys...).Result()
+}
+
+// ScanKeys pattern に一致するキーを SCAN で走査し、キーごとに fn を呼び出す
+// KEYS と異なり Redis をブロックしない。count は1回の SCAN で確認する件数の目安で、0 以下の場合は DefaultScanCount を使用する。
+// 走査中に追加・削除されたキーは含まれない場合があり、同じキーが複数回渡される場合もある。
+// fn が ErrStopScan を返すと走査を打ち切って nil を返し、それ以外のエラーはそのまま返す。
+// Cluster 構成では全てのマスターノードを並行して走査するが、fn の呼び出しは1つずつ行うため fn の中で排他する必要はない。
+// fn がエラーを返した場合は全てのノードの走査を打ち切る。
+func (rc *RedisClient) ScanKeys(ctx context.Context, pattern string, count int64, fn func(key string) error) error {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+
+	var err error
+	if cluster, ok := rc.client.(*redis.ClusterClient); ok {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		s := &serialScan{fn: fn, cancel: cancel}
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scan(ctx, node, pattern, count, s.call)
+		})
+		// 他のノードの打ち切りによる context.Canceled より fn のエラーを優先する
+		if fnErr := s.error(); fnErr != nil {
+			err = fnErr
+		}
+	} else {
+		err = scan(ctx, rc.client, pattern, count, fn)
+	}
+	if errors.Is(err, ErrStopScan) {
+		return nil
+	}
+	return err
+}
+
+// serialScan 複数のノードの走査から fn を1つずつ呼び出す
+// fn がエラーを返した場合は cancel で他のノードの走査を打ち切り、以降は fn を呼び出さない。
+type serialScan struct {
+	mu     sync.Mutex
+	fn     func(key string) error
+	cancel context.CancelFunc
+	err    error
+}
+
+func (s *serialScan) call(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.fn(key); err != nil {
+		s.err = err
+		s.cancel()
+		return err
+	}
+	return nil
+}
+
+func (s *serialScan) error() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// scan カーソルが 0 に戻るまで SCAN を繰り返す
+func scan(ctx context.Context, client redis.Cmdable, pattern string, count int64, fn func(key string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, count).Result()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
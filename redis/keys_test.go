@@ -0,0 +1,133 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_TTL(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-ttl"
+	assert.NoError(t, r.SetContext(ctx, key, "v", 0))
+
+	ttl, err := r.TTL(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, NoExpiry, ttl)
+
+	ok, err := r.Expire(ctx, key, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ttl, err = r.TTL(ctx, key)
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, 59*time.Second)
+	assert.LessOrEqual(t, ttl, time.Minute)
+
+	ok, err = r.Persist(ctx, key)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ttl, err = r.TTL(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, NoExpiry, ttl)
+
+	n, err := r.Del(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	_, err = r.TTL(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+	ok, err = r.Expire(ctx, key, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedisClient_ScanKeys(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	var want []string
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprintf("test-scan:%d", i)
+		want = append(want, key)
+		assert.NoError(t, r.SetContext(ctx, key, "v", time.Minute))
+	}
+
+	seen := map[string]bool{}
+	err = r.ScanKeys(ctx, "test-scan:*", 7, func(key string) error {
+		seen[key] = true
+		return nil
+	})
+	assert.NoError(t, err)
+	var got []string
+	for key := range seen {
+		got = append(got, key)
+	}
+	assert.ElementsMatch(t, want, got)
+
+	// ErrStopScan で途中終了
+	calls := 0
+	err = r.ScanKeys(ctx, "test-scan:*", 0, func(string) error {
+		calls++
+		return ErrStopScan
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	// それ以外のエラーはそのまま返す
+	errBoom := errors.New("boom")
+	err = r.ScanKeys(ctx, "test-scan:*", 0, func(string) error {
+		return errBoom
+	})
+	assert.ErrorIs(t, err, errBoom)
+}
+
+func TestSerialScan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 並行して呼び出しても fn は1つずつ実行される
+	var keys []string
+	s := &serialScan{
+		fn: func(key string) error {
+			keys = append(keys, key)
+			if len(keys) == 50 {
+				return ErrStopScan
+			}
+			return nil
+		},
+		cancel: cancel,
+	}
+
+	var wg sync.WaitGroup
+	for node := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				if err := s.call(fmt.Sprintf("node%d:%d", node, i)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// ErrStopScan 以降は呼び出さず、他のノードの走査も打ち切る
+	assert.Len(t, keys, 50)
+	assert.ErrorIs(t, s.error(), ErrStopScan)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,150 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LPush リストの先頭に値を追加し、追加後の要素数を返す
+func (rc *RedisClient) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return rc.client.LPush(ctx, key, values...).Result()
+}
+
+// RPush リストの末尾に値を追加し、追加後の要素数を返す
+func (rc *RedisClient) RPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return rc.client.RPush(ctx, key, values...).Result()
+}
+
+// LPop リストの先頭から値を取り出す
+// リストが空の場合は ErrNotFound を返す。
+func (rc *RedisClient) LPop(ctx context.Context, key string) (string, error) {
+	result, err := rc.client.LPop(ctx, key).Result()
+	if err != nil {
+		return "", notFound(key, err)
+	}
+	return result, nil
+}
+
+// RPop リストの末尾から値を取り出す
+// リストが空の場合は ErrNotFound を返す。
+func (rc *RedisClient) RPop(ctx context.Context, key string) (string, error) {
+	result, err := rc.client.RPop(ctx, key).Result()
+	if err != nil {
+		return "", notFound(key, err)
+	}
+	return result, nil
+}
+
+// BLPop keys のいずれかに値が入るまで最大 timeout 待ち、先頭から取り出す
+// 戻り値は取り出したキーと値。timeout までに値が入らない場合は ErrNotFound を返す。
+func (rc *RedisClient) BLPop(ctx context.Context, timeout time.Duration, keys ...string) (string, string, error) {
+	result, err := rc.client.BLPop(ctx, timeout, keys...).Result()
+	if err != nil {
+		return "", "", notFound(fmt.Sprint(keys), err)
+	}
+	return result[0], result[1], nil
+}
+
+// LRange リストの start から stop までの要素を返す（stop は -1 で末尾）
+func (rc *RedisClient) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return rc.client.LRange(ctx, key, start, stop).Result()
+}
+
+// LLen リストの要素数を返す
+func (rc *RedisClient) LLen(ctx context.Context, key string) (int64, error) {
+	return rc.client.LLen(ctx, key).Result()
+}
+
+// notFound redis.Nil を ErrNotFound に置き換える
+func notFound(key string, err error) error {
+	if errors.Is(err, redis.Nil) {
+		return fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	return err
+}
+
+// Queue リストを使った FIFO のワークキュー
+// Dequeue した値は処理中リストへアトミックに移動し、Ack されるまで残るため、
+// コンシューマーが異常終了しても Requeue で取りこぼさずに再処理できる。
+type Queue struct {
+	redis      *RedisClient
+	key        string
+	processing string
+}
+
+// NewQueue キュー名を指定してキューを生成
+// キューと処理中リストのキーは名前をハッシュタグにし、クラスタでも同じスロットに入れて LMOVE できるようにする。
+func NewQueue(rc *RedisClient, name string) *Queue {
+	return &Queue{
+		redis:      rc,
+		key:        fmt.Sprintf("queue:{%s}", name),
+		processing: fmt.Sprintf("queue:{%s}:processing", name),
+	}
+}
+
+// Enqueue キューの末尾に値を追加する
+func (q *Queue) Enqueue(ctx context.Context, values ...interface{}) error {
+	return q.redis.client.LPush(ctx, q.key, values...).Err()
+}
+
+// Dequeue キューの先頭から値を取り出し、処理中リストへ移動する
+// timeout が 0 の場合は待たずに返す。値が無い場合は ErrNotFound を返す。
+func (q *Queue) Dequeue(ctx context.Context, timeout time.Duration) (string, error) {
+	var (
+		result string
+		err    error
+	)
+	if timeout > 0 {
+		result, err = q.redis.client.BLMove(ctx, q.key, q.processing, "RIGHT", "LEFT", timeout).Result()
+	} else {
+		result, err = q.redis.client.LMove(ctx, q.key, q.processing, "RIGHT", "LEFT").Result()
+	}
+	if err != nil {
+		return "", notFound(q.key, err)
+	}
+	return result, nil
+}
+
+// Ack 処理が完了した値を処理中リストから取り除く
+// 処理中リストに値が無い場合は ErrNotFound を返す。
+func (q *Queue) Ack(ctx context.Context, value string) error {
+	n, err := q.redis.client.LRem(ctx, q.processing, 1, value).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%s: %w", q.processing, ErrNotFound)
+	}
+	return nil
+}
+
+// Requeue 処理中リストに残っている値を全てキューの先頭へ戻し、戻した件数を返す
+// 戻した値は Dequeue した順に再び取り出される。異常終了したコンシューマーの処理中の値を回収するために使用する。
+func (q *Queue) Requeue(ctx context.Context) (int, error) {
+	n := 0
+	for {
+		// 処理中リストの新しい値から順にキューの先頭へ積むことで、最も古い値が先頭になる
+		_, err := q.redis.client.LMove(ctx, q.processing, q.key, "LEFT", "RIGHT").Result()
+		if errors.Is(err, redis.Nil) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// Len キューに残っている値の件数を返す
+func (q *Queue) Len(ctx context.Context) (int64, error) {
+	return q.redis.client.LLen(ctx, q.key).Result()
+}
+
+// Processing 処理中の値の件数を返す
+func (q *Queue) Processing(ctx context.Context) (int64, error) {
+	return q.redis.client.LLen(ctx, q.processing).Result()
+}
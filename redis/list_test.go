@@ -0,0 +1,145 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_List(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-list"
+	_ = r.client.Del(ctx, key).Err()
+
+	n, err := r.RPush(ctx, key, "b", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	n, err = r.LPush(ctx, key, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	all, err := r.LRange(ctx, key, 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, all)
+
+	v, err := r.LPop(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", v)
+	v, err = r.RPop(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", v)
+
+	k, v, err := r.BLPop(ctx, time.Second, key)
+	assert.NoError(t, err)
+	assert.Equal(t, key, k)
+	assert.Equal(t, "b", v)
+
+	_, err = r.LPop(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, _, err = r.BLPop(ctx, 100*time.Millisecond, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// hashTag Redis Cluster がスロットの計算に使用するキーの部分を返す
+func hashTag(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+func TestNewQueue_Keys(t *testing.T) {
+	q := NewQueue(nil, "mail")
+	if q.key != "queue:{mail}" || q.processing != "queue:{mail}:processing" {
+		t.Errorf("キーが想定外です。got=%v, %v", q.key, q.processing)
+	}
+	// キューと処理中リストは同じスロットに入る
+	if got, want := hashTag(q.processing), hashTag(q.key); got != want || got != "mail" {
+		t.Errorf("ハッシュタグが想定外です。got=%v, want=%v", got, want)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	q := NewQueue(r, "test-jobs")
+	_ = r.client.Del(ctx, q.key, q.processing).Err()
+
+	assert.NoError(t, q.Enqueue(ctx, "job1", "job2"))
+
+	// 追加した順に取り出される
+	v, err := q.Dequeue(ctx, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "job1", v)
+	assert.NoError(t, q.Ack(ctx, v))
+	assert.ErrorIs(t, q.Ack(ctx, v), ErrNotFound)
+
+	// Ack されなかった値は Requeue でキューに戻る
+	v, err = q.Dequeue(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "job2", v)
+	processing, _ := q.Processing(ctx)
+	assert.Equal(t, int64(1), processing)
+
+	requeued, err := q.Requeue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requeued)
+	l, _ := q.Len(ctx)
+	assert.Equal(t, int64(1), l)
+
+	v, err = q.Dequeue(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "job2", v)
+	assert.NoError(t, q.Ack(ctx, v))
+
+	_, err = q.Dequeue(ctx, 0)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = q.Dequeue(ctx, 100*time.Millisecond)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestQueue_RequeueOrder(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	q := NewQueue(r, "test-requeue")
+	_ = r.client.Del(ctx, q.key, q.processing).Err()
+
+	assert.NoError(t, q.Enqueue(ctx, "job1", "job2", "job3", "job4"))
+	for _, want := range []string{"job1", "job2", "job3"} {
+		v, err := q.Dequeue(ctx, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, want, v)
+	}
+
+	// 処理中の値は Dequeue した順に、まだ取り出していない値より先に戻る
+	requeued, err := q.Requeue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requeued)
+	for _, want := range []string{"job1", "job2", "job3", "job4"} {
+		v, err := q.Dequeue(ctx, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, want, v)
+		assert.NoError(t, q.Ack(ctx, v))
+	}
+}
@@ -0,0 +1,272 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"valley-pkg/metrics"
+)
+
+// PoolStats コネクションプールの統計
+type PoolStats = redis.PoolStats
+
+// pipelineCommandName パイプラインをまとめて記録する際のコマンド名
+const pipelineCommandName = "pipeline"
+
+// MetricsSink コマンドの計測値の送り先
+// 実装は複数のゴルーチンから同時に呼び出されても安全である必要がある。
+type MetricsSink interface {
+	// ObserveCommand コマンドごとに呼び出される。パイプラインは name を "pipeline" としてまとめて1回呼び出す
+	// キーが存在しない（redis.Nil）場合は err を nil とする。
+	ObserveCommand(name string, latency time.Duration, err error)
+	// ObservePoolStats ReportPoolStats の間隔ごとに呼び出される
+	ObservePoolStats(stats PoolStats)
+}
+
+// AddMetrics コマンドの実行時間とエラーを sink に記録する
+func (rc *RedisClient) AddMetrics(sink MetricsSink) {
+	rc.client.AddHook(metricsHook{sink: sink})
+}
+
+// PoolStats 現在のコネクションプールの統計を返す
+func (rc *RedisClient) PoolStats() PoolStats {
+	return *rc.client.PoolStats()
+}
+
+// ReportPoolStats ctx が終了するか Close を呼ぶまで interval ごとにコネクションプールの統計を sink に記録する
+// interval が 0 以下の場合は15秒ごとに記録する。
+func (rc *RedisClient) ReportPoolStats(ctx context.Context, interval time.Duration, sink MetricsSink) {
+	if interval <= 0 {
+		interval = poolStatsInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-rc.closed:
+				return
+			case <-ticker.C:
+				sink.ObservePoolStats(rc.PoolStats())
+			}
+		}
+	}()
+}
+
+// metricsHook go-redis のフックとしてコマンドを計測する
+type metricsHook struct {
+	sink MetricsSink
+}
+
+func (h metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+		conn, err := next(ctx, network, addr)
+		h.sink.ObserveCommand("dial", time.Since(start), err)
+		return conn, err
+	}
+}
+
+func (h metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.sink.ObserveCommand(cmd.Name(), time.Since(start), ignoreNil(err))
+		return err
+	}
+}
+
+func (h metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.sink.ObserveCommand(pipelineCommandName, time.Since(start), ignoreNil(err))
+		return err
+	}
+}
+
+// ignoreNil キーが存在しないことを表す redis.Nil はエラーとして数えない
+func ignoreNil(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// poolStatsInterval Config.Metrics を指定した場合と、ReportPoolStats の interval が 0 以下の場合にコネクションプールの統計を記録する間隔
+const poolStatsInterval = 15 * time.Second
+
+// meterSink metrics.Meter に記録する MetricsSink
+type meterSink struct {
+	duration metrics.Histogram
+	errors   metrics.Counter
+	conns    metrics.Gauge
+	idle     metrics.Gauge
+	hits     metrics.Counter
+	misses   metrics.Counter
+	timeouts metrics.Counter
+
+	// last 前回の統計。累積の回数は前回からの差分をカウンターに加算する
+	mu   sync.Mutex
+	last PoolStats
+}
+
+// NewMeterSink m に記録する MetricsSink を生成する
+// コマンドの実行時間を redis_command_duration_seconds、エラー数を redis_command_errors_total に
+// command ラベル付きで記録する。コネクションプールのコネクション数は redis_pool_*connections のゲージに、
+// 累積の回数は redis_pool_*_total のカウンターに記録する。
+func NewMeterSink(m metrics.Meter) MetricsSink {
+	return &meterSink{
+		duration: m.Histogram("redis_command_duration_seconds", "Redis コマンドの実行時間（秒）", nil),
+		errors:   m.Counter("redis_command_errors_total", "Redis コマンドのエラー数"),
+		conns:    m.Gauge("redis_pool_connections", "コネクションプールのコネクション数"),
+		idle:     m.Gauge("redis_pool_idle_connections", "コネクションプールのアイドルコネクション数"),
+		hits:     m.Counter("redis_pool_hits_total", "コネクションプールから取得できた回数"),
+		misses:   m.Counter("redis_pool_misses_total", "コネクションプールに空きが無かった回数"),
+		timeouts: m.Counter("redis_pool_timeouts_total", "コネクションの取得がタイムアウトした回数"),
+	}
+}
+
+func (s *meterSink) ObserveCommand(name string, latency time.Duration, err error) {
+	label := metrics.L("command", name)
+	s.duration.Observe(latency.Seconds(), label)
+	if err != nil {
+		s.errors.Add(1, label)
+	}
+}
+
+func (s *meterSink) ObservePoolStats(stats PoolStats) {
+	s.conns.Set(float64(stats.TotalConns))
+	s.idle.Set(float64(stats.IdleConns))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits.Add(poolStatsDelta(stats.Hits, s.last.Hits))
+	s.misses.Add(poolStatsDelta(stats.Misses, s.last.Misses))
+	s.timeouts.Add(poolStatsDelta(stats.Timeouts, s.last.Timeouts))
+	s.last = stats
+}
+
+// poolStatsDelta 累積の回数の前回からの増分を返す。前回より減っている場合はリセットされたとみなして現在の値を返す
+func poolStatsDelta(cur, last uint32) float64 {
+	if cur < last {
+		return float64(cur)
+	}
+	return float64(cur - last)
+}
+
+// DefaultLatencyBuckets MemoryMetrics のヒストグラムの既定の上限値
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// CommandMetrics コマンドごとの集計
+type CommandMetrics struct {
+	Count  uint64
+	Errors uint64
+	Total  time.Duration
+	Max    time.Duration
+	// Buckets Buckets[i] は実行時間が LatencyBuckets[i] 以下だった回数。最後の要素は全ての上限を超えた回数
+	Buckets []uint64
+}
+
+// Mean 平均の実行時間を返す
+func (m CommandMetrics) Mean() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Count)
+}
+
+// ErrorRate エラーの割合を返す
+func (m CommandMetrics) ErrorRate() float64 {
+	if m.Count == 0 {
+		return 0
+	}
+	return float64(m.Errors) / float64(m.Count)
+}
+
+// MemoryMetrics プロセス内で集計する MetricsSink
+// 外部の監視基盤へ送る前の確認や、定期的に Snapshot をログへ出力する用途に使用する。
+type MemoryMetrics struct {
+	mu       sync.Mutex
+	buckets  []time.Duration
+	commands map[string]*CommandMetrics
+	pool     PoolStats
+}
+
+// NewMemoryMetrics ヒストグラムの上限値を指定して生成する。省略した場合は DefaultLatencyBuckets を使用する
+func NewMemoryMetrics(buckets ...time.Duration) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return &MemoryMetrics{
+		buckets:  buckets,
+		commands: make(map[string]*CommandMetrics),
+	}
+}
+
+// LatencyBuckets ヒストグラムの上限値を返す
+func (m *MemoryMetrics) LatencyBuckets() []time.Duration {
+	return append([]time.Duration(nil), m.buckets...)
+}
+
+func (m *MemoryMetrics) ObserveCommand(name string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.commands[name]
+	if !ok {
+		c = &CommandMetrics{Buckets: make([]uint64, len(m.buckets)+1)}
+		m.commands[name] = c
+	}
+	c.Count++
+	if err != nil {
+		c.Errors++
+	}
+	c.Total += latency
+	c.Max = max(c.Max, latency)
+	c.Buckets[sort.Search(len(m.buckets), func(i int) bool { return latency <= m.buckets[i] })]++
+}
+
+func (m *MemoryMetrics) ObservePoolStats(stats PoolStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pool = stats
+}
+
+// Snapshot コマンドごとの集計のコピーを返す
+func (m *MemoryMetrics) Snapshot() map[string]CommandMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]CommandMetrics, len(m.commands))
+	for name, c := range m.commands {
+		cp := *c
+		cp.Buckets = append([]uint64(nil), c.Buckets...)
+		result[name] = cp
+	}
+	return result
+}
+
+// Pool 最後に記録したコネクションプールの統計を返す
+func (m *MemoryMetrics) Pool() PoolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pool
+}
@@ -0,0 +1,157 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"valley-pkg/logging"
+	"valley-pkg/metrics"
+)
+
+func TestRedisClient_AddMetrics(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	m := NewMemoryMetrics()
+	r.AddMetrics(m)
+
+	assert.NoError(t, r.SetContext(ctx, "test-metrics", "v", time.Minute))
+	_, err = r.GetContext(ctx, "test-metrics")
+	assert.NoError(t, err)
+	// 存在しないキーはエラーとして数えない
+	_, err = r.GetContext(ctx, "test-metrics-missing")
+	assert.Error(t, err)
+	// 文字列以外への操作はエラーとして数える
+	_, err = r.LPush(ctx, "test-metrics", "x")
+	assert.Error(t, err)
+	_, err = r.Pipelined(ctx, func(p Pipeliner) error {
+		p.Get(ctx, "test-metrics")
+		p.Get(ctx, "test-metrics")
+		return nil
+	})
+	assert.NoError(t, err)
+
+	snap := m.Snapshot()
+	assert.Equal(t, uint64(1), snap["set"].Count)
+	assert.Equal(t, uint64(2), snap["get"].Count)
+	assert.Equal(t, uint64(0), snap["get"].Errors)
+	assert.Equal(t, uint64(1), snap["lpush"].Errors)
+	assert.Equal(t, 1.0, snap["lpush"].ErrorRate())
+	assert.Equal(t, uint64(1), snap[pipelineCommandName].Count)
+
+	var total uint64
+	for _, n := range snap["get"].Buckets {
+		total += n
+	}
+	assert.Equal(t, uint64(2), total)
+
+	reportCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	r.ReportPoolStats(reportCtx, 10*time.Millisecond, m)
+	assert.Eventually(t, func() bool { return m.Pool().TotalConns > 0 }, time.Second, 10*time.Millisecond)
+}
+
+// countingSink ObservePoolStats の呼び出し回数を数える
+type countingSink struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingSink) ObserveCommand(string, time.Duration, error) {}
+
+func (s *countingSink) ObservePoolStats(PoolStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+}
+
+func (s *countingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestRedisClient_ReportPoolStats_Close(t *testing.T) {
+	// 接続しなくてもプールの統計は取得できる
+	rc := &RedisClient{
+		client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}),
+		logger: logging.Nop(),
+		closed: make(chan struct{}),
+	}
+	sink := &countingSink{}
+	rc.ReportPoolStats(context.Background(), time.Millisecond, sink)
+	assert.Eventually(t, func() bool { return sink.count() > 0 }, time.Second, time.Millisecond)
+
+	// Close で記録を終了する
+	assert.NoError(t, rc.Close())
+	time.Sleep(10 * time.Millisecond)
+	n := sink.count()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, sink.count())
+}
+
+func TestRedisClient_ReportPoolStats_NonPositiveInterval(t *testing.T) {
+	rc := &RedisClient{
+		client: redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"}),
+		logger: logging.Nop(),
+		closed: make(chan struct{}),
+	}
+	defer rc.Close()
+
+	// 0 以下の間隔でも panic せずに既定の間隔で記録する
+	for _, interval := range []time.Duration{0, -time.Second} {
+		rc.ReportPoolStats(context.Background(), interval, &countingSink{})
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestMemoryMetrics(t *testing.T) {
+	m := NewMemoryMetrics(10*time.Millisecond, time.Millisecond)
+	assert.Equal(t, []time.Duration{time.Millisecond, 10 * time.Millisecond}, m.LatencyBuckets())
+
+	m.ObserveCommand("get", time.Millisecond, nil)
+	m.ObserveCommand("get", 5*time.Millisecond, errors.New("boom"))
+	m.ObserveCommand("get", time.Second, nil)
+
+	got := m.Snapshot()["get"]
+	assert.Equal(t, uint64(3), got.Count)
+	assert.Equal(t, uint64(1), got.Errors)
+	assert.Equal(t, []uint64{1, 1, 1}, got.Buckets)
+	assert.Equal(t, time.Second, got.Max)
+	assert.Equal(t, (time.Second+6*time.Millisecond)/3, got.Mean())
+}
+
+func TestMeterSink(t *testing.T) {
+	reg := metrics.NewRegistry()
+	s := NewMeterSink(reg)
+
+	s.ObserveCommand("get", time.Millisecond, nil)
+	s.ObserveCommand("get", 5*time.Millisecond, errors.New("boom"))
+	s.ObservePoolStats(PoolStats{TotalConns: 3, IdleConns: 1, Hits: 10, Misses: 2})
+	s.ObservePoolStats(PoolStats{TotalConns: 3, IdleConns: 1, Hits: 15, Misses: 2, Timeouts: 1})
+
+	got := make(map[string]metrics.Family)
+	for _, f := range reg.Gather() {
+		got[f.Name] = f
+	}
+	label := []metrics.Label{metrics.L("command", "get")}
+	assert.Equal(t, label, got["redis_command_duration_seconds"].Series[0].Labels)
+	assert.Equal(t, uint64(2), got["redis_command_duration_seconds"].Series[0].Count)
+	assert.Equal(t, 1.0, got["redis_command_errors_total"].Series[0].Value)
+	assert.Equal(t, 3.0, got["redis_pool_connections"].Series[0].Value)
+	assert.Equal(t, 1.0, got["redis_pool_idle_connections"].Series[0].Value)
+	// 累積の回数はカウンターとして記録する
+	assert.Equal(t, metrics.KindCounter, got["redis_pool_hits_total"].Kind)
+	assert.Equal(t, 15.0, got["redis_pool_hits_total"].Series[0].Value)
+	assert.Equal(t, 2.0, got["redis_pool_misses_total"].Series[0].Value)
+	assert.Equal(t, 1.0, got["redis_pool_timeouts_total"].Series[0].Value)
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrTxConflict 楽観的トランザクションが競合により規定回数失敗した場合のエラー
+var ErrTxConflict = errors.New("redis transaction conflict")
+
+// Pipeliner パイプラインにコマンドを積むためのインターフェース
+type Pipeliner = redis.Pipeliner
+
+// Tx WATCH 中のキーに対するトランザクション
+type Tx = redis.Tx
+
+// Cmder パイプラインで実行されたコマンドの結果
+type Cmder = redis.Cmder
+
+// Pipelined fn で積んだコマンドを1回の往復でまとめて実行する
+// いずれかのコマンドが失敗した場合は最初のエラーを返す。
+func (rc *RedisClient) Pipelined(ctx context.Context, fn func(p Pipeliner) error) ([]Cmder, error) {
+	return rc.client.Pipelined(ctx, fn)
+}
+
+// TxPipelined fn で積んだコマンドを MULTI/EXEC で囲んで実行する
+func (rc *RedisClient) TxPipelined(ctx context.Context, fn func(p Pipeliner) error) ([]Cmder, error) {
+	return rc.client.TxPipelined(ctx, fn)
+}
+
+// Pipeline 明示的に Exec を呼び出すパイプラインを生成する
+func (rc *RedisClient) Pipeline() Pipeliner {
+	return rc.client.Pipeline()
+}
+
+// TxPipeline 明示的に Exec を呼び出す MULTI/EXEC パイプラインを生成する
+func (rc *RedisClient) TxPipeline() Pipeliner {
+	return rc.client.TxPipeline()
+}
+
+// Watch keys を WATCH した状態で fn を実行する楽観的トランザクション
+// fn 内では tx で現在値を読み、tx.TxPipelined で更新を積む。
+// EXEC 前に他のクライアントが keys を更新した場合は fn からやり直し、
+// maxRetries 回やり直しても競合する場合は ErrTxConflict を返す。
+func (rc *RedisClient) Watch(ctx context.Context, maxRetries int, fn func(tx *Tx) error, keys ...string) error {
+	for i := 0; i <= maxRetries; i++ {
+		err := rc.client.Watch(ctx, fn, keys...)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("watch %v after %d retries: %w", keys, maxRetries, ErrTxConflict)
+}
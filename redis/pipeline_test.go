@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Pipelined(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	cmds, err := r.Pipelined(ctx, func(p Pipeliner) error {
+		p.Set(ctx, "test-pipe-a", "1", 0)
+		p.Set(ctx, "test-pipe-b", "2", 0)
+		p.Get(ctx, "test-pipe-a")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cmds, 3)
+	assert.Equal(t, "1", cmds[2].(*redis.StringCmd).Val())
+
+	cmds, err = r.TxPipelined(ctx, func(p Pipeliner) error {
+		p.Incr(ctx, "test-pipe-a")
+		p.Incr(ctx, "test-pipe-b")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cmds[0].(*redis.IntCmd).Val())
+	assert.Equal(t, int64(3), cmds[1].(*redis.IntCmd).Val())
+
+	p := r.Pipeline()
+	get := p.Get(ctx, "test-pipe-b")
+	_, err = p.Exec(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", get.Val())
+}
+
+func TestRedisClient_Watch(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-watch-counter"
+	assert.NoError(t, r.SetContext(ctx, key, "0", 0))
+
+	incr := func(tx *Tx) error {
+		n, err := tx.Get(ctx, key).Int()
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(p Pipeliner) error {
+			p.Set(ctx, key, strconv.Itoa(n+1), 0)
+			return nil
+		})
+		return err
+	}
+	assert.NoError(t, r.Watch(ctx, 3, incr, key))
+	got, err := r.GetContext(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", got)
+
+	// 毎回 WATCH 後にキーが書き換えられるので競合し続ける
+	conflict := func(tx *Tx) error {
+		if err := r.SetContext(ctx, key, "x", 0); err != nil {
+			return err
+		}
+		_, err := tx.TxPipelined(ctx, func(p Pipeliner) error {
+			p.Set(ctx, key, "y", 0)
+			return nil
+		})
+		return err
+	}
+	assert.ErrorIs(t, r.Watch(ctx, 2, conflict, key), ErrTxConflict)
+}
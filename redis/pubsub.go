@@ -1,8 +1,11 @@
 package redis
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"valley-pkg/logging"
 )
 
 type PubSubService struct {
@@ -15,21 +18,37 @@ func NewPubSubService(rdb *RedisClient) *PubSubService {
 	}
 }
 
-// PublishEvent パブリッシャーの実装
+// PublishEvent パブリッシャーの実装。生成時の context を使用する
 func (ps *PubSubService) PublishEvent(channel string, event interface{}) error {
+	return ps.PublishEventContext(ps.rdb.ctx, channel, event)
+}
+
+// PublishEventContext パブリッシャーの実装
+func (ps *PubSubService) PublishEventContext(ctx context.Context, channel string, event interface{}) error {
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
-	return ps.rdb.client.Publish(ps.rdb.ctx, channel, eventData).Err()
+	return ps.rdb.client.Publish(ctx, channel, eventData).Err()
 }
 
-// SubscribeToEvents サブスクライバーの実装
+// Publish payload をそのまま channel に送信し、受信したサブスクライバーの数を返す
+func (ps *PubSubService) Publish(ctx context.Context, channel string, payload []byte) (int64, error) {
+	return ps.rdb.client.Publish(ctx, channel, payload).Result()
+}
+
+// SubscribeToEvents サブスクライバーの実装。生成時の context を使用する
 func (ps *PubSubService) SubscribeToEvents(channel string, readyChan chan<- interface{}, handler func([]byte) error) error {
-	pubsub := ps.rdb.client.Subscribe(ps.rdb.ctx, channel)
+	return ps.SubscribeToEventsContext(ps.rdb.ctx, channel, readyChan, handler)
+}
+
+// SubscribeToEventsContext サブスクライバーの実装
+// ctx が終了すると購読をやめて ctx.Err() を返す。
+func (ps *PubSubService) SubscribeToEventsContext(ctx context.Context, channel string, readyChan chan<- interface{}, handler func([]byte) error) error {
+	pubsub := ps.rdb.client.Subscribe(ctx, channel)
 	defer pubsub.Close()
 	// サブスクリプション確認
-	_, err := pubsub.Receive(ps.rdb.ctx)
+	_, err := pubsub.Receive(ctx)
 	if err != nil {
 		return err
 	}
@@ -38,11 +57,214 @@ func (ps *PubSubService) SubscribeToEvents(channel string, readyChan chan<- inte
 	readyChan <- true
 
 	ch := pubsub.Channel()
-	for msg := range ch {
-		log.Printf("Received message: %s", msg.Payload)
-		if err := handler([]byte(msg.Payload)); err != nil {
-			log.Printf("Error handling message: %v", err)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			ps.rdb.logger.Debug("Received message", logging.F("channel", msg.Channel), logging.F("payload", msg.Payload))
+			if err := handler([]byte(msg.Payload)); err != nil {
+				ps.rdb.logger.Error("Error handling message", logging.Err(err), logging.F("channel", msg.Channel))
+			}
 		}
 	}
+}
+
+// resubscribeInterval 受信エラーから再接続を試みるまでの間隔
+const resubscribeInterval = 100 * time.Millisecond
+
+// Message 受信したメッセージ
+type Message struct {
+	// Channel 送信されたチャネル
+	Channel string
+	// Pattern PSubscribe で一致したパターン。Subscribe の場合は空
+	Pattern string
+	Payload []byte
+}
+
+// MessageHandler メッセージごとに呼び出される処理
+type MessageHandler func(ctx context.Context, msg Message) error
+
+// SubscribeOption Subscription の設定
+type SubscribeOption func(*Subscription)
+
+// WithErrorHandler ハンドラーのエラーや受信エラーを受け取る処理を指定する
+// 受信エラーの場合 msg は nil となる。指定しない場合はログに出力する。
+func WithErrorHandler(fn func(msg *Message, err error)) SubscribeOption {
+	return func(s *Subscription) {
+		s.onError = fn
+	}
+}
+
+// Subscription バックグラウンドで受信を続ける購読
+// 接続が切れた場合は再接続し、購読中のチャネルとパターンを自動で購読し直す。
+type Subscription struct {
+	pubsub  *redis.PubSub
+	handler MessageHandler
+	onError func(msg *Message, err error)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Subscribe channels を購読し、メッセージごとに handler を呼び出す
+// 購読の開始を確認してから返る。ctx が終了するか Close を呼ぶと購読をやめる。
+func (ps *PubSubService) Subscribe(ctx context.Context, channels []string, handler MessageHandler, opts ...SubscribeOption) (*Subscription, error) {
+	return ps.subscribe(ctx, ps.rdb.client.Subscribe(ctx), channels, nil, handler, opts)
+}
+
+// PSubscribe patterns（"user.*" などのグロブ）に一致するチャネルを購読し、メッセージごとに handler を呼び出す
+func (ps *PubSubService) PSubscribe(ctx context.Context, patterns []string, handler MessageHandler, opts ...SubscribeOption) (*Subscription, error) {
+	return ps.subscribe(ctx, ps.rdb.client.PSubscribe(ctx), nil, patterns, handler, opts)
+}
+
+func (ps *PubSubService) subscribe(ctx context.Context, pubsub *redis.PubSub, channels, patterns []string, handler MessageHandler, opts []SubscribeOption) (*Subscription, error) {
+	s := &Subscription{
+		pubsub:  pubsub,
+		handler: handler,
+		onError: func(msg *Message, err error) {
+			fields := []logging.Field{logging.Err(err)}
+			if msg != nil {
+				fields = append(fields, logging.F("channel", msg.Channel))
+			}
+			ps.rdb.logger.Error("Error handling message", fields...)
+		},
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if len(channels) > 0 {
+		if err := pubsub.Subscribe(ctx, channels...); err != nil {
+			_ = pubsub.Close()
+			return nil, err
+		}
+	}
+	if len(patterns) > 0 {
+		if err := pubsub.PSubscribe(ctx, patterns...); err != nil {
+			_ = pubsub.Close()
+			return nil, err
+		}
+	}
+	received, err := confirmSubscriptions(ctx, pubsub, channels, patterns)
+	if err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	// 受信待ちは context で中断できないため、終了時は接続を閉じる
+	stop := context.AfterFunc(s.ctx, func() { _ = pubsub.Close() })
+	go func() {
+		defer close(s.done)
+		defer stop()
+		s.run(received)
+	}()
+	return s, nil
+}
+
+// confirmSubscriptions 全てのチャネルとパターンの購読の確認を受け取るまで待つ
+// 確認を待つ間に受信したメッセージは捨てずに返す。
+func confirmSubscriptions(ctx context.Context, pubsub *redis.PubSub, channels, patterns []string) ([]*redis.Message, error) {
+	pending := make(map[string]struct{}, len(channels)+len(patterns))
+	for _, c := range channels {
+		pending["subscribe:"+c] = struct{}{}
+	}
+	for _, p := range patterns {
+		pending["psubscribe:"+p] = struct{}{}
+	}
+
+	var received []*redis.Message
+	for len(pending) > 0 {
+		r, err := pubsub.Receive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch v := r.(type) {
+		case *redis.Subscription:
+			delete(pending, v.Kind+":"+v.Channel)
+		case *redis.Message:
+			received = append(received, v)
+		}
+	}
+	return received, nil
+}
+
+// run 終了するまで受信を続ける
+// received は購読の確認を待つ間に受信したメッセージで、最初に handler に渡す。
+func (s *Subscription) run(received []*redis.Message) {
+	for _, msg := range received {
+		if s.ctx.Err() != nil {
+			return
+		}
+		s.handle(msg)
+	}
+	for {
+		received, err := s.pubsub.Receive(s.ctx)
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// 次の Receive で再接続と購読し直しが行われる
+			s.onError(nil, err)
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(resubscribeInterval):
+			}
+			continue
+		}
+
+		msg, ok := received.(*redis.Message)
+		if !ok {
+			// 購読の確認や Pong は無視する
+			continue
+		}
+		s.handle(msg)
+	}
+}
+
+// handle 受信したメッセージを handler に渡す
+func (s *Subscription) handle(msg *redis.Message) {
+	m := Message{Channel: msg.Channel, Pattern: msg.Pattern, Payload: []byte(msg.Payload)}
+	if err := s.handler(s.ctx, m); err != nil {
+		s.onError(&m, err)
+	}
+}
+
+// Subscribe 購読するチャネルを追加する
+func (s *Subscription) Subscribe(ctx context.Context, channels ...string) error {
+	return s.pubsub.Subscribe(ctx, channels...)
+}
+
+// PSubscribe 購読するパターンを追加する
+func (s *Subscription) PSubscribe(ctx context.Context, patterns ...string) error {
+	return s.pubsub.PSubscribe(ctx, patterns...)
+}
+
+// Unsubscribe チャネルの購読をやめる。channels を省略すると全てのチャネルの購読をやめる
+func (s *Subscription) Unsubscribe(ctx context.Context, channels ...string) error {
+	return s.pubsub.Unsubscribe(ctx, channels...)
+}
+
+// PUnsubscribe パターンの購読をやめる。patterns を省略すると全てのパターンの購読をやめる
+func (s *Subscription) PUnsubscribe(ctx context.Context, patterns ...string) error {
+	return s.pubsub.PUnsubscribe(ctx, patterns...)
+}
+
+// Close 購読を終了し、受信中のハンドラーが戻るまで待つ
+func (s *Subscription) Close() error {
+	s.cancel()
+	<-s.done
 	return nil
 }
+
+// Done 購読が終了するとクローズされるチャネルを返す
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
@@ -3,10 +3,13 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type UserEvent struct {
@@ -63,3 +66,103 @@ func TestPubSubService_SubscribeToEvents(t *testing.T) {
 		log.Printf("Failed to publish event: %v", err)
 	}
 }
+
+func TestPubSubService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	rdb, err := NewRedisClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdb.Close()
+	ps := NewPubSubService(rdb)
+
+	received := make(chan Message, 10)
+	errs := make(chan error, 10)
+	sub, err := ps.Subscribe(ctx, []string{"test-sub-a"}, func(ctx context.Context, msg Message) error {
+		received <- msg
+		if string(msg.Payload) == `"fail"` {
+			return errors.New("handler failed")
+		}
+		return nil
+	}, WithErrorHandler(func(msg *Message, err error) {
+		errs <- err
+	}))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ps.PublishEventContext(ctx, "test-sub-a", "hello"))
+	msg := receiveMessage(t, received)
+	assert.Equal(t, Message{Channel: "test-sub-a", Payload: []byte(`"hello"`)}, msg)
+
+	// Publish は JSON に変換せずに送信する
+	n, err := ps.Publish(ctx, "test-sub-a", []byte("raw"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	msg = receiveMessage(t, received)
+	assert.Equal(t, []byte("raw"), msg.Payload)
+
+	// ハンドラーのエラーはエラーハンドラーに渡される
+	assert.NoError(t, ps.PublishEventContext(ctx, "test-sub-a", "fail"))
+	receiveMessage(t, received)
+	select {
+	case err := <-errs:
+		assert.EqualError(t, err, "handler failed")
+	case <-time.After(time.Second):
+		t.Fatal("error handler was not called")
+	}
+
+	// 購読の追加と解除
+	assert.NoError(t, sub.Subscribe(ctx, "test-sub-b"))
+	assert.NoError(t, sub.Unsubscribe(ctx, "test-sub-a"))
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, ps.PublishEventContext(ctx, "test-sub-a", "ignored"))
+	assert.NoError(t, ps.PublishEventContext(ctx, "test-sub-b", "b"))
+	msg = receiveMessage(t, received)
+	assert.Equal(t, "test-sub-b", msg.Channel)
+
+	assert.NoError(t, sub.Close())
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("subscription is not done after Close")
+	}
+}
+
+func TestPubSubService_PSubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rdb, err := NewRedisClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdb.Close()
+	ps := NewPubSubService(rdb)
+
+	received := make(chan Message, 10)
+	sub, err := ps.PSubscribe(ctx, []string{"test-psub.*"}, func(ctx context.Context, msg Message) error {
+		received <- msg
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, ps.PublishEventContext(ctx, "test-psub.created", 1))
+	msg := receiveMessage(t, received)
+	assert.Equal(t, Message{Channel: "test-psub.created", Pattern: "test-psub.*", Payload: []byte("1")}, msg)
+
+	// ctx のキャンセルで購読が終了する
+	cancel()
+	select {
+	case <-sub.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscription is not done after cancel")
+	}
+}
+
+func receiveMessage(t *testing.T, ch <-chan Message) Message {
+	t.Helper()
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("message was not received")
+		return Message{}
+	}
+}
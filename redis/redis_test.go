@@ -57,3 +57,58 @@ func TestRedisClient_Write(t *testing.T) {
 	}
 	fmt.Printf("User profile: %v\n", all)
 }
+
+func TestRedisClient_Context(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, r.SetContext(ctx, "test-ctx-key", "value", 0))
+	got, err := r.GetContext(ctx, "test-ctx-key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", got)
+
+	assert.NoError(t, r.HSetContext(ctx, "test-ctx-hash", map[string]interface{}{"a": "1", "b": "2"}))
+	field, err := r.HGetContext(ctx, "test-ctx-hash", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", field)
+	all, err := r.HGetAllContext(ctx, "test-ctx-hash")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, all)
+	n, err := r.HDelContext(ctx, "test-ctx-hash", "a", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// キャンセル済みの context はリクエストごとに反映される
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.GetContext(canceled, "test-ctx-key")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDistributedLock_Context(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lock := NewDistributedLock(r, "test-ctx-lock")
+	other := NewDistributedLock(r, "test-ctx-lock")
+
+	ok, err := lock.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 他の保持者は取得も解放もできない
+	ok, err = other.AcquireContext(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Error(t, other.ReleaseContext(ctx))
+
+	assert.NoError(t, lock.ReleaseContext(ctx))
+}
@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Redlock の既定値
+const (
+	// DefaultRedlockDriftFactor ノード間の時計のずれとして有効期限から差し引く割合
+	DefaultRedlockDriftFactor = 0.01
+	// redlockDriftBase 時計のずれとして常に差し引く時間
+	redlockDriftBase = 2 * time.Millisecond
+)
+
+// Redlock 独立した複数の Redis ノードの過半数でロックを取得する分散ロック
+// 1台のノードが停止してもロックの安全性を保つ必要がある処理に使用する。
+// 各ノードはレプリケーションしていない独立したサーバーである必要がある。
+type Redlock struct {
+	clients []*RedisClient
+	quorum  int
+	// driftFactor 時計のずれとして有効期限から差し引く割合
+	driftFactor float64
+	// nodeTimeout 1ノードあたりの待ち時間。停止したノードで取得全体が止まらないようにする
+	nodeTimeout time.Duration
+}
+
+// NewRedlock ノードのクライアントを指定して Redlock を生成
+// 過半数を取るため、3台以上の奇数台を推奨する。
+func NewRedlock(clients ...*RedisClient) (*Redlock, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("redlock requires at least one client: %w", ErrConfig)
+	}
+	return &Redlock{
+		clients:     clients,
+		quorum:      len(clients)/2 + 1,
+		driftFactor: DefaultRedlockDriftFactor,
+		nodeTimeout: 50 * time.Millisecond,
+	}, nil
+}
+
+// RedlockLease 取得した Redlock のロック
+type RedlockLease struct {
+	redlock    *Redlock
+	key        string
+	value      string
+	validUntil time.Time
+}
+
+// Lock 全ノードに key のロックを ttl で要求し、過半数で取得できた場合にロックを返す
+// 取得に掛かった時間と時計のずれを差し引いた有効期間が残らない場合も失敗とし、
+// 失敗した場合は取得できたノードのロックを解放して ErrLockNotAcquired を返す。
+func (r *Redlock) Lock(ctx context.Context, key string, ttl time.Duration) (*RedlockLease, error) {
+	lease := &RedlockLease{
+		redlock: r,
+		key:     fmt.Sprintf("lock:%s", key),
+		value:   uuid.New().String(),
+	}
+
+	start := time.Now()
+	acquired := r.each(ctx, func(ctx context.Context, rc *RedisClient) (bool, error) {
+		return rc.client.SetNX(ctx, lease.key, lease.value, ttl).Result()
+	})
+
+	validity := ttl - time.Since(start) - r.drift(ttl)
+	if acquired < r.quorum || validity <= 0 {
+		// 失敗した場合は一部のノードに残ったロックを解放する
+		_ = lease.Unlock(context.WithoutCancel(ctx))
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s acquired %d/%d: %w", lease.key, acquired, len(r.clients), ErrLockNotAcquired)
+	}
+
+	lease.validUntil = start.Add(validity)
+	return lease, nil
+}
+
+// Key ロックのキーを返す
+func (l *RedlockLease) Key() string {
+	return l.key
+}
+
+// ValidUntil ロックが有効であることを保証できる時刻を返す
+func (l *RedlockLease) ValidUntil() time.Time {
+	return l.validUntil
+}
+
+// Validity ロックの残りの有効期間を返す。0 以下の場合は既に保証されない
+func (l *RedlockLease) Validity() time.Duration {
+	return time.Until(l.validUntil)
+}
+
+// Extend 過半数のノードでロックの有効期限を ttl に延長する
+// 過半数で延長できなかった場合は ErrLockNotOwned を返す。
+func (l *RedlockLease) Extend(ctx context.Context, ttl time.Duration) error {
+	r := l.redlock
+	start := time.Now()
+	extended := r.each(ctx, func(ctx context.Context, rc *RedisClient) (bool, error) {
+		result, err := rc.client.Eval(ctx, extendScript, []string{l.key}, l.value, ttl.Milliseconds()).Int64()
+		return result == 1, err
+	})
+
+	validity := ttl - time.Since(start) - r.drift(ttl)
+	if extended < r.quorum || validity <= 0 {
+		return fmt.Errorf("%s extended %d/%d: %w", l.key, extended, len(r.clients), ErrLockNotOwned)
+	}
+	l.validUntil = start.Add(validity)
+	return nil
+}
+
+// Unlock 全ノードのロックを解放する
+// 停止しているノードがあっても残りのノードは解放し、通信に失敗したノードのエラーをまとめて返す。
+// 有効期限切れなどで既に自分のロックでないノードはエラーにしない。
+func (l *RedlockLease) Unlock(ctx context.Context) error {
+	r := l.redlock
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, rc := range r.clients {
+		wg.Add(1)
+		go func(rc *RedisClient) {
+			defer wg.Done()
+			nodeCtx, cancel := context.WithTimeout(ctx, r.nodeTimeout)
+			defer cancel()
+			if err := rc.client.Eval(nodeCtx, releaseScript, []string{l.key}, l.value).Err(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(rc)
+	}
+	wg.Wait()
+	l.validUntil = time.Time{}
+	return errors.Join(errs...)
+}
+
+// each 全ノードに並行して fn を実行し、true を返したノードの数を返す
+// エラーになったノードは失敗として数える。
+func (r *Redlock) each(ctx context.Context, fn func(ctx context.Context, rc *RedisClient) (bool, error)) int {
+	var (
+		mu sync.Mutex
+		n  int
+		wg sync.WaitGroup
+	)
+	for _, rc := range r.clients {
+		wg.Add(1)
+		go func(rc *RedisClient) {
+			defer wg.Done()
+			nodeCtx, cancel := context.WithTimeout(ctx, r.nodeTimeout)
+			defer cancel()
+			if ok, err := fn(nodeCtx, rc); err == nil && ok {
+				mu.Lock()
+				n++
+				mu.Unlock()
+			}
+		}(rc)
+	}
+	wg.Wait()
+	return n
+}
+
+// drift ttl に対する時計のずれの見積もりを返す
+func (r *Redlock) drift(ttl time.Duration) time.Duration {
+	return time.Duration(float64(ttl)*r.driftFactor) + redlockDriftBase
+}
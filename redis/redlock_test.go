@@ -0,0 +1,85 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRedlockClients DB を分けて独立したノードの代わりとする
+func newRedlockClients(t *testing.T, n int) []*RedisClient {
+	t.Helper()
+	var clients []*RedisClient
+	for i := 0; i < n; i++ {
+		cfg := DefaultConfig()
+		cfg.DB = i + 1
+		rc, err := NewRedisClientWithOptions(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = rc.Close() })
+		clients = append(clients, rc)
+	}
+	return clients
+}
+
+func TestRedlock(t *testing.T) {
+	ctx := context.Background()
+	clients := newRedlockClients(t, 3)
+	rl, err := NewRedlock(clients...)
+	assert.NoError(t, err)
+
+	lease, err := rl.Lock(ctx, "test-redlock", time.Second)
+	assert.NoError(t, err)
+	assert.Greater(t, lease.Validity(), 900*time.Millisecond)
+	assert.LessOrEqual(t, lease.Validity(), time.Second)
+
+	// 保持中は他から取得できない
+	_, err = rl.Lock(ctx, "test-redlock", time.Second)
+	assert.ErrorIs(t, err, ErrLockNotAcquired)
+
+	assert.NoError(t, lease.Extend(ctx, time.Minute))
+	assert.Greater(t, lease.Validity(), 59*time.Second)
+
+	assert.NoError(t, lease.Unlock(ctx))
+	for _, rc := range clients {
+		n, err := rc.Exists(ctx, lease.Key())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), n)
+	}
+	assert.ErrorIs(t, lease.Extend(ctx, time.Second), ErrLockNotOwned)
+}
+
+func TestRedlock_Quorum(t *testing.T) {
+	ctx := context.Background()
+	clients := newRedlockClients(t, 3)
+	rl, err := NewRedlock(clients...)
+	assert.NoError(t, err)
+
+	// 1台が他の保持者に取られていても過半数で取得できる
+	assert.NoError(t, clients[0].SetContext(ctx, "lock:test-redlock-quorum", "other", time.Second))
+	lease, err := rl.Lock(ctx, "test-redlock-quorum", time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, lease.Unlock(ctx))
+	got, err := clients[0].GetContext(ctx, "lock:test-redlock-quorum")
+	assert.NoError(t, err)
+	assert.Equal(t, "other", got)
+
+	// 2台が取られていると取得できず、取得できたノードも解放される
+	assert.NoError(t, clients[1].SetContext(ctx, "lock:test-redlock-quorum", "other", time.Second))
+	_, err = rl.Lock(ctx, "test-redlock-quorum", time.Second)
+	assert.ErrorIs(t, err, ErrLockNotAcquired)
+	n, err := clients[2].Exists(ctx, "lock:test-redlock-quorum")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	_, _ = clients[0].Del(ctx, "lock:test-redlock-quorum")
+	_, _ = clients[1].Del(ctx, "lock:test-redlock-quorum")
+}
+
+func TestNewRedlock(t *testing.T) {
+	_, err := NewRedlock()
+	assert.ErrorIs(t, err, ErrConfig)
+}
@@ -0,0 +1,9 @@
+package redis
+
+import "context"
+
+// Eval Lua スクリプトを実行し、結果を返す
+// 複数のキーをまとめてアトミックに更新する場合に使用する。スクリプトが nil を返した場合は redis.Nil を返す。
+func (rc *RedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return rc.client.Eval(ctx, script, keys, args...).Result()
+}
@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Eval(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-eval"
+	_, _ = r.Del(ctx, key)
+
+	result, err := r.Eval(ctx, "redis.call('SET', KEYS[1], ARGV[1]); return redis.call('GET', KEYS[1])", []string{key}, "v")
+	assert.NoError(t, err)
+	assert.Equal(t, "v", result)
+	_, _ = r.Del(ctx, key)
+}
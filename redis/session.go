@@ -0,0 +1,169 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"valley-pkg/crypter"
+	"valley-pkg/parser"
+	"valley-pkg/rand"
+)
+
+// セッションIDの生成設定
+const (
+	sessionIDPrefix  = "sess"
+	sessionIDEntropy = 32
+)
+
+// SessionStore Redis に保存するセッション
+// セッションIDはチェックサム付きのトークンで、形式が不正なIDは Redis に問い合わせずに ErrNotFound とする。
+// 有効期限は最後に Create / Touch した時点から数える（スライディング期限）。
+type SessionStore struct {
+	redis   *RedisClient
+	prefix  string
+	ttl     time.Duration
+	parser  parser.Parser
+	crypter crypter.Crypter
+}
+
+// SessionOption SessionStore の設定
+type SessionOption func(*SessionStore)
+
+// WithSessionParser セッションデータの変換に使うパーサーを指定する。既定はJSON
+func WithSessionParser(p parser.Parser) SessionOption {
+	return func(s *SessionStore) {
+		s.parser = p
+	}
+}
+
+// WithSessionCrypter 保存するセッションデータを暗号化する
+func WithSessionCrypter(c crypter.Crypter) SessionOption {
+	return func(s *SessionStore) {
+		s.crypter = c
+	}
+}
+
+// NewSessionStore セッション名と有効期限を指定して生成する
+func NewSessionStore(rc *RedisClient, name string, ttl time.Duration, opts ...SessionOption) *SessionStore {
+	s := &SessionStore{
+		redis:  rc,
+		prefix: fmt.Sprintf("session:%s:", name),
+		ttl:    ttl,
+		parser: jsonParser,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create data を保存した新しいセッションを作成し、セッションIDを返す
+func (s *SessionStore) Create(ctx context.Context, data any) (string, error) {
+	id, err := rand.GenerateToken(sessionIDPrefix, sessionIDEntropy)
+	if err != nil {
+		return "", err
+	}
+	b, err := s.encode(data)
+	if err != nil {
+		return "", err
+	}
+	ok, err := s.redis.client.SetNX(ctx, s.prefix+id, b, s.ttl).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("session id collision")
+	}
+	return id, nil
+}
+
+// Get セッションデータを out に変換する
+// セッションが存在しないか期限切れの場合は ErrNotFound を返す。
+func (s *SessionStore) Get(ctx context.Context, id string, out any) error {
+	key, err := s.key(id)
+	if err != nil {
+		return err
+	}
+	b, err := s.redis.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return notFound(key, err)
+	}
+	return s.decode(b, out)
+}
+
+// Save 既存のセッションのデータを data で置き換える。有効期限は変更しない
+// セッションが存在しない場合は ErrNotFound を返す。
+func (s *SessionStore) Save(ctx context.Context, id string, data any) error {
+	key, err := s.key(id)
+	if err != nil {
+		return err
+	}
+	b, err := s.encode(data)
+	if err != nil {
+		return err
+	}
+	err = s.redis.client.SetArgs(ctx, key, b, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	if err != nil {
+		return notFound(key, err)
+	}
+	return nil
+}
+
+// Touch セッションの有効期限を延長する
+// セッションが存在しない場合は ErrNotFound を返す。
+func (s *SessionStore) Touch(ctx context.Context, id string) error {
+	key, err := s.key(id)
+	if err != nil {
+		return err
+	}
+	ok, err := s.redis.client.Expire(ctx, key, s.ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	return nil
+}
+
+// Destroy セッションを削除する。存在しない場合もエラーにしない
+func (s *SessionStore) Destroy(ctx context.Context, id string) error {
+	key, err := s.key(id)
+	if err != nil {
+		return nil
+	}
+	return s.redis.client.Del(ctx, key).Err()
+}
+
+// key セッションIDを検証してキーを返す
+func (s *SessionStore) key(id string) (string, error) {
+	if err := rand.ValidateToken(id, sessionIDPrefix); err != nil {
+		return "", fmt.Errorf("session %q: %w", id, errors.Join(ErrNotFound, err))
+	}
+	return s.prefix + id, nil
+}
+
+func (s *SessionStore) encode(data any) ([]byte, error) {
+	b, err := s.parser.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if s.crypter == nil {
+		return b, nil
+	}
+	return s.crypter.EnCrypt(b)
+}
+
+func (s *SessionStore) decode(b []byte, out any) error {
+	if s.crypter != nil {
+		var err error
+		if b, err = s.crypter.DeCrypt(b); err != nil {
+			return err
+		}
+	}
+	return s.parser.Unmarshal(b, out)
+}
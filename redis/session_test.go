@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"valley-pkg/crypter"
+	"valley-pkg/parser"
+	"valley-pkg/rand"
+)
+
+type testSession struct {
+	UserID string `json:"user_id" msgpack:"user_id"`
+	Role   string `json:"role" msgpack:"role"`
+}
+
+func TestSessionStore(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	aes, err := crypter.NewAes("0123456789abcdef0123456789abcdef", "abcdef0123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []SessionOption
+	}{
+		{name: "正常値: JSON"},
+		{name: "正常値: MessagePack", opts: []SessionOption{WithSessionParser(&parser.MsgpackParser{})}},
+		{name: "正常値: 暗号化", opts: []SessionOption{WithSessionCrypter(aes)}},
+	}
+
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSessionStore(r, "test", time.Minute, tt.opts...)
+
+			id, err := s.Create(ctx, testSession{UserID: "u1", Role: "admin"})
+			assert.NoError(t, err)
+			assert.NoError(t, rand.ValidateToken(id, sessionIDPrefix))
+
+			var got testSession
+			assert.NoError(t, s.Get(ctx, id, &got))
+			assert.Equal(t, testSession{UserID: "u1", Role: "admin"}, got)
+
+			assert.NoError(t, s.Save(ctx, id, testSession{UserID: "u1", Role: "guest"}))
+			assert.NoError(t, s.Get(ctx, id, &got))
+			assert.Equal(t, "guest", got.Role)
+			ttl, err := r.TTL(ctx, s.prefix+id)
+			assert.NoError(t, err)
+			assert.Greater(t, ttl, time.Duration(0))
+
+			assert.NoError(t, s.Touch(ctx, id))
+			assert.NoError(t, s.Destroy(ctx, id))
+			assert.ErrorIs(t, s.Get(ctx, id, &got), ErrNotFound)
+			assert.ErrorIs(t, s.Touch(ctx, id), ErrNotFound)
+			assert.ErrorIs(t, s.Save(ctx, id, got), ErrNotFound)
+		})
+	}
+}
+
+func TestSessionStore_InvalidID(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	s := NewSessionStore(r, "test", time.Minute)
+
+	// 形式が不正なIDは存在しないものとして扱う
+	var got testSession
+	err = s.Get(ctx, "sess_forged", &got)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, err, rand.ErrInvalidToken)
+	assert.NoError(t, s.Destroy(ctx, "sess_forged"))
+}
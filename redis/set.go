@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultChunkSize 分割版のコマンドで1回に送るメンバー数の既定値
+const DefaultChunkSize = 1000
+
+// SAdd セットにメンバーを追加し、新規に追加された件数を返す
+func (rc *RedisClient) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return rc.client.SAdd(ctx, key, members...).Result()
+}
+
+// SRem セットからメンバーを削除し、削除した件数を返す
+func (rc *RedisClient) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return rc.client.SRem(ctx, key, members...).Result()
+}
+
+// SIsMember メンバーがセットに含まれるかを返す
+func (rc *RedisClient) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	return rc.client.SIsMember(ctx, key, member).Result()
+}
+
+// SMIsMember 各メンバーがセットに含まれるかを members と同じ順で返す
+func (rc *RedisClient) SMIsMember(ctx context.Context, key string, members ...interface{}) ([]bool, error) {
+	return rc.client.SMIsMember(ctx, key, members...).Result()
+}
+
+// SMembers セットの全メンバーを返す
+// 大きなセットでは Redis をブロックするため、SScan の使用を検討すること。
+func (rc *RedisClient) SMembers(ctx context.Context, key string) ([]string, error) {
+	return rc.client.SMembers(ctx, key).Result()
+}
+
+// SInter keys の全てのセットに含まれるメンバーを返す
+func (rc *RedisClient) SInter(ctx context.Context, keys ...string) ([]string, error) {
+	return rc.client.SInter(ctx, keys...).Result()
+}
+
+// SCard セットのメンバー数を返す
+func (rc *RedisClient) SCard(ctx context.Context, key string) (int64, error) {
+	return rc.client.SCard(ctx, key).Result()
+}
+
+// SAddChunked 大量のメンバーを chunkSize 件ずつに分けてパイプラインで追加し、新規に追加された件数を返す
+// chunkSize が 0 以下の場合は DefaultChunkSize を使用する。
+func (rc *RedisClient) SAddChunked(ctx context.Context, key string, chunkSize int, members []string) (int64, error) {
+	cmds, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, chunk := range chunks(members, chunkSize) {
+			p.SAdd(ctx, key, chunk...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sumIntCmds(cmds), nil
+}
+
+// SRemChunked 大量のメンバーを chunkSize 件ずつに分けてパイプラインで削除し、削除した件数を返す
+// chunkSize が 0 以下の場合は DefaultChunkSize を使用する。
+func (rc *RedisClient) SRemChunked(ctx context.Context, key string, chunkSize int, members []string) (int64, error) {
+	cmds, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, chunk := range chunks(members, chunkSize) {
+			p.SRem(ctx, key, chunk...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sumIntCmds(cmds), nil
+}
+
+// SMIsMemberChunked 大量のメンバーを chunkSize 件ずつに分けて所属を確認し、members と同じ順で返す
+// chunkSize が 0 以下の場合は DefaultChunkSize を使用する。
+func (rc *RedisClient) SMIsMemberChunked(ctx context.Context, key string, chunkSize int, members []string) ([]bool, error) {
+	cmds, err := rc.client.Pipelined(ctx, func(p Pipeliner) error {
+		for _, chunk := range chunks(members, chunkSize) {
+			p.SMIsMember(ctx, key, chunk...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, 0, len(members))
+	for _, cmd := range cmds {
+		result = append(result, cmd.(*redis.BoolSliceCmd).Val()...)
+	}
+	return result, nil
+}
+
+// chunks members を size 件ずつの引数リストに分割する
+func chunks(members []string, size int) [][]interface{} {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	var result [][]interface{}
+	for start := 0; start < len(members); start += size {
+		end := min(start+size, len(members))
+		chunk := make([]interface{}, 0, end-start)
+		for _, m := range members[start:end] {
+			chunk = append(chunk, m)
+		}
+		result = append(result, chunk)
+	}
+	return result
+}
+
+// sumIntCmds パイプラインで実行した整数の結果を合計する
+func sumIntCmds(cmds []Cmder) int64 {
+	var n int64
+	for _, cmd := range cmds {
+		if c, ok := cmd.(*redis.IntCmd); ok {
+			n += c.Val()
+		}
+	}
+	return n
+}
@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Set(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	_ = r.client.Del(ctx, "test-set-a", "test-set-b").Err()
+
+	n, err := r.SAdd(ctx, "test-set-a", "x", "y", "z")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	_, err = r.SAdd(ctx, "test-set-b", "y", "z", "w")
+	assert.NoError(t, err)
+
+	ok, err := r.SIsMember(ctx, "test-set-a", "x")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	oks, err := r.SMIsMember(ctx, "test-set-a", "x", "w")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, oks)
+
+	inter, err := r.SInter(ctx, "test-set-a", "test-set-b")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"y", "z"}, inter)
+
+	n, err = r.SRem(ctx, "test-set-a", "x", "missing")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	all, err := r.SMembers(ctx, "test-set-a")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"y", "z"}, all)
+}
+
+func TestRedisClient_SetChunked(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-set-chunked"
+	_ = r.client.Del(ctx, key).Err()
+
+	members := make([]string, 25)
+	for i := range members {
+		members[i] = fmt.Sprintf("m%d", i)
+	}
+
+	n, err := r.SAddChunked(ctx, key, 10, members)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), n)
+	card, err := r.SCard(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), card)
+
+	oks, err := r.SMIsMemberChunked(ctx, key, 4, []string{"m0", "x", "m24", "m5", "y"})
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true, true, false}, oks)
+
+	n, err = r.SRemChunked(ctx, key, 0, members[:20])
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), n)
+
+	// 空のリストは何もしない
+	n, err = r.SAddChunked(ctx, key, 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []string
+		size    int
+		want    []int
+	}{
+		{name: "正常値: 割り切れる", members: make([]string, 4), size: 2, want: []int{2, 2}},
+		{name: "正常値: 端数", members: make([]string, 5), size: 2, want: []int{2, 2, 1}},
+		{name: "正常値: 既定のサイズ", members: make([]string, 3), size: 0, want: []int{3}},
+		{name: "正常値: 空", members: nil, size: 2, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, c := range chunks(tt.members, tt.size) {
+				got = append(got, len(c))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
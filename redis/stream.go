@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// XMessage ストリームのエントリ
+type XMessage = redis.XMessage
+
+// XAddArgs パイプラインで XAdd する場合の引数
+type XAddArgs = redis.XAddArgs
+
+// XAdd ストリームの末尾にエントリを追加し、採番された ID を返す
+// maxLen が 0 より大きい場合は、おおよそ maxLen 件を超えた古いエントリを削除する。
+func (rc *RedisClient) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	return rc.client.XAdd(ctx, args).Result()
+}
+
+// XLen ストリームのエントリ数を返す
+func (rc *RedisClient) XLen(ctx context.Context, stream string) (int64, error) {
+	return rc.client.XLen(ctx, stream).Result()
+}
+
+// XRange start から stop まで（"-" と "+" で先頭と末尾）のエントリを返す
+func (rc *RedisClient) XRange(ctx context.Context, stream, start, stop string) ([]XMessage, error) {
+	return rc.client.XRange(ctx, stream, start, stop).Result()
+}
+
+// XGroupCreate コンシューマーグループを作成する
+// start は "$"（以降の新しいエントリ）または "0"（先頭から）を指定する。
+// ストリームが存在しない場合は作成し、グループが既に存在する場合はエラーにしない。
+func (rc *RedisClient) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	err := rc.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// XReadGroup コンシューマーグループとして未配信のエントリを最大 count 件読み込む
+// エントリが無い場合は最大 block 待ち、それでも無い場合は空のスライスを返す。block が 0 の場合は待たない。
+func (rc *RedisClient) XReadGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]XMessage, error) {
+	if block <= 0 {
+		// go-redis では 0 が無期限待ちとなるため、負の値で BLOCK を指定しない
+		block = -1
+	}
+	streams, err := rc.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, nil
+	}
+	return streams[0].Messages, nil
+}
+
+// XAck 処理が完了したエントリを確認済みにし、確認した件数を返す
+func (rc *RedisClient) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	return rc.client.XAck(ctx, stream, group, ids...).Result()
+}
+
+// XPending グループの未確認のエントリ数を返す
+func (rc *RedisClient) XPending(ctx context.Context, stream, group string) (int64, error) {
+	pending, err := rc.client.XPending(ctx, stream, group).Result()
+	if err != nil {
+		return 0, err
+	}
+	return pending.Count, nil
+}
+
+// XAutoClaim minIdle 以上確認されていないエントリを consumer に付け替えて最大 count 件返す
+// 異常終了したコンシューマーのエントリを回収するために使用する。
+// 戻り値の next は次の呼び出しに渡す開始 ID で、"0-0" の場合は全て走査済み。
+func (rc *RedisClient) XAutoClaim(ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64) ([]XMessage, string, error) {
+	return rc.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+}
+
+// StreamConsumer コンシューマーグループの一員としてストリームを読み込み続ける
+// ハンドラーが成功したエントリは確認済みにし、失敗したエントリは未確認のまま残して
+// ClaimIdle を過ぎた後に（自分または他のコンシューマーが）再処理する。
+type StreamConsumer struct {
+	redis    *RedisClient
+	stream   string
+	group    string
+	consumer string
+
+	// Count 1回に読み込む最大件数
+	Count int64
+	// Block エントリが無い場合に待つ時間。0 以下の場合は DefaultStreamBlock を使用する
+	Block time.Duration
+	// ClaimIdle この時間以上確認されていないエントリを回収する。0 の場合は回収しない
+	ClaimIdle time.Duration
+}
+
+// DefaultStreamBlock StreamConsumer でエントリが無い場合に待つ時間の既定値
+const DefaultStreamBlock = time.Second
+
+// NewStreamConsumer コンシューマーを生成する。グループが無い場合は先頭から読み込むグループを作成する
+func NewStreamConsumer(ctx context.Context, rc *RedisClient, stream, group, consumer string) (*StreamConsumer, error) {
+	if err := rc.XGroupCreate(ctx, stream, group, "0"); err != nil {
+		return nil, err
+	}
+	return &StreamConsumer{
+		redis:     rc,
+		stream:    stream,
+		group:     group,
+		consumer:  consumer,
+		Count:     10,
+		Block:     DefaultStreamBlock,
+		ClaimIdle: time.Minute,
+	}, nil
+}
+
+// Run ctx が終了するまでエントリを読み込み、エントリごとに handler を呼び出す
+// ctx の終了時は nil を返し、Redis のエラーはそのまま返す。
+func (sc *StreamConsumer) Run(ctx context.Context, handler func(ctx context.Context, msg XMessage) error) error {
+	for ctx.Err() == nil {
+		msgs, err := sc.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+
+		for _, msg := range msgs {
+			if err := handler(ctx, msg); err != nil {
+				continue
+			}
+			// 処理済みのエントリは ctx が終了していても確認する
+			if _, err := sc.redis.XAck(context.WithoutCancel(ctx), sc.stream, sc.group, msg.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// next 回収対象のエントリがあればそれを、無ければ新しいエントリを返す
+func (sc *StreamConsumer) next(ctx context.Context) ([]XMessage, error) {
+	if sc.ClaimIdle > 0 {
+		claimed, _, err := sc.redis.XAutoClaim(ctx, sc.stream, sc.group, sc.consumer, sc.ClaimIdle, "0", sc.Count)
+		if err != nil {
+			return nil, err
+		}
+		if len(claimed) > 0 {
+			return claimed, nil
+		}
+	}
+	return sc.redis.XReadGroup(ctx, sc.stream, sc.group, sc.consumer, sc.Count, sc.block())
+}
+
+// block エントリが無い場合に待つ時間を返す
+// 待たずに読み込みを繰り返したり無期限に待ったりしないよう、0 以下の場合は DefaultStreamBlock とする。
+func (sc *StreamConsumer) block() time.Duration {
+	if sc.Block <= 0 {
+		return DefaultStreamBlock
+	}
+	return sc.Block
+}
@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_Stream(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const stream, group = "test-stream", "test-group"
+	_, _ = r.Del(ctx, stream)
+
+	assert.NoError(t, r.XGroupCreate(ctx, stream, group, "$"))
+	// 既に存在するグループはエラーにしない
+	assert.NoError(t, r.XGroupCreate(ctx, stream, group, "$"))
+
+	id1, err := r.XAdd(ctx, stream, map[string]interface{}{"n": "1"}, 0)
+	assert.NoError(t, err)
+	_, err = r.XAdd(ctx, stream, map[string]interface{}{"n": "2"}, 100)
+	assert.NoError(t, err)
+	n, err := r.XLen(ctx, stream)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	msgs, err := r.XReadGroup(ctx, stream, group, "c1", 1, 0)
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, id1, msgs[0].ID)
+	assert.Equal(t, "1", msgs[0].Values["n"])
+
+	acked, err := r.XAck(ctx, stream, group, msgs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), acked)
+
+	// c2 が読み込んだまま確認しなかったエントリを c3 が回収する
+	msgs, err = r.XReadGroup(ctx, stream, group, "c2", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	pending, err := r.XPending(ctx, stream, group)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pending)
+
+	time.Sleep(50 * time.Millisecond)
+	claimed, _, err := r.XAutoClaim(ctx, stream, group, "c3", 10*time.Millisecond, "0", 10)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, msgs[0].ID, claimed[0].ID)
+	_, err = r.XAck(ctx, stream, group, claimed[0].ID)
+	assert.NoError(t, err)
+
+	// 未配信のエントリが無い場合は空
+	msgs, err = r.XReadGroup(ctx, stream, group, "c1", 1, 50*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	msgs, err = r.XReadGroup(ctx, stream, group, "c1", 1, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	all, err := r.XRange(ctx, stream, "-", "+")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestStreamConsumer(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	const stream = "test-stream-consumer"
+	_, _ = r.Del(ctx, stream)
+
+	for _, n := range []string{"1", "fail", "3"} {
+		_, err := r.XAdd(ctx, stream, map[string]interface{}{"n": n}, 0)
+		assert.NoError(t, err)
+	}
+
+	sc, err := NewStreamConsumer(ctx, r, stream, "test-group", "c1")
+	assert.NoError(t, err)
+	sc.Block = 20 * time.Millisecond
+	sc.ClaimIdle = 50 * time.Millisecond
+
+	// 1回失敗したエントリは回収されて再処理される
+	var got []string
+	failed := false
+	err = sc.Run(ctx, func(ctx context.Context, msg XMessage) error {
+		n := msg.Values["n"].(string)
+		if n == "fail" && !failed {
+			failed = true
+			return assert.AnError
+		}
+		got = append(got, n)
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "fail"}, got)
+
+	pending, err := r.XPending(context.Background(), stream, "test-group")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), pending)
+}
+
+func TestStreamConsumer_Block(t *testing.T) {
+	tests := []struct {
+		name  string
+		block time.Duration
+		want  time.Duration
+	}{
+		{name: "正常値: 指定した値", block: 5 * time.Second, want: 5 * time.Second},
+		{name: "異常値: 0 は既定値", block: 0, want: DefaultStreamBlock},
+		{name: "異常値: 負の値は既定値", block: -time.Second, want: DefaultStreamBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := &StreamConsumer{Block: tt.block}
+			if got := sc.block(); got != tt.want {
+				t.Errorf("待つ時間が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Z ソート済みセットのメンバーとスコア
+type Z = redis.Z
+
+// ZAdd ソート済みセットにメンバーを追加し、新規に追加された件数を返す
+func (rc *RedisClient) ZAdd(ctx context.Context, key string, members ...Z) (int64, error) {
+	return rc.client.ZAdd(ctx, key, members...).Result()
+}
+
+// ZIncrBy メンバーのスコアに increment を加算し、加算後のスコアを返す
+func (rc *RedisClient) ZIncrBy(ctx context.Context, key string, increment float64, member string) (float64, error) {
+	return rc.client.ZIncrBy(ctx, key, increment, member).Result()
+}
+
+// ZRangeWithScores スコアの昇順で start から stop までのメンバーを返す
+func (rc *RedisClient) ZRangeWithScores(ctx context.Context, key string, start, stop int64) ([]Z, error) {
+	return rc.client.ZRangeWithScores(ctx, key, start, stop).Result()
+}
+
+// ZRevRangeWithScores スコアの降順で start から stop までのメンバーを返す
+func (rc *RedisClient) ZRevRangeWithScores(ctx context.Context, key string, start, stop int64) ([]Z, error) {
+	return rc.client.ZRevRangeWithScores(ctx, key, start, stop).Result()
+}
+
+// ZRank スコアの昇順での順位（0始まり）を返す
+// メンバーが存在しない場合は ErrNotFound を返す。
+func (rc *RedisClient) ZRank(ctx context.Context, key, member string) (int64, error) {
+	rank, err := rc.client.ZRank(ctx, key, member).Result()
+	if err != nil {
+		return 0, notFound(member, err)
+	}
+	return rank, nil
+}
+
+// ZRevRank スコアの降順での順位（0始まり）を返す
+// メンバーが存在しない場合は ErrNotFound を返す。
+func (rc *RedisClient) ZRevRank(ctx context.Context, key, member string) (int64, error) {
+	rank, err := rc.client.ZRevRank(ctx, key, member).Result()
+	if err != nil {
+		return 0, notFound(member, err)
+	}
+	return rank, nil
+}
+
+// ZScore メンバーのスコアを返す
+// メンバーが存在しない場合は ErrNotFound を返す。
+func (rc *RedisClient) ZScore(ctx context.Context, key, member string) (float64, error) {
+	score, err := rc.client.ZScore(ctx, key, member).Result()
+	if err != nil {
+		return 0, notFound(member, err)
+	}
+	return score, nil
+}
+
+// ZRem ソート済みセットからメンバーを削除し、削除した件数を返す
+func (rc *RedisClient) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return rc.client.ZRem(ctx, key, members...).Result()
+}
+
+// ZCard ソート済みセットのメンバー数を返す
+func (rc *RedisClient) ZCard(ctx context.Context, key string) (int64, error) {
+	return rc.client.ZCard(ctx, key).Result()
+}
+
+// LeaderboardEntry ランキングの1行
+type LeaderboardEntry struct {
+	Member string
+	Score  float64
+	// Rank 1始まりの順位
+	Rank int64
+}
+
+// Leaderboard ソート済みセットを使ったスコアの降順ランキング
+type Leaderboard struct {
+	redis *RedisClient
+	key   string
+}
+
+// NewLeaderboard ランキング名を指定してランキングを生成
+func NewLeaderboard(rc *RedisClient, name string) *Leaderboard {
+	return &Leaderboard{
+		redis: rc,
+		key:   fmt.Sprintf("leaderboard:%s", name),
+	}
+}
+
+// AddScore メンバーのスコアに score を加算し、加算後のスコアを返す
+func (lb *Leaderboard) AddScore(ctx context.Context, member string, score float64) (float64, error) {
+	return lb.redis.client.ZIncrBy(ctx, lb.key, score, member).Result()
+}
+
+// SetScore メンバーのスコアを score で上書きする
+func (lb *Leaderboard) SetScore(ctx context.Context, member string, score float64) error {
+	return lb.redis.client.ZAdd(ctx, lb.key, Z{Score: score, Member: member}).Err()
+}
+
+// Remove メンバーをランキングから削除する
+func (lb *Leaderboard) Remove(ctx context.Context, member string) error {
+	return lb.redis.client.ZRem(ctx, lb.key, member).Err()
+}
+
+// Top 上位 n 件を返す
+func (lb *Leaderboard) Top(ctx context.Context, n int64) ([]LeaderboardEntry, error) {
+	return lb.Page(ctx, 1, n)
+}
+
+// Page 1ページ size 件として page ページ目（1始まり）を返す
+func (lb *Leaderboard) Page(ctx context.Context, page, size int64) ([]LeaderboardEntry, error) {
+	if page < 1 || size < 1 {
+		return nil, nil
+	}
+	start := (page - 1) * size
+	return lb.rangeByRank(ctx, start, start+size-1)
+}
+
+// Rank メンバーの順位（1始まり）とスコアを返す
+// メンバーが存在しない場合は ErrNotFound を返す。
+func (lb *Leaderboard) Rank(ctx context.Context, member string) (LeaderboardEntry, error) {
+	rank, err := lb.redis.ZRevRank(ctx, lb.key, member)
+	if err != nil {
+		return LeaderboardEntry{}, err
+	}
+	score, err := lb.redis.ZScore(ctx, lb.key, member)
+	if err != nil {
+		return LeaderboardEntry{}, err
+	}
+	return LeaderboardEntry{Member: member, Score: score, Rank: rank + 1}, nil
+}
+
+// AroundMember メンバーの前後 window 件ずつを含めたランキングを返す
+// メンバーが存在しない場合は ErrNotFound を返す。
+func (lb *Leaderboard) AroundMember(ctx context.Context, member string, window int64) ([]LeaderboardEntry, error) {
+	rank, err := lb.redis.ZRevRank(ctx, lb.key, member)
+	if err != nil {
+		return nil, err
+	}
+	start := rank - window
+	if start < 0 {
+		start = 0
+	}
+	return lb.rangeByRank(ctx, start, rank+window)
+}
+
+// Len ランキングのメンバー数を返す
+func (lb *Leaderboard) Len(ctx context.Context) (int64, error) {
+	return lb.redis.client.ZCard(ctx, lb.key).Result()
+}
+
+// rangeByRank 0始まりの順位 start から stop までを降順で返す
+func (lb *Leaderboard) rangeByRank(ctx context.Context, start, stop int64) ([]LeaderboardEntry, error) {
+	zs, err := lb.redis.client.ZRevRangeWithScores(ctx, lb.key, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]LeaderboardEntry, len(zs))
+	for i, z := range zs {
+		entries[i] = LeaderboardEntry{
+			Member: fmt.Sprint(z.Member),
+			Score:  z.Score,
+			Rank:   start + int64(i) + 1,
+		}
+	}
+	return entries, nil
+}
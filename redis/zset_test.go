@@ -0,0 +1,103 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_ZSet(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	const key = "test-zset"
+	_ = r.client.Del(ctx, key).Err()
+
+	n, err := r.ZAdd(ctx, key, Z{Score: 1, Member: "a"}, Z{Score: 3, Member: "c"}, Z{Score: 2, Member: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	zs, err := r.ZRangeWithScores(ctx, key, 0, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []Z{{Score: 1, Member: "a"}, {Score: 2, Member: "b"}, {Score: 3, Member: "c"}}, zs)
+
+	rank, err := r.ZRank(ctx, key, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rank)
+	rank, err = r.ZRevRank(ctx, key, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), rank)
+
+	_, err = r.ZRank(ctx, key, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = r.ZScore(ctx, key, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLeaderboard(t *testing.T) {
+	r, err := NewRedisClient(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx := context.Background()
+	lb := NewLeaderboard(r, "test")
+	_ = r.client.Del(ctx, lb.key).Err()
+
+	for i, m := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, lb.SetScore(ctx, m, float64(i*10)))
+	}
+	score, err := lb.AddScore(ctx, "a", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), score)
+
+	top, err := lb.Top(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{
+		{Member: "a", Score: 100, Rank: 1},
+		{Member: "e", Score: 40, Rank: 2},
+	}, top)
+
+	page, err := lb.Page(ctx, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []LeaderboardEntry{
+		{Member: "d", Score: 30, Rank: 3},
+		{Member: "c", Score: 20, Rank: 4},
+	}, page)
+
+	entry, err := lb.Rank(ctx, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, LeaderboardEntry{Member: "c", Score: 20, Rank: 4}, entry)
+
+	// 先頭付近は範囲外を切り詰める
+	around, err := lb.AroundMember(ctx, "e", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "e", "d"}, members(around))
+	around, err = lb.AroundMember(ctx, "a", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "e", "d"}, members(around))
+
+	_, err = lb.Rank(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = lb.AroundMember(ctx, "missing", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.NoError(t, lb.Remove(ctx, "a"))
+	l, err := lb.Len(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), l)
+}
+
+func members(entries []LeaderboardEntry) []string {
+	ms := make([]string, len(entries))
+	for i, e := range entries {
+		ms[i] = e.Member
+	}
+	return ms
+}
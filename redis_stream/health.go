@@ -0,0 +1,28 @@
+package redis_stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"valley-pkg/health"
+)
+
+// ErrReplicationLag はレプリケーションの取り込みが遅れている場合のエラー
+var ErrReplicationLag = errors.New("replication lag exceeded")
+
+// HealthChecker は IncomingReplicationQueue が最後に状態ストレージから更新を取得してから maxLag を超えていないかを確認する health.Checker を返す
+// 一度も取得していない場合も異常とする。
+func (tc *ReplicatedTicketCache) HealthChecker(maxLag time.Duration) health.Checker {
+	return health.CheckerFunc(func(context.Context) error {
+		last := tc.lastPolled.Load()
+		if last == 0 {
+			return fmt.Errorf("no updates polled yet: %w", ErrReplicationLag)
+		}
+		if lag := time.Since(time.UnixMilli(last)); lag > maxLag {
+			return fmt.Errorf("last polled %s ago: %w", lag.Truncate(time.Millisecond), ErrReplicationLag)
+		}
+		return nil
+	})
+}
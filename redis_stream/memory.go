@@ -2,11 +2,11 @@ package redis_stream
 
 import (
 	"fmt"
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"regexp"
 	"strconv"
 	"time"
+	"valley-pkg/logging"
 )
 
 // ローカル、インメモリ状態ストレージ。
@@ -19,12 +19,12 @@ type memoryReplicator struct {
 	replTS          time.Time
 	replCount       int
 	replIdValidator *regexp.Regexp
+	logger          logging.Logger
 }
 
-func New(cfg *viper.Viper) *memoryReplicator {
-	logger.WithFields(logrus.Fields{
-		"repl_id": "N/A",
-	}).Debugf("Initializing cache")
+func New(cfg *viper.Viper, opts ...Option) *memoryReplicator {
+	o := newOptions(opts)
+	o.logger.Debug("Initializing cache", logging.F("app", "open_match"), logging.F("component", "cache"), logging.F("repl_id", "N/A"))
 	return &memoryReplicator{
 		// replIdValidator is a regular expression that can be used to match
 		// replication id strings, which are redis stream entry ids.
@@ -34,15 +34,14 @@ func New(cfg *viper.Viper) *memoryReplicator {
 		replTS:          time.Now(),
 		replCount:       0,
 		cfg:             cfg,
+		logger:          o.logger.With(logging.F("app", "open_match"), logging.F("component", "cache")),
 	}
 }
 
 // GetUpdates は、statestore/redis モジュールが Redis Stream XRANGE コマンドを処理する方法を模倣します。
 // https://redis.io/docs/data-types/streams/#querying-by-range-xrange-and-xrevrange
 func (rc *memoryReplicator) GetUpdates() (out []*StateUpdate) {
-	logger := logger.WithFields(logrus.Fields{
-		"direction": "getUpdates",
-	})
+	logger := rc.logger.With(logging.F("direction", "getUpdates"))
 
 	// TODO: histogram for number of updates, length of poll
 
@@ -67,7 +66,7 @@ func (rc *memoryReplicator) GetUpdates() (out []*StateUpdate) {
 	}
 
 	if len(out) > 0 {
-		logger.Debugf("read %v updates from state storage", len(out))
+		logger.Debug("read updates from state storage", logging.F("update_count", len(out)))
 	}
 
 	return out
@@ -76,9 +75,7 @@ func (rc *memoryReplicator) GetUpdates() (out []*StateUpdate) {
 // SendUpdates は、statestore/redis モジュールが Redis Stream の XADD コマンドを処理する方法を模擬します。
 // https://redis.io/docs/data-types/streams/#streams-basics
 func (rc *memoryReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse {
-	logger := logger.WithFields(logrus.Fields{
-		"direction": "sendUpdates",
-	})
+	logger := rc.logger.With(logging.F("direction", "sendUpdates"))
 
 	out := make([]*StateResponse, 0)
 	for _, up := range updates {
@@ -91,7 +88,7 @@ func (rc *memoryReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 		rc.replChan <- up
 		out = append(out, &StateResponse{Result: replId, Err: nil})
 	}
-	logger.Tracef("%v updates applied to state storage for replication (maximum number set by OM_REDIS_PIPELINE_MAX_QUEUE_THRESHOLD config variable)", len(updates))
+	logger.Debug("updates applied to state storage for replication (maximum number set by OM_REDIS_PIPELINE_MAX_QUEUE_THRESHOLD config variable)", logging.F("update_count", len(updates)))
 
 	return out
 }
@@ -0,0 +1,42 @@
+package redis_stream
+
+import (
+	"valley-pkg/metrics"
+)
+
+// countBuckets 1回の処理で扱う件数のヒストグラムのバケット
+var countBuckets = []float64{0, 1, 5, 10, 50, 100, 500, 1000, 5000}
+
+// cacheMetrics ReplicatedTicketCache の計測器
+type cacheMetrics struct {
+	outgoingQueueTimeouts      metrics.Counter
+	outgoingUpdatesPerPoll     metrics.Histogram
+	incomingPerPoll            metrics.Histogram
+	incomingEmptyTimeouts      metrics.Counter
+	incomingProcessingTimeouts metrics.Counter
+	tickets                    metrics.Gauge
+	inactives                  metrics.Gauge
+	assignments                metrics.Gauge
+	expirationCycleDuration    metrics.Histogram
+	ticketsExpiredPerCycle     metrics.Histogram
+	inactivesExpiredPerCycle   metrics.Histogram
+	assignmentsExpiredPerCycle metrics.Histogram
+}
+
+// newCacheMetrics m の計測器を取得する
+func newCacheMetrics(m metrics.Meter) *cacheMetrics {
+	return &cacheMetrics{
+		outgoingQueueTimeouts:      m.Counter("om_cache_outgoing_queue_timeouts_total", "送信キューがしきい値に達する前にタイムアウトした回数"),
+		outgoingUpdatesPerPoll:     m.Histogram("om_cache_outgoing_updates_per_poll", "1回のバッチで状態ストレージへ送信した更新数", countBuckets),
+		incomingPerPoll:            m.Histogram("om_cache_incoming_updates_per_poll", "1回のポーリングで状態ストレージから受信した更新数", countBuckets),
+		incomingEmptyTimeouts:      m.Counter("om_cache_incoming_empty_timeouts_total", "更新を受信せずにポーリングがタイムアウトした回数"),
+		incomingProcessingTimeouts: m.Counter("om_cache_incoming_processing_timeouts_total", "受信した更新の適用がロックの保持時間を超えた回数"),
+		tickets:                    m.Gauge("om_cache_tickets", "ローカルキャッシュのチケット数"),
+		inactives:                  m.Gauge("om_cache_inactive_tickets", "ローカルキャッシュの非アクティブなチケット数"),
+		assignments:                m.Gauge("om_cache_assignments", "ローカルキャッシュのアサインメント数"),
+		expirationCycleDuration:    m.Histogram("om_cache_expiration_cycle_duration_seconds", "ローカルキャッシュの期限切れ処理の実行時間（秒）", nil),
+		ticketsExpiredPerCycle:     m.Histogram("om_cache_tickets_expired_per_cycle", "1回の期限切れ処理で削除したチケット数", countBuckets),
+		inactivesExpiredPerCycle:   m.Histogram("om_cache_inactives_expired_per_cycle", "1回の期限切れ処理で非アクティブの一覧から削除したチケット数", countBuckets),
+		assignmentsExpiredPerCycle: m.Histogram("om_cache_assignments_expired_per_cycle", "1回の期限切れ処理で削除したアサインメント数", countBuckets),
+	}
+}
@@ -0,0 +1,43 @@
+package redis_stream
+
+import (
+	"valley-pkg/lifecycle"
+	"valley-pkg/logging"
+)
+
+// Option レプリケーターの設定
+type Option func(*options)
+
+type options struct {
+	logger    logging.Logger
+	lifecycle *lifecycle.Lifecycle
+}
+
+// WithLogger ログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithLifecycle 接続プールの停止処理を登録する Lifecycle を指定する。指定しない場合は lifecycle.Default() に登録する
+// 接続のリトライは Lifecycle の終了処理の開始時に中断する。終了処理を開始した Lifecycle では NewRedis はエラーを返すため、
+// 再度生成する場合は lifecycle.New で新しい Lifecycle を指定する。
+func WithLifecycle(l *lifecycle.Lifecycle) Option {
+	return func(o *options) {
+		o.lifecycle = l
+	}
+}
+
+// newOptions は opts を適用した設定を返す
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.logger = logging.OrDefault(o.logger)
+	if o.lifecycle == nil {
+		o.lifecycle = lifecycle.Default()
+	}
+	return o
+}
@@ -6,14 +6,11 @@ import (
 	"fmt"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gomodule/redigo/redis"
-	"github.com/sirupsen/logrus"
-	"os"
-	"os/signal"
 	"regexp"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
+	"valley-pkg/logging"
 )
 
 const (
@@ -63,13 +60,18 @@ type redisReplicator struct {
 	cfg             *RedisConfig
 	replId          string
 	replIdValidator *regexp.Regexp
+	logger          logging.Logger
 }
 
-func NewRedis(config *RedisConfig) (*redisReplicator, error) {
-	// シグナル通知
-	ctx, cancel := context.WithCancel(context.Background())
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGTERM, syscall.SIGINT)
+func NewRedis(config *RedisConfig, opts ...Option) (*redisReplicator, error) {
+	o := newOptions(opts)
+
+	// 終了処理の開始時に接続のリトライを中断する
+	ctx := o.lifecycle.Context()
+	if ctx.Err() != nil {
+		// 終了処理を開始した Lifecycle は再利用できない
+		return nil, fmt.Errorf("lifecycle already shut down: %w", ctx.Err())
+	}
 
 	var err error
 
@@ -86,8 +88,8 @@ func NewRedis(config *RedisConfig) (*redisReplicator, error) {
 	writeRedisPort := config.OmRedisWritePort
 	writeRedisUrl := fmt.Sprintf("%s:%s", writeRedisHost, writeRedisPort)
 
-	rConnPool := getReadConnectionPool(ctx, *config, cancel, signalChan, readRedisUrl)
-	wConnPool := getWriteConnectionPool(ctx, *config, cancel, signalChan, writeRedisUrl)
+	rConnPool := getReadConnectionPool(ctx, *config, readRedisUrl)
+	wConnPool := getWriteConnectionPool(ctx, *config, writeRedisUrl)
 
 	rr := &redisReplicator{
 		replIdValidator: regexp.MustCompile(`^\d{13}-\d+$`),
@@ -95,6 +97,7 @@ func NewRedis(config *RedisConfig) (*redisReplicator, error) {
 		cfg:             config,
 		rConnPool:       rConnPool,
 		wConnPool:       wConnPool,
+		logger:          o.logger,
 	}
 
 	// ReadRedisプールから接続を取得。内部でDial（新規接続）できるかどうかを確認。
@@ -107,7 +110,7 @@ func NewRedis(config *RedisConfig) (*redisReplicator, error) {
 		//rConnLogger.WithFields(logrus.Fields{
 		//	"error": err,
 		//}).Debug("read redis connection error")
-		return nil, err
+		return nil, errors.Join(err, rr.Close())
 	}
 
 	// WriteRedisプールから接続を取得。内部でDial（新規接続）できるかどうかを確認。
@@ -116,20 +119,28 @@ func NewRedis(config *RedisConfig) (*redisReplicator, error) {
 		defer wConn.Close()
 	} else {
 		//rConnLogger.WithFields(logrus.Fields{"error": err,}).Debug("write redis connection error")
-		return nil, err
+		return nil, errors.Join(err, rr.Close())
 	}
 
-	// コンテキストがSIGINTまたはSIGTERMによってキャンセルされたかどうかを確認。
+	// 終了処理が開始されたかどうかを確認。
 	if ctx.Err() != nil {
 		//rConnLogger.Fatal("cancellation requested")
-		return nil, ctx.Err()
+		return nil, errors.Join(ctx.Err(), rr.Close())
 	}
 
+	o.lifecycle.Register("redis_stream", func(context.Context) error {
+		return rr.Close()
+	})
 	return rr, err
 }
 
+// Close は読み取り・書き込みの接続プールを閉じる
+func (rr *redisReplicator) Close() error {
+	return errors.Join(rr.rConnPool.Close(), rr.wConnPool.Close())
+}
+
 // getReadConnectionPool 読み取り専用のRedis接続プールの取得
-func getReadConnectionPool(ctx context.Context, config RedisConfig, cancel context.CancelFunc, sigChan chan os.Signal, readRedisUrl string) *redis.Pool {
+func getReadConnectionPool(ctx context.Context, config RedisConfig, readRedisUrl string) *redis.Pool {
 	return &redis.Pool{ // Redis read pool
 		MaxIdle:     config.OmRedisPoolMaxIdle,
 		MaxActive:   config.OmRedisPoolMaxActive,
@@ -159,40 +170,40 @@ func getReadConnectionPool(ctx context.Context, config RedisConfig, cancel conte
 					// Local closure var
 					var err error
 
-					select {
-					case <-sigChan:
-						cancel()
-					default:
-						//rConnLogger.Debug("dialing Redis read replica")
-
-						// Dial options
-						dialOptions := []redis.DialOption{
-							redis.DialUsername(config.OmRedisReadUser),
-							redis.DialPassword(config.OmRedisReadPassword),
-							redis.DialConnectTimeout(config.OmRedisPoolIdleTimeout), // Redis へ TCP 接続するまでの待ち時間の上限。
-							redis.DialReadTimeout(config.OmRedisPoolIdleTimeout),    // Redis にコマンドを送った後、レスポンスを読み取る待ち時間の上限。
-						}
-
-						// TLSを使用するオプションの追加
-						if config.OmRedisUseTls {
-							//rConnLogger.Info("OM_REDIS_USE_TLS is set to true, will attempt to connect to Redis read replica(s) using TLS.")
-							dialOptions = append(dialOptions, redis.DialUseTLS(true))
-						}
-
-						// 設定フラグが設定されている場合、TLS証明書の検証をスキップする (例: 自己署名証明書の場合)
-						if config.OmRedisTlsSkipVerify {
-							//rConnLogger.Info("OM_REDIS_TLS_SKIP_VERIFY is set to true, will attempt to connect to Redis read replica(s) using TLS without verifying the TLS certificate.")
-							dialOptions = append(dialOptions, redis.DialTLSSkipVerify(true))
-						}
-
-						// redisへ接続
-						conn, err = redis.Dial("tcp",
-							readRedisUrl,
-							dialOptions...,
-						)
-						if err != nil { // Check for error on dial
-							//rConnLogger.Error("failure dialing Redis read replica")
-						}
+					// 終了処理が開始された場合はリトライしない
+					if ctx.Err() != nil {
+						return backoff.Permanent(ctx.Err())
+					}
+
+					//rConnLogger.Debug("dialing Redis read replica")
+
+					// Dial options
+					dialOptions := []redis.DialOption{
+						redis.DialUsername(config.OmRedisReadUser),
+						redis.DialPassword(config.OmRedisReadPassword),
+						redis.DialConnectTimeout(config.OmRedisPoolIdleTimeout), // Redis へ TCP 接続するまでの待ち時間の上限。
+						redis.DialReadTimeout(config.OmRedisPoolIdleTimeout),    // Redis にコマンドを送った後、レスポンスを読み取る待ち時間の上限。
+					}
+
+					// TLSを使用するオプションの追加
+					if config.OmRedisUseTls {
+						//rConnLogger.Info("OM_REDIS_USE_TLS is set to true, will attempt to connect to Redis read replica(s) using TLS.")
+						dialOptions = append(dialOptions, redis.DialUseTLS(true))
+					}
+
+					// 設定フラグが設定されている場合、TLS証明書の検証をスキップする (例: 自己署名証明書の場合)
+					if config.OmRedisTlsSkipVerify {
+						//rConnLogger.Info("OM_REDIS_TLS_SKIP_VERIFY is set to true, will attempt to connect to Redis read replica(s) using TLS without verifying the TLS certificate.")
+						dialOptions = append(dialOptions, redis.DialTLSSkipVerify(true))
+					}
+
+					// redisへ接続
+					conn, err = redis.Dial("tcp",
+						readRedisUrl,
+						dialOptions...,
+					)
+					if err != nil { // Check for error on dial
+						//rConnLogger.Error("failure dialing Redis read replica")
 					}
 					return err
 				},
@@ -210,7 +221,7 @@ func getReadConnectionPool(ctx context.Context, config RedisConfig, cancel conte
 }
 
 // getWriteConnectionPool 書き込み専用のRedis接続プールの取得
-func getWriteConnectionPool(ctx context.Context, config RedisConfig, cancel context.CancelFunc, sigChan chan os.Signal, readRedisUrl string) *redis.Pool {
+func getWriteConnectionPool(ctx context.Context, config RedisConfig, readRedisUrl string) *redis.Pool {
 	return &redis.Pool{
 		MaxIdle:     config.OmRedisPoolMaxIdle,
 		MaxActive:   config.OmRedisPoolMaxActive,
@@ -232,35 +243,34 @@ func getWriteConnectionPool(ctx context.Context, config RedisConfig, cancel cont
 					// Local closure var
 					var err error
 
-					select {
-					case <-sigChan:
-						cancel()
-					default:
-						// Dial options
-						dialOptions := []redis.DialOption{
-							redis.DialPassword(config.OmRedisWritePassword),
-							redis.DialConnectTimeout(config.OmRedisPoolIdleTimeout),
-							redis.DialReadTimeout(config.OmRedisPoolIdleTimeout),
-						}
-
-						if config.OmRedisUseTls {
-							//rConnLogger.Info("OM_REDIS_USE_TLS is set to true, will attempt to connect to Redis read replica(s) using TLS.")
-							dialOptions = append(dialOptions, redis.DialUseTLS(true))
-						}
-
-						if config.OmRedisTlsSkipVerify {
-							//rConnLogger.Info("OM_REDIS_TLS_SKIP_VERIFY is set to true, will attempt to connect to Redis read replica(s) using TLS without verifying the TLS certificate.")
-							dialOptions = append(dialOptions, redis.DialTLSSkipVerify(true))
-						}
-
-						conn, err = redis.Dial("tcp",
-							readRedisUrl,
-							dialOptions...,
-						)
-						if err != nil {
-							//rConnLogger.Error("failure dialing Redis read replica")
-						}
+					// 終了処理が開始された場合はリトライしない
+					if ctx.Err() != nil {
+						return backoff.Permanent(ctx.Err())
+					}
+
+					// Dial options
+					dialOptions := []redis.DialOption{
+						redis.DialPassword(config.OmRedisWritePassword),
+						redis.DialConnectTimeout(config.OmRedisPoolIdleTimeout),
+						redis.DialReadTimeout(config.OmRedisPoolIdleTimeout),
+					}
+
+					if config.OmRedisUseTls {
+						//rConnLogger.Info("OM_REDIS_USE_TLS is set to true, will attempt to connect to Redis read replica(s) using TLS.")
+						dialOptions = append(dialOptions, redis.DialUseTLS(true))
+					}
+
+					if config.OmRedisTlsSkipVerify {
+						//rConnLogger.Info("OM_REDIS_TLS_SKIP_VERIFY is set to true, will attempt to connect to Redis read replica(s) using TLS without verifying the TLS certificate.")
+						dialOptions = append(dialOptions, redis.DialTLSSkipVerify(true))
+					}
 
+					conn, err = redis.Dial("tcp",
+						readRedisUrl,
+						dialOptions...,
+					)
+					if err != nil {
+						//rConnLogger.Error("failure dialing Redis read replica")
 					}
 					return err
 				},
@@ -281,10 +291,10 @@ func getWriteConnectionPool(ctx context.Context, config RedisConfig, cancel cont
 // 関数に送信される更新に加え、各バッチの終了時には必ずXTRIMコマンドを実行し、
 // 環境変数OM_CACHE_TICKET_TTL_MSで設定されたTTLより古い更新を全て削除します。
 func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse {
-	logger := logrus.WithFields(logrus.Fields{
-		"app":       "open_match",
-		"component": "redisReplicator.sendUpdates",
-	})
+	logger := rr.logger.With(
+		logging.F("app", "open_match"),
+		logging.F("component", "redisReplicator.sendUpdates"),
+	)
 
 	// Var init
 	var err error
@@ -356,9 +366,7 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 		// コマンドをバッファに追加
 		err = rConn.Send(redisCmdXAdd, redisArgs...)
 		if err != nil {
-			logger.WithFields(logrus.Fields{
-				"redis_command": redisCmdWithArgs,
-			}).Errorf("Redis error: %v", err)
+			logger.Error("Redis error", logging.Err(err), logging.F("redis_command", redisCmdWithArgs))
 		}
 	}
 
@@ -371,16 +379,14 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 	// XTRIMコマンドをバッファに追加
 	err = rConn.Send("XTRIM", "om-replication", "MINID", expirationThresh)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"redis_command": redisCmdWithArgs,
-		}).Errorf("Redis error: %v", err)
+		logger.Error("Redis error", logging.Err(err), logging.F("redis_command", redisCmdWithArgs))
 	}
 
 	// パイプライン化されたコマンドを送信し、結果を取得
 	// 第一引数か空文字の場合はパイプラインの返信回収用になる。
 	r, err := rConn.Do("")
 	if err != nil {
-		logger.Errorf("Redis error when executing batch: %v", err)
+		logger.Error("Redis error when executing batch", logging.Err(err))
 	}
 
 	// コマンド実行結果の存在確認
@@ -402,13 +408,10 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 	// Redisからの最終結果は、XTRIMコマンドで削除したエントリ数のカウントになる。
 	expiredCount, err := redis.Int64(r.([]interface{})[len(r.([]interface{}))-1], err)
 	if err != nil {
-		logger.Errorf("Redis output int64 conversion error: %v", err)
+		logger.Error("Redis output int64 conversion error", logging.Err(err))
 	}
 	if expiredCount > 0 {
-		logger.WithFields(logrus.Fields{
-			"redis_expiration_count":  expiredCount,
-			"expiration_threshold_ts": expirationThresh,
-		}).Debug("Expired Redis entries older than expiration threshold timestamp")
+		logger.Debug("Expired Redis entries older than expiration threshold timestamp", logging.F("redis_expiration_count", expiredCount), logging.F("expiration_threshold_ts", expirationThresh))
 	}
 
 	// その他のすべてのRedis結果を返り値の出力配列に処理
@@ -417,7 +420,7 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 		// 更新の解析で問題が見つからなかったことを確認してください。
 		// 更新の解析でエラーが発生した場合、更新に必要なフィールドがすべて揃っていなかったため、Redisには送信されませんでした。
 		if out[index].Err != nil {
-			logger.WithFields(logrus.Fields{"update": updates[index]}).Error("an update could not be parsed and was skipped")
+			logger.Error("an update could not be parsed and was skipped", logging.F("update", updates[index]))
 
 			// Redisの結果を返す必要がないため、更新の解析エラーとエラーを発生させたキーを返す。
 			out[index].Result = updates[index].Key
@@ -428,10 +431,10 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 				// Redisの結果が文字列ではない場合はエラーになる。エラーコードを返し、結果はエラーを発生させたキーになる。
 				t = updates[index].Key
 				out[index].Err = fmt.Errorf("Redis output string conversion error: %w", err)
-				logger.WithFields(logrus.Fields{"err": err, "update": updates[index]}).Error("Redis returned an error while trying to update")
+				logger.Error("Redis returned an error while trying to update", logging.Err(err), logging.F("update", updates[index]))
 			}
 
-			logger.WithFields(logrus.Fields{"update": updates[index], "result": t}).Tracef("Redis successfully processed update")
+			logger.Debug("Redis successfully processed update", logging.F("update", updates[index]), logging.F("result", t))
 			out[index].Result = t
 		}
 	}
@@ -443,10 +446,10 @@ func (rr *redisReplicator) SendUpdates(updates []*StateUpdate) []*StateResponse
 // 前回の GetUpdates リクエスト以降にレプリケーターに送信されたすべての更新構造体を受信します。
 // これらは配列で返され、om-core はそれらを 元の受信順序でイベントとして適用します。
 func (rr *redisReplicator) GetUpdates() []*StateUpdate {
-	logger := logrus.WithFields(logrus.Fields{
-		"app":       "open_match",
-		"component": "redisReplicator.getUpdates",
-	})
+	logger := rr.logger.With(
+		logging.F("app", "open_match"),
+		logging.F("component", "redisReplicator.getUpdates"),
+	)
 
 	out := make([]*StateUpdate, 0)
 
@@ -469,12 +472,10 @@ func (rr *redisReplicator) GetUpdates() []*StateUpdate {
 	defer rConn.Close()
 
 	// Execute XREAD
-	logger.WithFields(logrus.Fields{
-		"redisCmd": fmt.Sprint(redisCmdXRead, strings.Trim(fmt.Sprint(redisArgs), "[]")),
-	}).Debugf("Executing redis command")
+	logger.Debug("Executing redis command", logging.F("redisCmd", fmt.Sprint(redisCmdXRead, strings.Trim(fmt.Sprint(redisArgs), "[]"))))
 	data, err := rConn.Do(redisCmdXRead, redisArgs...)
 	if err != nil {
-		logger.Errorf("Redis error: %v", err)
+		logger.Error("Redis error", logging.Err(err))
 	}
 
 	// Redigoモジュールは、タイムアウト（BLOCK Xms）に達するまでに更新を確認できなかった場合、
@@ -482,7 +483,7 @@ func (rr *redisReplicator) GetUpdates() []*StateUpdate {
 	if data != nil {
 		switch data.(type) {
 		case redis.Error:
-			logger.Errorf("Redis error: %v", data.(redis.Error))
+			logger.Error("Redis error", logging.Err(data.(redis.Error)))
 		case []interface{}:
 			// データはレスポンス内で数段階ネストされた配列レベルにある
 			// https://redis.io/docs/latest/develop/data-types/streams/#listening-for-new-items-with-xread
@@ -491,7 +492,7 @@ func (rr *redisReplicator) GetUpdates() []*StateUpdate {
 				// 要素0はRedisのストリームエントリIDであり、これをレプリケーションIDとして使用
 				replId, err := redis.String(v.([]interface{})[0], nil)
 				if err != nil {
-					logger.Error(err)
+					logger.Error("Redis stream entry id conversion error", logging.Err(err))
 				}
 				thisUpdate := &StateUpdate{}
 
@@ -499,7 +500,7 @@ func (rr *redisReplicator) GetUpdates() []*StateUpdate {
 				// 各ストリームエントリに1つの更新のみが保存される想定（Redisは複数許可可能）。
 				y, err := redis.Strings(v.([]interface{})[1], nil)
 				if err != nil {
-					logger.Error(err)
+					logger.Error("Redis stream entry data conversion error", logging.Err(err))
 				}
 
 				// Update type/key/value data
@@ -6,20 +6,15 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/protobuf/proto"
 
 	pb "github.com/googleforgames/open-match2/v2/pkg/pb"
 
-	"github.com/sirupsen/logrus"
-)
-
-var (
-	logger = logrus.WithFields(logrus.Fields{
-		"app":       "open_match",
-		"component": "cache",
-	})
+	"valley-pkg/logging"
+	"valley-pkg/metrics"
 )
 
 // UpdateRequest は基本的に、StateUpdate（store.go）をラップしたもので、コンテキストと結果を受け取るチャネルを追加します。
@@ -51,6 +46,30 @@ type ReplicatedTicketCache struct {
 	IdValidator *regexp.Regexp
 
 	Cfg *RedisConfig
+
+	// Logger ログの出力先。nil の場合は logrus に出力する
+	Logger logging.Logger
+	// Metrics キャッシュの複製と期限切れ処理の計測値の記録先。nil の場合は記録しない
+	Metrics metrics.Meter
+
+	// lastPolled 最後に GetUpdates が返った時刻（UnixMilli）
+	lastPolled atomic.Int64
+
+	metricsOnce sync.Once
+	cm          *cacheMetrics
+}
+
+// logger はログの出力先を返す
+func (tc *ReplicatedTicketCache) logger() logging.Logger {
+	return logging.OrDefault(tc.Logger)
+}
+
+// metrics は計測器を返す。Metrics は最初の呼び出しで確定する
+func (tc *ReplicatedTicketCache) metrics() *cacheMetrics {
+	tc.metricsOnce.Do(func() {
+		tc.cm = newCacheMetrics(metrics.OrNop(tc.Metrics))
+	})
+	return tc.cm
 }
 
 // OutgoingReplicationQueue はサーバーの存続期間中実行される非同期ゴルーチン。
@@ -58,13 +77,14 @@ type ReplicatedTicketCache struct {
 // それらのイベントを構成済みの状態ストレージに送信します。この時点では更新はまだチケットキャッシュのローカルコピーに適用されていません。
 // イベントが正常にレプリケーションされ、incomingReplicationQueue ゴルーチンで受信されると、更新がローカルキャッシュに適用されます。
 func (tc *ReplicatedTicketCache) OutgoingReplicationQueue(ctx context.Context) {
-	logger := logger.WithFields(logrus.Fields{
-		"app":       "open_match",
-		"component": "replicationQueue",
-		"direction": "outgoing",
-	})
+	logger := tc.logger().With(
+		logging.F("app", "open_match"),
+		logging.F("component", "replicationQueue"),
+		logging.F("direction", "outgoing"),
+	)
 
 	logger.Debug("Listening for replication requests")
+	m := tc.metrics()
 	exec := false
 	pipelineRequests := make([]*UpdateRequest, 0)
 	pipeline := make([]*StateUpdate, 0)
@@ -82,16 +102,16 @@ func (tc *ReplicatedTicketCache) OutgoingReplicationQueue(ctx context.Context) {
 				pipelineRequests = append(pipelineRequests, req)
 				pipeline = append(pipeline, &req.Update)
 
-				logger.Tracef(" %v requests queued for current batch", len(pipelineRequests))
+				logger.Debug("requests queued for current batch", logging.F("queued_count", len(pipelineRequests)))
 				// 最大バッチサイズに到達した場合
 				if len(pipelineRequests) >= tc.Cfg.OmCacheOutMaxQueueThreshold {
-					logger.Trace("OM_CACHE_OUT_MAX_QUEUE_THRESHOLD reached")
+					logger.Debug("OM_CACHE_OUT_MAX_QUEUE_THRESHOLD reached")
 					exec = true
 				}
 			// タイムアウトの場合、バッチのキューを万杯まで待たない
 			case <-timeout:
-				//otelCacheOutgoingQueueTimeouts.Add(ctx, 1)
-				logger.Trace("OM_CACHE_OUT_WAIT_TIMEOUT_MS reached")
+				m.outgoingQueueTimeouts.Add(1)
+				logger.Debug("OM_CACHE_OUT_WAIT_TIMEOUT_MS reached")
 				exec = true
 			}
 		}
@@ -99,18 +119,14 @@ func (tc *ReplicatedTicketCache) OutgoingReplicationQueue(ctx context.Context) {
 		// Redisの更新パイプラインバッチジョブに実行すべきコマンドがある場合、実行
 		if len(pipelineRequests) > 0 {
 			// リクエスト数記録
-			logger.WithFields(logrus.Fields{
-				"batch_update_count": len(pipelineRequests),
-			}).Trace("sending state update batch to replicator")
-			//otelCacheOutgoingUpdatesPerPoll.Record(ctx, int64(len(pipelineRequests)))
+			logger.Debug("sending state update batch to replicator", logging.F("batch_update_count", len(pipelineRequests)))
+			m.outgoingUpdatesPerPoll.Observe(float64(len(pipelineRequests)))
 
 			// 更新のバッチをRedisへ書き込み
 			results := tc.Replicator.SendUpdates(pipeline)
 
 			// レプリケーターから受信した結果の数を記録
-			logger.WithFields(logrus.Fields{
-				"result_count": len(results),
-			}).Trace("state update batch results received from replicator")
+			logger.Debug("state update batch results received from replicator", logging.F("result_count", len(results)))
 
 			for index, result := range results {
 				// リクエストしたチャネルに結果を送信
@@ -125,11 +141,12 @@ func (tc *ReplicatedTicketCache) OutgoingReplicationQueue(ctx context.Context) {
 // 実際には、InvokeMatchMakingFunction を除き、全ての om-core gRPC ハンドラーの
 // ほぼ全ての処理をこのゴルーチンが担います。
 func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
-	logger := logger.WithFields(logrus.Fields{
-		"app":       "open_match",
-		"component": "replicationQueue",
-		"direction": "incoming",
-	})
+	logger := tc.logger().With(
+		logging.F("app", "open_match"),
+		logging.F("component", "replicationQueue"),
+		logging.F("direction", "incoming"),
+	)
+	m := tc.metrics()
 
 	// Redisのレプリケーションストリームを非同期で監視し、
 	// 更新データをチャンネルに追加して、到着順に処理されるようにする
@@ -147,19 +164,17 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 			// 内部実装では設定変数OM_CACHE_IN_WAIT_TIMEOUT_MSで定義されたタイムアウトを遵守するため、
 			// タイムリーな返却が保証される。保留中の更新が最大 OmCacheInMaxUpdatesPerPoll 個存在する場合、その数まで取得します。
 			results := tc.Replicator.GetUpdates()
+			tc.lastPolled.Store(time.Now().UnixMilli())
 
-			//otelCacheIncomingPerPoll.Record(ctx, int64(len(results)))
+			m.incomingPerPoll.Observe(float64(len(results)))
 
 			if len(results) == 0 {
-				//otelCacheIncomingEmptyTimeouts.Add(ctx, 1)
+				m.incomingEmptyTimeouts.Add(1)
 			}
 
 			// 更新内容をレプリケーションチャネルに投入して処理
 			for _, curUpdate := range results {
-				logger.WithFields(logrus.Fields{
-					"update.key":     curUpdate.Key,
-					"update.command": curUpdate.Cmd,
-				}).Trace("queueing incoming update from state storage")
+				logger.Debug("queueing incoming update from state storage", logging.F("update.key", curUpdate.Key), logging.F("update.command", curUpdate.Cmd))
 				replStream <- *curUpdate
 			}
 
@@ -197,7 +212,7 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 					ticketPb := &pb.Ticket{}
 					err = proto.Unmarshal([]byte(curUpdate.Value), ticketPb)
 					if err != nil {
-						logger.Error("received ticket replication could not be unmarshalled")
+						logger.Error("received ticket replication could not be unmarshalled", logging.Err(err))
 					}
 
 					// TicketIDを設定する。レプリケーション後に実行する必要がある。
@@ -208,32 +223,32 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 					// すべてのチケットは非アクティブ状態で開始
 					tc.InactiveSet.Store(curUpdate.Key, true)
 					tc.Tickets.Store(curUpdate.Key, ticketPb)
-					logger.Tracef("ticket replication received: %v", curUpdate.Key)
+					logger.Debug("ticket replication received", logging.F("update.key", curUpdate.Key))
 
 				case Activate:
 					tc.InactiveSet.Delete(curUpdate.Key)
-					logger.Tracef("activation replication received: %v", curUpdate.Key)
+					logger.Debug("activation replication received", logging.F("update.key", curUpdate.Key))
 
 				case Deactivate:
 					tc.InactiveSet.Store(curUpdate.Key, true)
-					logger.Tracef("deactivate replication received: %v", curUpdate.Key)
+					logger.Debug("deactivate replication received", logging.F("update.key", curUpdate.Key))
 
 				case Assign:
 					// protobuf messageに更新
 					assignmentPb := &pb.Assignment{}
 					err = proto.Unmarshal([]byte(curUpdate.Value), assignmentPb)
 					if err != nil {
-						logger.Error("received assignment replication could not be unmarshalled")
+						logger.Error("received assignment replication could not be unmarshalled", logging.Err(err))
 					}
 					tc.Assignments.Store(curUpdate.Key, assignmentPb)
-					logger.Tracef("**DEPRECATED** assign replication received %v:%v", curUpdate.Key, assignmentPb.GetConnection())
+					logger.Debug("**DEPRECATED** assign replication received", logging.F("update.key", curUpdate.Key), logging.F("connection", assignmentPb.GetConnection()))
 				}
 			case <-updateTimeout:
-				//otelCacheIncomingProcessingTimeouts.Add(ctx, 1)
-				logger.Trace("lock hold timeout")
+				m.incomingProcessingTimeouts.Add(1)
+				logger.Debug("lock hold timeout")
 				done = true
 			default:
-				logger.Trace("Incoming update queue empty")
+				logger.Debug("Incoming update queue empty")
 				done = true
 			}
 		}
@@ -262,12 +277,12 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 		//   assigned and their assignments will be retained until the
 		//   expiration time described above. **DEPRECATED**
 		{
-			// Separate logrus instance with its own metadata to aid troubleshooting
-			exLogger := logrus.WithFields(logrus.Fields{
-				"app":       "open_match",
-				"component": "replicatedTicketCache",
-				"operation": "expiration",
-			})
+			// Separate logger instance with its own metadata to aid troubleshooting
+			exLogger := tc.logger().With(
+				logging.F("app", "open_match"),
+				logging.F("component", "replicatedTicketCache"),
+				logging.F("operation", "expiration"),
+			)
 
 			var (
 				numInactive            int64
@@ -290,9 +305,7 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 				// ミリ秒精度（13桁のUnixタイムスタンプ）であると仮定される
 				ticketCreationTime, err := strconv.ParseInt(strings.Split(id.(string), "-")[0], 10, 64)
 				if err != nil {
-					exLogger.WithFields(logrus.Fields{
-						"ticket_id": id,
-					}).Error("Unable to parse ticket ID into an unix timestamp when trying to expire old ticket active states")
+					exLogger.Error("Unable to parse ticket ID into an unix timestamp when trying to expire old ticket active states", logging.F("ticket_id", id))
 					return true
 				}
 				if (time.Now().UnixMilli() - ticketCreationTime) > int64(tc.Cfg.OmCacheTicketTtlMs) {
@@ -332,7 +345,7 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 				// IDから作成時刻を取得する
 				ticketCreationTime, err := strconv.ParseInt(strings.Split(id.(string), "-")[0], 10, 64)
 				if err != nil {
-					exLogger.Error("Unable to parse ticket ID into an unix timestamp when trying to expire old assignments")
+					exLogger.Error("Unable to parse ticket ID into an unix timestamp when trying to expire old assignments", logging.F("ticket_id", id))
 				}
 				if (time.Now().UnixMilli() - ticketCreationTime) >
 					int64(tc.Cfg.OmCacheTicketTtlMs+tc.Cfg.OmCacheAssignmentAdditionalTtlMs) {
@@ -345,31 +358,31 @@ func (tc *ReplicatedTicketCache) IncomingReplicationQueue(ctx context.Context) {
 			})
 
 			// Log results and record counter metrics
-			//AssignmentCount = numAssignments
-			//TicketCount = numTickets
-			//InactiveCount = numInactive
+			m.assignments.Set(float64(numAssignments))
+			m.tickets.Set(float64(numTickets))
+			m.inactives.Set(float64(numInactive))
 
 			// Record time elapsed for histogram
 			elapsed := float64(time.Since(startTime).Microseconds())
-			//otelCacheExpirationCycleDuration.Record(ctx, elapsed/1000.0) // OTEL measurements are in milliseconds
+			m.expirationCycleDuration.Observe(elapsed / 1e6)
 
 			// Record expiration count data for histograms
-			//otelCacheTicketsExpiredPerCycle.Record(ctx, int64(numTicketDeletions))
-			//otelCacheInactivesExpiredPerCycle.Record(ctx, int64(numInactiveDeletions))
-			//otelCacheAssignmentsExpiredPerCycle.Record(ctx, int64(numAssignmentDeletions))
+			m.ticketsExpiredPerCycle.Observe(float64(numTicketDeletions))
+			m.inactivesExpiredPerCycle.Observe(float64(numInactiveDeletions))
+			m.assignmentsExpiredPerCycle.Observe(float64(numAssignmentDeletions))
 
 			// Trace logging for advanced debugging
 			if numAssignmentDeletions > 0 {
-				exLogger.Tracef("Removed %v expired assignments from local cache", numAssignmentDeletions)
+				exLogger.Debug("Removed expired assignments from local cache", logging.F("deleted_count", numAssignmentDeletions))
 			}
 			if numInactiveDeletions > 0 {
-				exLogger.Tracef("%v ticket ids expired from the inactive list in local cache", numInactiveDeletions)
+				exLogger.Debug("ticket ids expired from the inactive list in local cache", logging.F("deleted_count", numInactiveDeletions))
 			}
 			if numTicketDeletions > 0 {
-				exLogger.Tracef("Removed %v expired tickets from local cache", numTicketDeletions)
+				exLogger.Debug("Removed expired tickets from local cache", logging.F("deleted_count", numTicketDeletions))
 			}
 			if elapsed >= 0.01 {
-				exLogger.Tracef("Local cache expiration code took %.2f us", elapsed)
+				exLogger.Debug("Local cache expiration code finished", logging.F("elapsed_us", elapsed))
 			}
 		}
 	}
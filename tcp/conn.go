@@ -2,12 +2,13 @@ package tcp
 
 import (
 	"bufio"
-	"log"
 	"net"
 	"strings"
 	"syscall"
 	"time"
 	"valley-pkg/crypter"
+	"valley-pkg/logging"
+	"valley-pkg/metrics"
 
 	"github.com/cockroachdb/errors"
 	"google.golang.org/protobuf/proto"
@@ -81,15 +82,41 @@ type messageConn struct {
 	parser     ParserType
 	compressor CompressorType
 	crypter    crypter.Crypter
+	logger     logging.Logger
+	meter      metrics.Meter
+	metrics    connMetrics
+}
+
+// Option はConnの設定
+type Option func(*messageConn)
+
+// WithLogger はログの出力先を指定する。指定しない場合は logrus に出力する
+func WithLogger(l logging.Logger) Option {
+	return func(mc *messageConn) {
+		mc.logger = l
+	}
+}
+
+// WithMetrics は送受信したメッセージ数・バイト数・エラー数の記録先を指定する。指定しない場合は記録しない
+func WithMetrics(m metrics.Meter) Option {
+	return func(mc *messageConn) {
+		mc.meter = m
+	}
 }
 
 // NewConn はConnの初期化を行う
-func NewConn(tcpConn *net.TCPConn, format string) Conn {
+func NewConn(tcpConn *net.TCPConn, format string, opts ...Option) Conn {
 	scanner := bufio.NewScanner(tcpConn)
 
 	// 1byte毎にデータを分割してスキャンする設定
 	scanner.Split(bufio.ScanBytes)
-	return &messageConn{conn: tcpConn, scanner: scanner, format: format, parser: DefaultParser, compressor: DefaultCompressor}
+	mc := &messageConn{conn: tcpConn, scanner: scanner, format: format, parser: DefaultParser, compressor: DefaultCompressor}
+	for _, opt := range opts {
+		opt(mc)
+	}
+	mc.logger = logging.OrDefault(mc.logger)
+	mc.metrics = newConnMetrics(metrics.OrNop(mc.meter))
+	return mc
 }
 
 // RemoteAddr はRemoteAddr
@@ -123,11 +150,28 @@ func (mc *messageConn) WriteMessage(kind int8, m proto.Message) error {
 	if err != nil {
 		return errors.Errorf("failed to create message: %w", err)
 	}
-	return mc.write(message)
+	if message.CompressorType != mc.compressor {
+		// サイズが小さいと圧縮できない可能性あり
+		mc.logger.Debug("tcp message is not compressed", logging.F("compressor", mc.compressor.String()), logging.F("kind", kind))
+	}
+	n, err := mc.write(message)
+	mc.metrics.observe(directionWrite, kind, n, err)
+	return err
 }
 
 // ReadMessage はコネクションからメッセージの読み取りを行う
 func (mc *messageConn) ReadMessage() (*TcpMessage, error) {
+	message, n, err := mc.readMessage()
+	var kind int8
+	if message != nil {
+		kind = message.Kind
+	}
+	mc.metrics.observe(directionRead, kind, n, err)
+	return message, err
+}
+
+// readMessage はメッセージと読み取ったバイト数を返す
+func (mc *messageConn) readMessage() (*TcpMessage, int, error) {
 	var rem []byte
 	var message *TcpMessage
 	var err error
@@ -137,20 +181,20 @@ func (mc *messageConn) ReadMessage() (*TcpMessage, error) {
 			err = mc.scanner.Err()
 			if err == nil {
 				if len(rem) > 0 {
-					return nil, ErrEofShort
+					return nil, 0, ErrEofShort
 				}
-				return nil, ErrEof
+				return nil, 0, ErrEof
 			}
 			if errors.Is(err, syscall.ECONNRESET) {
-				return nil, ErrEconnreset
+				return nil, 0, ErrEconnreset
 			}
 
 			// use of closed network connection / net.ErrClosed など
 			if errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "closed") {
-				return nil, ErrClosedConnection
+				return nil, 0, ErrClosedConnection
 			}
 
-			return nil, errors.Errorf("tcp scan error: %w", mc.scanner.Err())
+			return nil, 0, errors.Errorf("tcp scan error: %w", mc.scanner.Err())
 		}
 
 		b := mc.scanner.Bytes()
@@ -159,7 +203,7 @@ func (mc *messageConn) ReadMessage() (*TcpMessage, error) {
 		rem = append(rem, b...)
 
 		if len(rem) == 0 {
-			return nil, ErrHealthCheck
+			return nil, 0, ErrHealthCheck
 		}
 
 		message, err = NewMessageFromByte(mc.format, rem, mc.crypter)
@@ -168,45 +212,47 @@ func (mc *messageConn) ReadMessage() (*TcpMessage, error) {
 		}
 		switch true {
 		case errors.Is(err, ErrLen), errors.Is(err, ErrFormat):
-			// logrus.Infof("%v", err)
-			return nil, err
+			mc.logger.Info("tcp message is invalid", logging.Err(err), logging.F("remote_addr", mc.RemoteAddr()))
+			return nil, 0, err
 		case errors.Is(err, ErrHeaderShort):
 		case errors.Is(err, ErrBodyShort):
 		default:
-			return nil, err
+			return nil, 0, err
 		}
 	}
-	return message, nil
+	return message, len(rem), nil
 }
 
-// write はコネクションに対して、メッセージを書き込む
-func (mc *messageConn) write(tcpMessage *TcpMessage) error {
+// write はコネクションに対して、メッセージを書き込み、書き込んだバイト数を返す
+func (mc *messageConn) write(tcpMessage *TcpMessage) (int, error) {
 	b := tcpMessage.ToByte()
+	var written int
 
 	for len(b) > 0 {
 		n, err := mc.conn.Write(b)
 		if err != nil {
 			if errors.Is(err, syscall.EPIPE) {
-				return ErrEpipe
+				return written, ErrEpipe
 			}
 			if errors.Is(err, syscall.ECONNRESET) {
-				return ErrEconnreset
+				return written, ErrEconnreset
 			}
 
 			if errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "closed") {
-				return ErrClosedConnection
+				return written, ErrClosedConnection
 			}
 
-			return errors.Errorf("tcp write error: %w", err)
+			return written, errors.Errorf("tcp write error: %w", err)
 		}
 
 		if n == 0 {
-			log.Println("tcp write error: n == 0")
-			return nil
+			mc.logger.Error("tcp write error: n == 0", logging.F("remote_addr", mc.RemoteAddr()))
+			return written, nil
 		}
 
+		written += n
 		b = b[n:]
 	}
 
-	return nil
+	return written, nil
 }
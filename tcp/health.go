@@ -0,0 +1,24 @@
+package tcp
+
+import (
+	"context"
+	"net"
+
+	"github.com/cockroachdb/errors"
+	"valley-pkg/health"
+)
+
+// ErrListenerClosed はリスナーが閉じている場合のエラー
+var ErrListenerClosed = errors.New("tcp listener is closed")
+
+// ListenerChecker はリスナーが接続を受け付けられる状態かを確認する health.Checker を返す
+// リスナーのファイルディスクリプタを複製できるかで判定するため、クライアントへの接続は発生しない。
+func ListenerChecker(ln *net.TCPListener) health.Checker {
+	return health.CheckerFunc(func(context.Context) error {
+		f, err := ln.File()
+		if err != nil {
+			return errors.Errorf("%v: %w", err, ErrListenerClosed)
+		}
+		return f.Close()
+	})
+}
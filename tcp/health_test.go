@@ -0,0 +1,25 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestListenerChecker(t *testing.T) {
+	ln, err := ListenTCP("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenTCP error: %v", err)
+	}
+	checker := ListenerChecker(ln)
+
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("受付中のリスナーでエラーになりました。err=%v", err)
+	}
+
+	_ = ln.Close()
+	if err := checker.Check(context.Background()); !errors.Is(err, ErrListenerClosed) {
+		t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrListenerClosed)
+	}
+}
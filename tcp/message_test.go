@@ -155,8 +155,8 @@ func createValidMessageData() []byte {
 	copy(data[0:3], "TST") // Format
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser (JSON)
-	data[6] = 0            // Compressor (None)
+	data[5] = 1            // Parser (JSON)
+	data[6] = 1            // Compressor (None)
 	// Extension (5 bytes) はゼロのまま
 	// Length = 8
 	data[15] = 8
@@ -170,8 +170,8 @@ func createZeroLengthBodyData() []byte {
 	copy(data[0:3], "TST") // Format
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser (JSON)
-	data[6] = 0            // Compressor (None)
+	data[5] = 1            // Parser (JSON)
+	data[6] = 1            // Compressor (None)
 	// Extension (5 bytes) はゼロのまま
 	// Length = 0 (デフォルト)
 	return data
@@ -183,8 +183,8 @@ func createMinimalValidData() []byte {
 	copy(data[0:3], "TST") // Format
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser (JSON)
-	data[6] = 0            // Compressor (None)
+	data[5] = 1            // Parser (JSON)
+	data[6] = 1            // Compressor (None)
 	// Extension (5 bytes) はゼロのまま
 	// Length = 1
 	data[15] = 1
@@ -200,8 +200,8 @@ func createInsufficientBodyData() []byte {
 	copy(data[0:3], "TST") // Format
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser (JSON)
-	data[6] = 0            // Compressor (None)
+	data[5] = 1            // Parser (JSON)
+	data[6] = 1            // Compressor (None)
 	// Length = 10 だが実際のボディは2バイトのみ
 	data[15] = 10
 	data[16] = 'A'
@@ -216,7 +216,7 @@ func createUnsupportedParserData() []byte {
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
 	data[5] = 99           // 未対応Parser
-	data[6] = 0            // Compressor (None)
+	data[6] = 1            // Compressor (None)
 	// Length = 4
 	data[15] = 4
 	copy(data[16:20], "test")
@@ -229,7 +229,7 @@ func createUnsupportedCompressorData() []byte {
 	copy(data[0:3], "TST") // Format
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser (JSON)
+	data[5] = 1            // Parser (JSON)
 	data[6] = 99           // 未対応Compressor
 	// Length = 4
 	data[15] = 4
@@ -243,8 +243,8 @@ func createInvalidLengthData() []byte {
 	copy(data[0:3], "TST")
 	data[3] = 1 // Version
 	data[4] = 1 // Kind
-	data[5] = 0 // Parser
-	data[6] = 0 // Compressor
+	data[5] = 1 // Parser
+	data[6] = 1 // Compressor
 	// Length部分に負の値を設定（-1）
 	data[12] = 0xFF
 	data[13] = 0xFF
@@ -259,10 +259,40 @@ func createWrongFormatData() []byte {
 	copy(data[0:3], "WRG") // 間違ったフォーマット
 	data[3] = 1            // Version
 	data[4] = 1            // Kind
-	data[5] = 0            // Parser（JSON）
-	data[6] = 0            // Compressor（None）
+	data[5] = 1            // Parser（JSON）
+	data[6] = 1            // Compressor（None）
 	data[15] = 4           // Length = 4
 	copy(data[16:20], "body")
 
 	return data
 }
+
+func TestTcpMessage_getParser(t *testing.T) {
+	tests := []struct {
+		name       string
+		parserType ParserType
+		wantErr    bool
+	}{
+		{name: "正常系: JSON", parserType: JSON},
+		{name: "正常系: Protocol Buffer", parserType: PROTOBUF},
+		{name: "正常系: MessagePack", parserType: MSGPACK},
+		{name: "正常系: YAML", parserType: YAML},
+		{name: "異常系: 未対応パーサータイプ", parserType: 99, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := &TcpMessage{ParserType: tt.parserType}
+			p, err := message.getParser()
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrParser)
+				assert.Nil(t, p)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, p)
+		})
+	}
+}
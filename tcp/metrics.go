@@ -0,0 +1,43 @@
+package tcp
+
+import (
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"valley-pkg/metrics"
+)
+
+const (
+	directionRead  = "read"
+	directionWrite = "write"
+)
+
+// connMetrics はコネクションの計測器
+type connMetrics struct {
+	messages metrics.Counter
+	bytes    metrics.Counter
+	errors   metrics.Counter
+}
+
+// newConnMetrics はmの計測器を取得する
+func newConnMetrics(m metrics.Meter) connMetrics {
+	return connMetrics{
+		messages: m.Counter("tcp_messages_total", "送受信したメッセージ数"),
+		bytes:    m.Counter("tcp_bytes_total", "送受信したバイト数"),
+		errors:   m.Counter("tcp_errors_total", "送受信のエラー数"),
+	}
+}
+
+// observe はメッセージの送受信を記録する
+// ヘルスチェックと相手からの切断（EOF）はエラーとして数えない
+func (cm connMetrics) observe(direction string, kind int8, size int, err error) {
+	dir := metrics.L("direction", direction)
+	if err != nil {
+		if !errors.Is(err, ErrHealthCheck) && !errors.Is(err, ErrEof) {
+			cm.errors.Add(1, dir)
+		}
+		return
+	}
+	cm.messages.Add(1, dir, metrics.L("kind", strconv.Itoa(int(kind))))
+	cm.bytes.Add(float64(size), dir)
+}
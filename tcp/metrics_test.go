@@ -0,0 +1,47 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"valley-pkg/metrics"
+)
+
+func TestConnMetrics_Observe(t *testing.T) {
+	tests := []struct {
+		name       string
+		direction  string
+		size       int
+		err        error
+		wantBytes  float64
+		wantErrors float64
+	}{
+		{name: "正常値: 書き込み", direction: directionWrite, size: 10, wantBytes: 10},
+		{name: "正常値: 読み込み", direction: directionRead, size: 20, wantBytes: 20},
+		{name: "正常値: ヘルスチェックはエラーとして数えない", direction: directionRead, err: ErrHealthCheck},
+		{name: "正常値: EOF はエラーとして数えない", direction: directionRead, err: ErrEof},
+		{name: "異常値: エラー", direction: directionWrite, err: errors.Wrap(ErrEpipe, "write"), wantErrors: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := metrics.NewRegistry()
+			newConnMetrics(reg).observe(tt.direction, 1, tt.size, tt.err)
+
+			values := make(map[string]float64)
+			for _, f := range reg.Gather() {
+				for _, s := range f.Series {
+					values[f.Name] += s.Value
+					if s.Labels[0] != metrics.L("direction", tt.direction) {
+						t.Errorf("ラベルが想定外です。got=%v", s.Labels)
+					}
+				}
+			}
+			if got := values["tcp_bytes_total"]; got != tt.wantBytes {
+				t.Errorf("バイト数が想定外です。got=%v, want=%v", got, tt.wantBytes)
+			}
+			if got := values["tcp_errors_total"]; got != tt.wantErrors {
+				t.Errorf("エラー数が想定外です。got=%v, want=%v", got, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@
 package mocks
 
 import (
+	net "net"
+
 	mock "github.com/stretchr/testify/mock"
-	protoiface "google.golang.org/protobuf/runtime/protoiface"
+	proto "google.golang.org/protobuf/proto"
+	crypter "valley-pkg/crypter"
 	"valley-pkg/tcp"
 )
 
@@ -14,15 +17,15 @@ type Conn struct {
 }
 
 // ReadMessage provides a mock function with given fields:
-func (_m *Conn) ReadMessage() (*tcp.Message, error) {
+func (_m *Conn) ReadMessage() (*tcp.TcpMessage, error) {
 	ret := _m.Called()
 
-	var r0 *tcp.Message
-	if rf, ok := ret.Get(0).(func() *tcp.Message); ok {
+	var r0 *tcp.TcpMessage
+	if rf, ok := ret.Get(0).(func() *tcp.TcpMessage); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*tcp.Message)
+			r0 = ret.Get(0).(*tcp.TcpMessage)
 		}
 	}
 
@@ -36,22 +39,48 @@ func (_m *Conn) ReadMessage() (*tcp.Message, error) {
 	return r0, r1
 }
 
+// RemoteAddr provides a mock function with given fields:
+func (_m *Conn) RemoteAddr() net.Addr {
+	ret := _m.Called()
+
+	var r0 net.Addr
+	if rf, ok := ret.Get(0).(func() net.Addr); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(net.Addr)
+		}
+	}
+
+	return r0
+}
+
 // SetCompressor provides a mock function with given fields: compressor
-func (_m *Conn) SetCompressor(compressor tcp.Compressor) {
+func (_m *Conn) SetCompressor(compressor tcp.CompressorType) {
 	_m.Called(compressor)
 }
 
+// SetCrypter provides a mock function with given fields: _a0
+func (_m *Conn) SetCrypter(_a0 crypter.Crypter) {
+	_m.Called(_a0)
+}
+
+// SetDeadLine provides a mock function with given fields: seconds
+func (_m *Conn) SetDeadLine(seconds int) {
+	_m.Called(seconds)
+}
+
 // SetParser provides a mock function with given fields: parser
-func (_m *Conn) SetParser(parser tcp.Parser) {
+func (_m *Conn) SetParser(parser tcp.ParserType) {
 	_m.Called(parser)
 }
 
 // WriteMessage provides a mock function with given fields: kind, m
-func (_m *Conn) WriteMessage(kind int8, m protoiface.MessageV1) error {
+func (_m *Conn) WriteMessage(kind int8, m proto.Message) error {
 	ret := _m.Called(kind, m)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int8, protoiface.MessageV1) error); ok {
+	if rf, ok := ret.Get(0).(func(int8, proto.Message) error); ok {
 		r0 = rf(kind, m)
 	} else {
 		r0 = ret.Error(0)
@@ -59,3 +88,5 @@ func (_m *Conn) WriteMessage(kind int8, m protoiface.MessageV1) error {
 
 	return r0
 }
+
+var _ tcp.Conn = (*Conn)(nil)
@@ -1,13 +0,0 @@
-package tcp
-
-//go:generate enumer -type ParserType -json
-type ParserType int8
-
-const (
-	// Undefined
-	_ ParserType = iota
-
-	JSON
-
-	PROTOBUF
-)
@@ -0,0 +1,22 @@
+package tcp
+
+import "valley-pkg/wire"
+
+// ParserType はヘッダーに書き込むパーサーの識別子
+type ParserType = wire.ParserType
+
+const (
+	JSON     = wire.JSON
+	PROTOBUF = wire.PROTOBUF
+	MSGPACK  = wire.MSGPACK
+	YAML     = wire.YAML
+)
+
+// CompressorType はヘッダーに書き込むコンプレッサーの識別子
+type CompressorType = wire.CompressorType
+
+const (
+	None = wire.None
+	// ZSTD zstd
+	ZSTD = wire.ZSTD
+)
@@ -1,11 +0,0 @@
-package udp
-
-// Compressor あっしゅく
-type Compressor int8
-
-const (
-	// Compressor_NONE none
-	Compressor_NONE Compressor = 0
-	// Compressor_ZSTD zstd
-	Compressor_ZSTD Compressor = 1
-)
@@ -1,31 +1,24 @@
 package udp
 
 import (
-	"fmt"
 	"testing"
 )
 
 func Test_Compressor(t *testing.T) {
-	fmt.Println("Compressor_NONE is", Compressor_NONE.String())
-
-	anet := Compressor(3)
-	fmt.Println("anet is a Compressor?", anet.IsACompressor())
-
-	zstd := Compressor(1)
-	fmt.Println("zstd is a Compressor?", zstd.IsACompressor())
-
-	str, err := CompressorString("NONE")
-	if err != nil {
-		fmt.Println(err)
-		err = nil
-	} else {
-		fmt.Println(str)
+	tests := []struct {
+		name       string
+		compressor Compressor
+		want       bool
+	}{
+		{name: "正常値: NONE", compressor: Compressor_NONE, want: true},
+		{name: "正常値: ZSTD", compressor: Compressor_ZSTD, want: true},
+		{name: "異常値: 未定義", compressor: Compressor(3), want: false},
 	}
-
-	str, err = CompressorString("ANET")
-	if err != nil {
-		fmt.Println(err)
-	} else {
-		fmt.Println(str)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.compressor.IsACompressorType(); got != tt.want {
+				t.Errorf("IsACompressorType() が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
 	}
 }
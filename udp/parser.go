@@ -1,13 +0,0 @@
-package udp
-
-// Parser ぱーさ
-type Parser int8
-
-const (
-	// Parser_NONE none
-	Parser_NONE Parser = 0
-	// Parser_JSON json
-	Parser_JSON Parser = 1
-	// Parser_PROTOBUF pb_go
-	Parser_PROTOBUF Parser = 2
-)
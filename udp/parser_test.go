@@ -1,31 +1,24 @@
 package udp
 
 import (
-	"fmt"
 	"testing"
 )
 
 func Test_Parser(t *testing.T) {
-	fmt.Println("Parser_NONE is", Parser_NONE.String())
-
-	anet := Parser(3)
-	fmt.Println("anet is a Compressor?", anet.IsAParser())
-
-	json := Parser(1)
-	fmt.Println("json is a Compressor?", json.IsAParser())
-
-	str, err := ParserString("PROTOBUF")
-	if err != nil {
-		fmt.Println(err)
-		err = nil
-	} else {
-		fmt.Println(str)
+	tests := []struct {
+		name   string
+		parser Parser
+		want   bool
+	}{
+		{name: "正常値: JSON", parser: Parser_JSON, want: true},
+		{name: "正常値: PROTOBUF", parser: Parser_PROTOBUF, want: true},
+		{name: "異常値: 未定義", parser: Parser(0), want: false},
 	}
-
-	str, err = ParserString("ANET")
-	if err != nil {
-		fmt.Println(err)
-	} else {
-		fmt.Println(str)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.parser.IsAParserType(); got != tt.want {
+				t.Errorf("IsAParserType() が想定外です。got=%v, want=%v", got, tt.want)
+			}
+		})
 	}
 }
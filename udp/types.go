@@ -0,0 +1,23 @@
+package udp
+
+import "valley-pkg/wire"
+
+// Parser はヘッダーに書き込むパーサーの識別子
+type Parser = wire.ParserType
+
+const (
+	// Parser_JSON json
+	Parser_JSON = wire.JSON
+	// Parser_PROTOBUF pb_go
+	Parser_PROTOBUF = wire.PROTOBUF
+)
+
+// Compressor はヘッダーに書き込むコンプレッサーの識別子
+type Compressor = wire.CompressorType
+
+const (
+	// Compressor_NONE none
+	Compressor_NONE = wire.None
+	// Compressor_ZSTD zstd
+	Compressor_ZSTD = wire.ZSTD
+)
@@ -0,0 +1,76 @@
+package wire
+
+import (
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+	"valley-pkg/compressor"
+	"valley-pkg/crypter"
+)
+
+// Pack は m をヘッダーのパーサーで変換し、圧縮と暗号化を行ったbodyを返す
+// 圧縮で小さくならない場合は圧縮せず、h の CompressorType を None に変更する。
+// h の Length は返すbodyの長さに更新する。crypt が nil の場合は暗号化しない。
+func Pack(h *Header, m proto.Message, crypt crypter.Crypter) ([]byte, error) {
+	p, err := GetParser(h.ParserType)
+	if err != nil {
+		return nil, errors.Errorf("failed to get parser: %w", err)
+	}
+	b, err := p.Marshal(m)
+	if err != nil {
+		return nil, errors.Errorf("failed to parse: %w", err)
+	}
+
+	c, err := GetCompressor(h.CompressorType)
+	if err != nil {
+		return nil, errors.Errorf("failed to get compressor: %w", err)
+	}
+	comp, err := c.Compress(b)
+	if err != nil {
+		if !errors.Is(err, compressor.ErrNotShrunk) && !errors.Is(err, compressor.ErrIncompressible) {
+			return nil, errors.Errorf("failed to compress: %w", err)
+		}
+
+		// サイズが小さいと圧縮できない可能性あり
+		h.CompressorType = None
+		comp = b
+	}
+
+	body := comp
+	if crypt != nil {
+		if body, err = crypt.EnCrypt(comp); err != nil {
+			return nil, errors.Errorf("failed to encrypto: %w", err)
+		}
+	}
+	h.Length = int32(len(body))
+	return body, nil
+}
+
+// Unpack は Pack したbodyの復号と解凍を行い、ヘッダーのパーサーで m に変換する
+// crypt が nil の場合は復号しない。
+func Unpack(h Header, body []byte, m proto.Message, crypt crypter.Crypter) error {
+	decrypt := body
+	if crypt != nil {
+		var err error
+		if decrypt, err = crypt.DeCrypt(body); err != nil {
+			return errors.Errorf("failed to decrypto: %w", err)
+		}
+	}
+
+	c, err := GetCompressor(h.CompressorType)
+	if err != nil {
+		return errors.Errorf("failed to get compressor: %w", err)
+	}
+	deComp, err := c.Decompress(decrypt)
+	if err != nil {
+		return errors.Errorf("failed to uncompress: %w", err)
+	}
+
+	p, err := GetParser(h.ParserType)
+	if err != nil {
+		return errors.Errorf("failed to get parser: %w", err)
+	}
+	if err := p.Unmarshal(deComp, m); err != nil {
+		return errors.Errorf("failed to parse: %w", err)
+	}
+	return nil
+}
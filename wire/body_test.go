@@ -0,0 +1,54 @@
+package wire
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"valley-pkg/crypter"
+)
+
+func TestPackUnpack(t *testing.T) {
+	aes, err := crypter.NewAes(strings.Repeat("k", 32), strings.Repeat("i", 16))
+	if err != nil {
+		t.Fatalf("NewAes error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		parser         ParserType
+		compressor     CompressorType
+		crypt          crypter.Crypter
+		value          string
+		wantCompressor CompressorType
+	}{
+		{name: "正常値: JSON 非圧縮 暗号化なし", parser: JSON, compressor: None, value: "hello", wantCompressor: None},
+		{name: "正常値: PROTOBUF 非圧縮 暗号化あり", parser: PROTOBUF, compressor: None, crypt: aes, value: "hello", wantCompressor: None},
+		{name: "正常値: ZSTD で圧縮", parser: JSON, compressor: ZSTD, crypt: aes, value: strings.Repeat("a", 1024), wantCompressor: ZSTD},
+		{name: "正常値: 小さくならない場合は非圧縮", parser: JSON, compressor: ZSTD, value: "a", wantCompressor: None},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHeader("TST", 1, tt.parser, tt.compressor)
+			body, err := Pack(&h, wrapperspb.String(tt.value), tt.crypt)
+			if err != nil {
+				t.Fatalf("Pack error: %v", err)
+			}
+			if h.CompressorType != tt.wantCompressor {
+				t.Errorf("CompressorType が想定外です。got=%v, want=%v", h.CompressorType, tt.wantCompressor)
+			}
+			if int(h.Length) != len(body) {
+				t.Errorf("Length が想定外です。got=%v, want=%v", h.Length, len(body))
+			}
+
+			got := &wrapperspb.StringValue{}
+			if err := Unpack(h, body, got, tt.crypt); err != nil {
+				t.Fatalf("Unpack error: %v", err)
+			}
+			if !proto.Equal(got, wrapperspb.String(tt.value)) {
+				t.Errorf("値が想定外です。got=%v, want=%v", got.GetValue(), tt.value)
+			}
+		})
+	}
+}
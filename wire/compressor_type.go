@@ -0,0 +1,69 @@
+package wire
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"valley-pkg/compressor"
+)
+
+// go:generate go run github.com/dmarkham/enumer@latest -type=Compressor -json
+//
+//go:generate enumer -type CompressorType -json
+
+// CompressorType はヘッダーに書き込むコンプレッサーの識別子
+type CompressorType int8
+
+const (
+	_ CompressorType = iota
+
+	None
+
+	// ZSTD zstd
+	ZSTD
+)
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[CompressorType]compressor.Compresser{}
+)
+
+func init() {
+	RegisterCompressor(None, &compressor.NoneCompressor{})
+	RegisterCompressor(ZSTD, &compressor.ZstdCompressor{})
+}
+
+// RegisterCompressor はコンプレッサーを識別子と紐づけて登録する
+// parser.Register と同様に、二重登録や nil の登録はプログラムの誤りなので panic する。
+func RegisterCompressor(t CompressorType, c compressor.Compresser) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+
+	if c == nil {
+		panic(fmt.Sprintf("wire: RegisterCompressor compressor is nil for type %d", t))
+	}
+	if _, dup := compressors[t]; dup {
+		panic(fmt.Sprintf("wire: RegisterCompressor called twice for type %d", t))
+	}
+	compressors[t] = c
+}
+
+// GetCompressor は識別子に対応するコンプレッサーを取得する
+func GetCompressor(t CompressorType) (compressor.Compresser, error) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[t]
+	if !ok {
+		return nil, errors.Errorf("compressor type %d: %w", t, ErrCompressor)
+	}
+	return c, nil
+}
+
+// IsRegisteredCompressor は識別子に対応するコンプレッサーが登録されているかを返す
+func IsRegisteredCompressor(t CompressorType) bool {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	_, ok := compressors[t]
+	return ok
+}
@@ -0,0 +1,33 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestGetCompressor(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       CompressorType
+		wantErr bool
+	}{
+		{name: "正常値: None", t: None},
+		{name: "正常値: ZSTD", t: ZSTD},
+		{name: "異常値: 未登録", t: 99, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := GetCompressor(tt.t)
+			if tt.wantErr {
+				if !errors.Is(err, ErrCompressor) {
+					t.Errorf("エラーが想定外です。got=%v, want=%v", err, ErrCompressor)
+				}
+				return
+			}
+			if err != nil || c == nil {
+				t.Errorf("GetCompressor が想定外です。got=%v, err=%v", c, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 // Code generated by "enumer -type CompressorType -json"; DO NOT EDIT.
 
-package tcp
+package wire
 
 import (
 	"encoding/json"
@@ -0,0 +1,106 @@
+package wire
+
+import (
+	"github.com/cockroachdb/errors"
+	"valley-pkg/convert"
+)
+
+// Header はメッセージのヘッダー
+type Header struct {
+	Format         string         // 3バイト
+	Version        int8           // 1バイト
+	Kind           int8           // 1バイト
+	ParserType     ParserType     // 1バイト
+	CompressorType CompressorType // 1バイト
+	Extension      [5]byte        // 5バイト
+	Length         int32          // 4バイト
+}
+
+// NewHeader は現在のフォーマットバージョンでヘッダーを作成する
+func NewHeader(format string, kind int8, parser ParserType, compressor CompressorType) Header {
+	return Header{Format: format, Version: Version, Kind: kind, ParserType: parser, CompressorType: compressor}
+}
+
+// Encode はヘッダーを16バイトに変換する
+// Format は3バイトに満たない場合は0埋めし、超える場合は切り詰める。
+func (h Header) Encode() []byte {
+	var format [VersionPos - FormatPos]byte
+	copy(format[:], h.Format)
+
+	b := make([]byte, 0, HeaderLen)
+	b = append(b, format[:]...)
+	b = append(b, convert.Int8ToByte(h.Version)...)
+	b = append(b, convert.Int8ToByte(h.Kind)...)
+	b = append(b, convert.Int8ToByte(int8(h.ParserType))...)
+	b = append(b, convert.Int8ToByte(int8(h.CompressorType))...)
+	b = append(b, h.Extension[:]...)
+	b = append(b, convert.Int32ToByte(h.Length)...)
+	return b
+}
+
+// Append はヘッダーとbodyを繋げたメッセージに変換する
+func (h Header) Append(body []byte) []byte {
+	return append(h.Encode(), body...)
+}
+
+// DecodeHeader は先頭16バイトをヘッダーとして読み取る
+// 値の検証は行わないため、受信したデータには Decode を使用する。
+func DecodeHeader(b []byte) (Header, error) {
+	if len(b) < HeaderLen {
+		return Header{}, ErrHeaderShort
+	}
+
+	r := convert.NewByteReader(b)
+	var h Header
+	h.Format = r.ReadString(VersionPos - FormatPos)
+	h.Version = r.ReadInt8()
+	h.Kind = r.ReadInt8()
+	h.ParserType = ParserType(r.ReadInt8())
+	h.CompressorType = CompressorType(r.ReadInt8())
+	copy(h.Extension[:], r.ReadBytes(LenPos-ExtensionPos))
+	h.Length = r.ReadInt32()
+	if err := r.Err(); err != nil {
+		return Header{}, err
+	}
+	return h, nil
+}
+
+// Decode はメッセージをヘッダーとbodyに分けて読み取り、ヘッダーを検証する
+// bodyは容量を指定して切り出すので、b の後ろのデータを引き継がない。
+func Decode(format string, b []byte) (Header, []byte, error) {
+	h, err := DecodeHeader(b)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if h.Length < 0 {
+		return Header{}, nil, ErrLen
+	}
+
+	// データが足りない
+	end := BodyPos + int(h.Length)
+	if len(b) < end {
+		return Header{}, nil, ErrBodyShort
+	}
+
+	if err := h.Validate(format); err != nil {
+		return Header{}, nil, err
+	}
+	return h, b[BodyPos:end:end], nil
+}
+
+// Validate はフォーマット、バージョン、パーサー、コンプレッサーを検証する
+func (h Header) Validate(format string) error {
+	if h.Format != format {
+		return errors.Errorf("beginning of data is not %s : %w", format, ErrFormat)
+	}
+	if h.Version < MinVersion || h.Version > Version {
+		return errors.Errorf("version %d: %w", h.Version, ErrVersion)
+	}
+	if !IsRegisteredParser(h.ParserType) {
+		return ErrParser
+	}
+	if !IsRegisteredCompressor(h.CompressorType) {
+		return ErrCompressor
+	}
+	return nil
+}
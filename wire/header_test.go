@@ -0,0 +1,83 @@
+package wire
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestHeader_Encode(t *testing.T) {
+	h := Header{Format: "TST", Version: Version, Kind: 2, ParserType: JSON, CompressorType: ZSTD, Length: 258}
+	b := h.Encode()
+
+	if len(b) != HeaderLen {
+		t.Fatalf("ヘッダー長が想定外です。got=%v, want=%v", len(b), HeaderLen)
+	}
+	got, err := DecodeHeader(b)
+	if err != nil {
+		t.Fatalf("DecodeHeader error: %v", err)
+	}
+	if got != h {
+		t.Errorf("ヘッダーが想定外です。got=%+v, want=%+v", got, h)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	valid := NewHeader("TST", 1, JSON, None)
+	valid.Length = 4
+
+	withHeader := func(f func(h *Header)) []byte {
+		h := valid
+		f(&h)
+		return h.Append([]byte("body"))
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr error
+	}{
+		{name: "正常値: 有効なメッセージ", data: valid.Append([]byte("body")), want: []byte("body")},
+		{name: "正常値: 後ろのデータは切り捨てる", data: valid.Append([]byte("body-next")), want: []byte("body")},
+		{name: "異常値: ヘッダー未満", data: make([]byte, HeaderLen-1), wantErr: ErrHeaderShort},
+		{name: "異常値: ボディ不足", data: withHeader(func(h *Header) { h.Length = 10 }), wantErr: ErrBodyShort},
+		{name: "異常値: 負の長さ", data: withHeader(func(h *Header) { h.Length = -1 }), wantErr: ErrLen},
+		{name: "異常値: フォーマット不一致", data: withHeader(func(h *Header) { h.Format = "WRG" }), wantErr: ErrFormat},
+		{name: "異常値: 未対応バージョン", data: withHeader(func(h *Header) { h.Version = Version + 1 }), wantErr: ErrVersion},
+		{name: "異常値: バージョン0", data: withHeader(func(h *Header) { h.Version = 0 }), wantErr: ErrVersion},
+		{name: "異常値: 未対応パーサー", data: withHeader(func(h *Header) { h.ParserType = 99 }), wantErr: ErrParser},
+		{name: "異常値: 未対応コンプレッサー", data: withHeader(func(h *Header) { h.CompressorType = 99 }), wantErr: ErrCompressor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, err := Decode("TST", tt.data)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("エラーが想定外です。got=%v, want=%v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if string(body) != string(tt.want) {
+				t.Errorf("bodyが想定外です。got=%q, want=%q", body, tt.want)
+			}
+			if cap(body) != len(body) {
+				t.Errorf("bodyの容量が想定外です。got=%v, want=%v", cap(body), len(body))
+			}
+		})
+	}
+}
+
+func TestErrShort(t *testing.T) {
+	for _, err := range []error{ErrHeaderShort, ErrBodyShort} {
+		if !errors.Is(err, ErrShort) {
+			t.Errorf("%v が ErrShort として判定されません", err)
+		}
+	}
+	if errors.Is(ErrHeaderShort, ErrBodyShort) {
+		t.Errorf("ErrHeaderShort が ErrBodyShort として判定されます")
+	}
+}
@@ -0,0 +1,38 @@
+package wire
+
+import (
+	"github.com/cockroachdb/errors"
+	"valley-pkg/parser"
+)
+
+//go:generate enumer -type ParserType -json
+
+// ParserType はヘッダーに書き込むパーサーの識別子。値は parser パッケージのレジストリの識別子と対応する。
+type ParserType int8
+
+const (
+	// Undefined
+	_ ParserType = iota
+
+	JSON
+
+	PROTOBUF
+
+	MSGPACK
+
+	YAML
+)
+
+// GetParser は識別子に対応するパーサーを parser パッケージのレジストリから取得する
+func GetParser(t ParserType) (parser.Parser, error) {
+	p, err := parser.Get(byte(t))
+	if err != nil {
+		return nil, errors.Errorf("%v: %w", err, ErrParser)
+	}
+	return p, nil
+}
+
+// IsRegisteredParser は識別子に対応するパーサーが登録されているかを返す
+func IsRegisteredParser(t ParserType) bool {
+	return parser.IsRegistered(byte(t))
+}
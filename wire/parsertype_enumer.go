@@ -1,6 +1,6 @@
 // Code generated by "enumer -type ParserType -json"; DO NOT EDIT.
 
-package tcp
+package wire
 
 import (
 	"encoding/json"
@@ -8,11 +8,11 @@ import (
 	"strings"
 )
 
-const _ParserTypeName = "JSONPROTOBUF"
+const _ParserTypeName = "JSONPROTOBUFMSGPACKYAML"
 
-var _ParserTypeIndex = [...]uint8{0, 4, 12}
+var _ParserTypeIndex = [...]uint8{0, 4, 12, 19, 23}
 
-const _ParserTypeLowerName = "jsonprotobuf"
+const _ParserTypeLowerName = "jsonprotobufmsgpackyaml"
 
 func (i ParserType) String() string {
 	i -= 1
@@ -28,20 +28,28 @@ func _ParserTypeNoOp() {
 	var x [1]struct{}
 	_ = x[JSON-(1)]
 	_ = x[PROTOBUF-(2)]
+	_ = x[MSGPACK-(3)]
+	_ = x[YAML-(4)]
 }
 
-var _ParserTypeValues = []ParserType{JSON, PROTOBUF}
+var _ParserTypeValues = []ParserType{JSON, PROTOBUF, MSGPACK, YAML}
 
 var _ParserTypeNameToValueMap = map[string]ParserType{
-	_ParserTypeName[0:4]:       JSON,
-	_ParserTypeLowerName[0:4]:  JSON,
-	_ParserTypeName[4:12]:      PROTOBUF,
-	_ParserTypeLowerName[4:12]: PROTOBUF,
+	_ParserTypeName[0:4]:        JSON,
+	_ParserTypeLowerName[0:4]:   JSON,
+	_ParserTypeName[4:12]:       PROTOBUF,
+	_ParserTypeLowerName[4:12]:  PROTOBUF,
+	_ParserTypeName[12:19]:      MSGPACK,
+	_ParserTypeLowerName[12:19]: MSGPACK,
+	_ParserTypeName[19:23]:      YAML,
+	_ParserTypeLowerName[19:23]: YAML,
 }
 
 var _ParserTypeNames = []string{
 	_ParserTypeName[0:4],
 	_ParserTypeName[4:12],
+	_ParserTypeName[12:19],
+	_ParserTypeName[19:23],
 }
 
 // ParserTypeString retrieves an enum value from the enum constants string name.
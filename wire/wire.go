@@ -0,0 +1,79 @@
+// Package wire は tcp と udp で共通のメッセージ形式を扱う
+// 16バイトのヘッダーのエンコード・デコード、パーサーとコンプレッサーの識別子、バージョンの管理を行い、
+// tcp と udp はこのパッケージの上で送受信のみを担う。
+//
+// ヘッダーの形式
+//
+//	| Format(3) | Version(1) | Kind(1) | Parser(1) | Compressor(1) | Extension(5) | Length(4) |
+package wire
+
+import "github.com/cockroachdb/errors"
+
+const (
+	// Version は書き込むフォーマットバージョンを表す
+	Version = 1
+	// MinVersion は読み取り可能な最小のフォーマットバージョンを表す
+	MinVersion = 1
+	// HeaderLen はヘッダー長を表す
+	HeaderLen = 16
+	// FormatPos はBldの開始位置を表す
+	FormatPos = 0
+	// VersionPos はversionの開始位置を表す
+	VersionPos = 3
+	// KindPos はkindの開始位置を表す
+	KindPos = 4
+	// ParserPos はParserの開始位置を表す
+	ParserPos = 5
+	// CompressorPos はCompの開始位置を表す
+	CompressorPos = 6
+	// ExtensionPos はExtensionの開始位置を表す
+	ExtensionPos = 7
+	// LenPos はLenの開始位置を表す
+	LenPos = 12
+	// BodyPos はBodyの開始位置を表す
+	BodyPos = HeaderLen
+)
+
+// ErrKind はメッセージ種別がおかしい場合のエラー
+var ErrKind = errors.New("kind error")
+
+// ErrShort はデータ長が足りない場合のエラー
+// ErrHeaderShort と ErrBodyShort はどちらも errors.Is(err, ErrShort) が真になる。
+var ErrShort = errors.New("message is short")
+
+// ErrHeaderShort はheaderデータ長が足りない場合のエラー
+var ErrHeaderShort error = &shortError{msg: "header message is short"}
+
+// ErrBodyShort はbodyデータ長が足りない場合のエラー
+var ErrBodyShort error = &shortError{msg: "body message is short"}
+
+// ErrFormat はデータの先頭を表す識別子が誤っている場合のエラー
+var ErrFormat = errors.New("format error")
+
+// ErrVersion は読み取りできないフォーマットバージョンの場合のエラー
+var ErrVersion = errors.New("version is unsupported")
+
+// ErrParser はパーサーの種類が間違っている場合のエラー
+var ErrParser = errors.New("request parser is unsupported")
+
+// ErrCompressor はコンプレッサーの種類が間違っている場合のエラー
+var ErrCompressor = errors.New("request compressor is unsupported")
+
+// ErrLen はデータのLenの値がおかしい場合のエラー
+var ErrLen = errors.New("len is 0 or less")
+
+// ErrHealthCheck はデータがない場合のエラー
+var ErrHealthCheck = errors.New("health check")
+
+// shortError は ErrShort として判定されるデータ長不足のエラー
+type shortError struct {
+	msg string
+}
+
+func (e *shortError) Error() string {
+	return e.msg
+}
+
+func (e *shortError) Is(target error) bool {
+	return target == ErrShort
+}